@@ -0,0 +1,144 @@
+package pg
+
+import (
+	"context"
+)
+
+// LinkStatus is the unified status of a linked account
+type LinkStatus string
+
+const (
+	// LinkPending means the customer has not authorized the link yet
+	LinkPending LinkStatus = "PENDING"
+	// LinkActive means the account can be charged with its token
+	LinkActive LinkStatus = "ACTIVE"
+	// LinkExpired means the customer did not authorize the link in time
+	LinkExpired LinkStatus = "EXPIRED"
+	// LinkUnlinked means the link was removed by the merchant or the customer
+	LinkUnlinked LinkStatus = "UNLINKED"
+	// LinkFailed means the link could not be created
+	LinkFailed LinkStatus = "FAILED"
+)
+
+// AccountLinker is implemented by providers that can link customer e-wallet or bank accounts
+// A linked account is charged by setting ChargeParams.EWallet.AccountLinkID,
+// or ChargeParams.DirectDebit.AccountLinkID for bank accounts
+type AccountLinker interface {
+	LinkAccount(ctx context.Context, params LinkParams) (*LinkedAccount, error)
+	GetLinkedAccount(ctx context.Context, id string) (*LinkedAccount, error)
+	UnlinkAccount(ctx context.Context, id string) error
+}
+
+// LinkParams represents the parameters for linking a customer e-wallet or bank account
+type LinkParams struct {
+	// PaymentType is the e-wallet or direct debit bank to link, e.g. PaymentTypeGoPay (required)
+	PaymentType PaymentType `json:"payment_type"`
+
+	// Customer is the account owner (required)
+	// Midtrans needs Customer.Phone, Xendit needs Customer.ID to be a Xendit customer ID
+	Customer Customer `json:"customer"`
+
+	// RedirectURL is where the customer returns after authorizing the link (required)
+	RedirectURL string `json:"redirect_url"`
+
+	// Custom contains provider-specific parameters that are not mapped to unified fields
+	Custom map[string]interface{} `json:"-"`
+}
+
+// LinkedAccount represents a linked customer account
+type LinkedAccount struct {
+	// ID is the account link ID, used as EWALLETParams.AccountLinkID when charging
+	ID string `json:"id"`
+
+	// Token is the secret credential of the link when the provider issues one (Doku refresh token)
+	// It is passed as DirectDebitParams.AccountToken, store it like a password
+	Token string `json:"-"`
+
+	// PaymentType is the linked e-wallet
+	PaymentType PaymentType `json:"payment_type"`
+
+	// Status is the current link status
+	Status LinkStatus `json:"status"`
+
+	// ActionURL is where the customer authorizes the link when Status is PENDING
+	ActionURL string `json:"action_url,omitempty"`
+
+	// AccountName is the masked account name or number reported by the e-wallet
+	AccountName string `json:"account_name,omitempty"`
+
+	// Balance is the e-wallet balance, nil when the provider does not report it
+	Balance *int64 `json:"balance,omitempty"`
+
+	// NextActions are the steps the customer must take to finish the link
+	NextActions []Action `json:"next_actions,omitempty"`
+
+	// Raw contains the raw response from the provider
+	Raw map[string]interface{} `json:"-"`
+}
+
+// accountLinker returns the provider as an AccountLinker, or ErrUnimplemented
+func (c *Client) accountLinker() (AccountLinker, error) {
+	l, ok := c.provider.(AccountLinker)
+	if !ok {
+		return nil, ErrUnimplemented
+	}
+	return l, nil
+}
+
+// LinkAccount starts linking a customer e-wallet account
+// The customer finishes the link at LinkedAccount.ActionURL
+// Returns ErrUnimplemented if the provider does not support account linking
+func (c *Client) LinkAccount(ctx context.Context, params LinkParams) (*LinkedAccount, error) {
+	l, err := c.accountLinker()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return l.LinkAccount(ctx, params)
+}
+
+// GetLinkedAccount retrieves a linked account, including its balance when the provider reports it
+func (c *Client) GetLinkedAccount(ctx context.Context, id string) (*LinkedAccount, error) {
+	l, err := c.accountLinker()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+
+	return l.GetLinkedAccount(ctx, id)
+}
+
+// UnlinkAccount removes a linked account
+func (c *Client) UnlinkAccount(ctx context.Context, id string) error {
+	l, err := c.accountLinker()
+	if err != nil {
+		return err
+	}
+
+	if id == "" {
+		return NewRequiredFieldError("ID")
+	}
+
+	return l.UnlinkAccount(ctx, id)
+}
+
+// Validate validates the link parameters
+func (p LinkParams) Validate() error {
+	if p.PaymentType == "" {
+		return NewRequiredFieldError("PaymentType")
+	}
+	if !p.PaymentType.IsEWallet() && !p.PaymentType.IsDirectDebit() {
+		return NewFieldError("PaymentType", "only e-wallets and direct debit accounts can be linked")
+	}
+	if p.RedirectURL == "" {
+		return NewRequiredFieldError("RedirectURL")
+	}
+	return nil
+}
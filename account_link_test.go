@@ -0,0 +1,103 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// mockLinker is a mock provider which also implements AccountLinker
+type mockLinker struct {
+	mockProvider
+	calls int
+}
+
+func (m *mockLinker) LinkAccount(ctx context.Context, params LinkParams) (*LinkedAccount, error) {
+	m.calls++
+	return &LinkedAccount{ID: "link-123", PaymentType: params.PaymentType, Status: LinkPending, ActionURL: "https://example.com/auth"}, nil
+}
+
+func (m *mockLinker) GetLinkedAccount(ctx context.Context, id string) (*LinkedAccount, error) {
+	m.calls++
+	balance := int64(100000)
+	return &LinkedAccount{ID: id, Status: LinkActive, Balance: &balance}, nil
+}
+
+func (m *mockLinker) UnlinkAccount(ctx context.Context, id string) error {
+	m.calls++
+	return nil
+}
+
+func TestLinkParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  LinkParams
+		wantErr bool
+	}{
+		{name: "valid", params: LinkParams{PaymentType: PaymentTypeGoPay, RedirectURL: "https://example.com"}},
+		{name: "missing payment type", params: LinkParams{RedirectURL: "https://example.com"}, wantErr: true},
+		{name: "direct debit", params: LinkParams{PaymentType: PaymentTypeDDBRI, RedirectURL: "https://example.com"}},
+		{name: "not linkable", params: LinkParams{PaymentType: PaymentTypeVABCA, RedirectURL: "https://example.com"}, wantErr: true},
+		{name: "missing redirect URL", params: LinkParams{PaymentType: PaymentTypeOVO}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_AccountLink_Unimplemented(t *testing.T) {
+	client := &Client{provider: &mockProvider{name: "mock"}, config: &Config{}}
+	ctx := context.Background()
+
+	if _, err := client.LinkAccount(ctx, LinkParams{PaymentType: PaymentTypeGoPay, RedirectURL: "https://example.com"}); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("LinkAccount() error = %v, want %v", err, ErrUnimplemented)
+	}
+	if err := client.UnlinkAccount(ctx, "link-123"); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("UnlinkAccount() error = %v, want %v", err, ErrUnimplemented)
+	}
+}
+
+func TestClient_AccountLink(t *testing.T) {
+	mock := &mockLinker{}
+	client := &Client{provider: mock, config: &Config{}}
+	ctx := context.Background()
+
+	if _, err := client.LinkAccount(ctx, LinkParams{PaymentType: PaymentTypeGoPay}); err == nil {
+		t.Error("expected validation error, got nil")
+	}
+	if mock.calls != 0 {
+		t.Errorf("provider calls = %v, want 0", mock.calls)
+	}
+
+	account, err := client.LinkAccount(ctx, LinkParams{PaymentType: PaymentTypeGoPay, RedirectURL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("LinkAccount() error = %v", err)
+	}
+	if account.Status != LinkPending || account.ActionURL == "" {
+		t.Errorf("account = %+v, want pending with action URL", account)
+	}
+
+	if _, err := client.GetLinkedAccount(ctx, ""); err == nil {
+		t.Error("expected error for empty ID, got nil")
+	}
+	account, err = client.GetLinkedAccount(ctx, "link-123")
+	if err != nil {
+		t.Fatalf("GetLinkedAccount() error = %v", err)
+	}
+	if account.Balance == nil || *account.Balance != 100000 {
+		t.Errorf("Balance = %v, want 100000", account.Balance)
+	}
+
+	if err := client.UnlinkAccount(ctx, ""); err == nil {
+		t.Error("expected error for empty ID, got nil")
+	}
+	if err := client.UnlinkAccount(ctx, "link-123"); err != nil {
+		t.Errorf("UnlinkAccount() error = %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package pg
+
+// ActionType is the kind of step the customer must take to complete a payment
+type ActionType string
+
+const (
+	// ActionRedirect means the customer must be redirected to Action.URL, a web checkout page
+	ActionRedirect ActionType = "REDIRECT"
+	// ActionDeeplink means Action.URL opens the payment in the e-wallet or bank app
+	ActionDeeplink ActionType = "DEEPLINK"
+	// ActionShowQR means the customer must scan a QR code
+	// Action.QRString holds the QR content and Action.URL a QR image when the provider renders one
+	ActionShowQR ActionType = "SHOW_QR"
+	// ActionValidateOTP means the customer received an OTP which must be submitted
+	// with Client.ValidateChargeOTP or Client.ConfirmLink
+	ActionValidateOTP ActionType = "VALIDATE_OTP"
+	// ActionCheckStatus means Action.URL reports the payment status, it needs no customer interaction
+	ActionCheckStatus ActionType = "CHECK_STATUS"
+)
+
+// Action represents a step the customer must take to complete a payment or an account link
+type Action struct {
+	// Type is the kind of action
+	Type ActionType `json:"type"`
+
+	// URL is the URL to open, for ActionRedirect, ActionDeeplink and QR images
+	URL string `json:"url,omitempty"`
+
+	// Method is the HTTP method for URL
+	Method string `json:"method,omitempty"`
+
+	// QRString is the QR code content, for ActionShowQR
+	QRString string `json:"qr_string,omitempty"`
+}
+
+// PreferredURL returns the URL the customer should be sent to among actions
+// App deeplinks are preferred over web checkout pages when preferApp is set, and the other way around otherwise
+func PreferredURL(actions []Action, preferApp bool) string {
+	order := []ActionType{ActionRedirect, ActionDeeplink}
+	if preferApp {
+		order = []ActionType{ActionDeeplink, ActionRedirect}
+	}
+
+	for _, actionType := range order {
+		for _, action := range actions {
+			if action.Type == actionType && action.URL != "" {
+				return action.URL
+			}
+		}
+	}
+	return ""
+}
+
+// Action returns the first next action of the given type
+func (r *ChargeResponse) Action(actionType ActionType) (Action, bool) {
+	for _, action := range r.NextActions {
+		if action.Type == actionType {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// PrefersApp reports whether the charge asked for app deeplinks with EWALLETParams.RedirectToApp
+func (p ChargeParams) PrefersApp() bool {
+	return p.EWallet != nil && p.EWallet.RedirectToApp
+}
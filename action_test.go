@@ -0,0 +1,77 @@
+package pg
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPreferredURL(t *testing.T) {
+	actions := []Action{
+		{Type: ActionShowQR, URL: "https://example.com/qr.png", QRString: "000201"},
+		{Type: ActionDeeplink, URL: "gojek://gopay/pay"},
+		{Type: ActionRedirect, URL: "https://example.com/pay"},
+	}
+
+	tests := []struct {
+		name      string
+		actions   []Action
+		preferApp bool
+		want      string
+	}{
+		{name: "web", actions: actions, want: "https://example.com/pay"},
+		{name: "app", actions: actions, preferApp: true, want: "gojek://gopay/pay"},
+		{name: "app falls back to web", actions: actions[2:], preferApp: true, want: "https://example.com/pay"},
+		{name: "web falls back to app", actions: actions[:2], want: "gojek://gopay/pay"},
+		{name: "QR only", actions: actions[:1], want: ""},
+		{name: "none", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PreferredURL(tt.actions, tt.preferApp); got != tt.want {
+				t.Errorf("PreferredURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChargeResponse_Action(t *testing.T) {
+	resp := &ChargeResponse{NextActions: []Action{{Type: ActionShowQR, QRString: "000201"}}}
+
+	if action, ok := resp.Action(ActionShowQR); !ok || action.QRString != "000201" {
+		t.Errorf("Action(ActionShowQR) = %+v, %v, want QR action", action, ok)
+	}
+	if _, ok := resp.Action(ActionValidateOTP); ok {
+		t.Error("Action(ActionValidateOTP) found, want none")
+	}
+}
+
+func TestClient_CreateCharge_RedirectToApp(t *testing.T) {
+	newResp := func() *ChargeResponse {
+		return &ChargeResponse{
+			PaymentURL: "https://example.com/pay",
+			NextActions: []Action{
+				{Type: ActionRedirect, URL: "https://example.com/pay"},
+				{Type: ActionDeeplink, URL: "shopeeid://pay"},
+			},
+		}
+	}
+
+	client := &Client{provider: &mockProvider{name: "mock", chargeResp: newResp()}, config: &Config{}}
+	resp, err := client.CreateCharge(context.Background(), ChargeParams{EWallet: &EWALLETParams{RedirectToApp: true}})
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if resp.PaymentURL != "shopeeid://pay" {
+		t.Errorf("PaymentURL = %v, want the deeplink", resp.PaymentURL)
+	}
+
+	client = &Client{provider: &mockProvider{name: "mock", chargeResp: newResp()}, config: &Config{}}
+	resp, err = client.CreateCharge(context.Background(), ChargeParams{})
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if resp.PaymentURL != "https://example.com/pay" {
+		t.Errorf("PaymentURL = %v, want the web checkout", resp.PaymentURL)
+	}
+}
@@ -0,0 +1,77 @@
+package pg
+
+import (
+	"strings"
+)
+
+// Address represents a billing or shipping address
+type Address struct {
+	// Name is the recipient full name, defaults to the customer name
+	Name string `json:"name,omitempty"`
+
+	// Phone is the recipient phone number, defaults to the customer phone
+	Phone string `json:"phone,omitempty"`
+
+	// Line1 is the street address
+	Line1 string `json:"line1"`
+
+	// Line2 is the apartment, building or district (optional)
+	Line2 string `json:"line2,omitempty"`
+
+	// City is the city or regency
+	City string `json:"city"`
+
+	// State is the province
+	State string `json:"state,omitempty"`
+
+	// PostalCode is the postal code
+	PostalCode string `json:"postal_code"`
+
+	// CountryCode is the ISO 3166-1 alpha-2 country code, defaults to ID
+	CountryCode string `json:"country_code,omitempty"`
+}
+
+// Street returns Line1 and Line2 joined with a comma
+func (a *Address) Street() string {
+	if a.Line2 == "" {
+		return a.Line1
+	}
+	return a.Line1 + ", " + a.Line2
+}
+
+// Country returns the ISO 3166-1 alpha-2 country code, ID when not set
+func (a *Address) Country() string {
+	if a.CountryCode == "" {
+		return "ID"
+	}
+	return strings.ToUpper(a.CountryCode)
+}
+
+// SplitName splits a full name into given names and surname
+// The last word is the surname, a single word name has no surname
+func SplitName(name string) (given, surname string) {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		return parts[0], ""
+	}
+	return strings.Join(parts[:len(parts)-1], " "), parts[len(parts)-1]
+}
+
+// Recipient returns the address with Name and Phone defaulted to the customer ones
+// Returns nil when the address is nil
+func (c Customer) Recipient(a *Address) *Address {
+	if a == nil {
+		return nil
+	}
+	r := *a
+	if r.Name == "" {
+		r.Name = c.Name
+	}
+	if r.Phone == "" {
+		r.Phone = c.Phone
+	}
+	return &r
+}
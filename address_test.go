@@ -0,0 +1,72 @@
+package pg
+
+import (
+	"testing"
+)
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		name        string
+		fullName    string
+		wantGiven   string
+		wantSurname string
+	}{
+		{name: "empty", fullName: "", wantGiven: "", wantSurname: ""},
+		{name: "single word", fullName: "Budi", wantGiven: "Budi", wantSurname: ""},
+		{name: "two words", fullName: "John Doe", wantGiven: "John", wantSurname: "Doe"},
+		{name: "long name", fullName: "Muhammad Rizky Pratama Putra", wantGiven: "Muhammad Rizky Pratama", wantSurname: "Putra"},
+		{name: "extra spaces", fullName: "  Siti   Nurhaliza ", wantGiven: "Siti", wantSurname: "Nurhaliza"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			given, surname := SplitName(tt.fullName)
+			if given != tt.wantGiven || surname != tt.wantSurname {
+				t.Errorf("SplitName(%q) = %q, %q, want %q, %q", tt.fullName, given, surname, tt.wantGiven, tt.wantSurname)
+			}
+		})
+	}
+}
+
+func TestAddress_Street(t *testing.T) {
+	a := &Address{Line1: "Jl. Sudirman No. 1"}
+	if got := a.Street(); got != "Jl. Sudirman No. 1" {
+		t.Errorf("Street() = %v, want Line1", got)
+	}
+
+	a.Line2 = "Tanah Abang"
+	if got := a.Street(); got != "Jl. Sudirman No. 1, Tanah Abang" {
+		t.Errorf("Street() = %v, want Line1 and Line2", got)
+	}
+}
+
+func TestAddress_Country(t *testing.T) {
+	if got := (&Address{}).Country(); got != "ID" {
+		t.Errorf("Country() = %v, want ID", got)
+	}
+	if got := (&Address{CountryCode: "sg"}).Country(); got != "SG" {
+		t.Errorf("Country() = %v, want SG", got)
+	}
+}
+
+func TestCustomer_Recipient(t *testing.T) {
+	c := Customer{Name: "John Doe", Phone: "+6281234567890"}
+
+	if c.Recipient(nil) != nil {
+		t.Error("Recipient(nil) should be nil")
+	}
+
+	shipping := &Address{Line1: "Jl. Sudirman No. 1", City: "Jakarta"}
+	r := c.Recipient(shipping)
+	if r.Name != "John Doe" || r.Phone != "+6281234567890" {
+		t.Errorf("Recipient() = %+v, want customer name and phone", r)
+	}
+	if shipping.Name != "" {
+		t.Error("Recipient() should not modify the address")
+	}
+
+	r = c.Recipient(&Address{Name: "Jane Doe", Line1: "Jl. Thamrin No. 2"})
+	if r.Name != "Jane Doe" || r.Phone != "+6281234567890" {
+		t.Errorf("Recipient() = %+v, want Jane Doe with customer phone", r)
+	}
+}
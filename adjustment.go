@@ -0,0 +1,104 @@
+package pg
+
+import (
+	"strings"
+)
+
+// AdjustmentType is the kind of an adjustment line
+type AdjustmentType string
+
+const (
+	// AdjustmentDiscount is subtracted from the items
+	AdjustmentDiscount AdjustmentType = "DISCOUNT"
+	// AdjustmentFee is a service or admin fee
+	AdjustmentFee AdjustmentType = "FEE"
+	// AdjustmentTax is a tax line, e.g. VAT
+	AdjustmentTax AdjustmentType = "TAX"
+	// AdjustmentShipping is the shipping cost
+	AdjustmentShipping AdjustmentType = "SHIPPING"
+)
+
+// IsValid checks if the adjustment type is known
+func (t AdjustmentType) IsValid() bool {
+	switch t {
+	case AdjustmentDiscount, AdjustmentFee, AdjustmentTax, AdjustmentShipping:
+		return true
+	}
+	return false
+}
+
+// Adjustment is a charge line which is not an item
+type Adjustment struct {
+	// Type is the kind of adjustment
+	Type AdjustmentType `json:"type"`
+
+	// Name is the line name shown to the customer, defaults to the type
+	Name string `json:"name,omitempty"`
+
+	// Amount is the positive line amount, discounts are subtracted
+	Amount int64 `json:"amount"`
+}
+
+// SignedAmount returns the amount added to the items, negative for discounts
+func (a Adjustment) SignedAmount() int64 {
+	if a.Type == AdjustmentDiscount {
+		return -a.Amount
+	}
+	return a.Amount
+}
+
+// Label returns the name of the adjustment, or its type in title case, e.g. "Shipping"
+func (a Adjustment) Label() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	t := strings.ToLower(string(a.Type))
+	if t == "" {
+		return ""
+	}
+	return strings.ToUpper(t[:1]) + t[1:]
+}
+
+// Item returns the adjustment as a single quantity item with a signed price
+// It is used by providers which only accept item lists, e.g. Midtrans item_details
+func (a Adjustment) Item() Item {
+	return Item{
+		ID:       strings.ToLower(string(a.Type)),
+		Name:     a.Label(),
+		Price:    a.SignedAmount(),
+		Quantity: 1,
+		Category: string(a.Type),
+	}
+}
+
+// ItemsTotal returns the sum of the item prices times their quantity
+func (p ChargeParams) ItemsTotal() int64 {
+	var total int64
+	for _, item := range p.Items {
+		total += item.Price * item.Quantity
+	}
+	return total
+}
+
+// GrossAmount returns the items total plus the signed adjustments
+func (p ChargeParams) GrossAmount() int64 {
+	total := p.ItemsTotal()
+	for _, a := range p.Adjustments {
+		total += a.SignedAmount()
+	}
+	return total
+}
+
+// LineItems returns the items followed by the adjustments as items
+func (p ChargeParams) LineItems() []Item {
+	if len(p.Adjustments) == 0 {
+		return p.Items
+	}
+
+	items := make([]Item, 0, len(p.Items)+len(p.Adjustments))
+	items = append(items, p.Items...)
+	for _, a := range p.Adjustments {
+		items = append(items, a.Item())
+	}
+	return items
+}
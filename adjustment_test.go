@@ -0,0 +1,61 @@
+package pg
+
+import (
+	"testing"
+)
+
+func TestAdjustment_Item(t *testing.T) {
+	tests := []struct {
+		name       string
+		adjustment Adjustment
+		want       Item
+	}{
+		{
+			name:       "discount",
+			adjustment: Adjustment{Type: AdjustmentDiscount, Amount: 5000},
+			want:       Item{ID: "discount", Name: "Discount", Price: -5000, Quantity: 1, Category: "DISCOUNT"},
+		},
+		{
+			name:       "named tax",
+			adjustment: Adjustment{Type: AdjustmentTax, Name: "VAT 11%", Amount: 11000},
+			want:       Item{ID: "tax", Name: "VAT 11%", Price: 11000, Quantity: 1, Category: "TAX"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.adjustment.Item(); got != tt.want {
+				t.Errorf("Item() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChargeParams_GrossAmount(t *testing.T) {
+	params := ChargeParams{
+		Items: []Item{
+			{Name: "Shirt", Price: 100000, Quantity: 2},
+			{Name: "Socks", Price: 20000, Quantity: 1},
+		},
+		Adjustments: []Adjustment{
+			{Type: AdjustmentDiscount, Amount: 20000},
+			{Type: AdjustmentShipping, Amount: 15000},
+			{Type: AdjustmentFee, Amount: 2500},
+		},
+	}
+
+	if got := params.ItemsTotal(); got != 220000 {
+		t.Errorf("ItemsTotal() = %v, want 220000", got)
+	}
+	if got := params.GrossAmount(); got != 217500 {
+		t.Errorf("GrossAmount() = %v, want 217500", got)
+	}
+
+	items := params.LineItems()
+	if len(items) != 5 {
+		t.Fatalf("LineItems() returned %d items, want 5", len(items))
+	}
+	if items[2].Price != -20000 || items[3].Name != "Shipping" {
+		t.Errorf("LineItems() = %+v, want adjustments after the items", items)
+	}
+}
@@ -0,0 +1,220 @@
+package pg
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Bank is an Indonesian bank in the unified bank code registry
+type Bank struct {
+	// Code is the unified bank code, e.g. BCA, MANDIRI, BNI
+	// It is the code accepted by DisbursementParams.BankCode and ValidateBankAccount
+	Code string `json:"code"`
+
+	// Name is the bank name
+	Name string `json:"name"`
+
+	// BICode is the 3 digit Bank Indonesia clearing code
+	BICode string `json:"bi_code"`
+}
+
+// banks is the unified bank code registry
+var banks = []Bank{
+	{Code: "BCA", Name: "Bank Central Asia", BICode: "014"},
+	{Code: "MANDIRI", Name: "Bank Mandiri", BICode: "008"},
+	{Code: "BNI", Name: "Bank Negara Indonesia", BICode: "009"},
+	{Code: "BRI", Name: "Bank Rakyat Indonesia", BICode: "002"},
+	{Code: "BTN", Name: "Bank Tabungan Negara", BICode: "200"},
+	{Code: "BSI", Name: "Bank Syariah Indonesia", BICode: "451"},
+	{Code: "PERMATA", Name: "Bank Permata", BICode: "013"},
+	{Code: "CIMB", Name: "Bank CIMB Niaga", BICode: "022"},
+	{Code: "DANAMON", Name: "Bank Danamon", BICode: "011"},
+	{Code: "MAYBANK", Name: "Maybank Indonesia", BICode: "016"},
+	{Code: "PANIN", Name: "Bank Panin", BICode: "019"},
+	{Code: "OCBC", Name: "Bank OCBC NISP", BICode: "028"},
+	{Code: "UOB", Name: "Bank UOB Indonesia", BICode: "023"},
+	{Code: "CITIBANK", Name: "Citibank", BICode: "031"},
+	{Code: "DBS", Name: "Bank DBS Indonesia", BICode: "046"},
+	{Code: "HSBC", Name: "HSBC Indonesia", BICode: "087"},
+	{Code: "MUAMALAT", Name: "Bank Muamalat", BICode: "147"},
+	{Code: "SINARMAS", Name: "Bank Sinarmas", BICode: "153"},
+	{Code: "BJB", Name: "Bank BJB", BICode: "110"},
+	{Code: "BTPN", Name: "Bank BTPN", BICode: "213"},
+	{Code: "MEGA", Name: "Bank Mega", BICode: "426"},
+	{Code: "BUKOPIN", Name: "KB Bukopin", BICode: "441"},
+	{Code: "SEABANK", Name: "SeaBank Indonesia", BICode: "535"},
+	{Code: "JAGO", Name: "Bank Jago", BICode: "542"},
+}
+
+// bankAliases maps other commonly used codes to the unified bank code
+var bankAliases = map[string]string{
+	"BANK_CENTRAL_ASIA": "BCA",
+	"BSM":               "BSI",
+	"CIMB_NIAGA":        "CIMB",
+	"OCBC_NISP":         "OCBC",
+	"BANK_JAGO":         "JAGO",
+	"KB_BUKOPIN":        "BUKOPIN",
+}
+
+// Banks returns all banks of the unified bank code registry
+func Banks() []Bank {
+	result := make([]Bank, len(banks))
+	copy(result, banks)
+	return result
+}
+
+// LookupBank finds a bank by its unified code, BI code or a known alias
+// The lookup is case-insensitive
+func LookupBank(code string) (Bank, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if alias, ok := bankAliases[code]; ok {
+		code = alias
+	}
+
+	for _, bank := range banks {
+		if bank.Code == code || bank.BICode == code {
+			return bank, true
+		}
+	}
+
+	return Bank{}, false
+}
+
+// NormalizeBankCode returns the unified bank code for code
+// Unknown codes are returned upper-cased so providers still receive them
+func NormalizeBankCode(code string) string {
+	if bank, ok := LookupBank(code); ok {
+		return bank.Code
+	}
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// BankAccountValidator is implemented by providers that can look up the holder of a bank account
+type BankAccountValidator interface {
+	ValidateBankAccount(ctx context.Context, bankCode, accountNumber string) (*BankAccount, error)
+}
+
+// BankAccount is the result of a bank account validation
+type BankAccount struct {
+	// BankCode is the unified bank code
+	BankCode string `json:"bank_code"`
+
+	// AccountNumber is the validated account number
+	AccountNumber string `json:"account_number"`
+
+	// AccountHolder is the name registered at the bank
+	AccountHolder string `json:"account_holder"`
+
+	// Valid is true when the bank confirmed the account exists
+	Valid bool `json:"valid"`
+
+	// Status is SUCCESS when the inquiry finished, PENDING when the provider
+	// is still checking the account and FAILED when the account was not found
+	Status Status `json:"status"`
+
+	// FailureReason is the reason when the account could not be validated
+	FailureReason string `json:"failure_reason,omitempty"`
+
+	// Raw contains the raw response from the provider
+	Raw map[string]interface{} `json:"-"`
+}
+
+// NameMatchScore returns how closely the registered holder name matches the expected name
+// See NameMatchScore for the scoring rules
+func (a *BankAccount) NameMatchScore(expected string) float64 {
+	return NameMatchScore(a.AccountHolder, expected)
+}
+
+// ValidateBankAccount looks up the holder name of a bank account
+// bankCode may be a unified code, a BI code or a known alias
+// Returns ErrUnimplemented if the provider does not support account validation
+func (c *Client) ValidateBankAccount(ctx context.Context, bankCode, accountNumber string) (*BankAccount, error) {
+	v, ok := c.provider.(BankAccountValidator)
+	if !ok {
+		return nil, ErrUnimplemented
+	}
+
+	if bankCode == "" {
+		return nil, NewRequiredFieldError("BankCode")
+	}
+	if accountNumber == "" {
+		return nil, NewRequiredFieldError("AccountNumber")
+	}
+
+	return v.ValidateBankAccount(ctx, NormalizeBankCode(bankCode), accountNumber)
+}
+
+// honorifics are ignored when comparing account holder names
+var honorifics = map[string]bool{
+	"BPK": true, "BAPAK": true, "IBU": true, "SDR": true, "SDRI": true,
+	"TN": true, "NY": true, "NN": true, "MR": true, "MRS": true, "MS": true,
+	"PT": true, "CV": true, "TBK": true,
+}
+
+// NameMatchScore returns a similarity score between 0 and 1 for two names
+// Names are compared case-insensitively, without punctuation, honorifics
+// (BPK, IBU, PT, ...) and word order, so "Bpk. Budi Santoso" and
+// "SANTOSO BUDI" score 1. Typos lower the score proportionally to the edit distance.
+func NameMatchScore(a, b string) float64 {
+	na, nb := normalizeName(a), normalizeName(b)
+	if na == "" || nb == "" {
+		return 0
+	}
+	if na == nb {
+		return 1
+	}
+
+	longest := len([]rune(na))
+	if l := len([]rune(nb)); l > longest {
+		longest = l
+	}
+
+	return 1 - float64(levenshtein(na, nb))/float64(longest)
+}
+
+// normalizeName upper-cases a name, drops punctuation and honorifics and sorts its words
+func normalizeName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return ' '
+	}, name)
+
+	words := make([]string, 0)
+	for _, word := range strings.Fields(cleaned) {
+		if !honorifics[word] {
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+
+	return strings.Join(words, " ")
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,144 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// mockValidator is a mock provider which also implements BankAccountValidator
+type mockValidator struct {
+	mockProvider
+	bankCode string
+}
+
+func (m *mockValidator) ValidateBankAccount(ctx context.Context, bankCode, accountNumber string) (*BankAccount, error) {
+	m.bankCode = bankCode
+	return &BankAccount{
+		BankCode:      bankCode,
+		AccountNumber: accountNumber,
+		AccountHolder: "BUDI SANTOSO",
+		Valid:         true,
+		Status:        StatusSuccess,
+	}, nil
+}
+
+func TestLookupBank(t *testing.T) {
+	tests := []struct {
+		code     string
+		wantCode string
+		wantOK   bool
+	}{
+		{code: "BCA", wantCode: "BCA", wantOK: true},
+		{code: "bca", wantCode: "BCA", wantOK: true},
+		{code: "014", wantCode: "BCA", wantOK: true},
+		{code: "cimb_niaga", wantCode: "CIMB", wantOK: true},
+		{code: "BSM", wantCode: "BSI", wantOK: true},
+		{code: "UNKNOWN", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			bank, ok := LookupBank(tt.code)
+			if ok != tt.wantOK {
+				t.Fatalf("LookupBank() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if bank.Code != tt.wantCode {
+				t.Errorf("Code = %v, want %v", bank.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestBanks(t *testing.T) {
+	seenCodes := make(map[string]bool)
+	seenBICodes := make(map[string]bool)
+
+	for _, bank := range Banks() {
+		if seenCodes[bank.Code] {
+			t.Errorf("duplicate code %s", bank.Code)
+		}
+		if seenBICodes[bank.BICode] {
+			t.Errorf("duplicate BI code %s", bank.BICode)
+		}
+		if len(bank.BICode) != 3 {
+			t.Errorf("BI code of %s = %v, want 3 digits", bank.Code, bank.BICode)
+		}
+		seenCodes[bank.Code] = true
+		seenBICodes[bank.BICode] = true
+	}
+
+	for alias, code := range bankAliases {
+		if !seenCodes[code] {
+			t.Errorf("alias %s points to unknown code %s", alias, code)
+		}
+	}
+}
+
+func TestNormalizeBankCode(t *testing.T) {
+	tests := map[string]string{
+		"mandiri": "MANDIRI",
+		"009":     "BNI",
+		" bri ":   "BRI",
+		"artos":   "ARTOS",
+	}
+
+	for input, want := range tests {
+		if got := NormalizeBankCode(input); got != want {
+			t.Errorf("NormalizeBankCode(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestNameMatchScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		wantMin float64
+		wantMax float64
+	}{
+		{name: "identical", a: "Budi Santoso", b: "BUDI SANTOSO", wantMin: 1, wantMax: 1},
+		{name: "honorific and order", a: "Bpk. Budi Santoso", b: "santoso budi", wantMin: 1, wantMax: 1},
+		{name: "company suffix", a: "PT Maju Jaya Tbk", b: "Maju Jaya", wantMin: 1, wantMax: 1},
+		{name: "typo", a: "Budi Santoso", b: "Budi Santosa", wantMin: 0.9, wantMax: 0.99},
+		{name: "different person", a: "Budi Santoso", b: "Siti Rahayu", wantMin: 0, wantMax: 0.4},
+		{name: "empty", a: "", b: "Budi", wantMin: 0, wantMax: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NameMatchScore(tt.a, tt.b)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("NameMatchScore() = %v, want between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestClient_ValidateBankAccount(t *testing.T) {
+	client := &Client{provider: &mockProvider{name: "mock"}, config: &Config{}}
+	if _, err := client.ValidateBankAccount(context.Background(), "BCA", "123"); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("ValidateBankAccount() error = %v, want %v", err, ErrUnimplemented)
+	}
+
+	mock := &mockValidator{}
+	client = &Client{provider: mock, config: &Config{}}
+
+	if _, err := client.ValidateBankAccount(context.Background(), "", "123"); err == nil {
+		t.Error("expected error for empty bank code, got nil")
+	}
+	if _, err := client.ValidateBankAccount(context.Background(), "BCA", ""); err == nil {
+		t.Error("expected error for empty account number, got nil")
+	}
+
+	account, err := client.ValidateBankAccount(context.Background(), "014", "1234567890")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.bankCode != "BCA" {
+		t.Errorf("provider bank code = %v, want BCA", mock.bankCode)
+	}
+	if score := account.NameMatchScore("Sdr. Budi Santoso"); score != 1 {
+		t.Errorf("NameMatchScore() = %v, want 1", score)
+	}
+}
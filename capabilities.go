@@ -0,0 +1,114 @@
+package pg
+
+// Operation is a provider operation other than creating a charge
+type Operation string
+
+const (
+	// OperationCancel means unpaid charges can be cancelled with Client.Cancel
+	OperationCancel Operation = "cancel"
+	// OperationRefund means paid charges can be refunded
+	OperationRefund Operation = "refund"
+	// OperationCapture means authorized card charges can be captured
+	OperationCapture Operation = "capture"
+	// OperationToken means the provider issues access tokens through Client.GetToken
+	OperationToken Operation = "token"
+	// OperationBatchDisbursement means disbursements can be sent in batches with Client.CreateBatchDisbursement
+	OperationBatchDisbursement Operation = "batch_disbursement"
+	// OperationDisbursementWebhook means disbursement notifications can be parsed with Client.ParseDisbursementWebhook
+	OperationDisbursementWebhook Operation = "disbursement_webhook"
+)
+
+// Capabilities describes what a provider supports
+type Capabilities struct {
+	// PaymentTypes are the payment types accepted by CreateCharge
+	PaymentTypes []PaymentType `json:"payment_types"`
+
+	// Operations are the supported operations besides CreateCharge and GetStatus
+	Operations []Operation `json:"operations"`
+
+	// Limits are the amount limits per payment type
+	Limits map[PaymentType]AmountLimit `json:"limits,omitempty"`
+
+	// Currencies are the ISO 4217 currency codes charges are created in
+	Currencies []string `json:"currencies"`
+
+	// SandboxURL is false when sandbox and production share the same base URL
+	// and the environment is chosen by the API key alone
+	SandboxURL bool `json:"sandbox_url"`
+}
+
+// SupportsPaymentType returns true if pt is accepted by CreateCharge
+func (c Capabilities) SupportsPaymentType(pt PaymentType) bool {
+	for _, t := range c.PaymentTypes {
+		if t == pt {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsOperation returns true if op is supported
+func (c Capabilities) SupportsOperation(op Operation) bool {
+	for _, o := range c.Operations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsCurrency returns true if charges can be created in currency
+func (c Capabilities) SupportsCurrency(currency string) bool {
+	for _, cur := range c.Currencies {
+		if cur == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit returns the amount limit of pt
+func (c Capabilities) Limit(pt PaymentType) (AmountLimit, bool) {
+	l, ok := c.Limits[pt]
+	return l, ok
+}
+
+// CapabilityReporter is implemented by providers that describe their capabilities
+type CapabilityReporter interface {
+	// Capabilities returns what the provider supports
+	Capabilities() Capabilities
+}
+
+// Capabilities returns what the provider supports
+// Returns ErrUnimplemented if the provider does not describe its capabilities
+func (c *Client) Capabilities() (Capabilities, error) {
+	r, ok := c.provider.(CapabilityReporter)
+	if !ok {
+		return Capabilities{}, ErrUnimplemented
+	}
+	return r.Capabilities(), nil
+}
+
+// checkPaymentType returns an UnsupportedError if the provider reports it does not accept pt
+func (c *Client) checkPaymentType(pt PaymentType) error {
+	caps, err := c.Capabilities()
+	if err != nil || pt == "" {
+		return nil
+	}
+	if !caps.SupportsPaymentType(pt) {
+		return &UnsupportedError{Provider: c.provider.Name(), PaymentType: pt}
+	}
+	return nil
+}
+
+// checkOperation returns an UnsupportedError if the provider reports it does not support op
+func (c *Client) checkOperation(op Operation) error {
+	caps, err := c.Capabilities()
+	if err != nil {
+		return nil
+	}
+	if !caps.SupportsOperation(op) {
+		return &UnsupportedError{Provider: c.provider.Name(), Operation: op}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// mockReporter is a mock provider which also implements CapabilityReporter
+type mockReporter struct {
+	mockProvider
+	caps  Capabilities
+	calls int
+}
+
+func (m *mockReporter) CreateCharge(ctx context.Context, params ChargeParams) (*ChargeResponse, error) {
+	m.calls++
+	return &ChargeResponse{OrderID: params.OrderID}, nil
+}
+
+func (m *mockReporter) Cancel(ctx context.Context, orderID string) error {
+	m.calls++
+	return nil
+}
+
+func (m *mockReporter) Capabilities() Capabilities {
+	return m.caps
+}
+
+func TestClient_Capabilities(t *testing.T) {
+	mock := &mockReporter{
+		mockProvider: mockProvider{name: "mock"},
+		caps: Capabilities{
+			PaymentTypes: []PaymentType{PaymentTypeVABCA},
+			Operations:   []Operation{OperationToken},
+			Currencies:   []string{"IDR"},
+		},
+	}
+	client := &Client{provider: mock, config: &Config{}}
+	ctx := context.Background()
+
+	caps, err := client.Capabilities()
+	if err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+	if !caps.SupportsPaymentType(PaymentTypeVABCA) || caps.SupportsPaymentType(PaymentTypeAlfamart) {
+		t.Errorf("PaymentTypes = %v, want only VA_BCA", caps.PaymentTypes)
+	}
+	if !caps.SupportsCurrency("IDR") || caps.SupportsCurrency("PHP") {
+		t.Errorf("Currencies = %v, want only IDR", caps.Currencies)
+	}
+
+	// Unsupported payment types are rejected before the provider is called
+	_, err = client.CreateCharge(ctx, ChargeParams{OrderID: "ORDER-001", Amount: 50000, PaymentType: PaymentTypeAlfamart})
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.PaymentType != PaymentTypeAlfamart {
+		t.Errorf("CreateCharge() error = %v, want UnsupportedError for ALFAMART", err)
+	}
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("CreateCharge() error = %v, want %v", err, ErrUnsupported)
+	}
+
+	err = client.Cancel(ctx, "ORDER-001")
+	if !errors.As(err, &unsupported) || unsupported.Operation != OperationCancel {
+		t.Errorf("Cancel() error = %v, want UnsupportedError for cancel", err)
+	}
+	if mock.calls != 0 {
+		t.Errorf("provider called %d times, want 0", mock.calls)
+	}
+
+	if _, err := client.CreateCharge(ctx, ChargeParams{OrderID: "ORDER-001", Amount: 50000, PaymentType: PaymentTypeVABCA}); err != nil {
+		t.Errorf("CreateCharge() error = %v", err)
+	}
+	if mock.calls != 1 {
+		t.Errorf("provider called %d times, want 1", mock.calls)
+	}
+}
+
+func TestClient_Capabilities_Unimplemented(t *testing.T) {
+	client := &Client{provider: &mockProvider{name: "mock"}, config: &Config{}}
+
+	if _, err := client.Capabilities(); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("Capabilities() error = %v, want %v", err, ErrUnimplemented)
+	}
+
+	// Providers without capabilities are not checked
+	if err := client.Cancel(context.Background(), "ORDER-001"); err != nil {
+		t.Errorf("Cancel() error = %v", err)
+	}
+}
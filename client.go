@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 )
 
@@ -26,14 +27,34 @@ type Provider interface {
 
 // ProviderConfig holds the configuration for a provider
 type ProviderConfig struct {
-	Environment string
-	ServerKey   string
-	ClientKey   string
-	MerchantID  string
-	PrivateKey  string // RSA private key for asymmetric signature (Doku)
-	Timeout     int
-	SnapMode    bool
-	LogEnabled  bool
+	Environment            string
+	ServerKey              string
+	ClientKey              string
+	MerchantID             string
+	PrivateKey             string // RSA private key for asymmetric signature (Doku)
+	DisbursementKey        string // API key for disbursements (Midtrans Iris), defaults to ServerKey
+	DisbursementWebhookKey string // verifies disbursement notifications (Midtrans Iris merchant key)
+	Timeout                int
+	SnapMode               bool
+	LogEnabled             bool
+
+	// InstallmentTerms overrides the allowed card installment terms per bank code
+	InstallmentTerms map[string][]int
+
+	// AmountLimits overrides the provider amount limits per payment type
+	AmountLimits map[PaymentType]AmountLimit
+
+	// Transport is the HTTP transport, http.DefaultTransport when nil
+	Transport http.RoundTripper
+
+	// BaseURL replaces the provider API host when set
+	BaseURL string
+
+	// BillResolver looks up the bill of a virtual account inquiry
+	BillResolver BillResolver
+
+	// PaymentLedger records the payments into a virtual account
+	PaymentLedger PaymentLedger
 }
 
 var (
@@ -75,6 +96,15 @@ func NewClient(opts ...Option) (*Client, error) {
 	if envCfg.MerchantID != "" {
 		cfg.MerchantID = envCfg.MerchantID
 	}
+	if envCfg.DisbursementKey != "" {
+		cfg.DisbursementKey = envCfg.DisbursementKey
+	}
+	if envCfg.DisbursementWebhookKey != "" {
+		cfg.DisbursementWebhookKey = envCfg.DisbursementWebhookKey
+	}
+	if envCfg.BaseURL != "" {
+		cfg.BaseURL = envCfg.BaseURL
+	}
 
 	// Apply explicit options (overrides env vars)
 	cfg = ApplyOptions(cfg, opts...)
@@ -107,31 +137,67 @@ func createProvider(cfg *Config) (Provider, error) {
 	}
 
 	providerCfg := &ProviderConfig{
-		Environment: string(cfg.Environment),
-		ServerKey:   cfg.ServerKey,
-		ClientKey:   cfg.ClientKey,
-		MerchantID:  cfg.MerchantID,
-		PrivateKey:  cfg.PrivateKey,
-		Timeout:     int(cfg.Timeout.Seconds()),
-		SnapMode:    cfg.SnapMode,
-		LogEnabled:  cfg.LogEnabled,
+		Environment:            string(cfg.Environment),
+		ServerKey:              cfg.ServerKey,
+		ClientKey:              cfg.ClientKey,
+		MerchantID:             cfg.MerchantID,
+		PrivateKey:             cfg.PrivateKey,
+		DisbursementKey:        cfg.DisbursementKey,
+		DisbursementWebhookKey: cfg.DisbursementWebhookKey,
+		Timeout:                int(cfg.Timeout.Seconds()),
+		SnapMode:               cfg.SnapMode,
+		LogEnabled:             cfg.LogEnabled,
+
+		InstallmentTerms: cfg.InstallmentTerms,
+		AmountLimits:     cfg.AmountLimits,
+		Transport:        cfg.Transport,
+		BaseURL:          strings.TrimSuffix(cfg.BaseURL, "/"),
+		BillResolver:     cfg.BillResolver,
+		PaymentLedger:    cfg.PaymentLedger,
 	}
 
 	return factory(providerCfg)
 }
 
 // CreateCharge creates a new payment transaction
+// With EWALLETParams.RedirectToApp the PaymentURL is the app deeplink when the provider returns one
+// Payment types the provider reports as unsupported fail with an UnsupportedError
 func (c *Client) CreateCharge(ctx context.Context, params ChargeParams) (*ChargeResponse, error) {
-	return c.provider.CreateCharge(ctx, params)
+	if err := c.checkPaymentType(params.PaymentType); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.provider.CreateCharge(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp != nil && resp.Reference == "" {
+		resp.Reference = resp.OrderID
+	}
+
+	if resp != nil && params.PrefersApp() {
+		if url := PreferredURL(resp.NextActions, true); url != "" {
+			resp.PaymentURL = url
+		}
+	}
+
+	return resp, nil
 }
 
 // GetStatus retrieves the status of a payment transaction
+// orderID is the Reference of the ChargeResponse or WebhookEvent, which for most providers is the order ID
 func (c *Client) GetStatus(ctx context.Context, orderID string) (*PaymentStatus, error) {
 	return c.provider.GetStatus(ctx, orderID)
 }
 
 // Cancel cancels a payment transaction
+// orderID is the Reference of the ChargeResponse or WebhookEvent, as for GetStatus
+// Returns an UnsupportedError if the provider reports it cannot cancel
 func (c *Client) Cancel(ctx context.Context, orderID string) error {
+	if err := c.checkOperation(OperationCancel); err != nil {
+		return err
+	}
 	return c.provider.Cancel(ctx, orderID)
 }
 
@@ -143,13 +209,25 @@ func (c *Client) ParseWebhook(r *http.Request) (*WebhookEvent, error) {
 	}
 
 	// Parse webhook
-	return c.provider.ParseWebhook(r)
+	event, err := c.provider.ParseWebhook(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if event != nil && event.Reference == "" {
+		event.Reference = event.OrderID
+	}
+
+	return event, nil
 }
 
 // GetToken retrieves an OAuth access token from the provider
 // This is supported by Doku with asymmetric RSA signature
 // For Midtrans and Xendit, this returns an error as they use Basic Auth
 func (c *Client) GetToken(ctx context.Context) (*TokenResponse, error) {
+	if err := c.checkOperation(OperationToken); err != nil {
+		return nil, err
+	}
 	return c.provider.GetToken(ctx)
 }
 
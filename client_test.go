@@ -214,6 +214,10 @@ func TestClient_CreateCharge(t *testing.T) {
 		t.Errorf("TransactionID = %v, want txn-123", resp.TransactionID)
 	}
 
+	if resp.Reference != "ORDER-001" {
+		t.Errorf("Reference = %v, want the order ID", resp.Reference)
+	}
+
 	if !mock.webhookCalled {
 		t.Error("provider was not called")
 	}
@@ -346,6 +350,9 @@ func TestClient_ParseWebhook(t *testing.T) {
 				if event == nil && tt.webhookEvent != nil {
 					t.Error("expected event but got nil")
 				}
+				if event != nil && event.Reference != event.OrderID {
+					t.Errorf("Reference = %v, want the order ID", event.Reference)
+				}
 			}
 		})
 	}
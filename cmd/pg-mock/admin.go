@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+)
+
+// registerAdmin adds the admin API and UI
+func (s *server) registerAdmin() {
+	s.mux.HandleFunc("GET /admin", s.adminPage)
+	s.mux.HandleFunc("GET /admin/transactions", s.listTransactions)
+	s.mux.HandleFunc("POST /admin/transactions/{provider}/{id}/{action}", s.markTransaction)
+}
+
+func (s *server) listTransactions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.store.list())
+}
+
+// markTransaction marks a pending transaction paid, expired or failed and sends its webhook
+// The form of the admin UI is redirected back to the UI
+func (s *server) markTransaction(w http.ResponseWriter, r *http.Request) {
+	provider, id, action := r.PathValue("provider"), r.PathValue("id"), r.PathValue("action")
+
+	gw, ok := s.gateways[provider]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown provider " + provider})
+		return
+	}
+	status, ok := gw.transition(action)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "action must be paid, expired or failed"})
+		return
+	}
+
+	tx, webhook, err := s.setStatus(provider, id, status)
+	switch {
+	case errors.Is(err, errNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, errNotPending):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "transaction is already " + tx.Status})
+		return
+	}
+
+	if r.FormValue("ui") != "" {
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"transaction": tx, "webhook": webhook})
+}
+
+func (s *server) adminPage(w http.ResponseWriter, r *http.Request) {
+	pending := make(map[string]string, len(s.gateways))
+	for name, gw := range s.gateways {
+		pending[name] = gw.pending()
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	adminTemplate.Execute(w, struct {
+		Callback     string
+		Transactions []transaction
+		Pending      map[string]string
+	}{
+		Callback:     s.callback,
+		Transactions: s.store.list(),
+		Pending:      pending,
+	})
+}
+
+var adminTemplate = template.Must(template.New("admin").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>pg-mock</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: .4em; text-align: left; }
+form { display: inline; }
+:target { background: #fff7d6; }
+</style>
+</head>
+<body>
+<h1>pg-mock</h1>
+<p>Webhooks: {{if .Callback}}{{.Callback}}{{else}}not sent, start pg-mock with -callback{{end}}</p>
+<table>
+<tr><th>Provider</th><th>Order ID</th><th>Transaction ID</th><th>Payment</th><th>Amount</th><th>VA number</th><th>Status</th><th>Created</th><th></th></tr>
+{{range .Transactions}}
+<tr id="{{.Provider}}-{{.ID}}">
+<td>{{.Provider}}</td><td>{{.OrderID}}</td><td>{{.ID}}</td><td>{{.PaymentType}} {{.Channel}}</td><td>{{.Amount}}</td><td>{{.VANumber}}</td><td>{{.Status}}</td><td>{{.CreatedAt.Format "2006-01-02 15:04:05"}}</td>
+<td>{{if eq .Status (index $.Pending .Provider)}}
+<form method="post" action="/admin/transactions/{{.Provider}}/{{.ID}}/paid?ui=1"><button>Paid</button></form>
+<form method="post" action="/admin/transactions/{{.Provider}}/{{.ID}}/expired?ui=1"><button>Expired</button></form>
+<form method="post" action="/admin/transactions/{{.Provider}}/{{.ID}}/failed?ui=1"><button>Failed</button></form>
+{{end}}</td>
+</tr>
+{{else}}
+<tr><td colspan="9">No transactions yet</td></tr>
+{{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway/internal/webhook"
+)
+
+// dokuGateway emulates the Doku payment and transaction status APIs
+type dokuGateway struct {
+	s *server
+}
+
+// dokuCharge is the part of a generate payment request read by the mock
+type dokuCharge struct {
+	TransactionID string `json:"transaction_id"`
+	OrderAmount   int64  `json:"order_amount"`
+	PaymentType   string `json:"payment_type"`
+	PaymentDetail *struct {
+		VirtualAccount *struct {
+			VaType   string `json:"va_type"`
+			VANumber string `json:"virtual_account_number"`
+		} `json:"virtual_account"`
+		EWallet *struct {
+			Name string `json:"name"`
+		} `json:"e_wallet"`
+		Paylater *struct {
+			Name string `json:"name"`
+		} `json:"paylater"`
+	} `json:"payment_detail"`
+}
+
+func (g *dokuGateway) register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /payments/v2", g.auth(g.charge))
+	mux.HandleFunc("POST /transactions/v2", g.auth(g.status))
+}
+
+func (g *dokuGateway) pending() string { return "PENDING" }
+
+// transition maps expired to FAILED, the SDK knows no expired Doku status
+func (g *dokuGateway) transition(action string) (string, bool) {
+	status, ok := map[string]string{"paid": "SUCCESS", "expired": "FAILED", "failed": "FAILED"}[action]
+	return status, ok
+}
+
+// auth checks the Client-Id and the HMAC signature of the request
+// The body is restored for the handler
+func (g *dokuGateway) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			g.error(w, http.StatusBadRequest, "invalid_request", "cannot read the request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		want := webhook.DokuSignature(g.s.keys, r.Header.Get("Request-Id"), r.Header.Get("Request-Timestamp"), r.URL.Path, body)
+		if r.Header.Get("Client-Id") != g.s.keys.ClientKey || !hmac.Equal([]byte(r.Header.Get("Signature")), []byte(want)) {
+			g.error(w, http.StatusUnauthorized, "invalid_signature", "Invalid Header Signature")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (g *dokuGateway) charge(w http.ResponseWriter, r *http.Request) {
+	var req dokuCharge
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TransactionID == "" {
+		g.error(w, http.StatusBadRequest, "invalid_parameter", "transaction_id is required")
+		return
+	}
+	if req.OrderAmount <= 0 {
+		g.error(w, http.StatusBadRequest, "invalid_parameter", "order.amount must be greater than 0")
+		return
+	}
+
+	now := g.s.now()
+	tx := transaction{
+		Provider:    "doku",
+		ID:          req.TransactionID,
+		OrderID:     req.TransactionID,
+		PaymentType: req.PaymentType,
+		Amount:      req.OrderAmount,
+		Status:      g.pending(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if detail := req.PaymentDetail; detail != nil {
+		switch {
+		case detail.VirtualAccount != nil:
+			tx.Channel = detail.VirtualAccount.VaType
+			tx.VANumber = detail.VirtualAccount.VANumber
+		case detail.EWallet != nil:
+			tx.Channel = detail.EWallet.Name
+		case detail.Paylater != nil:
+			tx.Channel = detail.Paylater.Name
+		}
+	}
+	if tx.PaymentType == "VIRTUAL_ACCOUNT" && tx.VANumber == "" {
+		tx.VANumber = "19008" + randomDigits(11)
+	}
+
+	if err := g.s.store.add(tx); errors.Is(err, errDuplicateOrder) {
+		g.error(w, http.StatusConflict, "duplicate_transaction", "transaction_id "+tx.OrderID+" is already used")
+		return
+	}
+
+	resp := map[string]interface{}{
+		"response_code":    "00",
+		"response_message": "SUCCESS",
+		"transaction_id":   tx.ID,
+		"order_amount":     tx.Amount,
+	}
+	switch tx.PaymentType {
+	case "VIRTUAL_ACCOUNT":
+		resp["virtual_account_number"] = tx.VANumber
+		resp["va_bank"] = tx.Channel
+	case "QR_CODE":
+		resp["qr_string"] = "00020101021226670016ID.CO.DOKU.WWW" + tx.ID
+	default:
+		resp["payment_url"] = checkoutURL(r, "doku", tx.ID)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *dokuGateway) status(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TransactionID string `json:"transaction_id"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	tx, err := g.s.store.get("doku", req.TransactionID)
+	if err != nil {
+		g.error(w, http.StatusNotFound, "transaction_not_found", "transaction is not found")
+		return
+	}
+
+	resp := map[string]interface{}{
+		"response_code":      "00",
+		"response_message":   "SUCCESS",
+		"transaction_id":     tx.ID,
+		"order_amount":       tx.Amount,
+		"transaction_status": tx.Status,
+		"payment_type":       tx.PaymentType,
+	}
+	if tx.Status == "SUCCESS" {
+		resp["payment_date"] = tx.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *dokuGateway) error(w http.ResponseWriter, code int, errorCode, message string) {
+	writeJSON(w, code, map[string]interface{}{"error": map[string]string{"code": errorCode, "message": message}})
+}
@@ -0,0 +1,57 @@
+// Command pg-mock is a local payment gateway for development and QA
+//
+// It emulates the Midtrans, Xendit and Doku endpoints called by the SDK, each under its own
+// path prefix, and keeps the transactions in memory. Point a client at it with pg.WithBaseURL:
+//
+//	client, _ := pg.NewClient(
+//		pg.WithProvider("midtrans"),
+//		pg.WithServerKey("mock-server-key"),
+//		pg.WithBaseURL("http://localhost:8080/midtrans"),
+//	)
+//
+// Payments are marked paid, expired or failed from the admin UI at /admin or its API:
+//
+//	GET  /admin/transactions
+//	POST /admin/transactions/{provider}/{id}/{paid|expired|failed}
+//
+// Every status change is notified to the callback URL with a webhook signed like the provider does,
+// "{provider}" in the URL is replaced with the provider name
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+)
+
+const (
+	// defaultServerKey is the server key when neither -server-key nor PAYMENT_SERVER_KEY is set
+	defaultServerKey = "mock-server-key"
+	// defaultClientKey is the client key when neither -client-key nor PAYMENT_CLIENT_KEY is set
+	defaultClientKey = "mock-client-key"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	callback := flag.String("callback", "", "webhook URL, {provider} is replaced with the provider name")
+	serverKey := flag.String("server-key", envOr("PAYMENT_SERVER_KEY", defaultServerKey), "server key the clients authenticate with")
+	clientKey := flag.String("client-key", envOr("PAYMENT_CLIENT_KEY", defaultClientKey), "client key, the Xendit callback token and the Doku client ID")
+	flag.Parse()
+
+	srv := newServer(*serverKey, *clientKey, *callback)
+
+	log.Printf("pg-mock listening on %s, admin UI at /admin", *addr)
+	if *callback == "" {
+		log.Print("no -callback set, webhooks are not sent")
+	}
+	log.Fatal(http.ListenAndServe(*addr, srv))
+}
+
+// envOr returns the environment variable key, or fallback when it is empty
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
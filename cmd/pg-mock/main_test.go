@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	_ "github.com/pandudpn/go-payment-gateway/internal/provider/doku"
+	_ "github.com/pandudpn/go-payment-gateway/internal/provider/midtrans"
+	_ "github.com/pandudpn/go-payment-gateway/internal/provider/xendit"
+)
+
+// newMock starts pg-mock with webhooks sent to a server which parses them with clients
+func newMock(t *testing.T) (mock *httptest.Server, clients map[string]*pg.Client, events chan *pg.WebhookEvent) {
+	t.Helper()
+
+	clients = make(map[string]*pg.Client)
+	events = make(chan *pg.WebhookEvent, 1)
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, err := clients[strings.TrimPrefix(r.URL.Path, "/")].ParseWebhook(r)
+		if err != nil {
+			t.Errorf("ParseWebhook() error = %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		events <- event
+	}))
+	t.Cleanup(callback.Close)
+
+	mock = httptest.NewServer(newServer("mock-server-key", "mock-client-key", callback.URL+"/{provider}"))
+	t.Cleanup(mock.Close)
+
+	for _, provider := range []string{"midtrans", "xendit", "doku"} {
+		client, err := pg.NewClient(
+			pg.WithProvider(provider),
+			pg.WithServerKey("mock-server-key"),
+			pg.WithClientKey("mock-client-key"),
+			pg.WithBaseURL(mock.URL+"/"+provider),
+		)
+		if err != nil {
+			t.Fatalf("NewClient(%s) error = %v", provider, err)
+		}
+		clients[provider] = client
+	}
+	return mock, clients, events
+}
+
+// mark calls the admin API to mark the payment of orderID
+func mark(t *testing.T, mock *httptest.Server, provider, orderID, action string) *http.Response {
+	t.Helper()
+
+	resp, err := http.Post(mock.URL+"/admin/transactions/"+provider+"/"+orderID+"/"+action, "", nil)
+	if err != nil {
+		t.Fatalf("POST admin error = %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func chargeParams(orderID string, paymentType pg.PaymentType) pg.ChargeParams {
+	return pg.ChargeParams{
+		OrderID:     orderID,
+		Amount:      150000,
+		PaymentType: paymentType,
+		Customer:    pg.Customer{ID: "CUST-001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890"},
+		Items:       []pg.Item{{ID: "SKU-001", Name: "Kaos Polos", Price: 75000, Quantity: 2}},
+		CallbackURL: "https://example.com/callback",
+	}
+}
+
+func TestMock_PaymentFlow(t *testing.T) {
+	mock, clients, events := newMock(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		provider    string
+		paymentType pg.PaymentType
+		action      string
+		want        pg.Status
+	}{
+		{provider: "midtrans", paymentType: pg.PaymentTypeVABCA, action: "paid", want: pg.StatusSuccess},
+		{provider: "midtrans", paymentType: pg.PaymentTypeGoPay, action: "expired", want: pg.StatusExpired},
+		{provider: "xendit", paymentType: pg.PaymentTypeVABNI, action: "paid", want: pg.StatusSuccess},
+		{provider: "xendit", paymentType: pg.PaymentTypeOVO, action: "failed", want: pg.StatusFailed},
+		{provider: "doku", paymentType: pg.PaymentTypeVABCA, action: "paid", want: pg.StatusSuccess},
+		{provider: "doku", paymentType: pg.PaymentTypeQRIS, action: "failed", want: pg.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+"_"+string(tt.paymentType), func(t *testing.T) {
+			client := clients[tt.provider]
+			orderID := "ORDER-" + tt.provider + "-" + string(tt.paymentType)
+
+			charge, err := client.CreateCharge(ctx, chargeParams(orderID, tt.paymentType))
+			if err != nil {
+				t.Fatalf("CreateCharge() error = %v", err)
+			}
+			if charge.OrderID != orderID || charge.Amount != 150000 || charge.Status != pg.StatusPending {
+				t.Errorf("CreateCharge() = %+v, want a pending charge of %s", charge, orderID)
+			}
+			if tt.paymentType.IsVirtualAccount() && charge.VANumber == "" {
+				t.Error("VANumber is empty")
+			}
+
+			if resp := mark(t, mock, tt.provider, orderID, tt.action); resp.StatusCode != http.StatusOK {
+				t.Fatalf("admin %s status = %d, want 200", tt.action, resp.StatusCode)
+			}
+
+			select {
+			case event := <-events:
+				if event.OrderID != orderID || event.Status != tt.want || event.Amount != 150000 {
+					t.Errorf("webhook = %+v, want %s of %s", event, tt.want, orderID)
+				}
+			default:
+				t.Fatal("no webhook received")
+			}
+
+			status, err := client.GetStatus(ctx, orderID)
+			if err != nil {
+				t.Fatalf("GetStatus() error = %v", err)
+			}
+			if status.Status != tt.want {
+				t.Errorf("GetStatus() = %v, want %v", status.Status, tt.want)
+			}
+
+			if resp := mark(t, mock, tt.provider, orderID, "paid"); resp.StatusCode != http.StatusConflict {
+				t.Errorf("admin on a %s payment status = %d, want 409", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestMock_Cancel(t *testing.T) {
+	mock, clients, events := newMock(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		provider string
+		want     pg.Status
+	}{
+		{provider: "midtrans", want: pg.StatusCancelled},
+		{provider: "xendit", want: pg.StatusExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			client := clients[tt.provider]
+			orderID := "ORDER-CANCEL-" + tt.provider
+
+			if _, err := client.CreateCharge(ctx, chargeParams(orderID, pg.PaymentTypeVABCA)); err != nil {
+				t.Fatalf("CreateCharge() error = %v", err)
+			}
+			if err := client.Cancel(ctx, orderID); err != nil {
+				t.Fatalf("Cancel() error = %v", err)
+			}
+			if event := <-events; event.Status != tt.want {
+				t.Errorf("webhook status = %v, want %v", event.Status, tt.want)
+			}
+			if err := client.Cancel(ctx, orderID); err == nil {
+				t.Error("Cancel() of a cancelled payment error = nil")
+			}
+			if resp := mark(t, mock, tt.provider, orderID, "paid"); resp.StatusCode != http.StatusConflict {
+				t.Errorf("admin on a cancelled payment status = %d, want 409", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestMock_Errors(t *testing.T) {
+	mock, clients, _ := newMock(t)
+	ctx := context.Background()
+
+	for provider, client := range clients {
+		t.Run(provider, func(t *testing.T) {
+			orderID := "ORDER-DUP-" + provider
+			if _, err := client.CreateCharge(ctx, chargeParams(orderID, pg.PaymentTypeVABCA)); err != nil {
+				t.Fatalf("CreateCharge() error = %v", err)
+			}
+
+			var providerErr *pg.ProviderError
+			if _, err := client.CreateCharge(ctx, chargeParams(orderID, pg.PaymentTypeVABCA)); !errors.As(err, &providerErr) {
+				t.Errorf("duplicate CreateCharge() error = %v, want *pg.ProviderError", err)
+			}
+
+			wrongKey, err := pg.NewClient(
+				pg.WithProvider(provider),
+				pg.WithServerKey("wrong-key"),
+				pg.WithClientKey("mock-client-key"),
+				pg.WithBaseURL(mock.URL+"/"+provider),
+			)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			if _, err := wrongKey.CreateCharge(ctx, chargeParams("ORDER-AUTH-"+provider, pg.PaymentTypeVABCA)); !errors.As(err, &providerErr) {
+				t.Errorf("CreateCharge() with a wrong key error = %v, want *pg.ProviderError", err)
+			}
+
+			if _, err := client.GetStatus(ctx, "ORDER-UNKNOWN"); err == nil {
+				t.Error("GetStatus() of an unknown order error = nil")
+			}
+		})
+	}
+
+	if resp := mark(t, mock, "midtrans", "ORDER-DUP-midtrans", "refunded"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("admin refunded status = %d, want 400", resp.StatusCode)
+	}
+	if resp := mark(t, mock, "acme", "ORDER-001", "paid"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("admin of an unknown provider status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestMock_Admin(t *testing.T) {
+	mock, clients, _ := newMock(t)
+
+	if _, err := clients["midtrans"].CreateCharge(context.Background(), chargeParams("ORDER-ADMIN", pg.PaymentTypeVABCA)); err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+
+	resp, err := http.Get(mock.URL + "/admin/transactions")
+	if err != nil {
+		t.Fatalf("GET /admin/transactions error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var list []transaction
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if len(list) != 1 || list[0].OrderID != "ORDER-ADMIN" || list[0].Status != "pending" || list[0].VANumber == "" {
+		t.Errorf("transactions = %+v, want the pending ORDER-ADMIN", list)
+	}
+
+	page, err := http.Get(mock.URL + "/admin")
+	if err != nil {
+		t.Fatalf("GET /admin error = %v", err)
+	}
+	defer page.Body.Close()
+	if page.StatusCode != http.StatusOK || !strings.HasPrefix(page.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("GET /admin = %d %s, want an HTML page", page.StatusCode, page.Header.Get("Content-Type"))
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// midtransTimeLayout is the format of transaction_time, in WIB
+const midtransTimeLayout = "2006-01-02 15:04:05"
+
+// wib is the time zone of Midtrans transaction times
+var wib = time.FixedZone("WIB", 7*60*60)
+
+// midtransGateway emulates the Midtrans Core API
+type midtransGateway struct {
+	s *server
+}
+
+// midtransCharge is the part of a Core API charge read by the mock
+type midtransCharge struct {
+	PaymentType        string `json:"payment_type"`
+	TransactionDetails struct {
+		OrderID     string `json:"order_id"`
+		GrossAmount int64  `json:"gross_amount"`
+	} `json:"transaction_details"`
+	BankTransfer *struct {
+		Bank     string `json:"bank"`
+		VANumber string `json:"va_number"`
+	} `json:"bank_transfer"`
+}
+
+func (g *midtransGateway) register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v2/charge", g.auth(g.charge))
+	mux.HandleFunc("GET /v2/{id}/status", g.auth(g.status))
+	mux.HandleFunc("POST /v2/{id}/cancel", g.auth(g.cancel))
+}
+
+func (g *midtransGateway) pending() string { return "pending" }
+
+func (g *midtransGateway) transition(action string) (string, bool) {
+	status, ok := map[string]string{"paid": "settlement", "expired": "expire", "failed": "deny"}[action]
+	return status, ok
+}
+
+// auth checks the server key of the Basic authorization
+func (g *midtransGateway) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if key, _, ok := r.BasicAuth(); !ok || key != g.s.keys.ServerKey {
+			g.error(w, http.StatusUnauthorized, "Unknown Merchant server_key/id")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (g *midtransGateway) charge(w http.ResponseWriter, r *http.Request) {
+	var req midtransCharge
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TransactionDetails.OrderID == "" || req.TransactionDetails.GrossAmount <= 0 {
+		g.error(w, http.StatusBadRequest, "One or more parameters in the payload is invalid.")
+		return
+	}
+
+	now := g.s.now()
+	tx := transaction{
+		Provider:    "midtrans",
+		ID:          randomHex(16),
+		OrderID:     req.TransactionDetails.OrderID,
+		PaymentType: req.PaymentType,
+		Amount:      req.TransactionDetails.GrossAmount,
+		Status:      g.pending(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	switch req.PaymentType {
+	case "bank_transfer":
+		tx.Channel = "bca"
+		tx.VANumber = randomDigits(11)
+		if req.BankTransfer != nil {
+			if req.BankTransfer.Bank != "" {
+				tx.Channel = req.BankTransfer.Bank
+			}
+			if req.BankTransfer.VANumber != "" {
+				tx.VANumber = req.BankTransfer.VANumber
+			}
+		}
+	case "echannel":
+		tx.Channel = "mandiri"
+		tx.VANumber = randomDigits(12)
+	}
+
+	if err := g.s.store.add(tx); errors.Is(err, errDuplicateOrder) {
+		g.error(w, http.StatusNotAcceptable, "The request could not be processed due to duplicate order_id")
+		return
+	}
+
+	resp := g.response(tx, "201", "Success, transaction is created")
+	switch {
+	case tx.PaymentType == "bank_transfer" && tx.Channel == "permata":
+		resp["permata_va_number"] = tx.VANumber
+	case tx.PaymentType == "bank_transfer":
+		resp["va_numbers"] = []map[string]string{{"bank": tx.Channel, "va_number": tx.VANumber}}
+	case tx.PaymentType == "echannel":
+		resp["biller_code"] = "70012"
+		resp["bill_key"] = tx.VANumber
+	case tx.PaymentType == "qris" || tx.PaymentType == "gopay" || tx.PaymentType == "shopeepay":
+		checkout := checkoutURL(r, "midtrans", tx.ID)
+		resp["qr_string"] = "00020101021226620014COM.GO-JEK.WWW" + tx.ID
+		resp["actions"] = []map[string]string{
+			{"name": "generate-qr-code", "method": "GET", "url": checkout},
+			{"name": "deeplink-redirect", "method": "GET", "url": checkout},
+		}
+	default:
+		resp["redirect_url"] = checkoutURL(r, "midtrans", tx.ID)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *midtransGateway) status(w http.ResponseWriter, r *http.Request) {
+	tx, err := g.s.store.get("midtrans", r.PathValue("id"))
+	if err != nil {
+		g.error(w, http.StatusNotFound, "Transaction doesn't exist.")
+		return
+	}
+	writeJSON(w, http.StatusOK, g.response(tx, "200", "Success, transaction is found"))
+}
+
+func (g *midtransGateway) cancel(w http.ResponseWriter, r *http.Request) {
+	tx, err := g.s.store.setStatus("midtrans", r.PathValue("id"), g.pending(), "cancel", g.s.now())
+	switch {
+	case errors.Is(err, errNotFound):
+		g.error(w, http.StatusNotFound, "Transaction doesn't exist.")
+		return
+	case errors.Is(err, errNotPending):
+		g.error(w, http.StatusPreconditionFailed, "Merchant cannot modify the status of the transaction")
+		return
+	}
+
+	go g.s.notify(tx)
+	writeJSON(w, http.StatusOK, g.response(tx, "200", "Success, transaction is canceled"))
+}
+
+// response is the Core API representation of tx
+func (g *midtransGateway) response(tx transaction, code, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"status_code":        code,
+		"status_message":     message,
+		"transaction_id":     tx.ID,
+		"order_id":           tx.OrderID,
+		"gross_amount":       fmt.Sprintf("%d.00", tx.Amount),
+		"currency":           "IDR",
+		"payment_type":       tx.PaymentType,
+		"transaction_time":   tx.CreatedAt.In(wib).Format(midtransTimeLayout),
+		"transaction_status": tx.Status,
+		"fraud_status":       "accept",
+	}
+}
+
+// error writes a Core API error, Midtrans repeats the HTTP status in status_code
+func (g *midtransGateway) error(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"status_code": fmt.Sprint(code), "status_message": message})
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway/internal/webhook"
+)
+
+// gateway is the emulation of a provider API
+type gateway interface {
+	// register adds the provider endpoints to mux, without the provider prefix
+	register(mux *http.ServeMux)
+
+	// pending is the provider status of a new transaction
+	pending() string
+
+	// transition returns the provider status of an admin action: paid, expired or failed
+	transition(action string) (string, bool)
+}
+
+// server serves the emulated providers and the admin UI
+type server struct {
+	keys     webhook.Keys
+	callback string
+
+	store    *store
+	gateways map[string]gateway
+	httpCli  *http.Client
+	mux      *http.ServeMux
+	now      func() time.Time
+}
+
+func newServer(serverKey, clientKey, callback string) *server {
+	s := &server{
+		keys:     webhook.Keys{ServerKey: serverKey, ClientKey: clientKey},
+		callback: callback,
+		store:    newStore(),
+		httpCli:  &http.Client{Timeout: 10 * time.Second},
+		mux:      http.NewServeMux(),
+		now:      time.Now,
+	}
+
+	s.gateways = map[string]gateway{
+		"midtrans": &midtransGateway{s: s},
+		"xendit":   &xenditGateway{s: s},
+		"doku":     &dokuGateway{s: s},
+	}
+	for name, gw := range s.gateways {
+		mux := http.NewServeMux()
+		gw.register(mux)
+		s.mux.Handle("/"+name+"/", http.StripPrefix("/"+name, mux))
+	}
+
+	s.registerAdmin()
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// delivery is the outcome of a webhook
+type delivery struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// setStatus moves a pending transaction of provider to status and sends its webhook
+// The webhook is nil when no callback URL is configured
+func (s *server) setStatus(provider, id, status string) (transaction, *delivery, error) {
+	gw := s.gateways[provider]
+
+	tx, err := s.store.setStatus(provider, id, gw.pending(), status, s.now())
+	if err != nil {
+		return tx, nil, err
+	}
+	log.Printf("%s %s (order %s) is %s", provider, tx.ID, tx.OrderID, tx.Status)
+
+	return tx, s.notify(tx), nil
+}
+
+// notify sends the webhook of tx to the callback URL, signed like the provider does
+func (s *server) notify(tx transaction) *delivery {
+	if s.callback == "" {
+		return nil
+	}
+
+	url := strings.ReplaceAll(s.callback, "{provider}", tx.Provider)
+	d := &delivery{URL: url}
+
+	req, err := webhook.New(webhook.Notification{
+		Provider:      tx.Provider,
+		TransactionID: tx.ID,
+		OrderID:       tx.OrderID,
+		Status:        tx.Status,
+		Amount:        tx.Amount,
+		PaymentType:   tx.PaymentType,
+		Channel:       tx.Channel,
+		Time:          tx.UpdatedAt,
+	}, s.keys, url)
+	if err != nil {
+		d.Error = err.Error()
+		return d
+	}
+
+	resp, err := s.httpCli.Do(req)
+	if err != nil {
+		d.Error = err.Error()
+		log.Printf("webhook of %s %s failed: %v", tx.Provider, tx.ID, err)
+		return d
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	d.StatusCode = resp.StatusCode
+	log.Printf("webhook of %s %s answered %d", tx.Provider, tx.ID, resp.StatusCode)
+	return d
+}
+
+// writeJSON writes v as the JSON response with the HTTP status code
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// randomHex returns n random bytes in hex
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// randomDigits returns n random decimal digits, e.g. for a VA number
+func randomDigits(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	for i := range b {
+		b[i] = '0' + b[i]%10
+	}
+	return string(b)
+}
+
+// checkoutURL is the payment page of a transaction, the admin UI of the mock
+func checkoutURL(r *http.Request, provider, id string) string {
+	return fmt.Sprintf("http://%s/admin#%s-%s", r.Host, provider, id)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// errDuplicateOrder is returned when a provider already has a transaction for the order ID
+	errDuplicateOrder = errors.New("duplicate order ID")
+	// errNotFound is returned when no transaction has the ID
+	errNotFound = errors.New("transaction not found")
+	// errNotPending is returned when the status of a paid, expired or failed transaction is changed
+	errNotPending = errors.New("transaction is not pending")
+)
+
+// transaction is a payment created through one of the emulated providers
+// Status and PaymentType are in the format of the provider
+type transaction struct {
+	Provider    string    `json:"provider"`
+	ID          string    `json:"id"`
+	OrderID     string    `json:"order_id"`
+	PaymentType string    `json:"payment_type"`
+	Channel     string    `json:"channel,omitempty"`
+	Amount      int64     `json:"amount"`
+	Status      string    `json:"status"`
+	VANumber    string    `json:"va_number,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// store keeps the transactions in memory
+type store struct {
+	mu           sync.Mutex
+	transactions map[string]*transaction
+}
+
+func newStore() *store {
+	return &store{transactions: make(map[string]*transaction)}
+}
+
+// add saves tx, the order ID is unique per provider
+func (s *store) add(tx transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.find(tx.Provider, tx.OrderID); ok {
+		return errDuplicateOrder
+	}
+	s.transactions[tx.Provider+"/"+tx.ID] = &tx
+	return nil
+}
+
+// get returns the transaction of provider with the transaction ID or order ID id
+func (s *store) get(provider, id string) (transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.find(provider, id)
+	if !ok {
+		return transaction{}, errNotFound
+	}
+	return *tx, nil
+}
+
+// setStatus moves a pending transaction to status
+func (s *store) setStatus(provider, id, pending, status string, now time.Time) (transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.find(provider, id)
+	if !ok {
+		return transaction{}, errNotFound
+	}
+	if tx.Status != pending {
+		return *tx, errNotPending
+	}
+	tx.Status = status
+	tx.UpdatedAt = now
+	return *tx, nil
+}
+
+// list returns all the transactions, the newest first
+func (s *store) list() []transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]transaction, 0, len(s.transactions))
+	for _, tx := range s.transactions {
+		list = append(list, *tx)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].ID > list[j].ID
+		}
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list
+}
+
+// find looks a transaction up by transaction ID, then by order ID, s.mu must be held
+func (s *store) find(provider, id string) (*transaction, bool) {
+	if tx, ok := s.transactions[provider+"/"+id]; ok {
+		return tx, true
+	}
+	for _, tx := range s.transactions {
+		if tx.Provider == provider && tx.OrderID == id {
+			return tx, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// xenditGateway emulates the Xendit invoice, virtual account and e-wallet APIs
+// GetStatus of the SDK reads every transaction as an invoice
+type xenditGateway struct {
+	s *server
+}
+
+// xenditCharge is the part of an invoice, virtual account or e-wallet request read by the mock
+type xenditCharge struct {
+	ExternalID     string  `json:"external_id"`
+	Amount         float64 `json:"amount"`
+	ExpectedAmount float64 `json:"expected_amount"`
+	BankCode       string  `json:"bank_code"`
+	Name           string  `json:"name"`
+	VANumber       string  `json:"virtual_account_number"`
+	EWalletType    string  `json:"ewallet_type"`
+}
+
+func (g *xenditGateway) register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v2/invoices", g.auth(g.charge("INVOICE")))
+	mux.HandleFunc("POST /callback_virtual_accounts", g.auth(g.charge("VIRTUAL_ACCOUNT")))
+	mux.HandleFunc("POST /ewallets", g.auth(g.charge("EWALLET")))
+	mux.HandleFunc("GET /v2/invoices/{id}", g.auth(g.status))
+	mux.HandleFunc("PATCH /v2/invoices/{id}", g.auth(g.expire))
+}
+
+func (g *xenditGateway) pending() string { return "PENDING" }
+
+func (g *xenditGateway) transition(action string) (string, bool) {
+	status, ok := map[string]string{"paid": "PAID", "expired": "EXPIRED", "failed": "FAILED"}[action]
+	return status, ok
+}
+
+// auth checks the secret key of the Basic authorization
+func (g *xenditGateway) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if key, _, ok := r.BasicAuth(); !ok || key != g.s.keys.ServerKey {
+			g.error(w, http.StatusUnauthorized, "INVALID_API_KEY", "API key is invalid")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// charge creates a transaction of paymentType
+func (g *xenditGateway) charge(paymentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req xenditCharge
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ExternalID == "" {
+			g.error(w, http.StatusBadRequest, "API_VALIDATION_ERROR", "\"external_id\" is required")
+			return
+		}
+		amount := req.Amount
+		if paymentType == "VIRTUAL_ACCOUNT" {
+			amount = req.ExpectedAmount
+		}
+		if amount <= 0 {
+			g.error(w, http.StatusBadRequest, "API_VALIDATION_ERROR", "\"amount\" must be greater than 0")
+			return
+		}
+
+		now := g.s.now()
+		tx := transaction{
+			Provider:    "xendit",
+			ID:          randomHex(12),
+			OrderID:     req.ExternalID,
+			PaymentType: paymentType,
+			Channel:     req.BankCode + req.EWalletType,
+			Amount:      int64(amount),
+			Status:      g.pending(),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if paymentType == "VIRTUAL_ACCOUNT" {
+			tx.VANumber = req.VANumber
+			if tx.VANumber == "" {
+				tx.VANumber = "10766" + randomDigits(10)
+			}
+		}
+
+		if err := g.s.store.add(tx); errors.Is(err, errDuplicateOrder) {
+			g.error(w, http.StatusConflict, "DUPLICATE_ERROR", "external_id "+tx.OrderID+" already exists")
+			return
+		}
+
+		resp := map[string]interface{}{
+			"id":          tx.ID,
+			"external_id": tx.OrderID,
+			"status":      tx.Status,
+			"currency":    "IDR",
+			"created":     tx.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		checkout := checkoutURL(r, "xendit", tx.ID)
+		switch paymentType {
+		case "VIRTUAL_ACCOUNT":
+			resp["bank_code"] = tx.Channel
+			resp["merchant_code"] = "10766"
+			resp["name"] = req.Name
+			resp["account_number"] = tx.VANumber
+			resp["expected_amount"] = tx.Amount
+			resp["is_single_use"] = true
+			resp["is_closed"] = true
+		case "EWALLET":
+			resp["amount"] = tx.Amount
+			resp["ewallet_type"] = tx.Channel
+			actions := map[string]string{"desktop_web_checkout_url": checkout, "mobile_deeplink_checkout_url": checkout}
+			if tx.Channel == "QRIS" {
+				actions = map[string]string{"qr_checkout_string": "00020101021226660014ID.CO.QRIS.WWW" + tx.ID}
+			}
+			resp["actions"] = actions
+		default:
+			resp["amount"] = tx.Amount
+			resp["invoice_url"] = checkout
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (g *xenditGateway) status(w http.ResponseWriter, r *http.Request) {
+	tx, err := g.s.store.get("xendit", r.PathValue("id"))
+	if err != nil {
+		g.error(w, http.StatusNotFound, "INVOICE_NOT_FOUND_ERROR", "Could not find invoice by id")
+		return
+	}
+	writeJSON(w, http.StatusOK, g.invoice(r, tx))
+}
+
+// expire expires an invoice, the SDK cancels by moving expires_at to the past
+func (g *xenditGateway) expire(w http.ResponseWriter, r *http.Request) {
+	tx, err := g.s.store.setStatus("xendit", r.PathValue("id"), g.pending(), "EXPIRED", g.s.now())
+	switch {
+	case errors.Is(err, errNotFound):
+		g.error(w, http.StatusNotFound, "INVOICE_NOT_FOUND_ERROR", "Could not find invoice by id")
+		return
+	case errors.Is(err, errNotPending):
+		g.error(w, http.StatusBadRequest, "INVALID_INVOICE_STATUS", "Invoice is already "+tx.Status)
+		return
+	}
+
+	go g.s.notify(tx)
+	writeJSON(w, http.StatusOK, g.invoice(r, tx))
+}
+
+// invoice is the invoice representation of tx
+func (g *xenditGateway) invoice(r *http.Request, tx transaction) map[string]interface{} {
+	invoice := map[string]interface{}{
+		"id":          tx.ID,
+		"external_id": tx.OrderID,
+		"status":      tx.Status,
+		"amount":      tx.Amount,
+		"currency":    "IDR",
+		"invoice_url": checkoutURL(r, "xendit", tx.ID),
+		"created":     tx.CreatedAt.UTC().Format(time.RFC3339),
+		"updated":     tx.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if tx.Status == "PAID" {
+		invoice["paid_amount"] = tx.Amount
+		invoice["paid_at"] = tx.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return invoice
+}
+
+func (g *xenditGateway) error(w http.ResponseWriter, code int, errorCode, message string) {
+	writeJSON(w, code, map[string]string{"error_code": errorCode, "message": message})
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/webhook"
+)
+
+// requestLine matches the first line of a raw HTTP request
+var requestLine = regexp.MustCompile(`^[A-Z]+ \S+ HTTP/\d`)
+
+// headerFlags collects repeated -H "Name: value" flags
+type headerFlags http.Header
+
+func (h headerFlags) String() string { return "" }
+
+func (h headerFlags) Set(value string) error {
+	name, val, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("header %q is not Name: value", value)
+	}
+	http.Header(h).Add(strings.TrimSpace(name), strings.TrimSpace(val))
+	return nil
+}
+
+// parseFlags parses args with fs, flag errors are usage errors
+func (a *app) parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(a.stderr)
+	if err := fs.Parse(args); err != nil {
+		return &usageError{msg: err.Error()}
+	}
+	return nil
+}
+
+// orderArg returns the order ID, the only argument of status and cancel
+func orderArg(name string, args []string) (string, error) {
+	if len(args) != 1 || args[0] == "" {
+		return "", &usageError{msg: "usage: pgctl " + name + " <order-id>"}
+	}
+	return args[0], nil
+}
+
+func runCharge(a *app, args []string) error {
+	fs := flag.NewFlagSet("charge", flag.ContinueOnError)
+	file := fs.String("f", "", "JSON pg.ChargeParams file, - for stdin")
+	orderID := fs.String("order", "", "order ID")
+	amount := fs.Int64("amount", 0, "amount")
+	paymentType := fs.String("type", "", "payment type, e.g. VA_BCA, GOPAY or QRIS")
+	customerID := fs.String("customer-id", "", "customer ID")
+	name := fs.String("name", "", "customer name")
+	email := fs.String("email", "", "customer email")
+	phone := fs.String("phone", "", "customer phone")
+	description := fs.String("description", "", "description")
+	callbackURL := fs.String("callback", "", "callback URL")
+	returnURL := fs.String("return", "", "return URL")
+	preview := fs.Bool("preview", false, "print the provider request without sending it")
+	if err := a.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var params pg.ChargeParams
+	if *file != "" {
+		data, err := a.readFile(*file)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &params); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", *file, err)
+		}
+	}
+
+	// Flags override the file
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "order":
+			params.OrderID = *orderID
+		case "amount":
+			params.Amount = *amount
+		case "type":
+			params.PaymentType = pg.PaymentType(strings.ToUpper(*paymentType))
+		case "customer-id":
+			params.Customer.ID = *customerID
+		case "name":
+			params.Customer.Name = *name
+		case "email":
+			params.Customer.Email = *email
+		case "phone":
+			params.Customer.Phone = *phone
+		case "description":
+			params.Description = *description
+		case "callback":
+			params.CallbackURL = *callbackURL
+		case "return":
+			params.ReturnURL = *returnURL
+		}
+	})
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	if *preview {
+		req, err := client.PreviewCharge(context.Background(), params)
+		if err != nil {
+			return err
+		}
+		return a.print(req)
+	}
+
+	resp, err := client.CreateCharge(context.Background(), params)
+	if err != nil {
+		return err
+	}
+	return a.print(resp)
+}
+
+func runStatus(a *app, args []string) error {
+	orderID, err := orderArg("status", args)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	status, err := client.GetStatus(context.Background(), orderID)
+	if err != nil {
+		return err
+	}
+	return a.print(status)
+}
+
+func runCancel(a *app, args []string) error {
+	orderID, err := orderArg("cancel", args)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Cancel(context.Background(), orderID); err != nil {
+		return err
+	}
+	return a.print(map[string]interface{}{"order_id": orderID, "cancelled": true})
+}
+
+func runToken(a *app, args []string) error {
+	if len(args) != 0 {
+		return &usageError{msg: "usage: pgctl token"}
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	token, err := client.GetToken(context.Background())
+	if err != nil {
+		return err
+	}
+	return a.print(token)
+}
+
+// runVerifyWebhook verifies the signature of a webhook and prints the parsed event
+// The file is a raw HTTP request, or the body with the headers given by -H
+func runVerifyWebhook(a *app, args []string) error {
+	fs := flag.NewFlagSet("verify-webhook", flag.ContinueOnError)
+	header := headerFlags{}
+	fs.Var(header, "H", "request header \"Name: value\", repeatable")
+	if err := a.parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return &usageError{msg: "usage: pgctl verify-webhook [-H 'Name: value']... <file>"}
+	}
+
+	data, err := a.readFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	req, err := webhookRequest(data, http.Header(header))
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	event, err := client.ParseWebhook(req)
+	if err != nil {
+		return err
+	}
+	return a.print(map[string]interface{}{"valid": true, "event": event})
+}
+
+// webhookRequest rebuilds a webhook from a raw HTTP request, or from a body and its headers
+func webhookRequest(data []byte, header http.Header) (*http.Request, error) {
+	if requestLine.Match(data) {
+		br := bufio.NewReader(bytes.NewReader(data))
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the HTTP request: %w", err)
+		}
+
+		// A hand-written request often has no Content-Length, the body is the rest of the file
+		if req.ContentLength <= 0 && len(req.TransferEncoding) == 0 {
+			body, _ := io.ReadAll(br)
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			req.ContentLength = int64(len(body))
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		return req, nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/webhook", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	if req.Header.Get("Content-Type") == "" {
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			req.Header.Set("Content-Type", "application/json")
+		} else {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	return req, nil
+}
+
+// signedWebhook is the output of sign-webhook
+type signedWebhook struct {
+	Method   string           `json:"method"`
+	URL      string           `json:"url"`
+	Header   http.Header      `json:"header"`
+	Body     string           `json:"body"`
+	Response *webhookResponse `json:"response,omitempty"`
+}
+
+// webhookResponse is the answer of the webhook handler to sign-webhook -send
+type webhookResponse struct {
+	StatusCode int    `json:"status_code"`
+	Body       string `json:"body"`
+}
+
+// runSignWebhook builds a webhook signed with the configured keys, to test a webhook handler
+func runSignWebhook(a *app, args []string) error {
+	fs := flag.NewFlagSet("sign-webhook", flag.ContinueOnError)
+	orderID := fs.String("order", "", "order ID")
+	transactionID := fs.String("transaction-id", "", "transaction ID, the order ID when empty")
+	status := fs.String("status", string(pg.StatusSuccess), "unified status, e.g. SUCCESS, PENDING, FAILED or EXPIRED")
+	amount := fs.Int64("amount", 0, "amount")
+	paymentType := fs.String("payment-type", "", "payment type in the format of the provider")
+	url := fs.String("url", "http://localhost/webhook", "webhook URL")
+	send := fs.Bool("send", false, "POST the webhook to -url")
+	out := fs.String("o", "", "also write the raw HTTP request to this file, for verify-webhook")
+	if err := a.parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *orderID == "" || *amount <= 0 {
+		return &usageError{msg: "-order and -amount are required"}
+	}
+	if *transactionID == "" {
+		*transactionID = *orderID
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	cfg := client.GetConfig()
+	if !webhook.Supported(cfg.Provider) {
+		return fmt.Errorf("signing webhooks of %q is not supported, only midtrans, xendit and doku", cfg.Provider)
+	}
+	providerStatus, ok := webhook.Status(cfg.Provider, pg.Status(strings.ToUpper(*status)))
+	if !ok {
+		return fmt.Errorf("%s has no %s status", cfg.Provider, strings.ToUpper(*status))
+	}
+
+	req, err := webhook.New(webhook.Notification{
+		Provider:      cfg.Provider,
+		TransactionID: *transactionID,
+		OrderID:       *orderID,
+		Status:        providerStatus,
+		Amount:        *amount,
+		PaymentType:   *paymentType,
+		Time:          time.Now(),
+	}, webhook.Keys{ServerKey: cfg.ServerKey, ClientKey: cfg.ClientKey}, *url)
+	if err != nil {
+		return err
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	signed := signedWebhook{Method: req.Method, URL: req.URL.String(), Header: req.Header, Body: string(body)}
+
+	if *out != "" {
+		var raw bytes.Buffer
+		if err := req.Write(&raw); err != nil {
+			return err
+		}
+		if err := os.WriteFile(*out, raw.Bytes(), 0o600); err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if *send {
+		resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send the webhook: %w", err)
+		}
+		defer resp.Body.Close()
+
+		respBody, _ := io.ReadAll(resp.Body)
+		signed.Response = &webhookResponse{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	return a.print(signed)
+}
+
+// readFile reads name, or stdin when name is -
+func (a *app) readFile(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(a.stdin)
+	}
+	return os.ReadFile(name)
+}
@@ -0,0 +1,146 @@
+// Command pgctl inspects and fixes payments from a terminal
+//
+// The client is configured with the PAYMENT_* environment variables read by pg.LoadConfigFromEnv,
+// e.g. PAYMENT_PROVIDER, PAYMENT_SERVER_KEY, PAYMENT_CLIENT_KEY, PAYMENT_ENV and PAYMENT_BASE_URL.
+//
+// Usage:
+//
+//	pgctl charge [-f params.json] [-order ID] [-amount N] [-type VA_BCA] [-preview]
+//	pgctl status <order-id>
+//	pgctl cancel <order-id>
+//	pgctl token
+//	pgctl verify-webhook [-H 'Name: value']... <file>
+//	pgctl sign-webhook -order ID -status SUCCESS -amount N [-url URL] [-send] [-o file]
+//
+// Results are printed to stdout as JSON, errors to stderr as JSON with a non-zero exit status
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	_ "github.com/pandudpn/go-payment-gateway/internal/provider/doku"
+	_ "github.com/pandudpn/go-payment-gateway/internal/provider/duitku"
+	_ "github.com/pandudpn/go-payment-gateway/internal/provider/espay"
+	_ "github.com/pandudpn/go-payment-gateway/internal/provider/faspay"
+	_ "github.com/pandudpn/go-payment-gateway/internal/provider/midtrans"
+	_ "github.com/pandudpn/go-payment-gateway/internal/provider/xendit"
+)
+
+const (
+	// exitFailed is the exit status of a failed command
+	exitFailed = 1
+	// exitUsage is the exit status of invalid arguments
+	exitUsage = 2
+)
+
+const usage = `usage: pgctl <command> [flags]
+
+commands:
+  charge          create a charge from flags or a JSON pg.ChargeParams file
+  status          get the status of an order
+  cancel          cancel an order
+  token           get an access token
+  verify-webhook  verify and parse a webhook saved to a file
+  sign-webhook    build, and optionally send, a signed webhook
+
+The client is configured with the PAYMENT_* environment variables.
+`
+
+// command runs a subcommand with its arguments
+type command func(app *app, args []string) error
+
+var commands = map[string]command{
+	"charge":         runCharge,
+	"status":         runStatus,
+	"cancel":         runCancel,
+	"token":          runToken,
+	"verify-webhook": runVerifyWebhook,
+	"sign-webhook":   runSignWebhook,
+}
+
+// app is the environment of a command
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	// newClient creates the client, it is only called by the commands which need one
+	newClient func() (*pg.Client, error)
+}
+
+// usageError is an error in the command line
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+func main() {
+	a := &app{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		newClient: func() (*pg.Client, error) {
+			return pg.NewClient()
+		},
+	}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+// run runs the command of args and returns the exit status
+func (a *app) run(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		fmt.Fprint(a.stderr, usage)
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "pgctl: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	if err := cmd(a, args[1:]); err != nil {
+		var uerr *usageError
+		if errors.As(err, &uerr) {
+			fmt.Fprintf(a.stderr, "pgctl %s: %s\n", args[0], uerr.msg)
+			return exitUsage
+		}
+		a.printError(err)
+		return exitFailed
+	}
+	return 0
+}
+
+// print writes v to stdout as indented JSON
+func (a *app) print(v interface{}) error {
+	enc := json.NewEncoder(a.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printError writes err to stderr as JSON, with the provider or validation details when there are
+func (a *app) printError(err error) {
+	out := map[string]interface{}{"error": err.Error()}
+
+	var providerErr *pg.ProviderError
+	var validationErr *pg.ValidationError
+	var fieldErr *pg.FieldError
+	switch {
+	case errors.As(err, &providerErr):
+		out["provider_error"] = providerErr
+	case errors.As(err, &validationErr):
+		out["fields"] = validationErr.Errors
+	case errors.As(err, &fieldErr):
+		out["fields"] = []*pg.FieldError{fieldErr}
+	}
+
+	enc := json.NewEncoder(a.stderr)
+	enc.SetIndent("", "  ")
+	enc.Encode(out)
+}
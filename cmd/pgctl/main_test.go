@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+// runApp runs pgctl with args and the client configured from the environment
+func runApp(t *testing.T, stdin string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	a := &app{
+		stdin:  strings.NewReader(stdin),
+		stdout: &out,
+		stderr: &errOut,
+		newClient: func() (*pg.Client, error) {
+			return pg.NewClient()
+		},
+	}
+	code = a.run(args)
+	return code, out.String(), errOut.String()
+}
+
+// setEnv configures the client of pgctl for provider at baseURL
+func setEnv(t *testing.T, provider, baseURL string) {
+	t.Setenv(pg.EnvProvider, provider)
+	t.Setenv(pg.EnvServerKey, "server-key")
+	t.Setenv(pg.EnvClientKey, "client-key")
+	t.Setenv(pg.EnvBaseURL, baseURL)
+}
+
+// newMidtrans starts a Midtrans API answering charges, statuses and cancels of ORDER-001
+func newMidtrans(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+
+		if key, _, _ := r.BasicAuth(); key != "server-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"status_code":"401","status_message":"Unknown Merchant server_key/id"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/charge":
+			io.WriteString(w, `{"status_code":"201","transaction_id":"TRX-001","order_id":"ORDER-001","gross_amount":"150000.00","payment_type":"bank_transfer","transaction_time":"2024-03-01 10:00:00","transaction_status":"pending","va_numbers":[{"bank":"bca","va_number":"12345678901"}]}`)
+		case "/v2/ORDER-001/status":
+			io.WriteString(w, `{"status_code":"200","transaction_id":"TRX-001","order_id":"ORDER-001","gross_amount":"150000.00","payment_type":"bank_transfer","transaction_time":"2024-03-01 10:00:00","transaction_status":"settlement"}`)
+		case "/v2/ORDER-001/cancel":
+			io.WriteString(w, `{"status_code":"200","order_id":"ORDER-001","transaction_status":"cancel"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"status_code":"404","status_message":"Transaction doesn't exist."}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no command", args: nil},
+		{name: "unknown command", args: []string{"refund"}},
+		{name: "status without order", args: []string{"status"}},
+		{name: "unknown flag", args: []string{"charge", "-currency", "USD"}},
+		{name: "sign without amount", args: []string{"sign-webhook", "-order", "ORDER-001"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, stderr := runApp(t, "", tt.args...); code != exitUsage || stderr == "" {
+				t.Errorf("run() = %d, stderr %q, want %d with a message", code, stderr, exitUsage)
+			}
+		})
+	}
+}
+
+func TestRun_Charge(t *testing.T) {
+	srv, requests := newMidtrans(t)
+	setEnv(t, "midtrans", srv.URL)
+
+	params := `{"order_id":"ORDER-001","amount":150000,"payment_type":"VA_BNI","customer":{"id":"CUST-001","name":"Budi Santoso","email":"budi@example.com","phone":"081234567890"},"items":[{"id":"SKU-001","name":"Kaos Polos","price":75000,"quantity":2}],"callback_url":"https://example.com/callback"}`
+
+	code, stdout, stderr := runApp(t, params, "charge", "-f", "-", "-type", "va_bca")
+	if code != 0 {
+		t.Fatalf("run() = %d, stderr %s", code, stderr)
+	}
+
+	var resp pg.ChargeResponse
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if resp.OrderID != "ORDER-001" || resp.VANumber != "12345678901" || resp.Status != pg.StatusPending {
+		t.Errorf("charge = %+v", resp)
+	}
+	if len(*requests) != 1 || !strings.Contains((*requests)[0], `"bank":"bca"`) {
+		t.Errorf("requests = %v, want one charge of VA BCA, -type overrides the file", *requests)
+	}
+
+	// -preview builds the request without sending it
+	code, stdout, stderr = runApp(t, params, "charge", "-f", "-", "-preview")
+	if code != 0 {
+		t.Fatalf("run(-preview) = %d, stderr %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "/v2/charge") || len(*requests) != 1 {
+		t.Errorf("preview = %s, requests = %d, want the charge request unsent", stdout, len(*requests))
+	}
+}
+
+func TestRun_StatusAndCancel(t *testing.T) {
+	srv, requests := newMidtrans(t)
+	setEnv(t, "midtrans", srv.URL)
+
+	code, stdout, stderr := runApp(t, "", "status", "ORDER-001")
+	if code != 0 {
+		t.Fatalf("run(status) = %d, stderr %s", code, stderr)
+	}
+	var status pg.PaymentStatus
+	if err := json.Unmarshal([]byte(stdout), &status); err != nil || status.Status != pg.StatusSuccess {
+		t.Errorf("status = %s, want %s", stdout, pg.StatusSuccess)
+	}
+
+	code, stdout, stderr = runApp(t, "", "cancel", "ORDER-001")
+	if code != 0 {
+		t.Fatalf("run(cancel) = %d, stderr %s", code, stderr)
+	}
+	if !strings.Contains(stdout, `"cancelled": true`) {
+		t.Errorf("cancel = %s", stdout)
+	}
+	if got := (*requests)[len(*requests)-1]; !strings.HasPrefix(got, "POST /v2/ORDER-001/cancel") {
+		t.Errorf("last request = %s, want the cancel", got)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	srv, _ := newMidtrans(t)
+	setEnv(t, "midtrans", srv.URL)
+
+	code, stdout, stderr := runApp(t, "", "status", "ORDER-404")
+	if code != exitFailed || stdout != "" {
+		t.Fatalf("run() = %d, stdout %q, want %d and no output", code, stdout, exitFailed)
+	}
+	var out struct {
+		Error         string            `json:"error"`
+		ProviderError *pg.ProviderError `json:"provider_error"`
+	}
+	if err := json.Unmarshal([]byte(stderr), &out); err != nil {
+		t.Fatalf("stderr is not JSON: %v\n%s", err, stderr)
+	}
+	if out.ProviderError == nil || out.ProviderError.Code != "404" || out.ProviderError.Provider != "midtrans" {
+		t.Errorf("stderr = %s, want the provider error", stderr)
+	}
+
+	// Validation errors list the fields
+	code, _, stderr = runApp(t, "", "charge", "-order", "ORDER-002")
+	if code != exitFailed || !strings.Contains(stderr, `"fields"`) {
+		t.Errorf("run(charge) = %d, stderr %s, want the invalid fields", code, stderr)
+	}
+
+	// Midtrans has no token API
+	if code, _, _ := runApp(t, "", "token"); code != exitFailed {
+		t.Errorf("run(token) = %d, want %d", code, exitFailed)
+	}
+}
+
+func TestRun_SignAndVerifyWebhook(t *testing.T) {
+	for _, provider := range []string{"midtrans", "xendit", "doku"} {
+		t.Run(provider, func(t *testing.T) {
+			setEnv(t, provider, "")
+			file := filepath.Join(t.TempDir(), "webhook.http")
+
+			code, stdout, stderr := runApp(t, "", "sign-webhook", "-order", "ORDER-001", "-amount", "150000", "-status", "success", "-o", file)
+			if code != 0 {
+				t.Fatalf("run(sign-webhook) = %d, stderr %s", code, stderr)
+			}
+			var signed signedWebhook
+			if err := json.Unmarshal([]byte(stdout), &signed); err != nil || signed.Body == "" {
+				t.Fatalf("sign-webhook = %s", stdout)
+			}
+
+			code, stdout, stderr = runApp(t, "", "verify-webhook", file)
+			if code != 0 {
+				t.Fatalf("run(verify-webhook) = %d, stderr %s", code, stderr)
+			}
+			var verified struct {
+				Valid bool            `json:"valid"`
+				Event pg.WebhookEvent `json:"event"`
+			}
+			json.Unmarshal([]byte(stdout), &verified)
+			if !verified.Valid || verified.Event.OrderID != "ORDER-001" || verified.Event.Status != pg.StatusSuccess || verified.Event.Amount != 150000 {
+				t.Errorf("verify-webhook = %s", stdout)
+			}
+
+			// The body alone with the headers as flags
+			args := []string{"verify-webhook"}
+			for name, values := range signed.Header {
+				args = append(args, "-H", name+": "+values[0])
+			}
+			if code, _, stderr := runApp(t, signed.Body, append(args, "-")...); code != 0 {
+				t.Errorf("run(verify-webhook -H) = %d, stderr %s", code, stderr)
+			}
+
+			// Another key rejects the signature
+			t.Setenv(pg.EnvServerKey, "other-key")
+			t.Setenv(pg.EnvClientKey, "other-token")
+			if code, _, stderr := runApp(t, "", "verify-webhook", file); code != exitFailed || !strings.Contains(stderr, "signature") {
+				t.Errorf("run(verify-webhook) with another key = %d, stderr %s", code, stderr)
+			}
+		})
+	}
+}
+
+func TestRun_SignWebhookSend(t *testing.T) {
+	var got *http.Request
+	handler := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, "ok")
+	}))
+	defer handler.Close()
+	setEnv(t, "xendit", "")
+
+	code, stdout, stderr := runApp(t, "", "sign-webhook", "-order", "ORDER-001", "-amount", "150000", "-status", "expired", "-url", handler.URL+"/webhooks/xendit", "-send")
+	if code != 0 {
+		t.Fatalf("run() = %d, stderr %s", code, stderr)
+	}
+	if got == nil || got.URL.Path != "/webhooks/xendit" || got.Header.Get("X-Callback-Token") != "client-key" {
+		t.Errorf("webhook request = %+v", got)
+	}
+	if !strings.Contains(stdout, `"status_code": 202`) {
+		t.Errorf("sign-webhook = %s, want the handler response", stdout)
+	}
+
+	// Doku has no expired status
+	setEnv(t, "doku", "")
+	if code, _, _ := runApp(t, "", "sign-webhook", "-order", "ORDER-001", "-amount", "150000", "-status", "expired"); code != exitFailed {
+		t.Errorf("run() = %d, want %d for a status the provider does not have", code, exitFailed)
+	}
+}
+
+func TestMain(m *testing.M) {
+	// The tests configure the client with the environment, which must not come from the shell
+	for _, key := range []string{pg.EnvProvider, pg.EnvEnv, pg.EnvServerKey, pg.EnvClientKey, pg.EnvBaseURL, pg.EnvMerchantID, pg.EnvPrivateKey} {
+		os.Unsetenv(key)
+	}
+	os.Exit(m.Run())
+}
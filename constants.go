@@ -49,6 +49,21 @@ const (
 	PaymentTypeIndomaret  PaymentType = "INDOMARET"
 )
 
+// Paylater Payment Types
+const (
+	PaymentTypeKredivo  PaymentType = "KREDIVO"
+	PaymentTypeAkulaku  PaymentType = "AKULAKU"
+	PaymentTypeAtome    PaymentType = "ATOME"
+	PaymentTypeIndodana PaymentType = "INDODANA"
+)
+
+// Direct Debit Payment Types
+const (
+	PaymentTypeDDBRI        PaymentType = "DD_BRI"
+	PaymentTypeDDMandiri    PaymentType = "DD_MANDIRI"
+	PaymentTypeDDBCAOneKlik PaymentType = "DD_BCA_ONEKLIK"
+)
+
 // Status represents the status of a transaction
 type Status string
 
@@ -68,6 +83,7 @@ const (
 	EventPaymentPending   = "payment.pending"
 	EventPaymentExpired   = "payment.expired"
 	EventPaymentCancelled = "payment.cancelled"
+	EventPaymentInquiry   = "payment.inquiry"
 )
 
 // Provider names
@@ -86,11 +102,13 @@ const (
 
 // Default minimum amounts (in Rupiah)
 const (
-	MinAmountEWallet = 10000
-	MinAmountVA      = 10000
-	MinAmountQRIS    = 1000
-	MinAmountCC      = 10000
-	MinAmountRetail  = 10000
+	MinAmountEWallet     = 10000
+	MinAmountVA          = 10000
+	MinAmountQRIS        = 1000
+	MinAmountCC          = 10000
+	MinAmountRetail      = 10000
+	MinAmountPaylater    = 10000
+	MinAmountDirectDebit = 10000
 )
 
 // Default expiry times
@@ -136,6 +154,30 @@ func (p PaymentType) IsRetail() bool {
 	return false
 }
 
+// IsPaylater checks if the payment type is a paylater channel
+func (p PaymentType) IsPaylater() bool {
+	switch p {
+	case PaymentTypeKredivo, PaymentTypeAkulaku, PaymentTypeAtome, PaymentTypeIndodana:
+		return true
+	}
+	return false
+}
+
+// IsDirectDebit checks if the payment type is a direct bank debit
+func (p PaymentType) IsDirectDebit() bool {
+	switch p {
+	case PaymentTypeDDBRI, PaymentTypeDDMandiri, PaymentTypeDDBCAOneKlik:
+		return true
+	}
+	return false
+}
+
+// IsValid checks if the payment type is a known payment type
+func (p PaymentType) IsValid() bool {
+	return p.IsEWallet() || p.IsVirtualAccount() || p.IsQRIS() || p.IsCreditCard() ||
+		p.IsRetail() || p.IsPaylater() || p.IsDirectDebit()
+}
+
 // String returns the string representation of the payment type
 func (p PaymentType) String() string {
 	return string(p)
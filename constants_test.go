@@ -121,6 +121,51 @@ func TestPaymentType_IsRetail(t *testing.T) {
 	}
 }
 
+func TestPaymentType_IsPaylater(t *testing.T) {
+	tests := []struct {
+		name     string
+		payment  PaymentType
+		expected bool
+	}{
+		{"Kredivo", PaymentTypeKredivo, true},
+		{"Akulaku", PaymentTypeAkulaku, true},
+		{"Atome", PaymentTypeAtome, true},
+		{"Indodana", PaymentTypeIndodana, true},
+		{"GoPay", PaymentTypeGoPay, false},
+		{"Credit Card", PaymentTypeCC, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.payment.IsPaylater(); got != tt.expected {
+				t.Errorf("IsPaylater() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPaymentType_IsDirectDebit(t *testing.T) {
+	tests := []struct {
+		name     string
+		payment  PaymentType
+		expected bool
+	}{
+		{"BRI", PaymentTypeDDBRI, true},
+		{"Mandiri", PaymentTypeDDMandiri, true},
+		{"BCA OneKlik", PaymentTypeDDBCAOneKlik, true},
+		{"VA BRI", PaymentTypeVABRI, false},
+		{"GoPay", PaymentTypeGoPay, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.payment.IsDirectDebit(); got != tt.expected {
+				t.Errorf("IsDirectDebit() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestPaymentType_String(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,101 @@
+package pg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MergeCustom deep-merges custom into the JSON object body and returns the merged JSON
+//
+// Nested objects are merged key by key, any other value (including arrays) replaces the
+// value in body, and a nil value removes the key. Keys listed in options are provider
+// options read by the SDK itself and are not merged.
+func MergeCustom(body []byte, custom map[string]interface{}, options ...string) ([]byte, error) {
+	patch := customPatch(custom, options)
+	if len(patch) == 0 {
+		return body, nil
+	}
+
+	var target map[string]interface{}
+	if err := decodeJSON(body, &target); err != nil {
+		return nil, fmt.Errorf("failed to decode request body: %w", err)
+	}
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+
+	// Normalize the patch so structs and typed maps merge like plain JSON objects
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode custom fields: %w", err)
+	}
+	var normalized map[string]interface{}
+	if err := decodeJSON(raw, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to decode custom fields: %w", err)
+	}
+
+	mergeObject(target, normalized)
+	return json.Marshal(target)
+}
+
+// WithCustom wraps a provider request so it marshals with custom deep-merged in, see MergeCustom
+// Returns body itself when custom has nothing to merge
+func WithCustom(body interface{}, custom map[string]interface{}, options ...string) interface{} {
+	if len(customPatch(custom, options)) == 0 {
+		return body
+	}
+	return &customBody{body: body, custom: custom, options: options}
+}
+
+// customBody is a request body with custom fields merged on marshal
+type customBody struct {
+	body    interface{}
+	custom  map[string]interface{}
+	options []string
+}
+
+// MarshalJSON implements json.Marshaler
+func (c *customBody) MarshalJSON() ([]byte, error) {
+	body, err := json.Marshal(c.body)
+	if err != nil {
+		return nil, err
+	}
+	return MergeCustom(body, c.custom, c.options...)
+}
+
+// customPatch returns custom without the provider option keys
+func customPatch(custom map[string]interface{}, options []string) map[string]interface{} {
+	patch := make(map[string]interface{}, len(custom))
+	for k, v := range custom {
+		patch[k] = v
+	}
+	for _, option := range options {
+		delete(patch, option)
+	}
+	return patch
+}
+
+// mergeObject merges patch into target recursively
+func mergeObject(target, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		if patchObj, ok := v.(map[string]interface{}); ok {
+			if targetObj, ok := target[k].(map[string]interface{}); ok {
+				mergeObject(targetObj, patchObj)
+				continue
+			}
+		}
+		target[k] = v
+	}
+}
+
+// decodeJSON decodes data keeping numbers as json.Number so amounts are not rounded through float64
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -0,0 +1,80 @@
+package pg
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMergeCustom(t *testing.T) {
+	body := []byte(`{"order_id":"ORDER-001","amount":9007199254740993,"customer":{"name":"John","email":"john@example.com"},"tags":["a","b"],"description":"Order"}`)
+
+	merged, err := MergeCustom(body, map[string]interface{}{
+		"customer":    map[string]interface{}{"email": "jane@example.com", "locale": "id"},
+		"tags":        []string{"c"},
+		"description": nil,
+		"va_number":   "123456",
+		"expiry": struct {
+			Unit string `json:"unit"`
+		}{Unit: "minute"},
+	}, "va_number")
+	if err != nil {
+		t.Fatalf("MergeCustom() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := decodeJSON(merged, &got); err != nil {
+		t.Fatalf("failed to decode merged body: %v", err)
+	}
+
+	if got["amount"] != json.Number("9007199254740993") {
+		t.Errorf("amount = %v, want it unchanged", got["amount"])
+	}
+	customer := got["customer"].(map[string]interface{})
+	if customer["name"] != "John" || customer["email"] != "jane@example.com" || customer["locale"] != "id" {
+		t.Errorf("customer = %v, want name kept and email, locale merged", customer)
+	}
+	if tags := got["tags"].([]interface{}); len(tags) != 1 || tags[0] != "c" {
+		t.Errorf("tags = %v, want arrays replaced", tags)
+	}
+	if _, ok := got["description"]; ok {
+		t.Error("nil value should remove description")
+	}
+	if _, ok := got["va_number"]; ok {
+		t.Error("provider option va_number should not be merged")
+	}
+	if expiry := got["expiry"].(map[string]interface{}); expiry["unit"] != "minute" {
+		t.Errorf("expiry = %v, want struct encoded as JSON", expiry)
+	}
+}
+
+func TestMergeCustom_NothingToMerge(t *testing.T) {
+	body := []byte(`{"order_id":"ORDER-001"}`)
+
+	merged, err := MergeCustom(body, map[string]interface{}{"va_number": "123456"}, "va_number")
+	if err != nil {
+		t.Fatalf("MergeCustom() error = %v", err)
+	}
+	if string(merged) != string(body) {
+		t.Errorf("MergeCustom() = %s, want body unchanged", merged)
+	}
+}
+
+func TestWithCustom(t *testing.T) {
+	type request struct {
+		OrderID string `json:"order_id"`
+	}
+	req := &request{OrderID: "ORDER-001"}
+
+	if got := WithCustom(req, nil); got != req {
+		t.Errorf("WithCustom() = %v, want the request itself", got)
+	}
+
+	b, err := json.Marshal(WithCustom(req, map[string]interface{}{"channel_properties": map[string]string{"mobile_number": "+6281234567890"}}))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"channel_properties":{"mobile_number":"+6281234567890"},"order_id":"ORDER-001"}`
+	if string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,77 @@
+package pg
+
+import (
+	"context"
+)
+
+// DirectDebiter is implemented by providers that support direct bank debit
+// Bank accounts are bound with Client.LinkAccount and debited with Client.CreateCharge
+type DirectDebiter interface {
+	// ConfirmLink finishes binding a bank account
+	// code is the OTP sent to the customer, or the authorization code from the bank redirect
+	ConfirmLink(ctx context.Context, id, code string) (*LinkedAccount, error)
+
+	// ValidateChargeOTP submits the OTP of a direct debit charge
+	ValidateChargeOTP(ctx context.Context, params ChargeOTPParams) (*ChargeResponse, error)
+}
+
+// ChargeOTPParams represents the OTP of a direct debit charge
+type ChargeOTPParams struct {
+	// TransactionID is the ChargeResponse.TransactionID of the charge (required)
+	TransactionID string `json:"transaction_id"`
+
+	// OTP is the code sent to the customer (required)
+	OTP string `json:"otp"`
+
+	// OrderID is the order ID of the charge, required by Doku
+	OrderID string `json:"order_id,omitempty"`
+
+	// PaymentType is the direct debit payment type of the charge, required by Doku
+	PaymentType PaymentType `json:"payment_type,omitempty"`
+
+	// AccountToken is the DirectDebitParams.AccountToken the charge was made with, required by Doku
+	AccountToken string `json:"-"`
+}
+
+// directDebiter returns the provider as a DirectDebiter, or ErrUnimplemented
+func (c *Client) directDebiter() (DirectDebiter, error) {
+	d, ok := c.provider.(DirectDebiter)
+	if !ok {
+		return nil, ErrUnimplemented
+	}
+	return d, nil
+}
+
+// ConfirmLink finishes binding a direct debit bank account started with LinkAccount
+// Returns ErrUnimplemented if the provider does not support direct debit
+func (c *Client) ConfirmLink(ctx context.Context, id, code string) (*LinkedAccount, error) {
+	d, err := c.directDebiter()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+
+	return d.ConfirmLink(ctx, id, code)
+}
+
+// ValidateChargeOTP completes a direct debit charge which returned an ActionValidateOTP next action
+// Nothing is kept between the charge and its OTP, so any client can validate it
+// Returns ErrUnimplemented if the provider does not support direct debit
+func (c *Client) ValidateChargeOTP(ctx context.Context, params ChargeOTPParams) (*ChargeResponse, error) {
+	d, err := c.directDebiter()
+	if err != nil {
+		return nil, err
+	}
+
+	if params.TransactionID == "" {
+		return nil, NewRequiredFieldError("TransactionID")
+	}
+	if params.OTP == "" {
+		return nil, NewRequiredFieldError("OTP")
+	}
+
+	return d.ValidateChargeOTP(ctx, params)
+}
@@ -0,0 +1,67 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// mockDirectDebiter is a mock provider which also implements DirectDebiter
+type mockDirectDebiter struct {
+	mockProvider
+	calls int
+}
+
+func (m *mockDirectDebiter) ConfirmLink(ctx context.Context, id, code string) (*LinkedAccount, error) {
+	m.calls++
+	return &LinkedAccount{ID: "pm-123", PaymentType: PaymentTypeDDBRI, Status: LinkActive}, nil
+}
+
+func (m *mockDirectDebiter) ValidateChargeOTP(ctx context.Context, params ChargeOTPParams) (*ChargeResponse, error) {
+	m.calls++
+	return &ChargeResponse{TransactionID: params.TransactionID, Status: StatusSuccess}, nil
+}
+
+func TestClient_DirectDebit_Unimplemented(t *testing.T) {
+	client := &Client{provider: &mockProvider{name: "mock"}, config: &Config{}}
+	ctx := context.Background()
+
+	if _, err := client.ConfirmLink(ctx, "lat-123", "123456"); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("ConfirmLink() error = %v, want %v", err, ErrUnimplemented)
+	}
+	if _, err := client.ValidateChargeOTP(ctx, ChargeOTPParams{TransactionID: "dd-123", OTP: "123456"}); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("ValidateChargeOTP() error = %v, want %v", err, ErrUnimplemented)
+	}
+}
+
+func TestClient_DirectDebit(t *testing.T) {
+	mock := &mockDirectDebiter{}
+	client := &Client{provider: mock, config: &Config{}}
+	ctx := context.Background()
+
+	if _, err := client.ConfirmLink(ctx, "", "123456"); err == nil {
+		t.Error("ConfirmLink() expected error for empty ID")
+	}
+	if _, err := client.ValidateChargeOTP(ctx, ChargeOTPParams{TransactionID: "dd-123"}); err == nil {
+		t.Error("ValidateChargeOTP() expected error for empty OTP")
+	}
+	if mock.calls != 0 {
+		t.Errorf("provider called %d times for invalid input, want 0", mock.calls)
+	}
+
+	account, err := client.ConfirmLink(ctx, "lat-123", "123456")
+	if err != nil {
+		t.Fatalf("ConfirmLink() error = %v", err)
+	}
+	if account.Status != LinkActive {
+		t.Errorf("Status = %v, want %v", account.Status, LinkActive)
+	}
+
+	resp, err := client.ValidateChargeOTP(ctx, ChargeOTPParams{TransactionID: "dd-123", OTP: "123456"})
+	if err != nil {
+		t.Fatalf("ValidateChargeOTP() error = %v", err)
+	}
+	if resp.Status != StatusSuccess {
+		t.Errorf("Status = %v, want %v", resp.Status, StatusSuccess)
+	}
+}
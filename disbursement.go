@@ -0,0 +1,275 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Event Type for disbursement webhooks
+const (
+	EventDisbursementCompleted = "disbursement.completed"
+	EventDisbursementFailed    = "disbursement.failed"
+	EventDisbursementPending   = "disbursement.pending"
+	EventDisbursementCancelled = "disbursement.cancelled"
+)
+
+// Disburser is implemented by providers that support sending money to bank accounts
+// Operations a provider does not offer return ErrUnimplemented
+type Disburser interface {
+	CreateDisbursement(ctx context.Context, params DisbursementParams) (*Disbursement, error)
+	GetDisbursement(ctx context.Context, id string) (*Disbursement, error)
+	CreateBatchDisbursement(ctx context.Context, params BatchDisbursementParams) (*BatchDisbursement, error)
+	VerifyDisbursementWebhook(r *http.Request) bool
+	ParseDisbursementWebhook(r *http.Request) (*DisbursementEvent, error)
+}
+
+// DisbursementParams represents the parameters for sending money to a bank account
+type DisbursementParams struct {
+	// ReferenceID is the merchant's unique reference for the disbursement (required)
+	ReferenceID string `json:"reference_id"`
+
+	// BankCode is the destination bank code, e.g. BCA, MANDIRI, BNI (required)
+	// Unified codes, BI codes and aliases of the bank registry are mapped to each provider's code
+	BankCode string `json:"bank_code"`
+
+	// AccountNumber is the destination account number (required)
+	AccountNumber string `json:"account_number"`
+
+	// AccountHolder is the name of the destination account holder (required)
+	AccountHolder string `json:"account_holder"`
+
+	// Amount is the amount to send in smallest currency unit (required)
+	Amount int64 `json:"amount"`
+
+	// Description is the disbursement description
+	Description string `json:"description,omitempty"`
+
+	// Email is the email address notified about the disbursement (optional)
+	Email string `json:"email,omitempty"`
+
+	// Custom contains provider-specific parameters that are not mapped to unified fields
+	Custom map[string]interface{} `json:"-"`
+}
+
+// Disbursement represents a disbursement
+type Disbursement struct {
+	// ID is the unique identifier from the payment provider
+	ID string `json:"id"`
+
+	// ReferenceID is the merchant's reference
+	ReferenceID string `json:"reference_id"`
+
+	// BankCode is the destination bank code
+	BankCode string `json:"bank_code"`
+
+	// AccountNumber is the destination account number
+	AccountNumber string `json:"account_number"`
+
+	// AccountHolder is the name of the destination account holder
+	AccountHolder string `json:"account_holder"`
+
+	// Amount is the disbursed amount
+	Amount int64 `json:"amount"`
+
+	// Description is the disbursement description
+	Description string `json:"description,omitempty"`
+
+	// Status is the current disbursement status
+	// PENDING, PROCESSING, SUCCESS, FAILED or CANCELLED
+	Status Status `json:"status"`
+
+	// FailureReason is the reason when the disbursement failed
+	FailureReason string `json:"failure_reason,omitempty"`
+
+	// CreatedAt is when the disbursement was created
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// UpdatedAt is when the disbursement was last updated
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	// Raw contains the raw response from the provider
+	Raw map[string]interface{} `json:"-"`
+}
+
+// BatchDisbursementParams represents the parameters for a batch of disbursements
+type BatchDisbursementParams struct {
+	// ReferenceID is the merchant's unique reference for the batch (required)
+	ReferenceID string `json:"reference_id"`
+
+	// Disbursements is the list of disbursements in the batch (required)
+	Disbursements []DisbursementParams `json:"disbursements"`
+}
+
+// BatchDisbursement represents a batch of disbursements
+type BatchDisbursement struct {
+	// ID is the unique identifier of the batch from the payment provider
+	ID string `json:"id"`
+
+	// ReferenceID is the merchant's reference for the batch
+	ReferenceID string `json:"reference_id"`
+
+	// Status is the status of the batch
+	Status Status `json:"status"`
+
+	// TotalAmount is the sum of all disbursements in the batch
+	TotalAmount int64 `json:"total_amount"`
+
+	// Disbursements is the list of disbursements when the provider returns them
+	Disbursements []*Disbursement `json:"disbursements,omitempty"`
+
+	// Raw contains the raw response from the provider
+	Raw map[string]interface{} `json:"-"`
+}
+
+// DisbursementEvent represents a disbursement webhook notification
+type DisbursementEvent struct {
+	Disbursement
+
+	// EventType is the type of webhook event
+	EventType string `json:"event_type"`
+
+	// Timestamp is when the webhook was sent
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// disburser returns the provider as a Disburser, or ErrUnimplemented
+func (c *Client) disburser() (Disburser, error) {
+	d, ok := c.provider.(Disburser)
+	if !ok {
+		return nil, ErrUnimplemented
+	}
+	return d, nil
+}
+
+// CreateDisbursement sends money to a bank account
+// Returns ErrUnimplemented if the provider does not support disbursements
+func (c *Client) CreateDisbursement(ctx context.Context, params DisbursementParams) (*Disbursement, error) {
+	d, err := c.disburser()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return d.CreateDisbursement(ctx, params)
+}
+
+// GetDisbursement retrieves a disbursement
+// Returns ErrUnimplemented if the provider does not support disbursements
+func (c *Client) GetDisbursement(ctx context.Context, id string) (*Disbursement, error) {
+	d, err := c.disburser()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+
+	return d.GetDisbursement(ctx, id)
+}
+
+// CreateBatchDisbursement sends money to several bank accounts at once
+// Returns ErrUnimplemented if the provider does not support disbursements,
+// or an UnsupportedError if it reports it does not support batches
+func (c *Client) CreateBatchDisbursement(ctx context.Context, params BatchDisbursementParams) (*BatchDisbursement, error) {
+	d, err := c.disburser()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkOperation(OperationBatchDisbursement); err != nil {
+		return nil, err
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return d.CreateBatchDisbursement(ctx, params)
+}
+
+// ParseDisbursementWebhook parses and verifies a disbursement webhook notification
+// Returns ErrUnimplemented if the provider does not support disbursements,
+// or an UnsupportedError if it reports it does not send disbursement notifications
+func (c *Client) ParseDisbursementWebhook(r *http.Request) (*DisbursementEvent, error) {
+	d, err := c.disburser()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkOperation(OperationDisbursementWebhook); err != nil {
+		return nil, err
+	}
+
+	// Verify signature first
+	if !d.VerifyDisbursementWebhook(r) {
+		return nil, ErrInvalidSignature
+	}
+
+	return d.ParseDisbursementWebhook(r)
+}
+
+// Validate validates the disbursement parameters and returns all problems at once
+// The returned error is a *ValidationError
+func (p DisbursementParams) Validate() error {
+	verr := NewValidationError()
+	p.validate("", verr)
+	return verr.ToError()
+}
+
+// validate adds the problems of the disbursement to verr, prefixing the field names with prefix
+func (p DisbursementParams) validate(prefix string, verr *ValidationError) {
+	if p.ReferenceID == "" {
+		verr.Add(NewRequiredFieldError(prefix + "ReferenceID"))
+	}
+	if p.BankCode == "" {
+		verr.Add(NewRequiredFieldError(prefix + "BankCode"))
+	}
+	if p.AccountNumber == "" {
+		verr.Add(NewRequiredFieldError(prefix + "AccountNumber"))
+	}
+	if p.AccountHolder == "" {
+		verr.Add(NewRequiredFieldError(prefix + "AccountHolder"))
+	}
+	if p.Amount <= 0 {
+		verr.Add(NewFieldError(prefix+"Amount", "must be greater than 0"))
+	}
+}
+
+// Validate validates the batch disbursement parameters and every disbursement in it
+// Problems of a disbursement are reported with an indexed field, e.g. Disbursements[3].Amount
+// The returned error is a *ValidationError
+func (p BatchDisbursementParams) Validate() error {
+	verr := NewValidationError()
+
+	if p.ReferenceID == "" {
+		verr.Add(NewRequiredFieldError("ReferenceID"))
+	}
+	if len(p.Disbursements) == 0 {
+		verr.Add(NewRequiredFieldError("Disbursements"))
+	}
+	for i, d := range p.Disbursements {
+		d.validate(fmt.Sprintf("Disbursements[%d].", i), verr)
+	}
+
+	return verr.ToError()
+}
+
+// DisbursementEventType maps a unified disbursement status to its event type
+func DisbursementEventType(status Status) string {
+	switch status {
+	case StatusSuccess:
+		return EventDisbursementCompleted
+	case StatusFailed:
+		return EventDisbursementFailed
+	case StatusCancelled:
+		return EventDisbursementCancelled
+	default:
+		return EventDisbursementPending
+	}
+}
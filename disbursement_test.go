@@ -0,0 +1,318 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockDisburser is a mock provider which also implements Disburser
+type mockDisburser struct {
+	mockProvider
+	disbursement  *Disbursement
+	batch         *BatchDisbursement
+	disburseErr   error
+	webhookValid  bool
+	webhookEvent  *DisbursementEvent
+	disburseCalls int
+}
+
+func (m *mockDisburser) CreateDisbursement(ctx context.Context, params DisbursementParams) (*Disbursement, error) {
+	m.disburseCalls++
+	return m.disbursement, m.disburseErr
+}
+
+func (m *mockDisburser) GetDisbursement(ctx context.Context, id string) (*Disbursement, error) {
+	m.disburseCalls++
+	return m.disbursement, m.disburseErr
+}
+
+func (m *mockDisburser) CreateBatchDisbursement(ctx context.Context, params BatchDisbursementParams) (*BatchDisbursement, error) {
+	m.disburseCalls++
+	return m.batch, m.disburseErr
+}
+
+func (m *mockDisburser) VerifyDisbursementWebhook(r *http.Request) bool {
+	return m.webhookValid
+}
+
+func (m *mockDisburser) ParseDisbursementWebhook(r *http.Request) (*DisbursementEvent, error) {
+	return m.webhookEvent, nil
+}
+
+func validDisbursementParams() DisbursementParams {
+	return DisbursementParams{
+		ReferenceID:   "DISB-001",
+		BankCode:      "BCA",
+		AccountNumber: "1234567890",
+		AccountHolder: "John Doe",
+		Amount:        100000,
+	}
+}
+
+func TestClient_Disbursement_Unimplemented(t *testing.T) {
+	client := &Client{
+		provider: &mockProvider{name: "mock"},
+		config:   &Config{},
+	}
+
+	ctx := context.Background()
+
+	if _, err := client.CreateDisbursement(ctx, validDisbursementParams()); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("CreateDisbursement() error = %v, want %v", err, ErrUnimplemented)
+	}
+	if _, err := client.GetDisbursement(ctx, "disb-123"); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("GetDisbursement() error = %v, want %v", err, ErrUnimplemented)
+	}
+	if _, err := client.CreateBatchDisbursement(ctx, BatchDisbursementParams{}); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("CreateBatchDisbursement() error = %v, want %v", err, ErrUnimplemented)
+	}
+	req := httptest.NewRequest("POST", "/webhook", nil)
+	if _, err := client.ParseDisbursementWebhook(req); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("ParseDisbursementWebhook() error = %v, want %v", err, ErrUnimplemented)
+	}
+}
+
+func TestClient_CreateDisbursement(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    DisbursementParams
+		wantErr   bool
+		wantCalls int
+	}{
+		{
+			name:      "valid params",
+			params:    validDisbursementParams(),
+			wantCalls: 1,
+		},
+		{
+			name: "invalid params are rejected before the provider is called",
+			params: func() DisbursementParams {
+				p := validDisbursementParams()
+				p.AccountNumber = ""
+				return p
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockDisburser{
+				disbursement: &Disbursement{ID: "disb-123", Status: StatusPending},
+			}
+			client := &Client{provider: mock, config: &Config{}}
+
+			result, err := client.CreateDisbursement(context.Background(), tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateDisbursement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && result.ID != "disb-123" {
+				t.Errorf("ID = %v, want disb-123", result.ID)
+			}
+			if mock.disburseCalls != tt.wantCalls {
+				t.Errorf("provider calls = %v, want %v", mock.disburseCalls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClient_GetDisbursement(t *testing.T) {
+	mock := &mockDisburser{
+		disbursement: &Disbursement{ID: "disb-123", Status: StatusSuccess},
+	}
+	client := &Client{provider: mock, config: &Config{}}
+
+	if _, err := client.GetDisbursement(context.Background(), ""); err == nil {
+		t.Error("expected error for empty ID, got nil")
+	}
+
+	result, err := client.GetDisbursement(context.Background(), "disb-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusSuccess {
+		t.Errorf("Status = %v, want %v", result.Status, StatusSuccess)
+	}
+}
+
+func TestClient_ParseDisbursementWebhook(t *testing.T) {
+	tests := []struct {
+		name         string
+		webhookValid bool
+		wantErr      error
+	}{
+		{
+			name:         "valid webhook",
+			webhookValid: true,
+		},
+		{
+			name:         "invalid signature",
+			webhookValid: false,
+			wantErr:      ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockDisburser{
+				webhookValid: tt.webhookValid,
+				webhookEvent: &DisbursementEvent{
+					Disbursement: Disbursement{ID: "disb-123", Status: StatusSuccess},
+					EventType:    EventDisbursementCompleted,
+				},
+			}
+			client := &Client{provider: mock, config: &Config{}}
+
+			req := httptest.NewRequest("POST", "/webhook", nil)
+			event, err := client.ParseDisbursementWebhook(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseDisbursementWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && event.EventType != EventDisbursementCompleted {
+				t.Errorf("EventType = %v, want %v", event.EventType, EventDisbursementCompleted)
+			}
+		})
+	}
+}
+
+// mockDisburserReporter is a Disburser which also reports its capabilities
+type mockDisburserReporter struct {
+	mockDisburser
+	caps Capabilities
+}
+
+func (m *mockDisburserReporter) Capabilities() Capabilities {
+	return m.caps
+}
+
+func TestClient_Disbursement_UnsupportedOperations(t *testing.T) {
+	mock := &mockDisburserReporter{mockDisburser: mockDisburser{webhookValid: true}}
+	client := &Client{provider: mock, config: &Config{}}
+
+	batch := BatchDisbursementParams{ReferenceID: "BATCH-001", Disbursements: []DisbursementParams{validDisbursementParams()}}
+
+	var unsupported *UnsupportedError
+	if _, err := client.CreateBatchDisbursement(context.Background(), batch); !errors.As(err, &unsupported) || unsupported.Operation != OperationBatchDisbursement {
+		t.Errorf("CreateBatchDisbursement() error = %v, want UnsupportedError for batch_disbursement", err)
+	}
+
+	req := httptest.NewRequest("POST", "/webhook", nil)
+	if _, err := client.ParseDisbursementWebhook(req); !errors.As(err, &unsupported) || unsupported.Operation != OperationDisbursementWebhook {
+		t.Errorf("ParseDisbursementWebhook() error = %v, want UnsupportedError for disbursement_webhook", err)
+	}
+	if mock.disburseCalls != 0 {
+		t.Errorf("provider called %d times, want 0", mock.disburseCalls)
+	}
+
+	mock.caps.Operations = []Operation{OperationBatchDisbursement, OperationDisbursementWebhook}
+	mock.batch = &BatchDisbursement{ID: "batch-123"}
+	mock.webhookEvent = &DisbursementEvent{EventType: EventDisbursementCompleted}
+
+	if _, err := client.CreateBatchDisbursement(context.Background(), batch); err != nil {
+		t.Errorf("CreateBatchDisbursement() error = %v", err)
+	}
+	if _, err := client.ParseDisbursementWebhook(req); err != nil {
+		t.Errorf("ParseDisbursementWebhook() error = %v", err)
+	}
+}
+
+func TestBatchDisbursementParams_Validate(t *testing.T) {
+	invalid := validDisbursementParams()
+	invalid.Amount = 0
+
+	tests := []struct {
+		name    string
+		params  BatchDisbursementParams
+		wantErr bool
+	}{
+		{
+			name: "valid batch",
+			params: BatchDisbursementParams{
+				ReferenceID:   "BATCH-001",
+				Disbursements: []DisbursementParams{validDisbursementParams()},
+			},
+		},
+		{
+			name: "missing reference ID",
+			params: BatchDisbursementParams{
+				Disbursements: []DisbursementParams{validDisbursementParams()},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "empty batch",
+			params:  BatchDisbursementParams{ReferenceID: "BATCH-001"},
+			wantErr: true,
+		},
+		{
+			name: "invalid disbursement",
+			params: BatchDisbursementParams{
+				ReferenceID:   "BATCH-001",
+				Disbursements: []DisbursementParams{validDisbursementParams(), invalid},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBatchDisbursementParams_Validate_AllItems(t *testing.T) {
+	missingAccount := validDisbursementParams()
+	missingAccount.AccountNumber = ""
+	invalidAmount := validDisbursementParams()
+	invalidAmount.Amount = -1000
+
+	err := BatchDisbursementParams{
+		Disbursements: []DisbursementParams{validDisbursementParams(), missingAccount, validDisbursementParams(), invalidAmount},
+	}.Validate()
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate() error = %v, want ValidationError", err)
+	}
+
+	want := []string{"ReferenceID", "Disbursements[1].AccountNumber", "Disbursements[3].Amount"}
+	if len(verr.Errors) != len(want) {
+		t.Fatalf("Errors = %v, want %v", verr.Errors, want)
+	}
+	for i, field := range want {
+		if verr.Errors[i].Field != field {
+			t.Errorf("Errors[%d].Field = %v, want %v", i, verr.Errors[i].Field, field)
+		}
+	}
+	if !errors.Is(err, ErrMissingParameter) || !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("Validate() error = %v, want both %v and %v", err, ErrMissingParameter, ErrInvalidParameter)
+	}
+}
+
+func TestDisbursementEventType(t *testing.T) {
+	tests := []struct {
+		status Status
+		want   string
+	}{
+		{StatusSuccess, EventDisbursementCompleted},
+		{StatusFailed, EventDisbursementFailed},
+		{StatusCancelled, EventDisbursementCancelled},
+		{StatusPending, EventDisbursementPending},
+		{StatusProcessing, EventDisbursementPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := DisbursementEventType(tt.status); got != tt.want {
+				t.Errorf("DisbursementEventType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -14,9 +14,11 @@ var (
 	ErrInvalidSignature    = errors.New("invalid webhook signature")
 	ErrInvalidPayload      = errors.New("invalid webhook payload")
 	ErrMissingCredentials  = errors.New("missing credentials")
+	ErrUnsupported         = errors.New("not supported by this provider")
 
 	// Payment errors
 	ErrMinAmount          = errors.New("minimum transaction amount is Rp10.000")
+	ErrMaxAmount          = errors.New("maximum transaction amount exceeded")
 	ErrDuplicateTransaction = errors.New("duplicate transaction ID")
 	ErrTransactionNotFound = errors.New("transaction not found")
 	ErrTransactionFailed  = errors.New("transaction failed")
@@ -33,11 +35,27 @@ var (
 	ErrInvalidWebhookType        = errors.New("invalid webhook type")
 )
 
+// ErrorCode is a stable, machine readable code of a FieldError
+type ErrorCode string
+
+const (
+	CodeRequired      ErrorCode = "required"
+	CodeInvalid       ErrorCode = "invalid"
+	CodeInvalidFormat ErrorCode = "invalid_format"
+	CodeTooLong       ErrorCode = "too_long"
+	CodeMinAmount     ErrorCode = "min_amount"
+	CodeMaxAmount     ErrorCode = "max_amount"
+	CodeUnsupported   ErrorCode = "unsupported"
+	CodeExpired       ErrorCode = "expired"
+	CodeMismatch      ErrorCode = "mismatch"
+)
+
 // FieldError represents an error for a specific field
 type FieldError struct {
-	Field   string
-	Message string
-	Err     error
+	Field   string    `json:"field"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	Err     error     `json:"-"`
 }
 
 // Error returns the error message
@@ -57,6 +75,7 @@ func (e *FieldError) Unwrap() error {
 func NewFieldError(field, message string) *FieldError {
 	return &FieldError{
 		Field:   field,
+		Code:    CodeInvalid,
 		Message: message,
 		Err:     ErrInvalidParameter,
 	}
@@ -66,14 +85,36 @@ func NewFieldError(field, message string) *FieldError {
 func NewRequiredFieldError(field string) *FieldError {
 	return &FieldError{
 		Field:   field,
+		Code:    CodeRequired,
 		Message: "is required",
 		Err:     ErrMissingParameter,
 	}
 }
 
+// UnsupportedError is returned before any request is sent when the provider
+// reports it does not support a payment type or an operation
+type UnsupportedError struct {
+	Provider    string
+	PaymentType PaymentType
+	Operation   Operation
+}
+
+// Error returns the error message
+func (e *UnsupportedError) Error() string {
+	if e.PaymentType != "" {
+		return fmt.Sprintf("payment type %s is not supported by %s", e.PaymentType, e.Provider)
+	}
+	return fmt.Sprintf("%s is not supported by %s", e.Operation, e.Provider)
+}
+
+// Unwrap returns the underlying error
+func (e *UnsupportedError) Unwrap() error {
+	return ErrUnsupported
+}
+
 // ValidationError represents a collection of field errors
 type ValidationError struct {
-	Errors []*FieldError
+	Errors []*FieldError `json:"errors"`
 }
 
 // Error returns the error message
@@ -92,6 +133,25 @@ func (e *ValidationError) Add(err *FieldError) {
 	e.Errors = append(e.Errors, err)
 }
 
+// Merge adds the field errors of err, which is a FieldError or a ValidationError
+// Other errors are added without a field, nil is ignored
+func (e *ValidationError) Merge(err error) {
+	if err == nil {
+		return
+	}
+
+	var verr *ValidationError
+	var ferr *FieldError
+	switch {
+	case errors.As(err, &verr):
+		e.Errors = append(e.Errors, verr.Errors...)
+	case errors.As(err, &ferr):
+		e.Add(ferr)
+	default:
+		e.Add(&FieldError{Code: CodeInvalid, Message: err.Error(), Err: err})
+	}
+}
+
 // HasErrors returns true if there are any errors
 func (e *ValidationError) HasErrors() bool {
 	return len(e.Errors) > 0
@@ -105,6 +165,15 @@ func (e *ValidationError) ToError() error {
 	return nil
 }
 
+// Unwrap returns the field errors, so errors.Is matches their underlying errors
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
 // NewValidationError creates a new ValidationError
 func NewValidationError() *ValidationError {
 	return &ValidationError{
@@ -133,6 +133,29 @@ func TestValidationError_Add(t *testing.T) {
 	}
 }
 
+func TestValidationError_Merge(t *testing.T) {
+	ve := NewValidationError()
+
+	other := NewValidationError()
+	other.Add(NewRequiredFieldError("OrderID"))
+	other.Add(NewFieldError("Amount", "too small"))
+
+	ve.Merge(nil)
+	ve.Merge(other)
+	ve.Merge(NewRequiredFieldError("Items"))
+	ve.Merge(ErrTimeout)
+
+	if len(ve.Errors) != 4 {
+		t.Fatalf("errors length after Merge = %v, want 4", len(ve.Errors))
+	}
+	if ve.Errors[2].Field != "Items" || ve.Errors[2].Code != CodeRequired {
+		t.Errorf("Errors[2] = %+v, want required Items", ve.Errors[2])
+	}
+	if !errors.Is(ve, ErrTimeout) || !errors.Is(ve, ErrMissingParameter) {
+		t.Errorf("Merge() error = %v, want to match merged errors", ve)
+	}
+}
+
 func TestValidationError_HasErrors(t *testing.T) {
 	tests := []struct {
 		name     string
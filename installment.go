@@ -0,0 +1,113 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultInstallmentTerms are the card installment terms in months offered by each bank
+// Override them per bank with WithInstallmentTerms to match the terms enabled on your merchant account
+var DefaultInstallmentTerms = map[string][]int{
+	"BCA":     {3, 6, 12},
+	"BNI":     {3, 6, 12},
+	"BRI":     {3, 6, 12},
+	"MANDIRI": {3, 6, 12},
+	"CIMB":    {3, 6, 12},
+	"MAYBANK": {3, 6, 12},
+	"MEGA":    {3, 6, 12},
+}
+
+// InstallmentQuoter is implemented by providers that can list card installment options
+type InstallmentQuoter interface {
+	// GetInstallmentOptions returns the installment plans for a card BIN or a bank code
+	GetInstallmentOptions(ctx context.Context, binOrBank string, amount int64) ([]InstallmentPlan, error)
+}
+
+// installmentQuoter returns the provider as an InstallmentQuoter, or ErrUnimplemented
+func (c *Client) installmentQuoter() (InstallmentQuoter, error) {
+	q, ok := c.provider.(InstallmentQuoter)
+	if !ok {
+		return nil, ErrUnimplemented
+	}
+	return q, nil
+}
+
+// GetInstallmentOptions returns the card installment plans available for a card BIN
+// (the first 6 to 8 digits of the card number) or a unified bank code such as BNI
+// Returns ErrUnimplemented if the provider does not support card installments
+func (c *Client) GetInstallmentOptions(ctx context.Context, binOrBank string, amount int64) ([]InstallmentPlan, error) {
+	q, err := c.installmentQuoter()
+	if err != nil {
+		return nil, err
+	}
+
+	if binOrBank == "" {
+		return nil, NewRequiredFieldError("BINOrBank")
+	}
+	if amount <= 0 {
+		return nil, NewFieldError("Amount", "must be greater than zero")
+	}
+
+	return q.GetInstallmentOptions(ctx, binOrBank, amount)
+}
+
+// InstallmentTerms returns the installment terms allowed for a bank
+// Terms configured for the bank take precedence over DefaultInstallmentTerms
+func InstallmentTerms(configured map[string][]int, bank string) ([]int, bool) {
+	code := strings.ToUpper(strings.TrimSpace(bank))
+	if b, ok := LookupBank(code); ok {
+		code = b.Code
+	}
+
+	if terms, ok := configured[code]; ok {
+		return terms, len(terms) > 0
+	}
+	terms, ok := DefaultInstallmentTerms[code]
+	return terms, ok
+}
+
+// NewInstallmentPlans builds interest free installment plans of amount for each term
+// It is used by providers which do not quote plans themselves
+func NewInstallmentPlans(bank string, amount int64, terms []int) []InstallmentPlan {
+	plans := make([]InstallmentPlan, 0, len(terms))
+	for _, term := range terms {
+		plans = append(plans, InstallmentPlan{
+			Tenure:            term,
+			Bank:              bank,
+			InstallmentAmount: (amount + int64(term) - 1) / int64(term),
+			TotalAmount:       amount,
+		})
+	}
+	return plans
+}
+
+// IsInstallment reports whether the card charge is paid in installments
+func (p *CreditCardParams) IsInstallment() bool {
+	return p != nil && p.InstallmentTerm > 0
+}
+
+// ValidateInstallment checks the installment term against the terms allowed for the bank
+// It is nil-safe and accepts full payments
+func (p *CreditCardParams) ValidateInstallment(configured map[string][]int) error {
+	if !p.IsInstallment() {
+		return nil
+	}
+
+	if p.Bank == "" {
+		return NewRequiredFieldError("CreditCard.Bank")
+	}
+
+	terms, ok := InstallmentTerms(configured, p.Bank)
+	if !ok {
+		return NewFieldError("CreditCard.Bank", fmt.Sprintf("bank %s does not offer installments", p.Bank))
+	}
+
+	for _, term := range terms {
+		if term == p.InstallmentTerm {
+			return nil
+		}
+	}
+
+	return NewFieldError("CreditCard.InstallmentTerm", fmt.Sprintf("term %d is not offered by %s, allowed terms are %v", p.InstallmentTerm, p.Bank, terms))
+}
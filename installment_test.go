@@ -0,0 +1,91 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// mockQuoter is a mock provider which also implements InstallmentQuoter
+type mockQuoter struct {
+	mockProvider
+	calls int
+}
+
+func (m *mockQuoter) GetInstallmentOptions(ctx context.Context, binOrBank string, amount int64) ([]InstallmentPlan, error) {
+	m.calls++
+	return NewInstallmentPlans(binOrBank, amount, []int{3, 6}), nil
+}
+
+func TestCreditCardParams_ValidateInstallment(t *testing.T) {
+	configured := map[string][]int{"BCA": {3, 6, 12, 24}, "BNI": {}}
+
+	tests := []struct {
+		name    string
+		params  *CreditCardParams
+		wantErr bool
+	}{
+		{name: "nil", params: nil},
+		{name: "full payment", params: &CreditCardParams{CardNumber: "token"}},
+		{name: "default terms", params: &CreditCardParams{InstallmentTerm: 6, Bank: "MANDIRI"}},
+		{name: "bank alias", params: &CreditCardParams{InstallmentTerm: 3, Bank: "cimb_niaga"}},
+		{name: "configured terms", params: &CreditCardParams{InstallmentTerm: 24, Bank: "bca"}},
+		{name: "term not offered", params: &CreditCardParams{InstallmentTerm: 24, Bank: "MANDIRI"}, wantErr: true},
+		{name: "missing bank", params: &CreditCardParams{InstallmentTerm: 3}, wantErr: true},
+		{name: "bank without installments", params: &CreditCardParams{InstallmentTerm: 3, Bank: "PERMATA"}, wantErr: true},
+		{name: "installments disabled by config", params: &CreditCardParams{InstallmentTerm: 3, Bank: "BNI"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.ValidateInstallment(configured)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateInstallment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewInstallmentPlans(t *testing.T) {
+	plans := NewInstallmentPlans("BCA", 1000000, []int{3, 12})
+
+	if len(plans) != 2 {
+		t.Fatalf("got %d plans, want 2", len(plans))
+	}
+	if plans[0].InstallmentAmount != 333334 {
+		t.Errorf("InstallmentAmount = %v, want 333334 rounded up", plans[0].InstallmentAmount)
+	}
+	if plans[1].TotalAmount != 1000000 || plans[1].Bank != "BCA" {
+		t.Errorf("plan = %+v, want BCA total 1000000", plans[1])
+	}
+}
+
+func TestClient_GetInstallmentOptions(t *testing.T) {
+	ctx := context.Background()
+
+	client := &Client{provider: &mockProvider{name: "mock"}, config: &Config{}}
+	if _, err := client.GetInstallmentOptions(ctx, "BCA", 100000); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("GetInstallmentOptions() error = %v, want %v", err, ErrUnimplemented)
+	}
+
+	mock := &mockQuoter{}
+	client = &Client{provider: mock, config: &Config{}}
+
+	if _, err := client.GetInstallmentOptions(ctx, "", 100000); err == nil {
+		t.Error("GetInstallmentOptions() expected error for empty BIN or bank")
+	}
+	if _, err := client.GetInstallmentOptions(ctx, "BCA", 0); err == nil {
+		t.Error("GetInstallmentOptions() expected error for zero amount")
+	}
+	if mock.calls != 0 {
+		t.Errorf("provider called %d times for invalid input, want 0", mock.calls)
+	}
+
+	plans, err := client.GetInstallmentOptions(ctx, "BCA", 600000)
+	if err != nil {
+		t.Fatalf("GetInstallmentOptions() error = %v", err)
+	}
+	if len(plans) != 2 {
+		t.Errorf("got %d plans, want 2", len(plans))
+	}
+}
@@ -0,0 +1,67 @@
+package doku
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/pgtest"
+)
+
+// conformanceStatuses maps the unified statuses to the Doku transaction statuses
+var conformanceStatuses = map[pg.Status]PaymentStatus{
+	pg.StatusSuccess:   StatusSuccess,
+	pg.StatusPending:   StatusPending,
+	pg.StatusFailed:    StatusFailed,
+	pg.StatusCancelled: StatusCancelled,
+}
+
+// jsonHandler answers every request with body and the HTTP status code
+func jsonHandler(code int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	})
+}
+
+func TestDoku_Conformance(t *testing.T) {
+	config := pg.ProviderConfig{ClientKey: "BRN-0001", ServerKey: "SK-secret"}
+
+	pgtest.RunProviderConformance(t, New, pgtest.Stub{
+		Config: config,
+		Charge: pg.ChargeParams{
+			OrderID:     "ORDER-001",
+			Amount:      150000,
+			PaymentType: pg.PaymentTypeVABCA,
+			Customer:    pg.Customer{ID: "CUST-001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890"},
+			Items:       []pg.Item{{ID: "SKU-001", Name: "Kaos Polos", Price: 75000, Quantity: 2}},
+			CallbackURL: "https://example.com/callback",
+		},
+		CreateCharge: jsonHandler(http.StatusOK, `{"response_code":"00","response_message":"SUCCESS","transaction_id":"ORDER-001","order_amount":150000,"virtual_account_number":"1900800000123456","va_bank":"BCA"}`),
+		Rejected:     jsonHandler(http.StatusBadRequest, `{"error":{"code":"invalid_parameter","message":"order.amount must be greater than 0"}}`),
+		Statuses:     []pg.Status{pg.StatusSuccess, pg.StatusPending, pg.StatusFailed, pg.StatusCancelled},
+		Status: func(status pg.Status) http.Handler {
+			return jsonHandler(http.StatusOK, `{"response_code":"00","response_message":"SUCCESS","transaction_id":"ORDER-001","order_amount":150000,"transaction_status":"`+string(conformanceStatuses[status])+`","payment_type":"VIRTUAL_ACCOUNT"}`)
+		},
+		Webhook: func(status pg.Status, signed bool) *http.Request {
+			body := `{"transaction_id":"ORDER-001","transaction_status":"` + string(conformanceStatuses[status]) + `","amount":150000,"payment_date_time":"2024-03-01T03:15:30Z"}`
+
+			signer := &doku{config: &config}
+			if !signed {
+				signer = &doku{config: &pg.ProviderConfig{ClientKey: config.ClientKey, ServerKey: "wrong-key"}}
+			}
+			timestamp, requestID := "2024-03-01T03:15:31Z", "req-1709262931000-a1b2c3d4"
+			signature := signer.generateSignature(signer.generateDigest([]byte(body)), timestamp, requestID, generatePaymentUri)
+
+			r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Signature", signature)
+			r.Header.Set("Request-Timestamp", timestamp)
+			r.Header.Set("Request-Id", requestID)
+			return r
+		},
+	})
+}
@@ -0,0 +1,238 @@
+package doku
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// SNAP Direct Debit API endpoints
+	customerTokenUri      = "/authorization/v1/access-token/b2b2c"
+	accountBindingUri     = "/direct-debit/core/v1/registration-account-binding"
+	accountUnbindingUri   = "/direct-debit/core/v1/registration-account-unbinding"
+	directDebitPaymentUri = "/direct-debit/core/v1/debit/payment-host-to-host"
+	otpVerificationUri    = "/direct-debit/core/v1/otp-verification"
+
+	headerAuthorizationCustomer = "Authorization-Customer"
+)
+
+// LinkAccount starts binding a BRI or Mandiri account for direct debit
+// The customer authorizes the binding at LinkedAccount.ActionURL, which redirects back
+// to RedirectURL with an authCode to pass to ConfirmLink
+func (d *doku) LinkAccount(ctx context.Context, params pg.LinkParams) (*pg.LinkedAccount, error) {
+	if !params.PaymentType.IsDirectDebit() || d.mapper.mapDirectDebitChannel(params.PaymentType) == "" {
+		return nil, pg.NewFieldError("PaymentType", fmt.Sprintf("account linking is not supported for %s", params.PaymentType))
+	}
+	if params.Customer.Phone == "" {
+		return nil, pg.NewRequiredFieldError("Customer.Phone")
+	}
+
+	req := d.mapper.mapToAccountBindingRequest(params)
+
+	responseBody, err := d.sendSNAPRequest(ctx, accountBindingUri, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp AccountBindingResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return d.mapper.mapToPendingLinkedAccount(&resp, params.PaymentType, raw), nil
+}
+
+// GetLinkedAccount is not supported, SNAP has no binding inquiry
+func (d *doku) GetLinkedAccount(ctx context.Context, id string) (*pg.LinkedAccount, error) {
+	return nil, pg.ErrUnimplemented
+}
+
+// UnlinkAccount unbinds a direct debit account
+// SNAP unbinds with a customer token, so id is the LinkedAccount.Token returned by ConfirmLink
+func (d *doku) UnlinkAccount(ctx context.Context, id string) error {
+	token, err := d.requestCustomerToken(ctx, &CustomerTokenRequest{GrantType: "REFRESH_TOKEN", RefreshToken: id})
+	if err != nil {
+		return err
+	}
+
+	req := &AccountUnbindingRequest{TokenID: token.AccessToken}
+	headers := map[string]string{headerAuthorizationCustomer: "Bearer " + token.AccessToken}
+
+	_, err = d.sendSNAPRequestWithHeaders(ctx, accountUnbindingUri, req, headers)
+	return err
+}
+
+// ConfirmLink exchanges the authCode from the binding redirect for a customer token
+// The returned ID is the binding reference, the refresh token is returned as Token
+// and passed as DirectDebit.AccountToken when charging
+func (d *doku) ConfirmLink(ctx context.Context, id, code string) (*pg.LinkedAccount, error) {
+	if code == "" {
+		return nil, pg.NewRequiredFieldError("Code")
+	}
+
+	token, err := d.requestCustomerToken(ctx, &CustomerTokenRequest{GrantType: "AUTHORIZATION_CODE", AuthCode: code})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pg.LinkedAccount{
+		ID:     id,
+		Token:  token.RefreshToken,
+		Status: pg.LinkActive,
+	}, nil
+}
+
+// createDirectDebitCharge debits a bound account with a fresh customer token
+func (d *doku) createDirectDebitCharge(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	token, err := d.requestCustomerToken(ctx, &CustomerTokenRequest{GrantType: "REFRESH_TOKEN", RefreshToken: params.DirectDebit.AccountToken})
+	if err != nil {
+		return nil, err
+	}
+
+	req := d.mapper.mapToDirectDebitPaymentRequest(params)
+	headers := map[string]string{headerAuthorizationCustomer: "Bearer " + token.AccessToken}
+
+	responseBody, err := d.sendSNAPRequestWithHeaders(ctx, directDebitPaymentUri, pg.WithCustom(req, params.Custom), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DirectDebitPaymentResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return d.mapper.mapToChargeResponseFromDirectDebit(&resp, params, raw), nil
+}
+
+// ValidateChargeOTP verifies the OTP of a direct debit with a fresh customer token
+// OrderID, PaymentType and AccountToken are those of the charge
+func (d *doku) ValidateChargeOTP(ctx context.Context, params pg.ChargeOTPParams) (*pg.ChargeResponse, error) {
+	channel := d.mapper.mapDirectDebitChannel(params.PaymentType)
+
+	verr := pg.NewValidationError()
+	if params.OrderID == "" {
+		verr.Add(pg.NewRequiredFieldError("OrderID"))
+	}
+	if channel == "" {
+		verr.Add(pg.NewFieldError("PaymentType", fmt.Sprintf("direct debit is not supported for %s", params.PaymentType)))
+	}
+	if params.AccountToken == "" {
+		verr.Add(pg.NewRequiredFieldError("AccountToken"))
+	}
+	if err := verr.ToError(); err != nil {
+		return nil, err
+	}
+
+	token, err := d.requestCustomerToken(ctx, &CustomerTokenRequest{GrantType: "REFRESH_TOKEN", RefreshToken: params.AccountToken})
+	if err != nil {
+		return nil, err
+	}
+
+	req := &OTPVerificationRequest{
+		OriginalPartnerReferenceNo: params.OrderID,
+		OriginalReferenceNo:        params.TransactionID,
+		OTP:                        params.OTP,
+		AdditionalInfo:             map[string]interface{}{"channel": channel},
+	}
+	headers := map[string]string{headerAuthorizationCustomer: "Bearer " + token.AccessToken}
+
+	responseBody, err := d.sendSNAPRequestWithHeaders(ctx, otpVerificationUri, req, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DirectDebitPaymentResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	unified := &pg.ChargeResponse{
+		TransactionID: params.TransactionID,
+		OrderID:       params.OrderID,
+		Status:        pg.StatusSuccess,
+		Raw:           raw,
+	}
+	if resp.Amount != nil {
+		amount, _ := strconv.ParseFloat(resp.Amount.Value, 64)
+		unified.Amount = int64(amount)
+	}
+
+	return unified, nil
+}
+
+// requestCustomerToken requests a SNAP B2B2C token, signed like the B2B access token
+func (d *doku) requestCustomerToken(ctx context.Context, payload *CustomerTokenRequest) (*CustomerTokenResponse, error) {
+	if d.config.PrivateKey == "" {
+		return nil, fmt.Errorf("private key is required for the customer token API")
+	}
+
+	privateKey, err := d.parsePrivateKey(d.config.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	signature, err := d.generateAsymmetricSignature(privateKey, d.config.ClientKey+"|"+timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signature: %w", err)
+	}
+
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.getBaseURL()+customerTokenUri, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(headerXClientKey, d.config.ClientKey)
+	req.Header.Set(headerXTimestamp, timestamp)
+	req.Header.Set(headerXSignature, signature)
+
+	resp, err := d.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var tokenResp CustomerTokenResponse
+	if resp.StatusCode != http.StatusOK {
+		if err := json.Unmarshal(responseBody, &tokenResp); err == nil && tokenResp.ResponseCode != "" {
+			return nil, pg.WrapProviderError(ProviderName, tokenResp.ResponseCode, tokenResp.ResponseMessage, nil)
+		}
+		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+	}
+
+	if err := json.Unmarshal(responseBody, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &tokenResp, nil
+}
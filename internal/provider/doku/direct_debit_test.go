@@ -0,0 +1,180 @@
+package doku
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+func TestDoku_LinkAccount(t *testing.T) {
+	server := snapServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		if r.URL.Path != accountBindingUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, accountBindingUri)
+		}
+
+		var req AccountBindingRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.PhoneNo != "081234567890" || req.AdditionalInfo["channel"] != "DIRECT_DEBIT_BRI_SNAP" {
+			t.Errorf("request = %+v, want BRI binding for 081234567890", req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"responseCode":"2000700","responseMessage":"Successful","referenceNo":"BIND-123","redirectUrl":"https://sandbox.doku.com/bind/BIND-123"}`))
+	})
+	defer server.Close()
+
+	d := newTestProvider(t, server)
+
+	account, err := d.LinkAccount(context.Background(), pg.LinkParams{
+		PaymentType: pg.PaymentTypeDDBRI,
+		Customer:    pg.Customer{ID: "CUST-1", Name: "John Doe", Phone: "081234567890"},
+		RedirectURL: "https://example.com/bound",
+	})
+	if err != nil {
+		t.Fatalf("LinkAccount() error = %v", err)
+	}
+
+	if account.ID != "BIND-123" || account.Status != pg.LinkPending {
+		t.Errorf("account = %+v, want pending BIND-123", account)
+	}
+	if len(account.NextActions) != 1 || account.NextActions[0].Type != pg.ActionRedirect {
+		t.Errorf("NextActions = %+v, want redirect", account.NextActions)
+	}
+}
+
+func TestDoku_LinkAccount_Unsupported(t *testing.T) {
+	d := newTestProvider(t, nil)
+
+	_, err := d.LinkAccount(context.Background(), pg.LinkParams{
+		PaymentType: pg.PaymentTypeDDBCAOneKlik,
+		Customer:    pg.Customer{Phone: "081234567890"},
+		RedirectURL: "https://example.com/bound",
+	})
+	if err == nil {
+		t.Error("LinkAccount() expected error for BCA OneKlik")
+	}
+}
+
+func TestDoku_ConfirmLink(t *testing.T) {
+	server := snapServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		if r.URL.Path != customerTokenUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, customerTokenUri)
+		}
+
+		var req CustomerTokenRequest
+		_ = json.Unmarshal(body, &req)
+		if req.GrantType != "AUTHORIZATION_CODE" || req.AuthCode != "auth-code" {
+			t.Errorf("request = %+v, want authorization code grant", req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"responseCode":"2007400","accessToken":"customer-token","tokenType":"Bearer","refreshToken":"refresh-token"}`))
+	})
+	defer server.Close()
+
+	d := newTestProvider(t, server)
+
+	account, err := d.ConfirmLink(context.Background(), "BIND-123", "auth-code")
+	if err != nil {
+		t.Fatalf("ConfirmLink() error = %v", err)
+	}
+
+	// The refresh token is a credential, it is kept out of the ID
+	if account.ID != "BIND-123" || account.Token != "refresh-token" || account.Status != pg.LinkActive {
+		t.Errorf("account = %+v, want active BIND-123 with the refresh token", account)
+	}
+}
+
+func TestDoku_DirectDebit(t *testing.T) {
+	server := snapServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case customerTokenUri:
+			var req CustomerTokenRequest
+			_ = json.Unmarshal(body, &req)
+			if req.GrantType != "REFRESH_TOKEN" || req.RefreshToken != "refresh-token" {
+				t.Errorf("request = %+v, want refresh token grant", req)
+			}
+			w.Write([]byte(`{"responseCode":"2007400","accessToken":"customer-token","refreshToken":"refresh-token"}`))
+		case directDebitPaymentUri:
+			if r.Header.Get(headerAuthorizationCustomer) != "Bearer customer-token" {
+				t.Errorf("%s = %v, want Bearer customer-token", headerAuthorizationCustomer, r.Header.Get(headerAuthorizationCustomer))
+			}
+
+			var req DirectDebitPaymentRequest
+			_ = json.Unmarshal(body, &req)
+			if req.Amount.Value != "50000.00" || req.AdditionalInfo["channel"] != "DIRECT_DEBIT_MANDIRI_SNAP" {
+				t.Errorf("request = %+v, want Mandiri debit of 50000.00", req)
+			}
+
+			w.Write([]byte(`{"responseCode":"2005400","responseMessage":"Successful","referenceNo":"DD-123","partnerReferenceNo":"ORDER-DD-1"}`))
+		case otpVerificationUri:
+			var req OTPVerificationRequest
+			_ = json.Unmarshal(body, &req)
+			if req.OTP != "123456" || req.OriginalReferenceNo != "DD-123" || req.OriginalPartnerReferenceNo != "ORDER-DD-1" {
+				t.Errorf("request = %+v, want OTP for DD-123", req)
+			}
+
+			w.Write([]byte(`{"responseCode":"2000400","responseMessage":"Successful","originalReferenceNo":"DD-123","amount":{"value":"50000.00","currency":"IDR"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := newTestProvider(t, server)
+
+	resp, err := d.CreateCharge(context.Background(), pg.ChargeParams{
+		OrderID:     "ORDER-DD-1",
+		Amount:      50000,
+		PaymentType: pg.PaymentTypeDDMandiri,
+		Customer:    pg.Customer{ID: "CUST-1", Email: "john@example.com"},
+		DirectDebit: &pg.DirectDebitParams{AccountLinkID: "BIND-123", AccountToken: "refresh-token"},
+	})
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+
+	if resp.TransactionID != "DD-123" || resp.Status != pg.StatusPending {
+		t.Errorf("response = %+v, want pending DD-123", resp)
+	}
+	if len(resp.NextActions) != 1 || resp.NextActions[0].Type != pg.ActionValidateOTP {
+		t.Fatalf("NextActions = %+v, want OTP validation", resp.NextActions)
+	}
+
+	// A fresh provider validates the OTP, as after a restart or on another instance
+	d = newTestProvider(t, server)
+
+	resp, err = d.ValidateChargeOTP(context.Background(), pg.ChargeOTPParams{
+		TransactionID: resp.TransactionID,
+		OTP:           "123456",
+		OrderID:       resp.OrderID,
+		PaymentType:   pg.PaymentTypeDDMandiri,
+		AccountToken:  "refresh-token",
+	})
+	if err != nil {
+		t.Fatalf("ValidateChargeOTP() error = %v", err)
+	}
+
+	if resp.Status != pg.StatusSuccess || resp.Amount != 50000 {
+		t.Errorf("response = %+v, want successful 50000", resp)
+	}
+}
+
+func TestDoku_ValidateChargeOTP_MissingCharge(t *testing.T) {
+	d := newTestProvider(t, nil)
+
+	_, err := d.ValidateChargeOTP(context.Background(), pg.ChargeOTPParams{TransactionID: "DD-123", OTP: "123456"})
+
+	var verr *pg.ValidationError
+	if !errors.As(err, &verr) || len(verr.Errors) != 3 {
+		t.Errorf("ValidateChargeOTP() error = %v, want OrderID, PaymentType and AccountToken errors", err)
+	}
+}
@@ -0,0 +1,177 @@
+package doku
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// SNAP transfer endpoints
+	transferInterbankUri = "/snap-adapter/b2b/v1.0/transfer-interbank"
+	transferStatusUri    = "/snap-adapter/b2b/v1.0/transfer/status"
+
+	// transferInterbankServiceCode is the SNAP service code of Transfer Interbank
+	transferInterbankServiceCode = "18"
+
+	// SNAP header names
+	headerXPartnerID  = "X-PARTNER-ID"
+	headerXExternalID = "X-EXTERNAL-ID"
+	headerChannelID   = "CHANNEL-ID"
+
+	channelID = "H2H"
+)
+
+// CreateDisbursement sends money to a bank account using SNAP Transfer Interbank
+func (d *doku) CreateDisbursement(ctx context.Context, params pg.DisbursementParams) (*pg.Disbursement, error) {
+	req := d.mapper.mapToTransferInterbankRequest(params, time.Now().UTC().Format(time.RFC3339))
+
+	responseBody, err := d.sendSNAPRequest(ctx, transferInterbankUri, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TransferInterbankResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return d.mapper.mapToDisbursement(params, &resp, raw), nil
+}
+
+// GetDisbursement retrieves a transfer using SNAP Transfer Status Inquiry
+// id is the merchant's ReferenceID (partnerReferenceNo)
+func (d *doku) GetDisbursement(ctx context.Context, id string) (*pg.Disbursement, error) {
+	req := &TransferStatusRequest{
+		OriginalPartnerReferenceNo: id,
+		ServiceCode:                transferInterbankServiceCode,
+	}
+
+	responseBody, err := d.sendSNAPRequest(ctx, transferStatusUri, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TransferStatusResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return d.mapper.mapTransferStatusToDisbursement(&resp, raw), nil
+}
+
+// CreateBatchDisbursement is not supported by Doku, which has no SNAP batch transfer
+// Capabilities leaves out pg.OperationBatchDisbursement so the Client rejects it up front
+func (d *doku) CreateBatchDisbursement(ctx context.Context, params pg.BatchDisbursementParams) (*pg.BatchDisbursement, error) {
+	return nil, &pg.UnsupportedError{Provider: ProviderName, Operation: pg.OperationBatchDisbursement}
+}
+
+// VerifyDisbursementWebhook always returns false
+// Doku does not send notifications for SNAP transfers, use GetDisbursement instead
+func (d *doku) VerifyDisbursementWebhook(r *http.Request) bool {
+	return false
+}
+
+// ParseDisbursementWebhook is not supported by Doku
+// Capabilities leaves out pg.OperationDisbursementWebhook so the Client rejects it up front
+func (d *doku) ParseDisbursementWebhook(r *http.Request) (*pg.DisbursementEvent, error) {
+	return nil, &pg.UnsupportedError{Provider: ProviderName, Operation: pg.OperationDisbursementWebhook}
+}
+
+// sendSNAPRequest sends a signed SNAP transaction request and returns the response body
+// The access token is cached until it expires and shared by the disbursement and direct debit calls
+func (d *doku) sendSNAPRequest(ctx context.Context, path string, payload interface{}) ([]byte, error) {
+	return d.sendSNAPRequestWithHeaders(ctx, path, payload, nil)
+}
+
+// sendSNAPRequestWithHeaders sends a signed SNAP transaction request with extra headers
+func (d *doku) sendSNAPRequestWithHeaders(ctx context.Context, path string, payload interface{}, headers map[string]string) ([]byte, error) {
+	token, err := d.accessToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	signature := d.generateSymmetricSignature(http.MethodPost, path, token, bodyBytes, timestamp)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.getBaseURL()+path, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(headerXTimestamp, timestamp)
+	req.Header.Set(headerXSignature, signature)
+	req.Header.Set(headerXPartnerID, d.config.ClientKey)
+	req.Header.Set(headerXExternalID, fmt.Sprintf("%d", time.Now().UnixNano()))
+	req.Header.Set(headerChannelID, channelID)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := d.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// SNAP reports business failures in responseCode, sometimes with HTTP 200
+	// Successful codes start with the HTTP class "2", e.g. 2001800
+	var snapResp TransferInterbankResponse
+	if err := json.Unmarshal(responseBody, &snapResp); err == nil && snapResp.ResponseCode != "" && !strings.HasPrefix(snapResp.ResponseCode, "2") {
+		return nil, pg.WrapProviderError(ProviderName, snapResp.ResponseCode, snapResp.ResponseMessage, nil)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+	}
+
+	return responseBody, nil
+}
+
+// generateSymmetricSignature generates the SNAP HMAC-SHA512 transaction signature
+// String to sign format: METHOD:path:accessToken:lowercase(hex(sha256(minified body))):timestamp
+func (d *doku) generateSymmetricSignature(method, path, accessToken string, body []byte, timestamp string) string {
+	bodyHash := sha256.Sum256(body)
+	stringToSign := strings.Join([]string{
+		method,
+		path,
+		accessToken,
+		strings.ToLower(hex.EncodeToString(bodyHash[:])),
+		timestamp,
+	}, ":")
+
+	h := hmac.New(sha512.New, []byte(d.config.ServerKey))
+	h.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
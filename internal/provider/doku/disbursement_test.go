@@ -0,0 +1,317 @@
+package doku
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+// rewriteTransport sends every request to the test server regardless of the original host
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestProvider creates a Doku provider which talks to the given test server
+func newTestProvider(t *testing.T, server *httptest.Server) *doku {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	provider, err := New(&pg.ProviderConfig{
+		ClientKey:  "BRN-0001",
+		ServerKey:  "SK-secret",
+		PrivateKey: string(privateKey),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	d := provider.(*doku)
+	if server != nil {
+		target, _ := url.Parse(server.URL)
+		d.httpCli = &http.Client{
+			Transport: &rewriteTransport{target: target},
+		}
+	}
+
+	return d
+}
+
+// snapServer serves the access token endpoint and passes other requests to handler
+// after checking their SNAP signature
+func snapServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, body []byte)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenUri {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"token":"access-token"}`))
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+
+		// The customer token endpoint is signed like the access token endpoint
+		if r.URL.Path == customerTokenUri {
+			handler(w, r, body)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			t.Errorf("Authorization = %v, want Bearer access-token", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get(headerXPartnerID) != "BRN-0001" {
+			t.Errorf("%s = %v, want BRN-0001", headerXPartnerID, r.Header.Get(headerXPartnerID))
+		}
+
+		bodyHash := sha256.Sum256(body)
+		stringToSign := r.Method + ":" + r.URL.Path + ":access-token:" + hex.EncodeToString(bodyHash[:]) + ":" + r.Header.Get(headerXTimestamp)
+		h := hmac.New(sha512.New, []byte("SK-secret"))
+		h.Write([]byte(stringToSign))
+		if r.Header.Get(headerXSignature) != base64.StdEncoding.EncodeToString(h.Sum(nil)) {
+			t.Error("invalid X-SIGNATURE")
+		}
+
+		handler(w, r, body)
+	}))
+}
+
+func TestDoku_CreateDisbursement(t *testing.T) {
+	server := snapServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		if r.URL.Path != transferInterbankUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, transferInterbankUri)
+		}
+
+		var req TransferInterbankRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.BeneficiaryBankCode != "014" {
+			t.Errorf("BeneficiaryBankCode = %v, want 014", req.BeneficiaryBankCode)
+		}
+		if req.Amount.Value != "100000.00" {
+			t.Errorf("Amount.Value = %v, want 100000.00", req.Amount.Value)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"responseCode": "2001800",
+			"responseMessage": "Request has been processed successfully",
+			"referenceNo": "doku-ref-123",
+			"partnerReferenceNo": "DISB-001",
+			"amount": {"value": "100000.00", "currency": "IDR"}
+		}`))
+	})
+	defer server.Close()
+
+	d := newTestProvider(t, server)
+
+	result, err := d.CreateDisbursement(context.Background(), pg.DisbursementParams{
+		ReferenceID:   "DISB-001",
+		BankCode:      "BCA",
+		AccountNumber: "1234567890",
+		AccountHolder: "John Doe",
+		Amount:        100000,
+	})
+	if err != nil {
+		t.Fatalf("CreateDisbursement() error = %v", err)
+	}
+
+	if result.ID != "DISB-001" {
+		t.Errorf("ID = %v, want DISB-001", result.ID)
+	}
+	if result.Status != pg.StatusProcessing {
+		t.Errorf("Status = %v, want %v", result.Status, pg.StatusProcessing)
+	}
+}
+
+func TestDoku_CreateDisbursement_APIError(t *testing.T) {
+	server := snapServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"responseCode":"4001801","responseMessage":"Invalid Field Format"}`))
+	})
+	defer server.Close()
+
+	d := newTestProvider(t, server)
+
+	_, err := d.CreateDisbursement(context.Background(), pg.DisbursementParams{ReferenceID: "DISB-001"})
+	if !pg.IsProviderError(err) {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+}
+
+func TestDoku_CreateDisbursement_BusinessError(t *testing.T) {
+	server := snapServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"responseCode":"4031814","responseMessage":"Insufficient Funds","partnerReferenceNo":"DISB-001"}`))
+	})
+	defer server.Close()
+
+	d := newTestProvider(t, server)
+
+	_, err := d.CreateDisbursement(context.Background(), pg.DisbursementParams{
+		ReferenceID:   "DISB-001",
+		BankCode:      "BCA",
+		AccountNumber: "1234567890",
+		AccountHolder: "John Doe",
+		Amount:        100000,
+	})
+
+	var providerErr *pg.ProviderError
+	if !errors.As(err, &providerErr) {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+	if providerErr.Code != "4031814" || providerErr.Message != "Insufficient Funds" {
+		t.Errorf("error = %+v, want 4031814 Insufficient Funds", providerErr)
+	}
+}
+
+func TestDoku_GetDisbursement(t *testing.T) {
+	server := snapServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		var req TransferStatusRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.OriginalPartnerReferenceNo != "DISB-001" || req.ServiceCode != transferInterbankServiceCode {
+			t.Errorf("request = %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"responseCode": "2003600",
+			"responseMessage": "Successful",
+			"originalReferenceNo": "doku-ref-123",
+			"originalPartnerReferenceNo": "DISB-001",
+			"amount": {"value": "100000.00", "currency": "IDR"},
+			"latestTransactionStatus": "00"
+		}`))
+	})
+	defer server.Close()
+
+	d := newTestProvider(t, server)
+
+	result, err := d.GetDisbursement(context.Background(), "DISB-001")
+	if err != nil {
+		t.Fatalf("GetDisbursement() error = %v", err)
+	}
+
+	if result.Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", result.Status, pg.StatusSuccess)
+	}
+	if result.Amount != 100000 {
+		t.Errorf("Amount = %v, want 100000", result.Amount)
+	}
+}
+
+func TestDoku_accessToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		wantTokens int
+	}{
+		{
+			name:       "cached until expiresIn",
+			response:   `{"accessToken":"access-token","tokenType":"Bearer","expiresIn":900}`,
+			wantTokens: 1,
+		},
+		{
+			name:       "not cached without expiresIn",
+			response:   `{"token":"access-token"}`,
+			wantTokens: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == tokenUri {
+					tokens++
+					w.Write([]byte(tt.response))
+					return
+				}
+				if r.Header.Get("Authorization") != "Bearer access-token" {
+					t.Errorf("Authorization = %v, want Bearer access-token", r.Header.Get("Authorization"))
+				}
+				w.Write([]byte(`{"responseCode":"2003600","originalPartnerReferenceNo":"DISB-001","latestTransactionStatus":"00"}`))
+			}))
+			defer server.Close()
+
+			d := newTestProvider(t, server)
+
+			for i := 0; i < 3; i++ {
+				if _, err := d.GetDisbursement(context.Background(), "DISB-001"); err != nil {
+					t.Fatalf("GetDisbursement() error = %v", err)
+				}
+			}
+
+			if tokens != tt.wantTokens {
+				t.Errorf("token requests = %v, want %v", tokens, tt.wantTokens)
+			}
+		})
+	}
+}
+
+func TestDoku_Disbursement_Unsupported(t *testing.T) {
+	d := newTestProvider(t, nil)
+
+	caps := d.Capabilities()
+	if caps.SupportsOperation(pg.OperationBatchDisbursement) || caps.SupportsOperation(pg.OperationDisbursementWebhook) {
+		t.Errorf("Operations = %v, want no batch disbursement or disbursement webhook", caps.Operations)
+	}
+
+	if _, err := d.CreateBatchDisbursement(context.Background(), pg.BatchDisbursementParams{}); !errors.Is(err, pg.ErrUnsupported) {
+		t.Errorf("CreateBatchDisbursement() error = %v, want %v", err, pg.ErrUnsupported)
+	}
+
+	req := httptest.NewRequest("POST", "/webhook", nil)
+	if d.VerifyDisbursementWebhook(req) {
+		t.Error("VerifyDisbursementWebhook() = true, want false")
+	}
+	if _, err := d.ParseDisbursementWebhook(req); !errors.Is(err, pg.ErrUnsupported) {
+		t.Errorf("ParseDisbursementWebhook() error = %v, want %v", err, pg.ErrUnsupported)
+	}
+}
+
+func TestMapper_mapSNAPBankCode(t *testing.T) {
+	mapper := &Mapper{}
+
+	tests := map[string]string{
+		"BCA":     "014",
+		"mandiri": "008",
+		"451":     "451",
+	}
+	for input, want := range tests {
+		if got := mapper.mapSNAPBankCode(input); got != want {
+			t.Errorf("mapSNAPBankCode(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
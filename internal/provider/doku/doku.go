@@ -17,9 +17,9 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/pandudpn/go-payment-gateway/internal/utils"
 	"github.com/pandudpn/go-payment-gateway"
 )
 
@@ -32,6 +32,9 @@ const (
 	statusUri          = "/transactions/v2"
 	tokenUri           = "/authorization/v1/access-token/b2b"
 
+	// tokenExpiryMargin is how long before its expiresIn a cached access token is renewed
+	tokenExpiryMargin = time.Minute
+
 	// header names
 	headerSignature = "Signature"
 	headerTimestamp = "Request-Timestamp"
@@ -46,6 +49,29 @@ const (
 	productionURL = "https://api.doku.com"
 )
 
+// amountLimits are the Doku amount limits per payment type, a zero Max has no maximum
+// They are overridden with pg.WithAmountLimits
+var amountLimits = map[pg.PaymentType]pg.AmountLimit{
+	pg.PaymentTypeGoPay:     {Min: 10000, Max: 20000000},
+	pg.PaymentTypeOVO:       {Min: 10000, Max: 10000000},
+	pg.PaymentTypeDANA:      {Min: 10000, Max: 20000000},
+	pg.PaymentTypeShopeePay: {Min: 10000, Max: 20000000},
+	pg.PaymentTypeLinkAja:   {Min: 10000, Max: 10000000},
+	pg.PaymentTypeQRIS:      {Min: 1000, Max: 10000000},
+	pg.PaymentTypeVABCA:     {Min: 10000},
+	pg.PaymentTypeVABNI:     {Min: 10000},
+	pg.PaymentTypeVABRI:     {Min: 10000},
+	pg.PaymentTypeVAMandiri: {Min: 10000},
+	pg.PaymentTypeVAPermata: {Min: 10000},
+	pg.PaymentTypeVACIMB:    {Min: 10000},
+	pg.PaymentTypeKredivo:   {Min: 10000, Max: 30000000},
+	pg.PaymentTypeAkulaku:   {Min: 10000, Max: 15000000},
+	pg.PaymentTypeAtome:     {Min: 10000, Max: 6000000},
+	pg.PaymentTypeIndodana:  {Min: 10000, Max: 25000000},
+	pg.PaymentTypeDDBRI:     {Min: 10000},
+	pg.PaymentTypeDDMandiri: {Min: 10000},
+}
+
 func init() {
 	// Register this provider with the pg package
 	pg.RegisterProvider(ProviderName, New)
@@ -55,6 +81,11 @@ type doku struct {
 	config  *pg.ProviderConfig
 	mapper  *Mapper
 	httpCli *http.Client
+
+	// tokenMu guards the SNAP access token shared by the disbursement and direct debit calls
+	tokenMu     sync.Mutex
+	token       string
+	tokenExpiry time.Time
 }
 
 // New creates a new Doku provider
@@ -66,7 +97,7 @@ func New(cfg *pg.ProviderConfig) (pg.Provider, error) {
 	return &doku{
 		config:  cfg,
 		mapper:  &Mapper{},
-		httpCli: &http.Client{Timeout: getTimeout(cfg)},
+		httpCli: &http.Client{Timeout: getTimeout(cfg), Transport: cfg.Transport},
 	}, nil
 }
 
@@ -82,8 +113,12 @@ func (d *doku) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.Ch
 		return nil, err
 	}
 
+	if params.PaymentType.IsDirectDebit() {
+		return d.createDirectDebitCharge(ctx, params)
+	}
+
 	req := d.mapper.mapToGenerateRequest(params)
-	responseBody, err := d.generatePayment(ctx, req)
+	responseBody, err := d.generatePayment(ctx, pg.WithCustom(req, params.Custom))
 	if err != nil {
 		return nil, err
 	}
@@ -101,51 +136,8 @@ func (d *doku) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.Ch
 }
 
 // generatePayment initiates a payment
-func (d *doku) generatePayment(ctx context.Context, params *GeneratePaymentRequest) ([]byte, error) {
-	baseURL := d.getBaseURL()
-	fullURL := baseURL + generatePaymentUri
-
-	bodyBytes, err := json.Marshal(params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// Generate ISO8601 timestamp
-	timestamp := time.Now().UTC().Format(time.RFC3339)
-	// Generate unique request ID
-	requestID := generateRequestID()
-
-	digest := d.generateDigest(bodyBytes)
-	signature := d.generateSignature(digest, timestamp, requestID, generatePaymentUri)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set(headerClientID, d.config.ClientKey)
-	req.Header.Set(headerRequestID, requestID)
-	req.Header.Set(headerTimestamp, timestamp)
-	req.Header.Set(headerSignature, signature)
-
-	resp, err := d.httpCli.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
-	}
-
-	return responseBody, nil
+func (d *doku) generatePayment(ctx context.Context, params interface{}) ([]byte, error) {
+	return d.sendSignedRequest(ctx, http.MethodPost, generatePaymentUri, params)
 }
 
 // GetStatus retrieves payment status
@@ -286,6 +278,7 @@ func (d *doku) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
 		Amount:        amount,
 		EventType:     d.mapper.mapEventType(PaymentStatus(status)),
 		Timestamp:     timestamp,
+		Metadata:      d.mapper.mapMetadata(webhookData["additional_info"]),
 		Raw:           webhookData,
 	}, nil
 }
@@ -364,12 +357,138 @@ func (d *doku) GetToken(ctx context.Context) (*pg.TokenResponse, error) {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	accessToken := tokenResp.AccessToken
+	if accessToken == "" {
+		accessToken = tokenResp.Token
+	}
+
 	return &pg.TokenResponse{
-		AccessToken: tokenResp.Token,
+		AccessToken: accessToken,
 		TokenType:   "Bearer",
+		ExpiresIn:   tokenResp.ExpiresIn,
 	}, nil
 }
 
+// accessToken returns the cached SNAP access token, requesting a new one with GetToken
+// once it is within tokenExpiryMargin of its expiresIn
+func (d *doku) accessToken(ctx context.Context) (string, error) {
+	d.tokenMu.Lock()
+	defer d.tokenMu.Unlock()
+
+	if d.token != "" && time.Now().Before(d.tokenExpiry) {
+		return d.token, nil
+	}
+
+	token, err := d.GetToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	d.token = ""
+	if expiresIn := time.Duration(token.ExpiresIn) * time.Second; expiresIn > tokenExpiryMargin {
+		d.token = token.AccessToken
+		d.tokenExpiry = time.Now().Add(expiresIn - tokenExpiryMargin)
+	}
+
+	return token.AccessToken, nil
+}
+
+// Capabilities returns the payment types and operations supported by Doku
+// Doku has no cancel API, unpaid payments expire on their own
+func (d *doku) Capabilities() pg.Capabilities {
+	types := []pg.PaymentType{
+		pg.PaymentTypeGoPay, pg.PaymentTypeOVO, pg.PaymentTypeDANA, pg.PaymentTypeShopeePay, pg.PaymentTypeLinkAja,
+		pg.PaymentTypeQRIS,
+		pg.PaymentTypeVABCA, pg.PaymentTypeVABNI, pg.PaymentTypeVABRI, pg.PaymentTypeVAMandiri, pg.PaymentTypeVAPermata, pg.PaymentTypeVACIMB,
+		pg.PaymentTypeKredivo, pg.PaymentTypeAkulaku, pg.PaymentTypeAtome, pg.PaymentTypeIndodana,
+		pg.PaymentTypeDDBRI, pg.PaymentTypeDDMandiri,
+	}
+
+	return pg.Capabilities{
+		PaymentTypes: types,
+		Operations:   []pg.Operation{pg.OperationToken},
+		Limits:       pg.NewLimits(types, amountLimits, d.config.AmountLimits),
+		Currencies:   []string{"IDR"},
+		SandboxURL:   true,
+	}
+}
+
+// Do sends a request signed with the Client-Id, Request-Id and Digest headers and decodes the response into out
+// SNAP endpoints use an access token and a different signature, they are not supported
+func (d *doku) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	responseBody, err := d.sendSignedRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	if out == nil || len(responseBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(responseBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// sendSignedRequest sends a request with the Doku HMAC-SHA256 signature headers and returns the response body
+// The Digest is only part of the signature when there is a body
+func (d *doku) sendSignedRequest(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	var bodyBytes []byte
+	var digest string
+	if payload != nil {
+		var err error
+		bodyBytes, err = json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		digest = d.generateDigest(bodyBytes)
+	}
+
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	requestID := generateRequestID()
+	signature := d.generateSignature(digest, timestamp, requestID, path)
+
+	req, err := http.NewRequestWithContext(ctx, method, d.getBaseURL()+path, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(headerClientID, d.config.ClientKey)
+	req.Header.Set(headerRequestID, requestID)
+	req.Header.Set(headerTimestamp, timestamp)
+	req.Header.Set(headerSignature, signature)
+
+	resp, err := d.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp errorResponse
+		if err := json.Unmarshal(responseBody, &errResp); err == nil {
+			if errResp.Error != nil && errResp.Error.Code != "" {
+				return nil, pg.WrapProviderError(ProviderName, errResp.Error.Code, errResp.Error.Message, nil)
+			}
+			if errResp.ResponseCode != "" {
+				return nil, pg.WrapProviderError(ProviderName, errResp.ResponseCode, errResp.ResponseMessage, nil)
+			}
+		}
+		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+	}
+
+	return responseBody, nil
+}
+
 // parsePrivateKey parses a PEM-encoded RSA private key
 func (d *doku) parsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, error) {
 	// Try to parse as PKCS#1 or PKCS#8
@@ -425,15 +544,17 @@ func (d *doku) generateDigest(body []byte) string {
 // Request-Id:{requestId}
 // Request-Timestamp:{timestamp}
 // Request-Target:{target}
-// Digest:{digest}
+// Digest:{digest} (only for requests with a body)
 func (d *doku) generateSignature(digest, timestamp, requestID, targetPath string) string {
 	// Prepare signature component
 	var component strings.Builder
 	component.WriteString("Client-Id:" + d.config.ClientKey + "\n")
 	component.WriteString("Request-Id:" + requestID + "\n")
 	component.WriteString("Request-Timestamp:" + timestamp + "\n")
-	component.WriteString("Request-Target:" + targetPath + "\n")
-	component.WriteString("Digest:" + digest)
+	component.WriteString("Request-Target:" + targetPath)
+	if digest != "" {
+		component.WriteString("\nDigest:" + digest)
+	}
 
 	// Calculate HMAC-SHA256
 	h := hmac.New(sha256.New, []byte(d.config.ServerKey))
@@ -461,31 +582,32 @@ func randomString(n int) string {
 }
 
 // validateChargeParams validates charge parameters
+// All problems are returned at once in a *pg.ValidationError
 func (d *doku) validateChargeParams(params *pg.ChargeParams) error {
-	// Validate order ID
-	if err := utils.ValidateOrderID(params.OrderID); err != nil {
-		return err
-	}
-
-	// Validate amount
-	if err := utils.MinAmount(params.Amount, params.PaymentType); err != nil {
-		return err
-	}
+	verr := pg.NewValidationError()
+	verr.Merge(params.Validate(d))
 
 	// Validate customer
 	if params.Customer.ID == "" {
-		return pg.NewRequiredFieldError("Customer.ID")
+		verr.Add(pg.NewRequiredFieldError("Customer.ID"))
 	}
-	if err := utils.ValidateEmail(params.Customer.Email, "Customer.Email"); err != nil {
-		return err
+
+	// Direct debit needs a bound account and its refresh token
+	if params.PaymentType.IsDirectDebit() {
+		if params.DirectDebit == nil || params.DirectDebit.AccountLinkID == "" {
+			verr.Add(pg.NewRequiredFieldError("DirectDebit.AccountLinkID"))
+		}
+		if params.DirectDebit == nil || params.DirectDebit.AccountToken == "" {
+			verr.Add(pg.NewRequiredFieldError("DirectDebit.AccountToken"))
+		}
 	}
 
-	// Validate payment type
-	if err := utils.ValidatePaymentType(params.PaymentType); err != nil {
-		return err
+	// Doku VAs are always closed amount
+	if va := params.VirtualAccount; va != nil && (va.OpenAmount || va.MinAmount > 0 || va.MaxAmount > 0) {
+		verr.Add(pg.NewFieldError("VirtualAccount.OpenAmount", fmt.Sprintf("open amount VAs are not supported by %s", ProviderName)))
 	}
 
-	return nil
+	return verr.ToError()
 }
 
 // getTimeout returns the timeout duration
@@ -498,6 +620,9 @@ func getTimeout(cfg *pg.ProviderConfig) time.Duration {
 
 // getBaseURL returns the base URL based on environment
 func (d *doku) getBaseURL() string {
+	if d.config.BaseURL != "" {
+		return d.config.BaseURL
+	}
 	if d.config.Environment == "production" {
 		return productionURL
 	}
@@ -5,8 +5,11 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -79,6 +82,7 @@ func TestDoku_getBaseURL(t *testing.T) {
 	tests := []struct {
 		name     string
 		env      string
+		baseURL  string
 		wantURL  string
 	}{
 		{
@@ -91,6 +95,12 @@ func TestDoku_getBaseURL(t *testing.T) {
 			env:     "production",
 			wantURL: "https://api.doku.com",
 		},
+		{
+			name:    "base URL override",
+			env:     "production",
+			baseURL: "http://localhost:8080",
+			wantURL: "http://localhost:8080",
+		},
 	}
 
 	for _, tt := range tests {
@@ -98,6 +108,7 @@ func TestDoku_getBaseURL(t *testing.T) {
 			provider := &doku{
 				config: &pg.ProviderConfig{
 					Environment: tt.env,
+					BaseURL:     tt.baseURL,
 				},
 			}
 
@@ -108,6 +119,20 @@ func TestDoku_getBaseURL(t *testing.T) {
 	}
 }
 
+func TestDoku_Capabilities(t *testing.T) {
+	caps := (&doku{config: &pg.ProviderConfig{}}).Capabilities()
+
+	if caps.SupportsOperation(pg.OperationCancel) {
+		t.Error("cancel should not be supported")
+	}
+	if !caps.SupportsOperation(pg.OperationToken) {
+		t.Error("token should be supported")
+	}
+	if caps.SupportsPaymentType(pg.PaymentTypeDDBCAOneKlik) || caps.SupportsPaymentType(pg.PaymentTypeCC) {
+		t.Error("BCA OneKlik and credit card should not be supported")
+	}
+}
+
 func TestDoku_generateSignature(t *testing.T) {
 	provider := &doku{
 		config: &pg.ProviderConfig{
@@ -305,6 +330,8 @@ func TestMapper_mapPaymentType(t *testing.T) {
 		{"VA Mandiri", pg.PaymentTypeVAMandiri, PaymentTypeVirtualAccount},
 		{"VA Permata", pg.PaymentTypeVAPermata, PaymentTypeVirtualAccount},
 		{"VA CIMB", pg.PaymentTypeVACIMB, PaymentTypeVirtualAccount},
+		{"Kredivo", pg.PaymentTypeKredivo, PaymentTypePaylater},
+		{"Indodana", pg.PaymentTypeIndodana, PaymentTypePaylater},
 	}
 
 	for _, tt := range tests {
@@ -317,6 +344,28 @@ func TestMapper_mapPaymentType(t *testing.T) {
 	}
 }
 
+func TestMapper_mapToGenerateRequest_Paylater(t *testing.T) {
+	mapper := &Mapper{}
+
+	req := mapper.mapToGenerateRequest(pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      300000,
+		PaymentType: pg.PaymentTypeAkulaku,
+		Customer:    pg.Customer{ID: "CUST-001", Name: "John Doe", Email: "john@example.com"},
+		Paylater:    &pg.PaylaterParams{Tenure: 3},
+	})
+
+	if req.PaymentType != PaymentTypePaylater {
+		t.Errorf("PaymentType = %v, want %v", req.PaymentType, PaymentTypePaylater)
+	}
+	if req.PaymentDetail.Paylater == nil {
+		t.Fatal("Paylater is nil")
+	}
+	if req.PaymentDetail.Paylater.Name != "AKULAKU" || req.PaymentDetail.Paylater.Tenure != 3 {
+		t.Errorf("Paylater = %+v, want AKULAKU for 3 months", req.PaymentDetail.Paylater)
+	}
+}
+
 func TestMapper_mapStatus(t *testing.T) {
 	mapper := &Mapper{}
 
@@ -410,6 +459,20 @@ func TestMapper_mapToGenerateRequest(t *testing.T) {
 				t.Errorf("PaymentType = %v, want %v", result.PaymentType, tt.expectedPMT)
 			}
 
+			// The payment detail carries the amount as a decimal string
+			var amount string
+			switch detail := result.PaymentDetail; {
+			case detail.EWallet != nil:
+				amount = detail.EWallet.Amount
+			case detail.QRCode != nil:
+				amount = detail.QRCode.Amount
+			case detail.VirtualAccount != nil:
+				amount = detail.VirtualAccount.Amount
+			}
+			if want := strconv.FormatInt(tt.params.Amount, 10); amount != want {
+				t.Errorf("PaymentDetail amount = %q, want %q", amount, want)
+			}
+
 			if result.Customer == nil {
 				t.Error("Customer should not be nil")
 			} else {
@@ -424,6 +487,25 @@ func TestMapper_mapToGenerateRequest(t *testing.T) {
 	}
 }
 
+func TestMapper_mapToGenerateRequest_LineItems(t *testing.T) {
+	mapper := &Mapper{}
+
+	req := mapper.mapToGenerateRequest(pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      60000,
+		PaymentType: pg.PaymentTypeVABCA,
+		Items:       []pg.Item{{ID: "ITEM-001", Name: "Product", Price: 50000, Quantity: 1}},
+		Adjustments: []pg.Adjustment{{Type: pg.AdjustmentFee, Amount: 10000}},
+	})
+
+	if len(req.LineItems) != 2 {
+		t.Fatalf("got %d line items, want 2", len(req.LineItems))
+	}
+	if req.LineItems[1].Name != "Fee" || req.LineItems[1].Price != 10000 || req.LineItems[1].Quantity != 1 {
+		t.Errorf("LineItems[1] = %+v, want Fee 10000", req.LineItems[1])
+	}
+}
+
 func TestMapper_mapToChargeResponse(t *testing.T) {
 	mapper := &Mapper{}
 
@@ -740,6 +822,48 @@ func TestDoku_GetStatus(t *testing.T) {
 	}
 }
 
+func TestDoku_GetToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		wantToken     string
+		wantExpiresIn int64
+	}{
+		{
+			name:          "SNAP response",
+			body:          `{"responseCode":"2007300","responseMessage":"Successful","accessToken":"snap-token","tokenType":"Bearer","expiresIn":900}`,
+			wantToken:     "snap-token",
+			wantExpiresIn: 900,
+		},
+		{
+			name:      "legacy response",
+			body:      `{"token":"legacy-token"}`,
+			wantToken: "legacy-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tokenUri {
+					t.Errorf("path = %v, want %v", r.URL.Path, tokenUri)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			token, err := newTestProvider(t, server).GetToken(context.Background())
+			if err != nil {
+				t.Fatalf("GetToken() error = %v", err)
+			}
+			if token.AccessToken != tt.wantToken || token.ExpiresIn != tt.wantExpiresIn {
+				t.Errorf("GetToken() = %+v, want %s expiring in %d", token, tt.wantToken, tt.wantExpiresIn)
+			}
+		})
+	}
+}
+
 func TestDoku_Cancel(t *testing.T) {
 	provider := &doku{
 		config: &pg.ProviderConfig{},
@@ -755,3 +879,55 @@ func TestDoku_Cancel(t *testing.T) {
 		t.Errorf("error message should mention not supported, got: %v", err)
 	}
 }
+
+func TestDoku_Do(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headerClientID) != "BRN-0001" || r.Header.Get(headerRequestID) == "" {
+			t.Errorf("missing Client-Id or Request-Id header")
+		}
+
+		// Recompute the signature the way Doku does, the Digest is only signed with a body
+		component := "Client-Id:BRN-0001\nRequest-Id:" + r.Header.Get(headerRequestID) +
+			"\nRequest-Timestamp:" + r.Header.Get(headerTimestamp) +
+			"\nRequest-Target:" + r.URL.Path
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			digest := sha256.Sum256(body)
+			component += "\nDigest:" + base64.StdEncoding.EncodeToString(digest[:])
+		}
+		h := hmac.New(sha256.New, []byte("SK-secret"))
+		h.Write([]byte(component))
+		if want := "HMACSHA256=" + base64.StdEncoding.EncodeToString(h.Sum(nil)); r.Header.Get(headerSignature) != want {
+			t.Errorf("%s Signature = %v, want %v", r.Method, r.Header.Get(headerSignature), want)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": "invalid_parameter", "message": "Invalid amount"}}`))
+			return
+		}
+		w.Write([]byte(`{"order": {"invoice_number": "ORDER-001", "amount": 50000}}`))
+	}))
+	defer server.Close()
+
+	d := newTestProvider(t, server)
+
+	var out struct {
+		Order struct {
+			InvoiceNumber string `json:"invoice_number"`
+		} `json:"order"`
+	}
+	if err := d.Do(context.Background(), http.MethodGet, "/orders/v1/status/ORDER-001", nil, &out); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if out.Order.InvoiceNumber != "ORDER-001" {
+		t.Errorf("InvoiceNumber = %v, want ORDER-001", out.Order.InvoiceNumber)
+	}
+
+	err := d.Do(context.Background(), http.MethodPost, "/checkout/v1/payment", map[string]interface{}{"order": map[string]int64{"amount": -1}}, nil)
+	var providerErr *pg.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Code != "invalid_parameter" {
+		t.Errorf("Do() error = %v, want provider error invalid_parameter", err)
+	}
+}
@@ -1,6 +1,7 @@
 package doku
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/pandudpn/go-payment-gateway"
@@ -18,6 +19,8 @@ func (m *Mapper) mapPaymentType(pt pg.PaymentType) PaymentType {
 		return PaymentTypeQRCode
 	case pg.PaymentTypeVABCA, pg.PaymentTypeVABNI, pg.PaymentTypeVABRI, pg.PaymentTypeVAMandiri, pg.PaymentTypeVAPermata, pg.PaymentTypeVACIMB:
 		return PaymentTypeVirtualAccount
+	case pg.PaymentTypeKredivo, pg.PaymentTypeAkulaku, pg.PaymentTypeAtome, pg.PaymentTypeIndodana:
+		return PaymentTypePaylater
 	default:
 		return PaymentTypeVirtualAccount
 	}
@@ -39,6 +42,24 @@ func (m *Mapper) mapStatus(status PaymentStatus) pg.Status {
 	}
 }
 
+// mapAddress maps a unified address to a Doku address
+func (m *Mapper) mapAddress(address *pg.Address) *Address {
+	if address == nil {
+		return nil
+	}
+
+	firstName, lastName := pg.SplitName(address.Name)
+	return &Address{
+		FirstName:   firstName,
+		LastName:    lastName,
+		Address:     address.Street(),
+		City:        address.City,
+		PostalCode:  address.PostalCode,
+		Phone:       address.Phone,
+		CountryCode: address.Country(),
+	}
+}
+
 // mapToGenerateRequest maps unified ChargeParams to Doku GeneratePaymentRequest
 func (m *Mapper) mapToGenerateRequest(params pg.ChargeParams) *GeneratePaymentRequest {
 	paymentType := m.mapPaymentType(params.PaymentType)
@@ -48,6 +69,7 @@ func (m *Mapper) mapToGenerateRequest(params pg.ChargeParams) *GeneratePaymentRe
 		TransactionID: params.OrderID,
 		PaymentType:   paymentType,
 		TransactionDate: time.Now(),
+		AdditionalInfo: params.Metadata,
 		Locale:        "en",
 	}
 
@@ -59,6 +81,30 @@ func (m *Mapper) mapToGenerateRequest(params pg.ChargeParams) *GeneratePaymentRe
 			Phone: params.Customer.Phone,
 			ID:    params.Customer.ID,
 		}
+
+		// The customer address is the billing address
+		if a := params.Customer.BillingAddress; a != nil {
+			req.Customer.Address = a.Street()
+			req.Customer.City = a.City
+			req.Customer.State = a.State
+			req.Customer.Postcode = a.PostalCode
+			req.Customer.Country = a.Country()
+		}
+	}
+
+	// Set billing and shipping addresses
+	req.BillingAddress = m.mapAddress(params.Customer.Recipient(params.Customer.BillingAddress))
+	req.ShippingAddress = m.mapAddress(params.Customer.Recipient(params.Customer.ShippingAddress))
+
+	// Set line items, adjustments follow the items
+	for _, item := range params.LineItems() {
+		req.LineItems = append(req.LineItems, &LineItem{
+			ID:       item.ID,
+			Name:     item.Name,
+			Price:    item.Price,
+			Quantity: item.Quantity,
+			Category: item.Category,
+		})
 	}
 
 	// Set payment detail based on type
@@ -79,12 +125,26 @@ func (m *Mapper) mapToGenerateRequest(params pg.ChargeParams) *GeneratePaymentRe
 			Amount: formatAmount(params.Amount),
 			QRType: "DYNAMIC",
 		}
+	} else if paymentType == PaymentTypePaylater {
+		req.PaymentDetail.Paylater = &PaylaterComponent{
+			Name:   string(params.PaymentType),
+			Amount: formatAmount(params.Amount),
+		}
+		if params.Paylater != nil {
+			req.PaymentDetail.Paylater.Tenure = params.Paylater.Tenure
+		}
 	} else if paymentType == PaymentTypeVirtualAccount {
 		req.PaymentDetail.VirtualAccount = &VAComponent{
 			Name:   "VIRTUAL_ACCOUNT",
 			VaType: string(params.PaymentType),
 			Amount: formatAmount(params.Amount),
 		}
+		if va := params.VirtualAccount; va != nil {
+			req.PaymentDetail.VirtualAccount.VANumber = va.VANumber
+			if va.Reusable {
+				req.PaymentDetail.PaymentMethod = &PaymentMethod{Reus: true}
+			}
+		}
 	}
 
 	return req
@@ -104,9 +164,17 @@ func (m *Mapper) mapToChargeResponse(resp *GeneratePaymentResponse, paymentType
 		PaymentURL:    resp.PaymentURL,
 		VANumber:      resp.VANumber,
 		VABank:        resp.VABank,
+		QRString:      resp.QRString,
 		CreatedAt:     time.Now(),
 	}
 
+	if resp.PaymentURL != "" {
+		unified.NextActions = append(unified.NextActions, pg.Action{Type: pg.ActionRedirect, URL: resp.PaymentURL, Method: "GET"})
+	}
+	if resp.QRString != "" {
+		unified.NextActions = append(unified.NextActions, pg.Action{Type: pg.ActionShowQR, QRString: resp.QRString})
+	}
+
 	return unified
 }
 
@@ -128,7 +196,24 @@ func (m *Mapper) mapToPaymentStatus(orderID string, resp *TransactionStatusRespo
 		Amount:        resp.OrderAmount,
 		PaidAmount:    resp.OrderAmount,
 		PaidAt:        paidAt,
+		Metadata:      resp.AdditionalInfo,
+	}
+}
+
+// mapMetadata converts the additional_info object of a notification, non-string values are dropped
+func (m *Mapper) mapMetadata(v interface{}) map[string]string {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(obj) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]string, len(obj))
+	for k, val := range obj {
+		if str, ok := val.(string); ok {
+			metadata[k] = str
+		}
 	}
+	return metadata
 }
 
 // mapEventType maps Doku status to event type
@@ -149,5 +234,202 @@ func (m *Mapper) mapEventType(status PaymentStatus) string {
 
 // formatAmount formats amount as string for Doku
 func formatAmount(amount int64) string {
-	return string(rune(amount))
+	return strconv.FormatInt(amount, 10)
+}
+
+// mapSNAPBankCode maps a bank code to the BI bank code used by SNAP transfers
+// Codes missing from the bank registry are passed through as is
+func (m *Mapper) mapSNAPBankCode(bankCode string) string {
+	if bank, ok := pg.LookupBank(bankCode); ok {
+		return bank.BICode
+	}
+	return bankCode
+}
+
+// mapTransferStatus maps SNAP latestTransactionStatus to unified status
+func (m *Mapper) mapTransferStatus(status string) pg.Status {
+	switch status {
+	case "00":
+		return pg.StatusSuccess
+	case "01", "02":
+		return pg.StatusProcessing
+	case "04", "05":
+		return pg.StatusCancelled
+	case "06":
+		return pg.StatusFailed
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapToTransferInterbankRequest maps unified DisbursementParams to SNAP TransferInterbankRequest
+func (m *Mapper) mapToTransferInterbankRequest(params pg.DisbursementParams, transactionDate string) *TransferInterbankRequest {
+	req := &TransferInterbankRequest{
+		PartnerReferenceNo: params.ReferenceID,
+		Amount: SNAPAmount{
+			Value:    formatSNAPAmount(params.Amount),
+			Currency: "IDR",
+		},
+		BeneficiaryAccountName: params.AccountHolder,
+		BeneficiaryAccountNo:   params.AccountNumber,
+		BeneficiaryBankCode:    m.mapSNAPBankCode(params.BankCode),
+		BeneficiaryEmail:       params.Email,
+		TransactionDate:        transactionDate,
+	}
+
+	if params.Description != "" {
+		req.AdditionalInfo = map[string]interface{}{
+			"remark": params.Description,
+		}
+	}
+
+	return req
+}
+
+// mapToDisbursement maps SNAP TransferInterbankResponse to unified Disbursement
+// The ID is the partnerReferenceNo, which is what the status inquiry takes
+func (m *Mapper) mapToDisbursement(params pg.DisbursementParams, resp *TransferInterbankResponse, raw map[string]interface{}) *pg.Disbursement {
+	if resp == nil {
+		return nil
+	}
+
+	return &pg.Disbursement{
+		ID:            params.ReferenceID,
+		ReferenceID:   params.ReferenceID,
+		BankCode:      params.BankCode,
+		AccountNumber: params.AccountNumber,
+		AccountHolder: params.AccountHolder,
+		Amount:        params.Amount,
+		Description:   params.Description,
+		Status:        pg.StatusProcessing,
+		Raw:           raw,
+	}
+}
+
+// mapTransferStatusToDisbursement maps SNAP TransferStatusResponse to unified Disbursement
+func (m *Mapper) mapTransferStatusToDisbursement(resp *TransferStatusResponse, raw map[string]interface{}) *pg.Disbursement {
+	if resp == nil {
+		return nil
+	}
+
+	amount, _ := strconv.ParseFloat(resp.Amount.Value, 64)
+
+	d := &pg.Disbursement{
+		ID:            resp.OriginalPartnerReferenceNo,
+		ReferenceID:   resp.OriginalPartnerReferenceNo,
+		BankCode:      resp.BeneficiaryBankCode,
+		AccountNumber: resp.BeneficiaryAccountNo,
+		Amount:        int64(amount),
+		Status:        m.mapTransferStatus(resp.LatestTransactionStatus),
+		Raw:           raw,
+	}
+
+	if d.Status == pg.StatusFailed {
+		d.FailureReason = resp.TransactionStatusDesc
+	}
+
+	return d
+}
+
+// formatSNAPAmount formats amount with two decimals as required by SNAP
+func formatSNAPAmount(amount int64) string {
+	return strconv.FormatInt(amount, 10) + ".00"
+}
+
+// mapDirectDebitChannel maps a unified direct debit payment type to a SNAP direct debit channel
+func (m *Mapper) mapDirectDebitChannel(pt pg.PaymentType) string {
+	switch pt {
+	case pg.PaymentTypeDDBRI:
+		return "DIRECT_DEBIT_BRI_SNAP"
+	case pg.PaymentTypeDDMandiri:
+		return "DIRECT_DEBIT_MANDIRI_SNAP"
+	default:
+		return ""
+	}
+}
+
+// mapToAccountBindingRequest maps unified LinkParams to a SNAP account binding request
+func (m *Mapper) mapToAccountBindingRequest(params pg.LinkParams) *AccountBindingRequest {
+	return &AccountBindingRequest{
+		PhoneNo: params.Customer.Phone,
+		AdditionalInfo: map[string]interface{}{
+			"channel":                m.mapDirectDebitChannel(params.PaymentType),
+			"custIdMerchant":         params.Customer.ID,
+			"customerName":           params.Customer.Name,
+			"email":                  params.Customer.Email,
+			"successRegistrationUrl": params.RedirectURL,
+			"failedRegistrationUrl":  params.RedirectURL,
+		},
+	}
+}
+
+// mapToPendingLinkedAccount maps a SNAP account binding response to a pending unified LinkedAccount
+func (m *Mapper) mapToPendingLinkedAccount(resp *AccountBindingResponse, paymentType pg.PaymentType, raw map[string]interface{}) *pg.LinkedAccount {
+	account := &pg.LinkedAccount{
+		ID:          resp.ReferenceNo,
+		PaymentType: paymentType,
+		Status:      pg.LinkPending,
+		ActionURL:   resp.RedirectURL,
+		Raw:         raw,
+	}
+
+	if resp.RedirectURL != "" {
+		account.NextActions = []pg.Action{{Type: pg.ActionRedirect, URL: resp.RedirectURL, Method: "GET"}}
+	}
+
+	return account
+}
+
+// mapToDirectDebitPaymentRequest maps unified ChargeParams to a SNAP direct debit payment request
+func (m *Mapper) mapToDirectDebitPaymentRequest(params pg.ChargeParams) *DirectDebitPaymentRequest {
+	items := params.LineItems()
+	lineItems := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		lineItems = append(lineItems, map[string]interface{}{
+			"name":     item.Name,
+			"price":    formatSNAPAmount(item.Price),
+			"quantity": item.Quantity,
+		})
+	}
+
+	req := &DirectDebitPaymentRequest{
+		PartnerReferenceNo: params.OrderID,
+		Amount: SNAPAmount{
+			Value:    formatSNAPAmount(params.Amount),
+			Currency: "IDR",
+		},
+		AdditionalInfo: map[string]interface{}{
+			"channel":           m.mapDirectDebitChannel(params.PaymentType),
+			"remarks":           params.Description,
+			"successPaymentUrl": params.ReturnURL,
+			"failedPaymentUrl":  params.ReturnURL,
+			"lineItems":         lineItems,
+		},
+	}
+	if len(params.Metadata) > 0 {
+		req.AdditionalInfo["metadata"] = params.Metadata
+	}
+
+	return req
+}
+
+// mapToChargeResponseFromDirectDebit maps a SNAP direct debit payment response to unified ChargeResponse
+// A pending host-to-host debit without a redirect URL waits for the OTP sent to the customer
+func (m *Mapper) mapToChargeResponseFromDirectDebit(resp *DirectDebitPaymentResponse, params pg.ChargeParams, raw map[string]interface{}) *pg.ChargeResponse {
+	unified := &pg.ChargeResponse{
+		TransactionID: resp.ReferenceNo,
+		OrderID:       params.OrderID,
+		Amount:        params.Amount,
+		Status:        pg.StatusPending,
+		PaymentURL:    resp.WebRedirectURL,
+		Raw:           raw,
+	}
+
+	if resp.WebRedirectURL != "" {
+		unified.NextActions = []pg.Action{{Type: pg.ActionRedirect, URL: resp.WebRedirectURL, Method: "GET"}}
+	} else {
+		unified.NextActions = []pg.Action{{Type: pg.ActionValidateOTP}}
+	}
+
+	return unified
 }
@@ -70,15 +70,44 @@ type GeneratePaymentRequest struct {
 	Customer        *Customer       `json:"customer,omitempty"`
 	PaymentType     PaymentType     `json:"payment_type"`
 	PaymentDetail   *PaymentDetail  `json:"payment_detail,omitempty"`
+	LineItems       []*LineItem     `json:"line_items,omitempty"`
+	BillingAddress  *Address        `json:"billing_address,omitempty"`
+	ShippingAddress *Address        `json:"shipping_address,omitempty"`
+	AdditionalInfo  map[string]string `json:"additional_info,omitempty"`
 	Locale          string          `json:"locale,omitempty"`
 }
 
+// LineItem represents an order line, discounts have a negative price
+type LineItem struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Price    int64  `json:"price"`
+	Quantity int64  `json:"quantity"`
+	Category string `json:"category,omitempty"`
+}
+
 // Customer represents customer details
 type Customer struct {
-	Name    string `json:"name"`
-	Email   string `json:"email"`
-	Phone   string `json:"phone"`
-	ID      string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	ID       string `json:"id,omitempty"`
+	Address  string `json:"address,omitempty"`
+	City     string `json:"city,omitempty"`
+	State    string `json:"state,omitempty"`
+	Postcode string `json:"postcode,omitempty"`
+	Country  string `json:"country,omitempty"`
+}
+
+// Address represents a billing or shipping address
+type Address struct {
+	FirstName   string `json:"first_name,omitempty"`
+	LastName    string `json:"last_name,omitempty"`
+	Address     string `json:"address,omitempty"`
+	City        string `json:"city,omitempty"`
+	PostalCode  string `json:"postal_code,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
 }
 
 // PaymentDetail represents payment details
@@ -118,6 +147,16 @@ type TransactionStatusRequest struct {
 	TransactionID string `json:"transaction_id"`
 }
 
+// errorResponse is the error body of the non-SNAP APIs
+type errorResponse struct {
+	ResponseCode    string `json:"response_code,omitempty"`
+	ResponseMessage string `json:"response_message,omitempty"`
+	Error           *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
 // TransactionStatusResponse from Doku
 type TransactionStatusResponse struct {
 	ResponseCode      string         `json:"response_code"`
@@ -127,9 +166,130 @@ type TransactionStatusResponse struct {
 	TransactionStatus PaymentStatus  `json:"transaction_status,omitempty"`
 	PaymentType       PaymentType    `json:"payment_type,omitempty"`
 	PaymentDate       *time.Time     `json:"payment_date,omitempty"`
+	AdditionalInfo    map[string]string `json:"additional_info,omitempty"`
 }
 
 // tokenResponse from Doku Get Token API
+// The SNAP endpoint returns accessToken, older responses carry token
 type tokenResponse struct {
-	Token string `json:"token"`
+	AccessToken string `json:"accessToken"`
+	Token       string `json:"token"`
+	ExpiresIn   int64  `json:"expiresIn"`
+}
+
+// SNAPAmount is an amount object of the SNAP API
+type SNAPAmount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// TransferInterbankRequest for SNAP Transfer Interbank
+type TransferInterbankRequest struct {
+	PartnerReferenceNo     string                 `json:"partnerReferenceNo"`
+	Amount                 SNAPAmount             `json:"amount"`
+	BeneficiaryAccountName string                 `json:"beneficiaryAccountName"`
+	BeneficiaryAccountNo   string                 `json:"beneficiaryAccountNo"`
+	BeneficiaryBankCode    string                 `json:"beneficiaryBankCode"`
+	BeneficiaryEmail       string                 `json:"beneficiaryEmail,omitempty"`
+	SourceAccountNo        string                 `json:"sourceAccountNo,omitempty"`
+	TransactionDate        string                 `json:"transactionDate"`
+	AdditionalInfo         map[string]interface{} `json:"additionalInfo,omitempty"`
+}
+
+// TransferInterbankResponse from SNAP Transfer Interbank
+type TransferInterbankResponse struct {
+	ResponseCode           string     `json:"responseCode"`
+	ResponseMessage        string     `json:"responseMessage"`
+	ReferenceNo            string     `json:"referenceNo,omitempty"`
+	PartnerReferenceNo     string     `json:"partnerReferenceNo,omitempty"`
+	Amount                 SNAPAmount `json:"amount"`
+	BeneficiaryAccountNo   string     `json:"beneficiaryAccountNo,omitempty"`
+	BeneficiaryAccountName string     `json:"beneficiaryAccountName,omitempty"`
+	BeneficiaryBankCode    string     `json:"beneficiaryBankCode,omitempty"`
+}
+
+// TransferStatusRequest for SNAP Transfer Status Inquiry
+type TransferStatusRequest struct {
+	OriginalPartnerReferenceNo string `json:"originalPartnerReferenceNo"`
+	ServiceCode                string `json:"serviceCode"`
+}
+
+// TransferStatusResponse from SNAP Transfer Status Inquiry
+type TransferStatusResponse struct {
+	ResponseCode               string     `json:"responseCode"`
+	ResponseMessage            string     `json:"responseMessage"`
+	OriginalReferenceNo        string     `json:"originalReferenceNo,omitempty"`
+	OriginalPartnerReferenceNo string     `json:"originalPartnerReferenceNo,omitempty"`
+	ServiceCode                string     `json:"serviceCode,omitempty"`
+	Amount                     SNAPAmount `json:"amount"`
+	BeneficiaryAccountNo       string     `json:"beneficiaryAccountNo,omitempty"`
+	BeneficiaryBankCode        string     `json:"beneficiaryBankCode,omitempty"`
+	LatestTransactionStatus    string     `json:"latestTransactionStatus"`
+	TransactionStatusDesc      string     `json:"transactionStatusDesc,omitempty"`
+}
+
+// CustomerTokenRequest for SNAP B2B2C access token
+type CustomerTokenRequest struct {
+	GrantType    string `json:"grantType"`
+	AuthCode     string `json:"authCode,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+// CustomerTokenResponse from SNAP B2B2C access token
+type CustomerTokenResponse struct {
+	ResponseCode           string `json:"responseCode"`
+	ResponseMessage        string `json:"responseMessage"`
+	AccessToken            string `json:"accessToken"`
+	TokenType              string `json:"tokenType"`
+	AccessTokenExpiryTime  string `json:"accessTokenExpiryTime"`
+	RefreshToken           string `json:"refreshToken"`
+	RefreshTokenExpiryTime string `json:"refreshTokenExpiryTime"`
+}
+
+// AccountBindingRequest for SNAP direct debit Account Binding
+type AccountBindingRequest struct {
+	PhoneNo        string                 `json:"phoneNo"`
+	AdditionalInfo map[string]interface{} `json:"additionalInfo"`
+}
+
+// AccountBindingResponse from SNAP direct debit Account Binding
+type AccountBindingResponse struct {
+	ResponseCode    string                 `json:"responseCode"`
+	ResponseMessage string                 `json:"responseMessage"`
+	ReferenceNo     string                 `json:"referenceNo,omitempty"`
+	RedirectURL     string                 `json:"redirectUrl,omitempty"`
+	AdditionalInfo  map[string]interface{} `json:"additionalInfo,omitempty"`
+}
+
+// AccountUnbindingRequest for SNAP direct debit Account Unbinding
+type AccountUnbindingRequest struct {
+	TokenID        string                 `json:"tokenId"`
+	AdditionalInfo map[string]interface{} `json:"additionalInfo,omitempty"`
+}
+
+// DirectDebitPaymentRequest for SNAP direct debit Payment
+type DirectDebitPaymentRequest struct {
+	PartnerReferenceNo string                 `json:"partnerReferenceNo"`
+	Amount             SNAPAmount             `json:"amount"`
+	AdditionalInfo     map[string]interface{} `json:"additionalInfo"`
+}
+
+// DirectDebitPaymentResponse from SNAP direct debit Payment and OTP Verification
+type DirectDebitPaymentResponse struct {
+	ResponseCode        string                 `json:"responseCode"`
+	ResponseMessage     string                 `json:"responseMessage"`
+	ReferenceNo         string                 `json:"referenceNo,omitempty"`
+	PartnerReferenceNo  string                 `json:"partnerReferenceNo,omitempty"`
+	OriginalReferenceNo string                 `json:"originalReferenceNo,omitempty"`
+	WebRedirectURL      string                 `json:"webRedirectUrl,omitempty"`
+	Amount              *SNAPAmount            `json:"amount,omitempty"`
+	AdditionalInfo      map[string]interface{} `json:"additionalInfo,omitempty"`
+}
+
+// OTPVerificationRequest for SNAP direct debit OTP Verification
+type OTPVerificationRequest struct {
+	OriginalPartnerReferenceNo string                 `json:"originalPartnerReferenceNo"`
+	OriginalReferenceNo        string                 `json:"originalReferenceNo"`
+	OTP                        string                 `json:"otp"`
+	AdditionalInfo             map[string]interface{} `json:"additionalInfo,omitempty"`
 }
@@ -0,0 +1,75 @@
+package duitku
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+	"github.com/pandudpn/go-payment-gateway/pgtest"
+)
+
+// conformanceStatuses maps the unified statuses to the Duitku transaction status codes
+var conformanceStatuses = map[pg.Status]ResultCode{
+	pg.StatusSuccess: ResultSuccess,
+	pg.StatusFailed:  ResultFailed,
+}
+
+// conformanceCallbackCodes maps the unified statuses to the Duitku callback result codes
+// Callbacks report a failed payment with "01"
+var conformanceCallbackCodes = map[pg.Status]ResultCode{
+	pg.StatusSuccess: ResultSuccess,
+	pg.StatusFailed:  ResultPending,
+}
+
+// jsonHandler answers every request with body and the HTTP status code
+func jsonHandler(code int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	})
+}
+
+func TestDuitku_Conformance(t *testing.T) {
+	pgtest.RunProviderConformance(t, New, pgtest.Stub{
+		Config: pg.ProviderConfig{ServerKey: "test-api-key", MerchantID: "D0001"},
+		Charge: pg.ChargeParams{
+			OrderID:     "ORDER-001",
+			Amount:      150000,
+			PaymentType: pg.PaymentTypeVABCA,
+			Customer:    pg.Customer{ID: "CUST-001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890"},
+			Items:       []pg.Item{{ID: "SKU-001", Name: "Kaos Polos", Price: 75000, Quantity: 2}},
+			CallbackURL: "https://example.com/callback",
+			ReturnURL:   "https://example.com/return",
+		},
+		CreateCharge: jsonHandler(http.StatusOK, `{"merchantCode":"D0001","reference":"D0001ABCDEF123","paymentUrl":"https://sandbox.duitku.com/topup/v2/TopUpCreditCardPayment.aspx?reference=D0001ABCDEF123","vaNumber":"7007014001234567","amount":"150000","statusCode":"00","statusMessage":"SUCCESS"}`),
+		Rejected:     jsonHandler(http.StatusBadRequest, `{"Message":"Minimum Payment 10000 IDR"}`),
+		Statuses:     []pg.Status{pg.StatusSuccess, pg.StatusFailed},
+		Status: func(status pg.Status) http.Handler {
+			return jsonHandler(http.StatusOK, `{"merchantOrderId":"ORDER-001","reference":"D0001ABCDEF123","amount":"150000","fee":"0.00","statusCode":"`+string(conformanceStatuses[status])+`","statusMessage":"SUCCESS"}`)
+		},
+		Webhook: func(status pg.Status, signed bool) *http.Request {
+			key := "test-api-key"
+			if !signed {
+				key = "wrong-key"
+			}
+			form := url.Values{
+				"merchantCode":    {"D0001"},
+				"amount":          {"150000"},
+				"merchantOrderId": {"ORDER-001"},
+				"paymentCode":     {"BC"},
+				"resultCode":      {string(conformanceCallbackCodes[status])},
+				"reference":       {"D0001ABCDEF123"},
+				"signature":       {utils.CalculateMD5("D0001" + "150000" + "ORDER-001" + key)},
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return r
+		},
+	})
+}
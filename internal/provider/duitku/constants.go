@@ -0,0 +1,37 @@
+package duitku
+
+import (
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// ProviderName is the name of the Duitku provider
+	ProviderName = "duitku"
+
+	// API URLs
+	sandboxURL    = "https://sandbox.duitku.com/webapi/api/merchant"
+	productionURL = "https://passport.duitku.com/webapi/api/merchant"
+
+	// POP (Payment Page) URLs
+	popSandboxURL    = "https://api-sandbox.duitku.com/api/merchant"
+	popProductionURL = "https://api-prod.duitku.com/api/merchant"
+)
+
+// amountLimits are the Duitku amount limits per payment type, a zero Max has no maximum
+// They are overridden with pg.WithAmountLimits
+var amountLimits = map[pg.PaymentType]pg.AmountLimit{
+	pg.PaymentTypeOVO:       {Min: 10000, Max: 10000000},
+	pg.PaymentTypeShopeePay: {Min: 10000, Max: 20000000},
+	pg.PaymentTypeDANA:      {Min: 10000, Max: 20000000},
+	pg.PaymentTypeLinkAja:   {Min: 10000, Max: 10000000},
+	pg.PaymentTypeQRIS:      {Min: 1000, Max: 10000000},
+	pg.PaymentTypeVABCA:     {Min: 10000},
+	pg.PaymentTypeVABNI:     {Min: 10000},
+	pg.PaymentTypeVABRI:     {Min: 10000},
+	pg.PaymentTypeVAMandiri: {Min: 10000},
+	pg.PaymentTypeVAPermata: {Min: 10000},
+	pg.PaymentTypeVACIMB:    {Min: 10000},
+	pg.PaymentTypeCC:        {Min: 10000},
+	pg.PaymentTypeAlfamart:  {Min: 10000, Max: 5000000},
+	pg.PaymentTypeIndomaret: {Min: 10000, Max: 5000000},
+}
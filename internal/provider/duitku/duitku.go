@@ -0,0 +1,352 @@
+package duitku
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+)
+
+const (
+	// API endpoints
+	inquiryUri       = "/v2/inquiry"
+	statusUri        = "/transactionStatus"
+	createInvoiceUri = "/createInvoice"
+
+	// header names for POP API
+	headerSignature    = "x-duitku-signature"
+	headerTimestamp    = "x-duitku-timestamp"
+	headerMerchantCode = "x-duitku-merchantcode"
+)
+
+func init() {
+	// Register this provider with the pg package
+	pg.RegisterProvider(ProviderName, New)
+}
+
+type duitku struct {
+	config  *pg.ProviderConfig
+	mapper  *Mapper
+	httpCli *http.Client
+}
+
+// New creates a new Duitku provider
+// ServerKey is the Duitku API key and MerchantID is the Duitku merchant code
+func New(cfg *pg.ProviderConfig) (pg.Provider, error) {
+	if cfg.ServerKey == "" || cfg.MerchantID == "" {
+		return nil, pg.ErrMissingCredentials
+	}
+
+	return &duitku{
+		config:  cfg,
+		mapper:  &Mapper{},
+		httpCli: &http.Client{Timeout: getTimeout(cfg), Transport: cfg.Transport},
+	}, nil
+}
+
+// Name returns the provider name
+func (d *duitku) Name() string {
+	return ProviderName
+}
+
+// CreateCharge creates a new payment transaction
+// Credit card payments use the Duitku POP payment page, other channels use the v2 inquiry API
+func (d *duitku) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	// Validate parameters
+	if err := d.validateChargeParams(&params); err != nil {
+		return nil, err
+	}
+
+	req := d.mapper.mapToInquiryRequest(d.config.MerchantID, params)
+
+	var responseBody []byte
+	var err error
+
+	if params.PaymentType.IsCreditCard() {
+		responseBody, err = d.createInvoice(ctx, pg.WithCustom(req, params.Custom))
+	} else {
+		req.Signature = d.inquirySignature(req.MerchantOrderID, req.PaymentAmount)
+		responseBody, err = d.sendRequest(ctx, d.getBaseURL()+inquiryUri, pg.WithCustom(req, params.Custom), nil)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var resp InquiryResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if resp.StatusCode != string(ResultSuccess) {
+		return nil, pg.WrapProviderError(ProviderName, resp.StatusCode, resp.StatusMessage, nil)
+	}
+
+	return d.mapper.mapToChargeResponse(&resp, params), nil
+}
+
+// createInvoice creates a Duitku POP invoice which returns a hosted payment page
+func (d *duitku) createInvoice(ctx context.Context, req interface{}) ([]byte, error) {
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	headers := map[string]string{
+		headerSignature:    d.popSignature(timestamp),
+		headerTimestamp:    timestamp,
+		headerMerchantCode: d.config.MerchantID,
+	}
+
+	return d.sendRequest(ctx, d.getPOPURL()+createInvoiceUri, req, headers)
+}
+
+// sendRequest sends a JSON POST request to Duitku and returns the response body
+func (d *duitku) sendRequest(ctx context.Context, fullURL string, payload interface{}, headers map[string]string) ([]byte, error) {
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := d.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, apiError(resp.StatusCode, responseBody)
+	}
+
+	return responseBody, nil
+}
+
+// apiError returns the error of a failed response
+// Error bodies carry a Message or a statusMessage, they are returned as *pg.ProviderError
+func apiError(statusCode int, body []byte) error {
+	var errResp struct {
+		Message       string `json:"Message"`
+		StatusCode    string `json:"statusCode"`
+		StatusMessage string `json:"statusMessage"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil {
+		if errResp.StatusMessage != "" {
+			code := errResp.StatusCode
+			if code == "" {
+				code = strconv.Itoa(statusCode)
+			}
+			return pg.WrapProviderError(ProviderName, code, errResp.StatusMessage, nil)
+		}
+		if errResp.Message != "" {
+			return pg.WrapProviderError(ProviderName, strconv.Itoa(statusCode), errResp.Message, nil)
+		}
+	}
+	return fmt.Errorf("API error: status=%d, body=%s", statusCode, string(body))
+}
+
+// GetStatus retrieves payment status
+func (d *duitku) GetStatus(ctx context.Context, orderID string) (*pg.PaymentStatus, error) {
+	req := &TransactionStatusRequest{
+		MerchantCode:    d.config.MerchantID,
+		MerchantOrderID: orderID,
+		Signature:       d.statusSignature(orderID),
+	}
+
+	responseBody, err := d.sendRequest(ctx, d.getBaseURL()+statusUri, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var duitkuResponse TransactionStatusResponse
+	if err := json.Unmarshal(responseBody, &duitkuResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return d.mapper.mapToPaymentStatus(orderID, &duitkuResponse), nil
+}
+
+// Cancel cancels a transaction
+func (d *duitku) Cancel(ctx context.Context, orderID string) error {
+	// Duitku doesn't have a cancel API
+	// The payment will expire automatically based on the expiry period
+	return fmt.Errorf("cancel not supported by Duitku, payment will expire automatically")
+}
+
+// VerifyWebhook verifies callback signature
+// Duitku signature: MD5(merchantCode + amount + merchantOrderId + apiKey)
+func (d *duitku) VerifyWebhook(r *http.Request) bool {
+	if err := r.ParseForm(); err != nil {
+		return false
+	}
+
+	merchantCode := r.FormValue("merchantCode")
+	amount := r.FormValue("amount")
+	orderID := r.FormValue("merchantOrderId")
+	signature := r.FormValue("signature")
+
+	if merchantCode == "" || amount == "" || orderID == "" || signature == "" {
+		return false
+	}
+
+	if merchantCode != d.config.MerchantID {
+		return false
+	}
+
+	expected := d.callbackSignature(amount, orderID)
+	return utils.SecureCompare(signature, expected)
+}
+
+// ParseWebhook parses callback payload
+func (d *duitku) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	orderID := r.FormValue("merchantOrderId")
+	if orderID == "" {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	var amount int64
+	if amountStr := r.FormValue("amount"); amountStr != "" {
+		amount, _ = strconv.ParseInt(amountStr, 10, 64)
+	}
+
+	// Parse settlement date
+	timestamp := time.Now()
+	if settlementDate := r.FormValue("settlementDate"); settlementDate != "" {
+		if parsed, err := time.Parse("2006-01-02", settlementDate); err == nil {
+			timestamp = parsed
+		}
+	}
+
+	status := d.mapper.mapCallbackStatus(ResultCode(r.FormValue("resultCode")))
+
+	// Build raw response map
+	raw := make(map[string]interface{})
+	for key, values := range r.Form {
+		if len(values) == 1 {
+			raw[key] = values[0]
+		} else {
+			raw[key] = values
+		}
+	}
+
+	return &pg.WebhookEvent{
+		OrderID:       orderID,
+		TransactionID: r.FormValue("reference"),
+		Status:        status,
+		Amount:        amount,
+		PaymentType:   d.mapper.unifiedPaymentType(r.FormValue("paymentCode")),
+		EventType:     d.mapper.mapEventType(status),
+		Timestamp:     timestamp,
+		Raw:           raw,
+	}, nil
+}
+
+// GetToken retrieves an access token for the provider
+// Duitku signs each request with the API key, so this returns an error
+func (d *duitku) GetToken(ctx context.Context) (*pg.TokenResponse, error) {
+	return nil, fmt.Errorf("GetToken API is not supported by %s. Duitku signs every request with the API Key", ProviderName)
+}
+
+// Capabilities returns the payment types and operations supported by Duitku
+// Duitku has no cancel API and signs requests instead of issuing tokens
+func (d *duitku) Capabilities() pg.Capabilities {
+	types := []pg.PaymentType{
+		pg.PaymentTypeOVO, pg.PaymentTypeShopeePay, pg.PaymentTypeDANA, pg.PaymentTypeLinkAja,
+		pg.PaymentTypeQRIS,
+		pg.PaymentTypeVABCA, pg.PaymentTypeVABNI, pg.PaymentTypeVABRI, pg.PaymentTypeVAMandiri, pg.PaymentTypeVAPermata, pg.PaymentTypeVACIMB,
+		pg.PaymentTypeCC,
+		pg.PaymentTypeAlfamart, pg.PaymentTypeIndomaret,
+	}
+
+	return pg.Capabilities{
+		PaymentTypes: types,
+		Operations:   []pg.Operation{},
+		Limits:       pg.NewLimits(types, amountLimits, d.config.AmountLimits),
+		Currencies:   []string{"IDR"},
+		SandboxURL:   true,
+	}
+}
+
+// inquirySignature generates signature for inquiry API
+// Format: MD5(merchantCode + merchantOrderId + paymentAmount + apiKey)
+func (d *duitku) inquirySignature(orderID string, amount int64) string {
+	return utils.CalculateMD5(d.config.MerchantID + orderID + strconv.FormatInt(amount, 10) + d.config.ServerKey)
+}
+
+// statusSignature generates signature for transaction status API
+// Format: MD5(merchantCode + merchantOrderId + apiKey)
+func (d *duitku) statusSignature(orderID string) string {
+	return utils.CalculateMD5(d.config.MerchantID + orderID + d.config.ServerKey)
+}
+
+// callbackSignature generates the expected signature of a callback
+// Format: MD5(merchantCode + amount + merchantOrderId + apiKey)
+func (d *duitku) callbackSignature(amount, orderID string) string {
+	return utils.CalculateMD5(d.config.MerchantID + amount + orderID + d.config.ServerKey)
+}
+
+// popSignature generates signature for POP API
+// Format: SHA256(merchantCode + timestamp + apiKey)
+func (d *duitku) popSignature(timestamp string) string {
+	return utils.CalculateSHA256(d.config.MerchantID + timestamp + d.config.ServerKey)
+}
+
+// validateChargeParams validates charge parameters
+// All problems are returned at once in a *pg.ValidationError
+func (d *duitku) validateChargeParams(params *pg.ChargeParams) error {
+	verr := pg.NewValidationError()
+	verr.Merge(params.Validate(d))
+
+	// Duitku requires both callback and return URL
+	verr.Merge(utils.RequiredString(params.CallbackURL, "CallbackURL"))
+	verr.Merge(utils.RequiredString(params.ReturnURL, "ReturnURL"))
+
+	return verr.ToError()
+}
+
+// getTimeout returns the timeout duration
+func getTimeout(cfg *pg.ProviderConfig) time.Duration {
+	if cfg.Timeout > 0 {
+		return time.Duration(cfg.Timeout) * time.Second
+	}
+	return 30 * time.Second
+}
+
+// getBaseURL returns the base URL based on environment
+func (d *duitku) getBaseURL() string {
+	if d.config.Environment == "production" {
+		return productionURL
+	}
+	return sandboxURL
+}
+
+// getPOPURL returns the POP API base URL based on environment
+func (d *duitku) getPOPURL() string {
+	if d.config.Environment == "production" {
+		return popProductionURL
+	}
+	return popSandboxURL
+}
@@ -0,0 +1,743 @@
+package duitku
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+)
+
+// rewriteTransport sends every request to the test server regardless of the original host
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestProvider creates a Duitku provider which talks to the given test server
+func newTestProvider(t *testing.T, server *httptest.Server) *duitku {
+	t.Helper()
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+
+	return &duitku{
+		config: &pg.ProviderConfig{
+			ServerKey:   "test-api-key",
+			MerchantID:  "D0001",
+			Environment: "sandbox",
+		},
+		mapper: &Mapper{},
+		httpCli: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &rewriteTransport{target: target},
+		},
+	}
+}
+
+func validChargeParams(paymentType pg.PaymentType) pg.ChargeParams {
+	return pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: paymentType,
+		Customer: pg.Customer{
+			ID:    "CUST-001",
+			Name:  "John Doe",
+			Email: "john@example.com",
+			Phone: "+62812345678",
+		},
+		Items: []pg.Item{
+			{
+				ID:       "ITEM-001",
+				Name:     "Test Product",
+				Price:    25000,
+				Quantity: 2,
+			},
+		},
+		CallbackURL: "https://example.com/callback",
+		ReturnURL:   "https://example.com/return",
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *pg.ProviderConfig
+		wantErr bool
+	}{
+		{
+			name: "valid config",
+			config: &pg.ProviderConfig{
+				ServerKey:   "test-api-key",
+				MerchantID:  "D0001",
+				Environment: "sandbox",
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing api key",
+			config: &pg.ProviderConfig{
+				MerchantID: "D0001",
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing merchant code",
+			config: &pg.ProviderConfig{
+				ServerKey: "test-api-key",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := New(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if provider == nil {
+					t.Error("expected provider but got nil")
+				}
+			}
+		})
+	}
+}
+
+func TestDuitku_Name(t *testing.T) {
+	provider := &duitku{
+		config: &pg.ProviderConfig{},
+		mapper: &Mapper{},
+	}
+
+	if name := provider.Name(); name != ProviderName {
+		t.Errorf("Name() = %v, want %v", name, ProviderName)
+	}
+}
+
+func TestDuitku_getBaseURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string
+		wantURL    string
+		wantPOPURL string
+	}{
+		{
+			name:       "sandbox",
+			env:        "sandbox",
+			wantURL:    sandboxURL,
+			wantPOPURL: popSandboxURL,
+		},
+		{
+			name:       "production",
+			env:        "production",
+			wantURL:    productionURL,
+			wantPOPURL: popProductionURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &duitku{
+				config: &pg.ProviderConfig{
+					Environment: tt.env,
+				},
+			}
+
+			if got := provider.getBaseURL(); got != tt.wantURL {
+				t.Errorf("getBaseURL() = %v, want %v", got, tt.wantURL)
+			}
+			if got := provider.getPOPURL(); got != tt.wantPOPURL {
+				t.Errorf("getPOPURL() = %v, want %v", got, tt.wantPOPURL)
+			}
+		})
+	}
+}
+
+func TestDuitku_signatures(t *testing.T) {
+	provider := &duitku{
+		config: &pg.ProviderConfig{
+			ServerKey:  "test-api-key",
+			MerchantID: "D0001",
+		},
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "inquiry",
+			got:  provider.inquirySignature("ORDER-001", 50000),
+			want: utils.CalculateMD5("D0001ORDER-00150000test-api-key"),
+		},
+		{
+			name: "status",
+			got:  provider.statusSignature("ORDER-001"),
+			want: utils.CalculateMD5("D0001ORDER-001test-api-key"),
+		},
+		{
+			name: "callback",
+			got:  provider.callbackSignature("50000", "ORDER-001"),
+			want: utils.CalculateMD5("D000150000ORDER-001test-api-key"),
+		},
+		{
+			name: "pop",
+			got:  provider.popSignature("1700000000000"),
+			want: utils.CalculateSHA256("D00011700000000000test-api-key"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("signature = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuitku_CreateCharge(t *testing.T) {
+	tests := []struct {
+		name         string
+		paymentType  pg.PaymentType
+		wantPath     string
+		wantMethod   PaymentMethod
+		mockResponse string
+		wantErr      bool
+		check        func(t *testing.T, resp *pg.ChargeResponse)
+	}{
+		{
+			name:        "VA BCA",
+			paymentType: pg.PaymentTypeVABCA,
+			wantPath:    "/webapi/api/merchant/v2/inquiry",
+			wantMethod:  MethodVABCA,
+			mockResponse: `{
+				"merchantCode": "D0001",
+				"reference": "D0001ABC123",
+				"paymentUrl": "https://sandbox.duitku.com/topup/v2/TopUpCreditCardPayment.aspx?ref=BC",
+				"vaNumber": "7007014001444348",
+				"amount": "50000",
+				"statusCode": "00",
+				"statusMessage": "SUCCESS"
+			}`,
+			check: func(t *testing.T, resp *pg.ChargeResponse) {
+				if resp.VANumber != "7007014001444348" {
+					t.Errorf("VANumber = %v, want 7007014001444348", resp.VANumber)
+				}
+				if resp.TransactionID != "D0001ABC123" {
+					t.Errorf("TransactionID = %v, want D0001ABC123", resp.TransactionID)
+				}
+			},
+		},
+		{
+			name:        "OVO",
+			paymentType: pg.PaymentTypeOVO,
+			wantPath:    "/webapi/api/merchant/v2/inquiry",
+			wantMethod:  MethodOVO,
+			mockResponse: `{
+				"merchantCode": "D0001",
+				"reference": "D0001OVO123",
+				"paymentUrl": "https://sandbox.duitku.com/pay/OV",
+				"amount": "50000",
+				"statusCode": "00",
+				"statusMessage": "SUCCESS"
+			}`,
+			check: func(t *testing.T, resp *pg.ChargeResponse) {
+				if resp.PaymentURL != "https://sandbox.duitku.com/pay/OV" {
+					t.Errorf("PaymentURL = %v", resp.PaymentURL)
+				}
+			},
+		},
+		{
+			name:        "QRIS",
+			paymentType: pg.PaymentTypeQRIS,
+			wantPath:    "/webapi/api/merchant/v2/inquiry",
+			wantMethod:  MethodQRIS,
+			mockResponse: `{
+				"merchantCode": "D0001",
+				"reference": "D0001QR123",
+				"qrString": "00020101021226660014ID.CO.QRIS.WWW",
+				"amount": "50000",
+				"statusCode": "00",
+				"statusMessage": "SUCCESS"
+			}`,
+			check: func(t *testing.T, resp *pg.ChargeResponse) {
+				if resp.QRString == "" {
+					t.Error("QRString is empty")
+				}
+			},
+		},
+		{
+			name:        "Indomaret",
+			paymentType: pg.PaymentTypeIndomaret,
+			wantPath:    "/webapi/api/merchant/v2/inquiry",
+			wantMethod:  MethodIndomaret,
+			mockResponse: `{
+				"merchantCode": "D0001",
+				"reference": "D0001IR123",
+				"vaNumber": "1234567890",
+				"amount": "50000",
+				"statusCode": "00",
+				"statusMessage": "SUCCESS"
+			}`,
+		},
+		{
+			name:        "credit card through POP",
+			paymentType: pg.PaymentTypeCC,
+			wantPath:    "/api/merchant/createInvoice",
+			wantMethod:  MethodCreditCard,
+			mockResponse: `{
+				"merchantCode": "D0001",
+				"reference": "D0001CC123",
+				"paymentUrl": "https://app-sandbox.duitku.com/redirect_checkout?reference=D0001CC123",
+				"amount": "50000",
+				"statusCode": "00",
+				"statusMessage": "SUCCESS"
+			}`,
+		},
+		{
+			name:        "provider error",
+			paymentType: pg.PaymentTypeVABNI,
+			wantPath:    "/webapi/api/merchant/v2/inquiry",
+			wantMethod:  MethodVABNI,
+			mockResponse: `{
+				"statusCode": "01",
+				"statusMessage": "Payment channel not available"
+			}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST request, got %s", r.Method)
+				}
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("path = %v, want %v", r.URL.Path, tt.wantPath)
+				}
+
+				var body InquiryRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode request: %v", err)
+				}
+				if body.PaymentMethod != tt.wantMethod {
+					t.Errorf("paymentMethod = %v, want %v", body.PaymentMethod, tt.wantMethod)
+				}
+
+				if tt.paymentType.IsCreditCard() {
+					if r.Header.Get(headerSignature) == "" || r.Header.Get(headerTimestamp) == "" {
+						t.Error("missing POP signature headers")
+					}
+				} else {
+					want := utils.CalculateMD5("D0001" + body.MerchantOrderID + "50000" + "test-api-key")
+					if body.Signature != want {
+						t.Errorf("signature = %v, want %v", body.Signature, want)
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.mockResponse))
+			}))
+			defer server.Close()
+
+			provider := newTestProvider(t, server)
+
+			resp, err := provider.CreateCharge(context.Background(), validChargeParams(tt.paymentType))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error but got nil")
+				}
+				if !pg.IsProviderError(err) {
+					t.Errorf("expected ProviderError, got %T", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateCharge() error = %v", err)
+			}
+
+			if resp.OrderID != "ORDER-001" {
+				t.Errorf("OrderID = %v, want ORDER-001", resp.OrderID)
+			}
+			if resp.Amount != 50000 {
+				t.Errorf("Amount = %v, want 50000", resp.Amount)
+			}
+			if resp.Status != pg.StatusPending {
+				t.Errorf("Status = %v, want %v", resp.Status, pg.StatusPending)
+			}
+			if tt.check != nil {
+				tt.check(t, resp)
+			}
+		})
+	}
+}
+
+func TestDuitku_GetStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockResponse string
+		wantStatus   pg.Status
+		wantPaid     int64
+	}{
+		{
+			name:         "success",
+			mockResponse: `{"merchantOrderId":"ORDER-001","reference":"D0001ABC123","amount":"50000","fee":"0","statusCode":"00","statusMessage":"SUCCESS"}`,
+			wantStatus:   pg.StatusSuccess,
+			wantPaid:     50000,
+		},
+		{
+			name:         "pending",
+			mockResponse: `{"merchantOrderId":"ORDER-001","reference":"D0001ABC123","amount":"50000","statusCode":"01","statusMessage":"PROCESS"}`,
+			wantStatus:   pg.StatusPending,
+		},
+		{
+			name:         "failed",
+			mockResponse: `{"merchantOrderId":"ORDER-001","reference":"D0001ABC123","amount":"50000","statusCode":"02","statusMessage":"FAILED"}`,
+			wantStatus:   pg.StatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/webapi/api/merchant/transactionStatus" {
+					t.Errorf("unexpected path %v", r.URL.Path)
+				}
+
+				var body TransactionStatusRequest
+				json.NewDecoder(r.Body).Decode(&body)
+				if body.Signature != utils.CalculateMD5("D0001ORDER-001test-api-key") {
+					t.Errorf("invalid signature %v", body.Signature)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.mockResponse))
+			}))
+			defer server.Close()
+
+			provider := newTestProvider(t, server)
+
+			status, err := provider.GetStatus(context.Background(), "ORDER-001")
+			if err != nil {
+				t.Fatalf("GetStatus() error = %v", err)
+			}
+
+			if status.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", status.Status, tt.wantStatus)
+			}
+			if status.PaidAmount != tt.wantPaid {
+				t.Errorf("PaidAmount = %v, want %v", status.PaidAmount, tt.wantPaid)
+			}
+			if status.TransactionID != "D0001ABC123" {
+				t.Errorf("TransactionID = %v, want D0001ABC123", status.TransactionID)
+			}
+		})
+	}
+}
+
+func TestDuitku_GetStatus_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"Message":"Wrong signature"}`))
+	}))
+	defer server.Close()
+
+	provider := newTestProvider(t, server)
+
+	if _, err := provider.GetStatus(context.Background(), "ORDER-001"); err == nil {
+		t.Error("expected error but got nil")
+	}
+}
+
+func TestDuitku_Cancel(t *testing.T) {
+	provider := &duitku{
+		config: &pg.ProviderConfig{},
+		mapper: &Mapper{},
+	}
+
+	err := provider.Cancel(context.Background(), "ORDER-001")
+	if err == nil {
+		t.Fatal("expected error for cancel, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("error message should mention not supported, got: %v", err)
+	}
+}
+
+func callbackForm(signature string) url.Values {
+	return url.Values{
+		"merchantCode":    {"D0001"},
+		"amount":          {"50000"},
+		"merchantOrderId": {"ORDER-001"},
+		"paymentCode":     {"BC"},
+		"resultCode":      {"00"},
+		"reference":       {"D0001ABC123"},
+		"settlementDate":  {"2024-01-01"},
+		"signature":       {signature},
+	}
+}
+
+func TestDuitku_VerifyWebhook(t *testing.T) {
+	validSignature := utils.CalculateMD5("D000150000ORDER-001test-api-key")
+
+	tests := []struct {
+		name string
+		form url.Values
+		want bool
+	}{
+		{
+			name: "valid signature",
+			form: callbackForm(validSignature),
+			want: true,
+		},
+		{
+			name: "invalid signature",
+			form: callbackForm("invalid-signature"),
+			want: false,
+		},
+		{
+			name: "missing signature",
+			form: callbackForm(""),
+			want: false,
+		},
+		{
+			name: "different merchant code",
+			form: func() url.Values {
+				f := callbackForm(validSignature)
+				f.Set("merchantCode", "D9999")
+				return f
+			}(),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &duitku{
+				config: &pg.ProviderConfig{
+					ServerKey:  "test-api-key",
+					MerchantID: "D0001",
+				},
+				mapper: &Mapper{},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			if got := provider.VerifyWebhook(req); got != tt.want {
+				t.Errorf("VerifyWebhook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuitku_ParseWebhook(t *testing.T) {
+	provider := &duitku{
+		config: &pg.ProviderConfig{
+			ServerKey:  "test-api-key",
+			MerchantID: "D0001",
+		},
+		mapper: &Mapper{},
+	}
+
+	form := callbackForm(utils.CalculateMD5("D000150000ORDER-001test-api-key"))
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Verify first, the same way pg.Client does
+	if !provider.VerifyWebhook(req) {
+		t.Fatal("VerifyWebhook() = false, want true")
+	}
+
+	event, err := provider.ParseWebhook(req)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+
+	if event.OrderID != "ORDER-001" {
+		t.Errorf("OrderID = %v, want ORDER-001", event.OrderID)
+	}
+	if event.TransactionID != "D0001ABC123" {
+		t.Errorf("TransactionID = %v, want D0001ABC123", event.TransactionID)
+	}
+	if event.Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", event.Status, pg.StatusSuccess)
+	}
+	if event.Amount != 50000 {
+		t.Errorf("Amount = %v, want 50000", event.Amount)
+	}
+	if event.PaymentType != pg.PaymentTypeVABCA {
+		t.Errorf("PaymentType = %v, want %v", event.PaymentType, pg.PaymentTypeVABCA)
+	}
+	if event.EventType != pg.EventPaymentCompleted {
+		t.Errorf("EventType = %v, want %v", event.EventType, pg.EventPaymentCompleted)
+	}
+}
+
+func TestDuitku_ParseWebhook_InvalidPayload(t *testing.T) {
+	provider := &duitku{
+		config: &pg.ProviderConfig{},
+		mapper: &Mapper{},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader("resultCode=00"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if _, err := provider.ParseWebhook(req); err != pg.ErrInvalidPayload {
+		t.Errorf("ParseWebhook() error = %v, want %v", err, pg.ErrInvalidPayload)
+	}
+}
+
+func TestDuitku_validateChargeParams(t *testing.T) {
+	provider := &duitku{
+		config: &pg.ProviderConfig{},
+		mapper: &Mapper{},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(p *pg.ChargeParams)
+		wantErr bool
+	}{
+		{
+			name:    "valid params",
+			modify:  func(p *pg.ChargeParams) {},
+			wantErr: false,
+		},
+		{
+			name:    "missing order ID",
+			modify:  func(p *pg.ChargeParams) { p.OrderID = "" },
+			wantErr: true,
+		},
+		{
+			name:    "amount below minimum",
+			modify:  func(p *pg.ChargeParams) { p.Amount = 100 },
+			wantErr: true,
+		},
+		{
+			name:    "unsupported payment type",
+			modify:  func(p *pg.ChargeParams) { p.PaymentType = pg.PaymentTypeGoPay },
+			wantErr: true,
+		},
+		{
+			name:    "missing callback URL",
+			modify:  func(p *pg.ChargeParams) { p.CallbackURL = "" },
+			wantErr: true,
+		},
+		{
+			name:    "missing return URL",
+			modify:  func(p *pg.ChargeParams) { p.ReturnURL = "" },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validChargeParams(pg.PaymentTypeVABCA)
+			tt.modify(&params)
+
+			err := provider.validateChargeParams(&params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateChargeParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMapper_mapToInquiryRequest(t *testing.T) {
+	mapper := &Mapper{}
+	params := validChargeParams(pg.PaymentTypeVABCA)
+	params.ExpiryTime = time.Now().Add(61 * time.Minute)
+
+	req := mapper.mapToInquiryRequest("D0001", params)
+
+	if req.MerchantCode != "D0001" {
+		t.Errorf("MerchantCode = %v, want D0001", req.MerchantCode)
+	}
+	if req.PaymentMethod != MethodVABCA {
+		t.Errorf("PaymentMethod = %v, want %v", req.PaymentMethod, MethodVABCA)
+	}
+	if req.ProductDetails != "Test Product" {
+		t.Errorf("ProductDetails = %v, want 'Test Product'", req.ProductDetails)
+	}
+	if req.CustomerDetail == nil || req.CustomerDetail.FirstName != "John" || req.CustomerDetail.LastName != "Doe" {
+		t.Errorf("CustomerDetail = %+v, want John Doe", req.CustomerDetail)
+	}
+	// Duitku item price is the line total
+	if len(req.ItemDetails) != 1 || req.ItemDetails[0].Price != 50000 {
+		t.Errorf("ItemDetails = %+v, want line total 50000", req.ItemDetails)
+	}
+	if req.ExpiryPeriod != 60 {
+		t.Errorf("ExpiryPeriod = %v, want 60", req.ExpiryPeriod)
+	}
+}
+
+func TestMapper_mapToInquiryRequest_Addresses(t *testing.T) {
+	mapper := &Mapper{}
+	params := validChargeParams(pg.PaymentTypeVABCA)
+	params.Customer.BillingAddress = &pg.Address{
+		Line1:      "Jl. Sudirman No. 1",
+		City:       "Jakarta",
+		PostalCode: "10220",
+	}
+
+	req := mapper.mapToInquiryRequest("D0001", params)
+
+	billing := req.CustomerDetail.BillingAddress
+	if billing == nil || billing.FirstName != "John" || billing.LastName != "Doe" {
+		t.Fatalf("BillingAddress = %+v, want John Doe", billing)
+	}
+	if billing.Address != "Jl. Sudirman No. 1" || billing.PostalCode != "10220" || billing.CountryCode != "ID" {
+		t.Errorf("BillingAddress = %+v, want Jl. Sudirman 10220 ID", billing)
+	}
+	if req.CustomerDetail.ShippingAddress != nil {
+		t.Errorf("ShippingAddress = %+v, want nil", req.CustomerDetail.ShippingAddress)
+	}
+}
+
+func TestMapper_unifiedPaymentType(t *testing.T) {
+	mapper := &Mapper{}
+
+	types := []pg.PaymentType{
+		pg.PaymentTypeVABCA, pg.PaymentTypeVAMandiri, pg.PaymentTypeVABNI, pg.PaymentTypeVABRI,
+		pg.PaymentTypeVAPermata, pg.PaymentTypeVACIMB, pg.PaymentTypeOVO, pg.PaymentTypeShopeePay,
+		pg.PaymentTypeDANA, pg.PaymentTypeLinkAja, pg.PaymentTypeQRIS, pg.PaymentTypeAlfamart,
+		pg.PaymentTypeIndomaret, pg.PaymentTypeCC,
+	}
+
+	for _, pt := range types {
+		t.Run(string(pt), func(t *testing.T) {
+			method := mapper.mapPaymentMethod(pt)
+			if method == "" {
+				t.Fatalf("mapPaymentMethod(%v) returned empty code", pt)
+			}
+			if got := mapper.unifiedPaymentType(string(method)); got != pt {
+				t.Errorf("unifiedPaymentType(%v) = %v, want %v", method, got, pt)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package duitku
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+// Mapper handles conversion between unified and Duitku-specific types
+type Mapper struct{}
+
+// mapPaymentMethod maps unified payment type to Duitku payment method code
+func (m *Mapper) mapPaymentMethod(pt pg.PaymentType) PaymentMethod {
+	switch pt {
+	case pg.PaymentTypeVABCA:
+		return MethodVABCA
+	case pg.PaymentTypeVAMandiri:
+		return MethodVAMandiri
+	case pg.PaymentTypeVABNI:
+		return MethodVABNI
+	case pg.PaymentTypeVABRI:
+		return MethodVABRI
+	case pg.PaymentTypeVAPermata:
+		return MethodVAPermata
+	case pg.PaymentTypeVACIMB:
+		return MethodVACIMB
+	case pg.PaymentTypeOVO:
+		return MethodOVO
+	case pg.PaymentTypeShopeePay:
+		return MethodShopeePay
+	case pg.PaymentTypeDANA:
+		return MethodDANA
+	case pg.PaymentTypeLinkAja:
+		return MethodLinkAja
+	case pg.PaymentTypeQRIS:
+		return MethodQRIS
+	case pg.PaymentTypeAlfamart:
+		return MethodAlfamart
+	case pg.PaymentTypeIndomaret:
+		return MethodIndomaret
+	case pg.PaymentTypeCC:
+		return MethodCreditCard
+	default:
+		return ""
+	}
+}
+
+// unifiedPaymentType maps Duitku payment method code to unified payment type
+func (m *Mapper) unifiedPaymentType(method string) pg.PaymentType {
+	switch PaymentMethod(method) {
+	case MethodVABCA:
+		return pg.PaymentTypeVABCA
+	case MethodVAMandiri:
+		return pg.PaymentTypeVAMandiri
+	case MethodVABNI:
+		return pg.PaymentTypeVABNI
+	case MethodVABRI:
+		return pg.PaymentTypeVABRI
+	case MethodVAPermata:
+		return pg.PaymentTypeVAPermata
+	case MethodVACIMB:
+		return pg.PaymentTypeVACIMB
+	case MethodOVO:
+		return pg.PaymentTypeOVO
+	case MethodShopeePay:
+		return pg.PaymentTypeShopeePay
+	case MethodDANA:
+		return pg.PaymentTypeDANA
+	case MethodLinkAja:
+		return pg.PaymentTypeLinkAja
+	case MethodQRIS:
+		return pg.PaymentTypeQRIS
+	case MethodAlfamart:
+		return pg.PaymentTypeAlfamart
+	case MethodIndomaret:
+		return pg.PaymentTypeIndomaret
+	case MethodCreditCard:
+		return pg.PaymentTypeCC
+	default:
+		return pg.PaymentType(method)
+	}
+}
+
+// mapStatus maps Duitku result code to unified status
+func (m *Mapper) mapStatus(code ResultCode) pg.Status {
+	switch code {
+	case ResultSuccess:
+		return pg.StatusSuccess
+	case ResultPending:
+		return pg.StatusPending
+	case ResultFailed:
+		return pg.StatusFailed
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapCallbackStatus maps Duitku callback result code to unified status
+// Callbacks only report "00" (success) and "01" (failed)
+func (m *Mapper) mapCallbackStatus(code ResultCode) pg.Status {
+	switch code {
+	case ResultSuccess:
+		return pg.StatusSuccess
+	case ResultPending, ResultFailed:
+		return pg.StatusFailed
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapEventType maps unified status to event type
+func (m *Mapper) mapEventType(status pg.Status) string {
+	switch status {
+	case pg.StatusSuccess:
+		return pg.EventPaymentCompleted
+	case pg.StatusFailed:
+		return pg.EventPaymentFailed
+	case pg.StatusCancelled:
+		return pg.EventPaymentCancelled
+	case pg.StatusExpired:
+		return pg.EventPaymentExpired
+	default:
+		return pg.EventPaymentPending
+	}
+}
+
+// mapToInquiryRequest maps unified ChargeParams to Duitku InquiryRequest
+// The signature is not set here since it depends on the merchant API key
+func (m *Mapper) mapToInquiryRequest(merchantCode string, params pg.ChargeParams) *InquiryRequest {
+	req := &InquiryRequest{
+		MerchantCode:     merchantCode,
+		PaymentAmount:    params.Amount,
+		PaymentMethod:    m.mapPaymentMethod(params.PaymentType),
+		MerchantOrderID:  params.OrderID,
+		ProductDetails:   productDetails(params),
+		MerchantUserInfo: params.Customer.ID,
+		CustomerVaName:   params.Customer.Name,
+		Email:            params.Customer.Email,
+		PhoneNumber:      params.Customer.Phone,
+		CallbackURL:      params.CallbackURL,
+		ReturnURL:        params.ReturnURL,
+	}
+
+	// Map customer details
+	if params.Customer.Name != "" || params.Customer.Email != "" {
+		firstName, lastName := pg.SplitName(params.Customer.Name)
+		req.CustomerDetail = &CustomerDetail{
+			FirstName:       firstName,
+			LastName:        lastName,
+			Email:           params.Customer.Email,
+			PhoneNumber:     params.Customer.Phone,
+			BillingAddress:  m.mapAddress(params.Customer.Recipient(params.Customer.BillingAddress)),
+			ShippingAddress: m.mapAddress(params.Customer.Recipient(params.Customer.ShippingAddress)),
+		}
+	}
+
+	// Map items
+	if items := params.LineItems(); len(items) > 0 {
+		req.ItemDetails = make([]*ItemDetail, len(items))
+		for i, item := range items {
+			req.ItemDetails[i] = &ItemDetail{
+				Name:     item.Name,
+				Price:    item.Price * item.Quantity,
+				Quantity: item.Quantity,
+			}
+		}
+	}
+
+	// Duitku expects the expiry period in minutes
+	if !params.ExpiryTime.IsZero() {
+		if minutes := int64(time.Until(params.ExpiryTime).Minutes()); minutes > 0 {
+			req.ExpiryPeriod = minutes
+		}
+	}
+
+	return req
+}
+
+// mapToChargeResponse maps Duitku InquiryResponse to unified ChargeResponse
+func (m *Mapper) mapToChargeResponse(resp *InquiryResponse, params pg.ChargeParams) *pg.ChargeResponse {
+	if resp == nil {
+		return nil
+	}
+
+	amount := params.Amount
+	if resp.Amount != "" {
+		if parsed, err := strconv.ParseInt(resp.Amount, 10, 64); err == nil {
+			amount = parsed
+		}
+	}
+
+	unified := &pg.ChargeResponse{
+		TransactionID: resp.Reference,
+		OrderID:       params.OrderID,
+		Amount:        amount,
+		Status:        pg.StatusPending, // Duitku returns pending on successful creation
+		PaymentURL:    resp.PaymentURL,
+		QRString:      resp.QRString,
+		ExpiryTime:    params.ExpiryTime,
+		CreatedAt:     time.Now(),
+	}
+
+	if resp.VANumber != "" {
+		unified.VANumber = resp.VANumber
+		unified.VABank = string(params.PaymentType)
+	}
+
+	if resp.PaymentURL != "" {
+		unified.NextActions = append(unified.NextActions, pg.Action{Type: pg.ActionRedirect, URL: resp.PaymentURL, Method: "GET"})
+	}
+	if resp.QRString != "" {
+		unified.NextActions = append(unified.NextActions, pg.Action{Type: pg.ActionShowQR, QRString: resp.QRString})
+	}
+
+	// Store raw response
+	raw := make(map[string]interface{})
+	rawBytes, _ := json.Marshal(resp)
+	json.Unmarshal(rawBytes, &raw)
+	unified.Raw = raw
+
+	return unified
+}
+
+// mapToPaymentStatus maps Duitku TransactionStatusResponse to unified PaymentStatus
+func (m *Mapper) mapToPaymentStatus(orderID string, resp *TransactionStatusResponse) *pg.PaymentStatus {
+	if resp == nil {
+		return nil
+	}
+
+	var amount int64
+	if resp.Amount != "" {
+		amount, _ = strconv.ParseInt(resp.Amount, 10, 64)
+	}
+
+	status := m.mapStatus(resp.StatusCode)
+
+	var paidAmount int64
+	if status == pg.StatusSuccess {
+		paidAmount = amount
+	}
+
+	var failureReason string
+	if status == pg.StatusFailed {
+		failureReason = resp.StatusMessage
+	}
+
+	return &pg.PaymentStatus{
+		TransactionID: resp.Reference,
+		OrderID:       orderID,
+		Status:        status,
+		Amount:        amount,
+		PaidAmount:    paidAmount,
+		FailureReason: failureReason,
+	}
+}
+
+// productDetails returns the product description required by Duitku
+func productDetails(params pg.ChargeParams) string {
+	if params.Description != "" {
+		return params.Description
+	}
+	if len(params.Items) > 0 {
+		names := make([]string, len(params.Items))
+		for i, item := range params.Items {
+			names[i] = item.Name
+		}
+		return strings.Join(names, ", ")
+	}
+	return "Payment " + params.OrderID
+}
+
+// mapAddress maps a unified address to a Duitku address
+func (m *Mapper) mapAddress(address *pg.Address) *Address {
+	if address == nil {
+		return nil
+	}
+
+	firstName, lastName := pg.SplitName(address.Name)
+	return &Address{
+		FirstName:   firstName,
+		LastName:    lastName,
+		Address:     address.Street(),
+		City:        address.City,
+		PostalCode:  address.PostalCode,
+		Phone:       address.Phone,
+		CountryCode: address.Country(),
+	}
+}
@@ -0,0 +1,128 @@
+package duitku
+
+// PaymentMethod represents Duitku payment method codes
+type PaymentMethod string
+
+const (
+	// MethodVABCA Duitku VA BCA
+	MethodVABCA PaymentMethod = "BC"
+	// MethodVAMandiri Duitku VA Mandiri
+	MethodVAMandiri PaymentMethod = "M2"
+	// MethodVABNI Duitku VA BNI
+	MethodVABNI PaymentMethod = "I1"
+	// MethodVABRI Duitku VA BRI (BRIVA)
+	MethodVABRI PaymentMethod = "BR"
+	// MethodVAPermata Duitku VA Permata
+	MethodVAPermata PaymentMethod = "BT"
+	// MethodVACIMB Duitku VA CIMB Niaga
+	MethodVACIMB PaymentMethod = "B1"
+
+	// MethodOVO Duitku OVO
+	MethodOVO PaymentMethod = "OV"
+	// MethodShopeePay Duitku ShopeePay Apps
+	MethodShopeePay PaymentMethod = "SA"
+	// MethodDANA Duitku DANA
+	MethodDANA PaymentMethod = "DA"
+	// MethodLinkAja Duitku LinkAja Apps (percentage fee)
+	MethodLinkAja PaymentMethod = "LA"
+
+	// MethodQRIS Duitku QRIS by Nobu
+	MethodQRIS PaymentMethod = "NQ"
+
+	// MethodAlfamart Duitku Pegadaian/ALFA/Pos
+	MethodAlfamart PaymentMethod = "FT"
+	// MethodIndomaret Duitku Indomaret
+	MethodIndomaret PaymentMethod = "IR"
+
+	// MethodCreditCard Duitku Credit Card (Visa/Master/JCB)
+	MethodCreditCard PaymentMethod = "VC"
+)
+
+// ResultCode represents Duitku transaction result codes
+type ResultCode string
+
+const (
+	// ResultSuccess means the transaction is paid
+	ResultSuccess ResultCode = "00"
+	// ResultPending means the transaction is still waiting for payment
+	ResultPending ResultCode = "01"
+	// ResultFailed means the transaction is failed, cancelled or expired
+	ResultFailed ResultCode = "02"
+)
+
+// ItemDetail details items purchased by Customer
+type ItemDetail struct {
+	Name     string `json:"name"`
+	Price    int64  `json:"price"`
+	Quantity int64  `json:"quantity"`
+}
+
+// CustomerDetail details of customer
+type CustomerDetail struct {
+	FirstName       string   `json:"firstName,omitempty"`
+	LastName        string   `json:"lastName,omitempty"`
+	Email           string   `json:"email,omitempty"`
+	PhoneNumber     string   `json:"phoneNumber,omitempty"`
+	BillingAddress  *Address `json:"billingAddress,omitempty"`
+	ShippingAddress *Address `json:"shippingAddress,omitempty"`
+}
+
+// Address details a billing or shipping address of Customer
+type Address struct {
+	FirstName   string `json:"firstName,omitempty"`
+	LastName    string `json:"lastName,omitempty"`
+	Address     string `json:"address,omitempty"`
+	City        string `json:"city,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+// InquiryRequest for creating Duitku transaction (API v2)
+type InquiryRequest struct {
+	MerchantCode     string          `json:"merchantCode"`
+	PaymentAmount    int64           `json:"paymentAmount"`
+	PaymentMethod    PaymentMethod   `json:"paymentMethod,omitempty"`
+	MerchantOrderID  string          `json:"merchantOrderId"`
+	ProductDetails   string          `json:"productDetails"`
+	AdditionalParam  string          `json:"additionalParam,omitempty"`
+	MerchantUserInfo string          `json:"merchantUserInfo,omitempty"`
+	CustomerVaName   string          `json:"customerVaName,omitempty"`
+	Email            string          `json:"email,omitempty"`
+	PhoneNumber      string          `json:"phoneNumber,omitempty"`
+	ItemDetails      []*ItemDetail   `json:"itemDetails,omitempty"`
+	CustomerDetail   *CustomerDetail `json:"customerDetail,omitempty"`
+	CallbackURL      string          `json:"callbackUrl"`
+	ReturnURL        string          `json:"returnUrl"`
+	Signature        string          `json:"signature,omitempty"`
+	ExpiryPeriod     int64           `json:"expiryPeriod,omitempty"`
+}
+
+// InquiryResponse from Duitku inquiry and POP createInvoice
+type InquiryResponse struct {
+	MerchantCode  string `json:"merchantCode"`
+	Reference     string `json:"reference"`
+	PaymentURL    string `json:"paymentUrl"`
+	VANumber      string `json:"vaNumber,omitempty"`
+	QRString      string `json:"qrString,omitempty"`
+	Amount        string `json:"amount"`
+	StatusCode    string `json:"statusCode"`
+	StatusMessage string `json:"statusMessage"`
+}
+
+// TransactionStatusRequest for checking transaction status
+type TransactionStatusRequest struct {
+	MerchantCode    string `json:"merchantCode"`
+	MerchantOrderID string `json:"merchantOrderId"`
+	Signature       string `json:"signature"`
+}
+
+// TransactionStatusResponse from Duitku
+type TransactionStatusResponse struct {
+	MerchantOrderID string     `json:"merchantOrderId"`
+	Reference       string     `json:"reference"`
+	Amount          string     `json:"amount"`
+	Fee             string     `json:"fee,omitempty"`
+	StatusCode      ResultCode `json:"statusCode"`
+	StatusMessage   string     `json:"statusMessage"`
+}
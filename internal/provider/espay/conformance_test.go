@@ -0,0 +1,120 @@
+package espay
+
+import (
+	"net/http"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/pgtest"
+)
+
+// conformanceStatuses maps the unified statuses to the SNAP latestTransactionStatus of a Direct Debit
+var conformanceStatuses = map[pg.Status]TransactionStatus{
+	pg.StatusSuccess:    TransactionStatusSuccess,
+	pg.StatusPending:    TransactionStatusPending,
+	pg.StatusProcessing: TransactionStatusPaying,
+	pg.StatusCancelled:  TransactionStatusCancelled,
+	pg.StatusFailed:     TransactionStatusFailed,
+}
+
+// conformancePaymentFlags maps the unified statuses to the SNAP paymentFlagStatus of a virtual account
+var conformancePaymentFlags = map[pg.Status]PaymentFlagStatus{
+	pg.StatusSuccess: PaymentFlagSuccess,
+}
+
+// jsonHandler answers every request with body and the HTTP status code
+func jsonHandler(code int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	})
+}
+
+// conformanceConfig returns the provider configuration with the test keys
+func conformanceConfig(t *testing.T) pg.ProviderConfig {
+	merchantKey, espayKey := testKeys(t)
+	return pg.ProviderConfig{
+		MerchantID: "SGWTEST",
+		PrivateKey: privateKeyPEM(merchantKey),
+		ServerKey:  publicKeyPEM(&espayKey.PublicKey),
+	}
+}
+
+// conformanceCallback returns a callback signed with the Espay key, or with the merchant key when signed is false
+func conformanceCallback(t *testing.T, path string, payload interface{}, signed bool) *http.Request {
+	merchantKey, espayKey := testKeys(t)
+	if !signed {
+		return callbackSignedWith(t, merchantKey, path, payload)
+	}
+	return callbackSignedWith(t, espayKey, path, payload)
+}
+
+func TestEspay_Conformance(t *testing.T) {
+	pgtest.RunProviderConformance(t, New, pgtest.Stub{
+		Config: conformanceConfig(t),
+		Charge: pg.ChargeParams{
+			OrderID:     "ORDER-001",
+			Amount:      150000,
+			PaymentType: pg.PaymentTypeDANA,
+			Customer:    pg.Customer{ID: "CUST-001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890"},
+			Items:       []pg.Item{{ID: "SKU-001", Name: "Kaos Polos", Price: 75000, Quantity: 2}},
+			CallbackURL: "https://example.com/callback",
+			ReturnURL:   "https://example.com/return",
+		},
+		Reference:    "debit:ORDER-001",
+		CreateCharge: jsonHandler(http.StatusOK, `{"responseCode":"2005400","responseMessage":"Successful","referenceNo":"ESP-REF-001","partnerReferenceNo":"ORDER-001","webRedirectUrl":"https://sandbox-api.espay.id/pay/ESP-REF-001"}`),
+		Rejected:     jsonHandler(http.StatusBadRequest, `{"responseCode":"4005401","responseMessage":"Invalid Field Format amount"}`),
+		Statuses:     []pg.Status{pg.StatusSuccess, pg.StatusPending, pg.StatusProcessing, pg.StatusCancelled, pg.StatusFailed},
+		Status: func(status pg.Status) http.Handler {
+			return jsonHandler(http.StatusOK, `{"responseCode":"2005500","responseMessage":"Successful","originalPartnerReferenceNo":"ORDER-001","originalReferenceNo":"ESP-REF-001","latestTransactionStatus":"`+string(conformanceStatuses[status])+`","amount":{"value":"150000.00","currency":"IDR"}}`)
+		},
+		Cancel: jsonHandler(http.StatusOK, `{"responseCode":"2005700","responseMessage":"Successful"}`),
+		Webhook: func(status pg.Status, signed bool) *http.Request {
+			return conformanceCallback(t, "/v1.0/debit/notify", map[string]interface{}{
+				"originalPartnerReferenceNo": "ORDER-001",
+				"originalReferenceNo":        "ESP-REF-001",
+				"latestTransactionStatus":    conformanceStatuses[status],
+				"amount":                     map[string]string{"value": "150000.00", "currency": "IDR"},
+				"finishedTime":               "2024-03-01T10:15:30+07:00",
+				"additionalInfo":             map[string]string{"productCode": "DANA"},
+			}, signed)
+		},
+	})
+}
+
+// Virtual accounts are checked and cancelled with the va: reference of the charge,
+// and only successful payments are notified
+func TestEspay_Conformance_VirtualAccount(t *testing.T) {
+	pgtest.RunProviderConformance(t, New, pgtest.Stub{
+		Config: conformanceConfig(t),
+		Charge: pg.ChargeParams{
+			OrderID:     "ORDER-001",
+			Amount:      150000,
+			PaymentType: pg.PaymentTypeVABCA,
+			Customer:    pg.Customer{ID: "CUST-001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890"},
+			Items:       []pg.Item{{ID: "SKU-001", Name: "Kaos Polos", Price: 75000, Quantity: 2}},
+			CallbackURL: "https://example.com/callback",
+		},
+		Reference:    "va:ORDER-001",
+		CreateCharge: jsonHandler(http.StatusOK, `{"responseCode":"2002700","responseMessage":"Successful","virtualAccountData":{"partnerServiceId":" SGWTEST","customerNo":"ORDER-001","virtualAccountNo":" SGWTESTORDER-001","virtualAccountName":"Budi Santoso","trxId":"ORDER-001","totalAmount":{"value":"150000.00","currency":"IDR"}}}`),
+		Rejected:     jsonHandler(http.StatusBadRequest, `{"responseCode":"4002701","responseMessage":"Invalid Field Format totalAmount"}`),
+		Statuses:     []pg.Status{pg.StatusSuccess},
+		Status: func(status pg.Status) http.Handler {
+			return jsonHandler(http.StatusOK, `{"responseCode":"2002600","responseMessage":"Successful","virtualAccountData":{"partnerServiceId":" SGWTEST","customerNo":"ORDER-001","virtualAccountNo":" SGWTESTORDER-001","trxId":"ORDER-001","totalAmount":{"value":"150000.00","currency":"IDR"},"paidAmount":{"value":"150000.00","currency":"IDR"},"paymentFlagStatus":"`+string(conformancePaymentFlags[status])+`"}}`)
+		},
+		Cancel: jsonHandler(http.StatusOK, `{"responseCode":"2003100","responseMessage":"Successful"}`),
+		Webhook: func(status pg.Status, signed bool) *http.Request {
+			return conformanceCallback(t, "/v1.0/transfer-va/payment", map[string]interface{}{
+				"partnerServiceId": " SGWTEST",
+				"customerNo":       "ORDER-001",
+				"virtualAccountNo": " SGWTESTORDER-001",
+				"paymentRequestId": "PAY-001",
+				"trxId":            "ORDER-001",
+				"paidAmount":       map[string]string{"value": "150000.00", "currency": "IDR"},
+				"trxDateTime":      "2024-03-01T10:15:30+07:00",
+				"additionalInfo":   map[string]string{"bankCode": "014"},
+			}, signed)
+		},
+	})
+}
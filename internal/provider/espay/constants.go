@@ -0,0 +1,57 @@
+package espay
+
+import (
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// ProviderName is the name of the Espay provider
+	ProviderName = "espay"
+
+	// API URLs
+	sandboxURL    = "https://sandbox-api.espay.id"
+	productionURL = "https://api.espay.id"
+
+	// header names
+	headerXTimestamp  = "X-TIMESTAMP"
+	headerXSignature  = "X-SIGNATURE"
+	headerXPartnerID  = "X-PARTNER-ID"
+	headerXExternalID = "X-EXTERNAL-ID"
+	headerChannelID   = "CHANNEL-ID"
+
+	// channelID is the CHANNEL-ID header value assigned by Espay
+	channelID = "ESPAY"
+
+	// timestampLayout is the ISO8601 timestamp format used by SNAP (WIB)
+	timestampLayout = "2006-01-02T15:04:05+07:00"
+
+	// currencyIDR is the only currency supported by Espay SNAP
+	currencyIDR = "IDR"
+
+	// vaReferencePrefix marks the reference of a virtual account in GetStatus and Cancel
+	vaReferencePrefix = "va:"
+
+	// debitReferencePrefix marks the reference of a Direct Debit payment in GetStatus and Cancel
+	debitReferencePrefix = "debit:"
+
+	// referenceSeparator separates the parts of an explicit VA reference
+	referenceSeparator = ":"
+)
+
+// customOptions are the pg.ChargeParams.Custom keys read by the provider, they are not merged into the request body
+var customOptions = []string{"espay_partner_service_id", "espay_customer_no"}
+
+// amountLimits are the Espay amount limits per payment type, a zero Max has no maximum
+// They are overridden with pg.WithAmountLimits
+var amountLimits = map[pg.PaymentType]pg.AmountLimit{
+	pg.PaymentTypeOVO:       {Min: 10000, Max: 10000000},
+	pg.PaymentTypeDANA:      {Min: 10000, Max: 20000000},
+	pg.PaymentTypeShopeePay: {Min: 10000, Max: 20000000},
+	pg.PaymentTypeLinkAja:   {Min: 10000, Max: 10000000},
+	pg.PaymentTypeVABCA:     {Min: 10000},
+	pg.PaymentTypeVABNI:     {Min: 10000},
+	pg.PaymentTypeVABRI:     {Min: 10000},
+	pg.PaymentTypeVAMandiri: {Min: 10000},
+	pg.PaymentTypeVAPermata: {Min: 10000},
+	pg.PaymentTypeVACIMB:    {Min: 10000},
+}
@@ -0,0 +1,724 @@
+package espay
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// API endpoints
+	createVAUri     = "/api/merchant/v1.0/transfer-va/create-va"
+	vaStatusUri     = "/api/merchant/v1.0/transfer-va/status"
+	deleteVAUri     = "/api/merchant/v1.0/transfer-va/delete-va"
+	debitPaymentUri = "/api/v1.0/debit/payment-host-to-host"
+	debitStatusUri  = "/api/v1.0/debit/status"
+	debitCancelUri  = "/api/v1.0/debit/cancel"
+
+	// serviceCodeDebitPayment is the SNAP service code of Direct Debit Payment Host to Host
+	serviceCodeDebitPayment = "54"
+)
+
+func init() {
+	// Register this provider with the pg package
+	pg.RegisterProvider(ProviderName, New)
+}
+
+type espay struct {
+	config     *pg.ProviderConfig
+	mapper     *Mapper
+	httpCli    *http.Client
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+}
+
+// New creates a new Espay provider
+// MerchantID is the Espay partner ID (merchant code), PrivateKey is the merchant RSA private key
+// used to sign requests and ServerKey is the Espay RSA public key used to verify callbacks
+func New(cfg *pg.ProviderConfig) (pg.Provider, error) {
+	if cfg.MerchantID == "" || cfg.PrivateKey == "" {
+		return nil, pg.ErrMissingCredentials
+	}
+
+	privateKey, err := parsePrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	var publicKey *rsa.PublicKey
+	if cfg.ServerKey != "" {
+		publicKey, err = parsePublicKey(cfg.ServerKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Espay public key: %w", err)
+		}
+	}
+
+	return &espay{
+		config:     cfg,
+		mapper:     &Mapper{},
+		httpCli:    &http.Client{Timeout: getTimeout(cfg), Transport: cfg.Transport},
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}, nil
+}
+
+// Name returns the provider name
+func (e *espay) Name() string {
+	return ProviderName
+}
+
+// CreateCharge creates a new payment
+// Virtual accounts use the SNAP Transfer VA API, e-wallets use Direct Debit Payment Host to Host
+func (e *espay) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	// Validate parameters
+	if err := e.validateChargeParams(&params); err != nil {
+		return nil, err
+	}
+
+	if params.PaymentType.IsVirtualAccount() {
+		return e.createVirtualAccount(ctx, params)
+	}
+
+	return e.createDebitPayment(ctx, params)
+}
+
+// createVirtualAccount creates a closed-amount virtual account
+func (e *espay) createVirtualAccount(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	req := e.mapper.mapToCreateVARequest(e.partnerServiceID(params), customerNo(params), params)
+
+	responseBody, err := e.sendRequest(ctx, createVAUri, pg.WithCustom(req, params.Custom, customOptions...))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp VAResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if err := checkResponseCode(resp.ResponseCode, resp.ResponseMessage); err != nil {
+		return nil, err
+	}
+
+	unified := e.mapper.mapVAToChargeResponse(&resp, req, params)
+	unified.Reference = e.vaReference(req.PartnerServiceID, req.CustomerNo, params.OrderID)
+
+	return unified, nil
+}
+
+// createDebitPayment creates an e-wallet payment
+func (e *espay) createDebitPayment(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	req := e.mapper.mapToDebitPaymentRequest(e.config.MerchantID, params)
+
+	responseBody, err := e.sendRequest(ctx, debitPaymentUri, pg.WithCustom(req, params.Custom, customOptions...))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DebitPaymentResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if err := checkResponseCode(resp.ResponseCode, resp.ResponseMessage); err != nil {
+		return nil, err
+	}
+
+	unified := e.mapper.mapDebitToChargeResponse(&resp, req, params)
+	unified.Reference = debitReferencePrefix + params.OrderID
+
+	return unified, nil
+}
+
+// sendRequest sends a signed SNAP request to Espay and returns the response body
+func (e *espay) sendRequest(ctx context.Context, path string, payload interface{}) ([]byte, error) {
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	timestamp := time.Now().In(wib).Format(timestampLayout)
+	signature, err := e.generateSignature(http.MethodPost, path, bodyBytes, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signature: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.getBaseURL()+path, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(headerXTimestamp, timestamp)
+	req.Header.Set(headerXSignature, signature)
+	req.Header.Set(headerXPartnerID, e.config.MerchantID)
+	req.Header.Set(headerXExternalID, generateExternalID())
+	req.Header.Set(headerChannelID, channelID)
+
+	resp, err := e.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		// SNAP errors carry a response code, prefer it over the raw body
+		var errResp errorResponse
+		if err := json.Unmarshal(responseBody, &errResp); err == nil && errResp.ResponseCode != "" {
+			return nil, pg.WrapProviderError(ProviderName, errResp.ResponseCode, errResp.ResponseMessage, nil)
+		}
+		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+	}
+
+	return responseBody, nil
+}
+
+// GetStatus retrieves payment status
+// orderID is the Reference of the charge (see parseReference): a "va:" reference is checked with
+// the Transfer VA status API, a "debit:" reference with the Direct Debit status API
+func (e *espay) GetStatus(ctx context.Context, orderID string) (*pg.PaymentStatus, error) {
+	ref, debitOrderID, err := e.parseReference(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref != nil {
+		responseBody, err := e.sendRequest(ctx, vaStatusUri, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp VAResponse
+		if err := json.Unmarshal(responseBody, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		if err := checkResponseCode(resp.ResponseCode, resp.ResponseMessage); err != nil {
+			return nil, err
+		}
+		if resp.VirtualAccountData == nil {
+			return nil, fmt.Errorf("failed to parse response: missing virtualAccountData")
+		}
+
+		return e.mapper.mapVAToPaymentStatus(ref.TrxID, &resp), nil
+	}
+
+	req := &DebitStatusRequest{
+		OriginalPartnerReferenceNo: debitOrderID,
+		MerchantID:                 e.config.MerchantID,
+		ServiceCode:                serviceCodeDebitPayment,
+	}
+
+	responseBody, err := e.sendRequest(ctx, debitStatusUri, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DebitStatusResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if err := checkResponseCode(resp.ResponseCode, resp.ResponseMessage); err != nil {
+		return nil, err
+	}
+
+	return e.mapper.mapDebitToPaymentStatus(debitOrderID, &resp), nil
+}
+
+// Cancel cancels an unpaid payment
+// orderID is the Reference of the charge: a "va:" reference deletes the virtual account,
+// a "debit:" reference is cancelled with Direct Debit Cancel
+func (e *espay) Cancel(ctx context.Context, orderID string) error {
+	ref, debitOrderID, err := e.parseReference(orderID)
+	if err != nil {
+		return err
+	}
+
+	if ref != nil {
+		responseBody, err := e.sendRequest(ctx, deleteVAUri, ref)
+		if err != nil {
+			return err
+		}
+
+		var resp VAResponse
+		if err := json.Unmarshal(responseBody, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		return checkResponseCode(resp.ResponseCode, resp.ResponseMessage)
+	}
+
+	req := &DebitCancelRequest{
+		OriginalPartnerReferenceNo: debitOrderID,
+		MerchantID:                 e.config.MerchantID,
+		Reason:                     "User requested cancellation",
+	}
+
+	responseBody, err := e.sendRequest(ctx, debitCancelUri, req)
+	if err != nil {
+		return err
+	}
+
+	var resp DebitCancelResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return checkResponseCode(resp.ResponseCode, resp.ResponseMessage)
+}
+
+// VerifyWebhook verifies the SHA256withRSA signature of an Espay callback
+// String to sign: HTTPMethod:RelativePath:Lowercase(Hex(SHA256(minify(body)))):X-TIMESTAMP
+func (e *espay) VerifyWebhook(r *http.Request) bool {
+	signature := r.Header.Get(headerXSignature)
+	timestamp := r.Header.Get(headerXTimestamp)
+
+	if signature == "" || timestamp == "" || e.publicKey == nil || r.Body == nil {
+		return false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false
+	}
+
+	// Restore body for subsequent reads
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	stringToSign, err := stringToSign(r.Method, r.URL.Path, body, timestamp)
+	if err != nil {
+		return false
+	}
+
+	hashed := sha256.Sum256([]byte(stringToSign))
+	return rsa.VerifyPKCS1v15(e.publicKey, crypto.SHA256, hashed[:], sig) == nil
+}
+
+// ParseWebhook parses an Espay callback
+// Espay sends three kinds of callbacks: a virtual account inquiry before payment, a virtual account
+// payment and a Direct Debit payment notification. The returned event carries the SNAP response
+// Espay expects in reply.
+func (e *espay) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
+	if r.Body == nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	// Restore body for subsequent reads
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	switch {
+	case raw["originalPartnerReferenceNo"] != nil:
+		return e.parseDebitNotification(body)
+	case raw["paymentRequestId"] != nil:
+		return e.parseVAPayment(body, raw)
+	case raw["inquiryRequestId"] != nil:
+		return e.parseVAInquiry(r.Context(), body, raw)
+	default:
+		return nil, pg.ErrInvalidPayload
+	}
+}
+
+// parseVAInquiry parses a virtual account inquiry and answers with the bill found by the configured
+// pg.BillResolver, the inquiry is answered as not found without a resolver or a bill
+func (e *espay) parseVAInquiry(ctx context.Context, body []byte, raw map[string]interface{}) (*pg.WebhookEvent, error) {
+	var inquiry VirtualAccountData
+	if err := json.Unmarshal(body, &inquiry); err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	event := &pg.WebhookEvent{
+		Status:    pg.StatusPending,
+		EventType: pg.EventPaymentInquiry,
+		Timestamp: time.Now(),
+		Raw:       raw,
+	}
+
+	data := &VirtualAccountData{
+		PartnerServiceID: inquiry.PartnerServiceID,
+		CustomerNo:       inquiry.CustomerNo,
+		VirtualAccountNo: inquiry.VirtualAccountNo,
+		InquiryRequestID: inquiry.InquiryRequestID,
+	}
+
+	var bill *pg.Bill
+	if e.config.BillResolver != nil {
+		var err error
+		bill, err = e.config.BillResolver(ctx, strings.TrimSpace(inquiry.VirtualAccountNo))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the bill of VA %s: %w", strings.TrimSpace(inquiry.VirtualAccountNo), err)
+		}
+	}
+	if bill == nil {
+		data.InquiryStatus = "01"
+		data.InquiryReason = &Reason{English: "Bill not found", Indonesia: "Tagihan tidak ditemukan"}
+
+		ack, err := acknowledgement(http.StatusNotFound, responseCodeBillNotFound, responseMessageBillNotFound, data)
+		if err != nil {
+			return nil, err
+		}
+		event.Acknowledgement = ack
+		return event, nil
+	}
+
+	event.OrderID = bill.OrderID
+	event.Reference = e.vaReference(inquiry.PartnerServiceID, strings.TrimSpace(inquiry.CustomerNo), bill.OrderID)
+	event.TransactionID = inquiry.InquiryRequestID
+	event.Amount = bill.Amount
+	event.PaymentType = bill.PaymentType
+
+	data.VirtualAccountName = bill.Name
+	data.TotalAmount = &Amount{Value: formatAmount(bill.Amount), Currency: currencyIDR}
+	data.InquiryStatus = "00"
+	data.InquiryReason = &Reason{English: "Success", Indonesia: "Sukses"}
+
+	ack, err := acknowledgement(http.StatusOK, responseCodeInquirySuccess, responseMessageSuccessful, data)
+	if err != nil {
+		return nil, err
+	}
+	event.Acknowledgement = ack
+
+	return event, nil
+}
+
+// parseVAPayment parses a virtual account payment
+func (e *espay) parseVAPayment(body []byte, raw map[string]interface{}) (*pg.WebhookEvent, error) {
+	var payment VirtualAccountData
+	if err := json.Unmarshal(body, &payment); err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	timestamp := time.Now()
+	if trxDateTime, err := time.Parse(time.RFC3339, payment.TrxDateTime); err == nil {
+		timestamp = trxDateTime
+	}
+
+	event := &pg.WebhookEvent{
+		OrderID:       payment.TrxID,
+		TransactionID: payment.PaymentRequestID,
+		Status:        pg.StatusSuccess,
+		Amount:        parseAmount(payment.PaidAmount),
+		EventType:     pg.EventPaymentCompleted,
+		Timestamp:     timestamp,
+		Raw:           raw,
+	}
+
+	if bankCode, ok := payment.AdditionalInfo["bankCode"].(string); ok {
+		event.PaymentType = e.mapper.unifiedPaymentType(bankCode)
+	}
+
+	// The customer number is the order ID unless the charge overrode it
+	if event.OrderID == "" {
+		event.OrderID = strings.TrimSpace(payment.CustomerNo)
+	}
+	event.Reference = e.vaReference(payment.PartnerServiceID, strings.TrimSpace(payment.CustomerNo), event.OrderID)
+
+	ack, err := acknowledgement(http.StatusOK, responseCodePaymentSuccess, responseMessageSuccessful, &VirtualAccountData{
+		PartnerServiceID:   payment.PartnerServiceID,
+		CustomerNo:         payment.CustomerNo,
+		VirtualAccountNo:   payment.VirtualAccountNo,
+		VirtualAccountName: payment.VirtualAccountName,
+		PaymentRequestID:   payment.PaymentRequestID,
+		TrxID:              payment.TrxID,
+		PaidAmount:         payment.PaidAmount,
+		PaymentFlagStatus:  PaymentFlagSuccess,
+		PaymentFlagReason:  &Reason{English: "Success", Indonesia: "Sukses"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	event.Acknowledgement = ack
+
+	return event, nil
+}
+
+// parseDebitNotification parses a Direct Debit payment notification
+func (e *espay) parseDebitNotification(body []byte) (*pg.WebhookEvent, error) {
+	var notification DebitNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	event := e.mapper.mapDebitNotificationToWebhookEvent(&notification)
+	event.Reference = debitReferencePrefix + event.OrderID
+
+	ack, err := acknowledgement(http.StatusOK, responseCodeNotifySuccess, responseMessageSuccessful, nil)
+	if err != nil {
+		return nil, err
+	}
+	event.Acknowledgement = ack
+
+	return event, nil
+}
+
+// acknowledgement builds the SNAP callback response
+func acknowledgement(statusCode int, responseCode, responseMessage string, data *VirtualAccountData) (*pg.WebhookAcknowledgement, error) {
+	body, err := json.Marshal(&CallbackResponse{
+		ResponseCode:       responseCode,
+		ResponseMessage:    responseMessage,
+		VirtualAccountData: data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal acknowledgement: %w", err)
+	}
+
+	return &pg.WebhookAcknowledgement{
+		StatusCode:  statusCode,
+		ContentType: "application/json",
+		Body:        body,
+	}, nil
+}
+
+// GetToken retrieves an access token for the provider
+// Espay SNAP signs every request with the merchant private key, so this returns an error
+func (e *espay) GetToken(ctx context.Context) (*pg.TokenResponse, error) {
+	return nil, fmt.Errorf("GetToken API is not supported by %s. Espay signs every request with the merchant private key", ProviderName)
+}
+
+// Capabilities returns the payment types and operations supported by Espay
+// Espay SNAP only offers virtual accounts and e-wallets
+func (e *espay) Capabilities() pg.Capabilities {
+	types := []pg.PaymentType{
+		pg.PaymentTypeOVO, pg.PaymentTypeDANA, pg.PaymentTypeShopeePay, pg.PaymentTypeLinkAja,
+		pg.PaymentTypeVABCA, pg.PaymentTypeVABNI, pg.PaymentTypeVABRI, pg.PaymentTypeVAMandiri, pg.PaymentTypeVAPermata, pg.PaymentTypeVACIMB,
+	}
+
+	return pg.Capabilities{
+		PaymentTypes: types,
+		Operations:   []pg.Operation{pg.OperationCancel},
+		Limits:       pg.NewLimits(types, amountLimits, e.config.AmountLimits),
+		Currencies:   []string{currencyIDR},
+		SandboxURL:   true,
+	}
+}
+
+// generateSignature generates the SHA256withRSA signature of a request
+func (e *espay) generateSignature(method, path string, body []byte, timestamp string) (string, error) {
+	stringToSign, err := stringToSign(method, path, body, timestamp)
+	if err != nil {
+		return "", err
+	}
+
+	hashed := sha256.Sum256([]byte(stringToSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, e.privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// stringToSign builds the SNAP string to sign
+// Format: HTTPMethod:RelativePath:Lowercase(Hex(SHA256(minify(body)))):Timestamp
+func stringToSign(method, path string, body []byte, timestamp string) (string, error) {
+	var minified bytes.Buffer
+	if len(body) > 0 {
+		if err := json.Compact(&minified, body); err != nil {
+			return "", err
+		}
+	}
+
+	bodyHash := sha256.Sum256(minified.Bytes())
+	return method + ":" + path + ":" + strings.ToLower(hex.EncodeToString(bodyHash[:])) + ":" + timestamp, nil
+}
+
+// parsePrivateKey parses a PEM-encoded RSA private key (PKCS#1 or PKCS#8)
+func parsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA private key")
+	}
+
+	return rsaKey, nil
+}
+
+// parsePublicKey parses a PEM-encoded RSA public key (PKIX or PKCS#1)
+func parsePublicKey(publicKeyPEM string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA public key")
+	}
+
+	return rsaKey, nil
+}
+
+// checkResponseCode returns a provider error unless the SNAP response code is a success (2xx)
+func checkResponseCode(code, message string) error {
+	if strings.HasPrefix(code, "200") {
+		return nil
+	}
+	return pg.WrapProviderError(ProviderName, code, message, nil)
+}
+
+// generateExternalID generates a unique X-EXTERNAL-ID
+func generateExternalID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+// partnerServiceID returns the 8 character, left padded SNAP partner service ID
+// It defaults to the merchant ID and can be overridden with Custom["espay_partner_service_id"]
+func (e *espay) partnerServiceID(params pg.ChargeParams) string {
+	id := e.config.MerchantID
+	if custom, ok := params.Custom["espay_partner_service_id"].(string); ok && custom != "" {
+		id = custom
+	}
+	return fmt.Sprintf("%8s", id)
+}
+
+// customerNo returns the SNAP customer number of a virtual account
+// It defaults to the order ID and can be overridden with VirtualAccount.VANumber
+// or Custom["espay_customer_no"]
+func customerNo(params pg.ChargeParams) string {
+	if custom, ok := params.Custom["espay_customer_no"].(string); ok && custom != "" {
+		return custom
+	}
+	if params.VirtualAccount != nil && params.VirtualAccount.VANumber != "" {
+		return params.VirtualAccount.VANumber
+	}
+	return params.OrderID
+}
+
+// vaReference returns the reference GetStatus and Cancel take for a virtual account
+// It is "va:<order ID>" when the VA uses the default partner service ID and customer number,
+// "va:<partner service ID>:<customer number>:<order ID>" otherwise
+func (e *espay) vaReference(partnerServiceID, customerNo, orderID string) string {
+	partnerServiceID = strings.TrimSpace(partnerServiceID)
+	if partnerServiceID == e.config.MerchantID && customerNo == orderID {
+		return vaReferencePrefix + orderID
+	}
+	return vaReferencePrefix + partnerServiceID + referenceSeparator + customerNo + referenceSeparator + orderID
+}
+
+// parseReference parses the Reference of a charge
+// It returns the Transfer VA request of a reference returned by vaReference, or the order ID
+// of a "debit:" reference. Other references, including plain order IDs, are a *pg.ValidationError
+func (e *espay) parseReference(reference string) (*VARequest, string, error) {
+	if orderID, ok := strings.CutPrefix(reference, debitReferencePrefix); ok && orderID != "" {
+		return nil, orderID, nil
+	}
+
+	rest, ok := strings.CutPrefix(reference, vaReferencePrefix)
+	partnerServiceID, customerNo, trxID := e.config.MerchantID, rest, rest
+	if parts := strings.SplitN(rest, referenceSeparator, 3); len(parts) == 3 {
+		partnerServiceID, customerNo, trxID = parts[0], parts[1], parts[2]
+	}
+	if !ok || partnerServiceID == "" || customerNo == "" || trxID == "" {
+		verr := pg.NewValidationError()
+		verr.Add(pg.NewFieldError("OrderID", "must be the Reference of the charge: debit:<order ID>, va:<order ID> or va:<partner service ID>:<customer number>:<order ID>"))
+		return nil, "", verr.ToError()
+	}
+
+	partnerServiceID = fmt.Sprintf("%8s", partnerServiceID)
+	return &VARequest{
+		PartnerServiceID: partnerServiceID,
+		CustomerNo:       customerNo,
+		VirtualAccountNo: partnerServiceID + customerNo,
+		TrxID:            trxID,
+	}, "", nil
+}
+
+// validateChargeParams validates charge parameters
+// All problems are returned at once in a *pg.ValidationError
+func (e *espay) validateChargeParams(params *pg.ChargeParams) error {
+	verr := pg.NewValidationError()
+	verr.Merge(params.Validate(e))
+
+	// Validate virtual account options
+	verr.Merge(params.VirtualAccount.Validate())
+	if va := params.VirtualAccount; va != nil {
+		if va.MinAmount > 0 || va.MaxAmount > 0 {
+			verr.Add(pg.NewFieldError("VirtualAccount.MinAmount", fmt.Sprintf("min and max amounts are not supported by %s", ProviderName)))
+		}
+		if va.Reusable {
+			verr.Add(pg.NewFieldError("VirtualAccount.Reusable", fmt.Sprintf("reusable VAs are not supported by %s", ProviderName)))
+		}
+	}
+
+	// Validate customer
+	if params.Customer.Name == "" {
+		verr.Add(pg.NewRequiredFieldError("Customer.Name"))
+	}
+
+	return verr.ToError()
+}
+
+// getTimeout returns the timeout duration
+func getTimeout(cfg *pg.ProviderConfig) time.Duration {
+	if cfg.Timeout > 0 {
+		return time.Duration(cfg.Timeout) * time.Second
+	}
+	return 30 * time.Second
+}
+
+// getBaseURL returns the base URL based on environment
+func (e *espay) getBaseURL() string {
+	if e.config.Environment == "production" {
+		return productionURL
+	}
+	return sandboxURL
+}
@@ -0,0 +1,828 @@
+package espay
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+var (
+	testKeysOnce    sync.Once
+	testMerchantKey *rsa.PrivateKey
+	testEspayKey    *rsa.PrivateKey
+)
+
+// testKeys returns the merchant key pair and the Espay key pair used in tests
+func testKeys(t *testing.T) (*rsa.PrivateKey, *rsa.PrivateKey) {
+	t.Helper()
+
+	testKeysOnce.Do(func() {
+		var err error
+		if testMerchantKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		if testEspayKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+	})
+
+	return testMerchantKey, testEspayKey
+}
+
+func privateKeyPEM(key *rsa.PrivateKey) string {
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func publicKeyPEM(key *rsa.PublicKey) string {
+	der, _ := x509.MarshalPKIXPublicKey(key)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// rewriteTransport sends every request to the test server regardless of the original host
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestProvider creates an Espay provider which talks to the given test server
+func newTestProvider(t *testing.T, server *httptest.Server) *espay {
+	t.Helper()
+
+	merchantKey, espayKey := testKeys(t)
+
+	provider, err := New(&pg.ProviderConfig{
+		MerchantID:  "SGWTEST",
+		PrivateKey:  privateKeyPEM(merchantKey),
+		ServerKey:   publicKeyPEM(&espayKey.PublicKey),
+		Environment: "sandbox",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	e := provider.(*espay)
+	if server != nil {
+		target, err := url.Parse(server.URL)
+		if err != nil {
+			t.Fatalf("failed to parse server URL: %v", err)
+		}
+		e.httpCli = &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &rewriteTransport{target: target},
+		}
+	}
+
+	return e
+}
+
+// verifyRequestSignature checks a request signed with the merchant key
+func verifyRequestSignature(t *testing.T, r *http.Request, body []byte) {
+	t.Helper()
+
+	merchantKey, _ := testKeys(t)
+
+	for _, header := range []string{headerXTimestamp, headerXSignature, headerXPartnerID, headerXExternalID, headerChannelID} {
+		if r.Header.Get(header) == "" {
+			t.Errorf("missing header %s", header)
+		}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(r.Header.Get(headerXSignature))
+	if err != nil {
+		t.Fatalf("invalid signature encoding: %v", err)
+	}
+
+	s, err := stringToSign(r.Method, r.URL.Path, body, r.Header.Get(headerXTimestamp))
+	if err != nil {
+		t.Fatalf("stringToSign() error = %v", err)
+	}
+
+	hashed := sha256.Sum256([]byte(s))
+	if err := rsa.VerifyPKCS1v15(&merchantKey.PublicKey, crypto.SHA256, hashed[:], sig); err != nil {
+		t.Errorf("request signature is invalid: %v", err)
+	}
+}
+
+// signedCallback creates a callback request signed with the Espay key
+func signedCallback(t *testing.T, path string, payload interface{}) *http.Request {
+	t.Helper()
+
+	_, espayKey := testKeys(t)
+	return callbackSignedWith(t, espayKey, path, payload)
+}
+
+// callbackSignedWith creates a callback request signed with key
+func callbackSignedWith(t *testing.T, key *rsa.PrivateKey, path string, payload interface{}) *http.Request {
+	t.Helper()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	timestamp := time.Now().In(wib).Format(timestampLayout)
+	s, err := stringToSign(http.MethodPost, path, body, timestamp)
+	if err != nil {
+		t.Fatalf("stringToSign() error = %v", err)
+	}
+
+	hashed := sha256.Sum256([]byte(s))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerXTimestamp, timestamp)
+	req.Header.Set(headerXSignature, base64.StdEncoding.EncodeToString(sig))
+	return req
+}
+
+func validChargeParams(paymentType pg.PaymentType) pg.ChargeParams {
+	return pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: paymentType,
+		Customer: pg.Customer{
+			ID:    "CUST-001",
+			Name:  "John Doe",
+			Email: "john@example.com",
+			Phone: "+62812345678",
+		},
+		ReturnURL: "https://example.com/return",
+	}
+}
+
+func TestNew(t *testing.T) {
+	merchantKey, espayKey := testKeys(t)
+
+	tests := []struct {
+		name    string
+		config  *pg.ProviderConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid config",
+			config:  &pg.ProviderConfig{MerchantID: "SGWTEST", PrivateKey: privateKeyPEM(merchantKey), ServerKey: publicKeyPEM(&espayKey.PublicKey)},
+			wantErr: false,
+		},
+		{
+			name:    "without Espay public key",
+			config:  &pg.ProviderConfig{MerchantID: "SGWTEST", PrivateKey: privateKeyPEM(merchantKey)},
+			wantErr: false,
+		},
+		{
+			name:    "missing merchant id",
+			config:  &pg.ProviderConfig{PrivateKey: privateKeyPEM(merchantKey)},
+			wantErr: true,
+		},
+		{
+			name:    "missing private key",
+			config:  &pg.ProviderConfig{MerchantID: "SGWTEST"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid private key",
+			config:  &pg.ProviderConfig{MerchantID: "SGWTEST", PrivateKey: "not a key"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid public key",
+			config:  &pg.ProviderConfig{MerchantID: "SGWTEST", PrivateKey: privateKeyPEM(merchantKey), ServerKey: "not a key"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := New(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && provider.Name() != ProviderName {
+				t.Errorf("Name() = %v, want %v", provider.Name(), ProviderName)
+			}
+		})
+	}
+}
+
+func TestGetBaseURL(t *testing.T) {
+	e := newTestProvider(t, nil)
+	if got := e.getBaseURL(); got != sandboxURL {
+		t.Errorf("getBaseURL() = %v, want %v", got, sandboxURL)
+	}
+
+	e.config.Environment = "production"
+	if got := e.getBaseURL(); got != productionURL {
+		t.Errorf("getBaseURL() = %v, want %v", got, productionURL)
+	}
+}
+
+func TestStringToSign(t *testing.T) {
+	body := []byte(`{ "a": 1,
+		"b": "x" }`)
+
+	got, err := stringToSign(http.MethodPost, "/api/v1.0/debit/status", body, "2024-01-15T10:30:00+07:00")
+	if err != nil {
+		t.Fatalf("stringToSign() error = %v", err)
+	}
+
+	hash := sha256.Sum256([]byte(`{"a":1,"b":"x"}`))
+	want := "POST:/api/v1.0/debit/status:" + hex.EncodeToString(hash[:]) + ":2024-01-15T10:30:00+07:00"
+	if got != want {
+		t.Errorf("stringToSign() = %v, want %v", got, want)
+	}
+}
+
+func TestCreateCharge_VirtualAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != createVAUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, createVAUri)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		verifyRequestSignature(t, r, body)
+
+		var req CreateVARequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.AdditionalInfo == nil || req.AdditionalInfo.BankCode != BankCodeBCA {
+			t.Errorf("additionalInfo = %+v, want bank code %v", req.AdditionalInfo, BankCodeBCA)
+		}
+		if req.TotalAmount.Value != "50000.00" || req.TotalAmount.Currency != "IDR" {
+			t.Errorf("totalAmount = %+v", req.TotalAmount)
+		}
+		if len(req.PartnerServiceID) != 8 {
+			t.Errorf("partnerServiceId = %q, want 8 characters", req.PartnerServiceID)
+		}
+
+		_ = json.NewEncoder(w).Encode(VAResponse{
+			ResponseCode:    "2002700",
+			ResponseMessage: "Successful",
+			VirtualAccountData: &VirtualAccountData{
+				PartnerServiceID: req.PartnerServiceID,
+				CustomerNo:       req.CustomerNo,
+				VirtualAccountNo: " SGWTESTORDER-001",
+				TrxID:            req.TrxID,
+				ExpiredDate:      req.ExpiredDate,
+			},
+		})
+	}))
+	defer server.Close()
+
+	e := newTestProvider(t, server)
+	resp, err := e.CreateCharge(context.Background(), validChargeParams(pg.PaymentTypeVABCA))
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+
+	if resp.VANumber != "SGWTESTORDER-001" {
+		t.Errorf("VANumber = %v, want SGWTESTORDER-001", resp.VANumber)
+	}
+	if resp.Status != pg.StatusPending {
+		t.Errorf("Status = %v, want %v", resp.Status, pg.StatusPending)
+	}
+	if resp.ExpiryTime.IsZero() {
+		t.Error("ExpiryTime should not be zero")
+	}
+	if resp.Reference != "va:ORDER-001" {
+		t.Errorf("Reference = %v, want va:ORDER-001", resp.Reference)
+	}
+}
+
+func TestCreateCharge_EWallet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != debitPaymentUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, debitPaymentUri)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		verifyRequestSignature(t, r, body)
+
+		var req DebitPaymentRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.AdditionalInfo.ProductCode != ProductCodeOVO {
+			t.Errorf("productCode = %v, want %v", req.AdditionalInfo.ProductCode, ProductCodeOVO)
+		}
+		if len(req.URLParams) != 1 || req.URLParams[0].URL != "https://example.com/return" {
+			t.Errorf("urlParams = %+v", req.URLParams)
+		}
+
+		_ = json.NewEncoder(w).Encode(DebitPaymentResponse{
+			ResponseCode:       "2005400",
+			ResponseMessage:    "Successful",
+			ReferenceNo:        "ESP-REF-001",
+			PartnerReferenceNo: req.PartnerReferenceNo,
+			WebRedirectURL:     "https://sandbox-kit.espay.id/pay/abc",
+		})
+	}))
+	defer server.Close()
+
+	e := newTestProvider(t, server)
+	resp, err := e.CreateCharge(context.Background(), validChargeParams(pg.PaymentTypeOVO))
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+
+	if resp.TransactionID != "ESP-REF-001" {
+		t.Errorf("TransactionID = %v, want ESP-REF-001", resp.TransactionID)
+	}
+	if resp.PaymentURL != "https://sandbox-kit.espay.id/pay/abc" {
+		t.Errorf("PaymentURL = %v", resp.PaymentURL)
+	}
+	if resp.Reference != "debit:ORDER-001" {
+		t.Errorf("Reference = %v, want debit:ORDER-001", resp.Reference)
+	}
+}
+
+func TestCreateCharge_ProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"responseCode":"4012700","responseMessage":"Unauthorized. Signature"}`))
+	}))
+	defer server.Close()
+
+	e := newTestProvider(t, server)
+	_, err := e.CreateCharge(context.Background(), validChargeParams(pg.PaymentTypeVABCA))
+	if err == nil {
+		t.Fatal("CreateCharge() expected error")
+	}
+	if !pg.IsProviderError(err) {
+		t.Errorf("expected provider error, got %v", err)
+	}
+}
+
+func TestGetStatus_VirtualAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != vaStatusUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, vaStatusUri)
+		}
+
+		var req VARequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.PartnerServiceID != " SGWTEST" || req.CustomerNo != "ORDER-001" || req.VirtualAccountNo != " SGWTESTORDER-001" || req.TrxID != "ORDER-001" {
+			t.Errorf("request = %+v", req)
+		}
+
+		_ = json.NewEncoder(w).Encode(VAResponse{
+			ResponseCode:    "2002600",
+			ResponseMessage: "Successful",
+			VirtualAccountData: &VirtualAccountData{
+				TotalAmount:       &Amount{Value: "50000.00", Currency: "IDR"},
+				PaidAmount:        &Amount{Value: "50000.00", Currency: "IDR"},
+				TrxDateTime:       "2024-01-15T10:30:00+07:00",
+				PaymentFlagStatus: PaymentFlagSuccess,
+			},
+		})
+	}))
+	defer server.Close()
+
+	// The VA is derived from the reference, a new provider instance checks it
+	e := newTestProvider(t, server)
+	status, err := e.GetStatus(context.Background(), "va:ORDER-001")
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+
+	if status.Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", status.Status, pg.StatusSuccess)
+	}
+	if status.PaidAmount != 50000 {
+		t.Errorf("PaidAmount = %v, want 50000", status.PaidAmount)
+	}
+	if status.PaidAt == nil {
+		t.Error("PaidAt should be set")
+	}
+}
+
+func TestGetStatus_Debit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != debitStatusUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, debitStatusUri)
+		}
+
+		var req DebitStatusRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.OriginalPartnerReferenceNo != "ORDER-002" || req.ServiceCode != serviceCodeDebitPayment {
+			t.Errorf("request = %+v", req)
+		}
+
+		_ = json.NewEncoder(w).Encode(DebitStatusResponse{
+			ResponseCode:               "2005500",
+			ResponseMessage:            "Successful",
+			OriginalPartnerReferenceNo: "ORDER-002",
+			OriginalReferenceNo:        "ESP-REF-002",
+			LatestTransactionStatus:    TransactionStatusFailed,
+			TransactionStatusDesc:      "Insufficient balance",
+			Amount:                     &Amount{Value: "50000.00", Currency: "IDR"},
+		})
+	}))
+	defer server.Close()
+
+	e := newTestProvider(t, server)
+	status, err := e.GetStatus(context.Background(), "debit:ORDER-002")
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+
+	if status.Status != pg.StatusFailed {
+		t.Errorf("Status = %v, want %v", status.Status, pg.StatusFailed)
+	}
+	if status.FailureReason != "Insufficient balance" {
+		t.Errorf("FailureReason = %v", status.FailureReason)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == deleteVAUri {
+			_, _ = w.Write([]byte(`{"responseCode":"2003100","responseMessage":"Successful"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"responseCode":"2005700","responseMessage":"Successful"}`))
+	}))
+	defer server.Close()
+
+	e := newTestProvider(t, server)
+	if err := e.Cancel(context.Background(), "va:ORDER-001"); err != nil {
+		t.Errorf("Cancel() VA error = %v", err)
+	}
+	if err := e.Cancel(context.Background(), "debit:ORDER-002"); err != nil {
+		t.Errorf("Cancel() debit error = %v", err)
+	}
+
+	if len(paths) != 2 || paths[0] != deleteVAUri || paths[1] != debitCancelUri {
+		t.Errorf("paths = %v", paths)
+	}
+}
+
+func TestGetStatus_UnknownReference(t *testing.T) {
+	e := newTestProvider(t, nil)
+
+	// A plain order ID could be a VA or a Direct Debit payment, so it is rejected before any request
+	for _, reference := range []string{"ORDER-001", "va:", "debit:", "va::0812345:ORDER-001", "ovo:ORDER-001"} {
+		if _, err := e.GetStatus(context.Background(), reference); !pg.IsValidationError(err) || !errors.Is(err, pg.ErrInvalidParameter) {
+			t.Errorf("GetStatus(%q) error = %v, want a validation error", reference, err)
+		}
+		if err := e.Cancel(context.Background(), reference); !pg.IsValidationError(err) {
+			t.Errorf("Cancel(%q) error = %v, want a validation error", reference, err)
+		}
+	}
+}
+
+func TestVAReference(t *testing.T) {
+	e := newTestProvider(t, nil)
+
+	tests := []struct {
+		name             string
+		partnerServiceID string
+		customerNo       string
+		orderID          string
+		want             string
+	}{
+		{name: "defaults", partnerServiceID: " SGWTEST", customerNo: "ORDER-001", orderID: "ORDER-001", want: "va:ORDER-001"},
+		{name: "custom customer number", partnerServiceID: " SGWTEST", customerNo: "0812345", orderID: "ORDER-001", want: "va:SGWTEST:0812345:ORDER-001"},
+		{name: "custom partner service ID", partnerServiceID: "   12345", customerNo: "ORDER-001", orderID: "ORDER-001", want: "va:12345:ORDER-001:ORDER-001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := e.vaReference(tt.partnerServiceID, tt.customerNo, tt.orderID)
+			if ref != tt.want {
+				t.Fatalf("vaReference() = %v, want %v", ref, tt.want)
+			}
+
+			req, _, err := e.parseReference(ref)
+			if err != nil || req == nil {
+				t.Fatalf("parseReference() = %+v, %v", req, err)
+			}
+			if req.PartnerServiceID != tt.partnerServiceID || req.CustomerNo != tt.customerNo || req.TrxID != tt.orderID {
+				t.Errorf("parseReference() = %+v", req)
+			}
+		})
+	}
+
+	if req, orderID, err := e.parseReference("debit:ORDER-002"); err != nil || req != nil || orderID != "ORDER-002" {
+		t.Errorf("parseReference(debit:ORDER-002) = %+v, %v, %v, want the Direct Debit order", req, orderID, err)
+	}
+}
+
+func TestGetToken(t *testing.T) {
+	e := newTestProvider(t, nil)
+	if _, err := e.GetToken(context.Background()); err == nil {
+		t.Error("GetToken() expected error")
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	e := newTestProvider(t, nil)
+	payload := map[string]interface{}{"originalPartnerReferenceNo": "ORDER-001", "latestTransactionStatus": "00"}
+
+	t.Run("valid signature", func(t *testing.T) {
+		req := signedCallback(t, "/v1.0/debit/notify", payload)
+		if !e.VerifyWebhook(req) {
+			t.Error("VerifyWebhook() = false, want true")
+		}
+		// Body is restored for ParseWebhook
+		if _, err := e.ParseWebhook(req); err != nil {
+			t.Errorf("ParseWebhook() error = %v", err)
+		}
+	})
+
+	t.Run("tampered timestamp", func(t *testing.T) {
+		req := signedCallback(t, "/v1.0/debit/notify", payload)
+		req.Header.Set(headerXTimestamp, "2020-01-01T00:00:00+07:00")
+		if e.VerifyWebhook(req) {
+			t.Error("VerifyWebhook() = true, want false")
+		}
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		req := signedCallback(t, "/v1.0/debit/notify", payload)
+		req.Header.Del(headerXSignature)
+		if e.VerifyWebhook(req) {
+			t.Error("VerifyWebhook() = true, want false")
+		}
+	})
+
+	t.Run("without Espay public key", func(t *testing.T) {
+		e := newTestProvider(t, nil)
+		e.publicKey = nil
+		if e.VerifyWebhook(signedCallback(t, "/v1.0/debit/notify", payload)) {
+			t.Error("VerifyWebhook() = true, want false")
+		}
+	})
+}
+
+func TestParseWebhook_Inquiry(t *testing.T) {
+	e := newTestProvider(t, nil)
+	e.config.BillResolver = func(ctx context.Context, vaNumber string) (*pg.Bill, error) {
+		switch vaNumber {
+		case "SGWTESTORDER-001":
+			return &pg.Bill{OrderID: "ORDER-001", Name: "John Doe", Amount: 50000, PaymentType: pg.PaymentTypeVABCA}, nil
+		case "SGWTESTBROKEN":
+			return nil, errors.New("database is down")
+		}
+		return nil, nil
+	}
+
+	t.Run("known virtual account", func(t *testing.T) {
+		req := signedCallback(t, "/v1.0/transfer-va/inquiry", map[string]interface{}{
+			"partnerServiceId": " SGWTEST",
+			"customerNo":       "ORDER-001",
+			"virtualAccountNo": " SGWTESTORDER-001",
+			"inquiryRequestId": "INQ-001",
+		})
+
+		event, err := e.ParseWebhook(req)
+		if err != nil {
+			t.Fatalf("ParseWebhook() error = %v", err)
+		}
+
+		if event.EventType != pg.EventPaymentInquiry {
+			t.Errorf("EventType = %v, want %v", event.EventType, pg.EventPaymentInquiry)
+		}
+		if event.OrderID != "ORDER-001" || event.Amount != 50000 {
+			t.Errorf("OrderID/Amount = %v/%v", event.OrderID, event.Amount)
+		}
+
+		rec := httptest.NewRecorder()
+		_ = event.Acknowledge(rec)
+		if rec.Code != http.StatusOK {
+			t.Errorf("ack status = %v, want 200", rec.Code)
+		}
+
+		var ack CallbackResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &ack); err != nil {
+			t.Fatalf("failed to decode acknowledgement: %v", err)
+		}
+		if ack.ResponseCode != responseCodeInquirySuccess {
+			t.Errorf("responseCode = %v, want %v", ack.ResponseCode, responseCodeInquirySuccess)
+		}
+		if ack.VirtualAccountData == nil || ack.VirtualAccountData.TotalAmount.Value != "50000.00" || ack.VirtualAccountData.InquiryRequestID != "INQ-001" {
+			t.Errorf("virtualAccountData = %+v", ack.VirtualAccountData)
+		}
+	})
+
+	t.Run("unknown virtual account", func(t *testing.T) {
+		req := signedCallback(t, "/v1.0/transfer-va/inquiry", map[string]interface{}{
+			"partnerServiceId": " SGWTEST",
+			"customerNo":       "UNKNOWN",
+			"virtualAccountNo": " SGWTESTUNKNOWN",
+			"inquiryRequestId": "INQ-002",
+		})
+
+		event, err := e.ParseWebhook(req)
+		if err != nil {
+			t.Fatalf("ParseWebhook() error = %v", err)
+		}
+
+		if event.Acknowledgement == nil || event.Acknowledgement.StatusCode != http.StatusNotFound {
+			t.Fatalf("Acknowledgement = %+v, want 404", event.Acknowledgement)
+		}
+		if !strings.Contains(string(event.Acknowledgement.Body), responseCodeBillNotFound) {
+			t.Errorf("ack body = %s", event.Acknowledgement.Body)
+		}
+	})
+
+	t.Run("resolver error", func(t *testing.T) {
+		req := signedCallback(t, "/v1.0/transfer-va/inquiry", map[string]interface{}{
+			"partnerServiceId": " SGWTEST",
+			"customerNo":       "BROKEN",
+			"virtualAccountNo": " SGWTESTBROKEN",
+			"inquiryRequestId": "INQ-003",
+		})
+
+		if _, err := e.ParseWebhook(req); err == nil {
+			t.Error("ParseWebhook() expected error when the bill cannot be resolved")
+		}
+	})
+
+	t.Run("no resolver", func(t *testing.T) {
+		e := newTestProvider(t, nil)
+		req := signedCallback(t, "/v1.0/transfer-va/inquiry", map[string]interface{}{
+			"partnerServiceId": " SGWTEST",
+			"customerNo":       "ORDER-001",
+			"virtualAccountNo": " SGWTESTORDER-001",
+			"inquiryRequestId": "INQ-004",
+		})
+
+		event, err := e.ParseWebhook(req)
+		if err != nil {
+			t.Fatalf("ParseWebhook() error = %v", err)
+		}
+		if event.Acknowledgement == nil || event.Acknowledgement.StatusCode != http.StatusNotFound {
+			t.Errorf("Acknowledgement = %+v, want 404", event.Acknowledgement)
+		}
+	})
+}
+
+func TestParseWebhook_Payment(t *testing.T) {
+	e := newTestProvider(t, nil)
+
+	req := signedCallback(t, "/v1.0/transfer-va/payment", map[string]interface{}{
+		"partnerServiceId":   " SGWTEST",
+		"customerNo":         "ORDER-001",
+		"virtualAccountNo":   " SGWTESTORDER-001",
+		"virtualAccountName": "John Doe",
+		"paymentRequestId":   "PAY-001",
+		"trxId":              "ORDER-001",
+		"paidAmount":         map[string]string{"value": "50000.00", "currency": "IDR"},
+		"trxDateTime":        "2024-01-15T10:30:00+07:00",
+		"additionalInfo":     map[string]string{"bankCode": "014"},
+	})
+
+	event, err := e.ParseWebhook(req)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+
+	if event.OrderID != "ORDER-001" || event.TransactionID != "PAY-001" {
+		t.Errorf("OrderID/TransactionID = %v/%v", event.OrderID, event.TransactionID)
+	}
+	if event.Status != pg.StatusSuccess || event.EventType != pg.EventPaymentCompleted {
+		t.Errorf("Status/EventType = %v/%v", event.Status, event.EventType)
+	}
+	if event.Amount != 50000 {
+		t.Errorf("Amount = %v, want 50000", event.Amount)
+	}
+	if event.PaymentType != pg.PaymentTypeVABCA {
+		t.Errorf("PaymentType = %v, want %v", event.PaymentType, pg.PaymentTypeVABCA)
+	}
+	if event.Reference != "va:ORDER-001" {
+		t.Errorf("Reference = %v, want va:ORDER-001", event.Reference)
+	}
+
+	var ack CallbackResponse
+	if err := json.Unmarshal(event.Acknowledgement.Body, &ack); err != nil {
+		t.Fatalf("failed to decode acknowledgement: %v", err)
+	}
+	if ack.ResponseCode != responseCodePaymentSuccess || ack.VirtualAccountData.PaymentFlagStatus != PaymentFlagSuccess {
+		t.Errorf("acknowledgement = %+v", ack)
+	}
+}
+
+func TestParseWebhook_DebitNotification(t *testing.T) {
+	e := newTestProvider(t, nil)
+
+	req := signedCallback(t, "/v1.0/debit/notify", map[string]interface{}{
+		"originalPartnerReferenceNo": "ORDER-002",
+		"originalReferenceNo":        "ESP-REF-002",
+		"latestTransactionStatus":    "00",
+		"amount":                     map[string]string{"value": "50000.00", "currency": "IDR"},
+		"finishedTime":               "2024-01-15T10:30:00+07:00",
+		"additionalInfo":             map[string]string{"productCode": "DANA"},
+	})
+
+	event, err := e.ParseWebhook(req)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+
+	if event.OrderID != "ORDER-002" || event.Status != pg.StatusSuccess {
+		t.Errorf("OrderID/Status = %v/%v", event.OrderID, event.Status)
+	}
+	if event.PaymentType != pg.PaymentTypeDANA {
+		t.Errorf("PaymentType = %v, want %v", event.PaymentType, pg.PaymentTypeDANA)
+	}
+	if event.Reference != "debit:ORDER-002" {
+		t.Errorf("Reference = %v, want debit:ORDER-002", event.Reference)
+	}
+	if !strings.Contains(string(event.Acknowledgement.Body), responseCodeNotifySuccess) {
+		t.Errorf("ack body = %s", event.Acknowledgement.Body)
+	}
+}
+
+func TestParseWebhook_InvalidPayload(t *testing.T) {
+	e := newTestProvider(t, nil)
+
+	for _, body := range []string{"{invalid", `{"foo":"bar"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		if _, err := e.ParseWebhook(req); err != pg.ErrInvalidPayload {
+			t.Errorf("ParseWebhook(%s) error = %v, want %v", body, err, pg.ErrInvalidPayload)
+		}
+	}
+}
+
+func TestValidateChargeParams(t *testing.T) {
+	e := newTestProvider(t, nil)
+
+	tests := []struct {
+		name    string
+		modify  func(p *pg.ChargeParams)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *pg.ChargeParams) {}, wantErr: false},
+		{name: "missing order id", modify: func(p *pg.ChargeParams) { p.OrderID = "" }, wantErr: true},
+		{name: "missing customer name", modify: func(p *pg.ChargeParams) { p.Customer.Name = "" }, wantErr: true},
+		{name: "invalid email", modify: func(p *pg.ChargeParams) { p.Customer.Email = "invalid" }, wantErr: true},
+		{name: "unsupported payment type", modify: func(p *pg.ChargeParams) { p.PaymentType = pg.PaymentTypeGoPay }, wantErr: true},
+		{name: "credit card", modify: func(p *pg.ChargeParams) { p.PaymentType = pg.PaymentTypeCC }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validChargeParams(pg.PaymentTypeVABCA)
+			tt.modify(&params)
+			err := e.validateChargeParams(&params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateChargeParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMapper_MapTransactionStatus(t *testing.T) {
+	m := &Mapper{}
+
+	tests := []struct {
+		status TransactionStatus
+		want   pg.Status
+	}{
+		{TransactionStatusSuccess, pg.StatusSuccess},
+		{TransactionStatusInitiated, pg.StatusPending},
+		{TransactionStatusPaying, pg.StatusProcessing},
+		{TransactionStatusPending, pg.StatusPending},
+		{TransactionStatusRefunded, pg.StatusCancelled},
+		{TransactionStatusCancelled, pg.StatusCancelled},
+		{TransactionStatusFailed, pg.StatusFailed},
+		{TransactionStatusNotFound, pg.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		if got := m.mapTransactionStatus(tt.status); got != tt.want {
+			t.Errorf("mapTransactionStatus(%v) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
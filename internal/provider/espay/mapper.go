@@ -0,0 +1,378 @@
+package espay
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+// wib is the Western Indonesia Time zone used by SNAP timestamps
+var wib = time.FixedZone("WIB", 7*60*60)
+
+// Mapper handles conversion between unified and Espay-specific types
+type Mapper struct{}
+
+// mapBankCode maps unified payment type to Espay bank code
+func (m *Mapper) mapBankCode(pt pg.PaymentType) BankCode {
+	switch pt {
+	case pg.PaymentTypeVABCA:
+		return BankCodeBCA
+	case pg.PaymentTypeVABNI:
+		return BankCodeBNI
+	case pg.PaymentTypeVABRI:
+		return BankCodeBRI
+	case pg.PaymentTypeVAMandiri:
+		return BankCodeMandiri
+	case pg.PaymentTypeVAPermata:
+		return BankCodePermata
+	case pg.PaymentTypeVACIMB:
+		return BankCodeCIMB
+	default:
+		return ""
+	}
+}
+
+// mapProductCode maps unified payment type to Espay e-wallet product code
+func (m *Mapper) mapProductCode(pt pg.PaymentType) ProductCode {
+	switch pt {
+	case pg.PaymentTypeOVO:
+		return ProductCodeOVO
+	case pg.PaymentTypeDANA:
+		return ProductCodeDANA
+	case pg.PaymentTypeLinkAja:
+		return ProductCodeLinkAja
+	case pg.PaymentTypeShopeePay:
+		return ProductCodeShopeePay
+	default:
+		return ""
+	}
+}
+
+// unifiedPaymentType maps Espay bank code or product code to unified payment type
+func (m *Mapper) unifiedPaymentType(code string) pg.PaymentType {
+	switch code {
+	case string(BankCodeBCA):
+		return pg.PaymentTypeVABCA
+	case string(BankCodeBNI):
+		return pg.PaymentTypeVABNI
+	case string(BankCodeBRI):
+		return pg.PaymentTypeVABRI
+	case string(BankCodeMandiri):
+		return pg.PaymentTypeVAMandiri
+	case string(BankCodePermata):
+		return pg.PaymentTypeVAPermata
+	case string(BankCodeCIMB):
+		return pg.PaymentTypeVACIMB
+	case string(ProductCodeOVO):
+		return pg.PaymentTypeOVO
+	case string(ProductCodeDANA):
+		return pg.PaymentTypeDANA
+	case string(ProductCodeLinkAja):
+		return pg.PaymentTypeLinkAja
+	case string(ProductCodeShopeePay):
+		return pg.PaymentTypeShopeePay
+	default:
+		return pg.PaymentType(code)
+	}
+}
+
+// mapTransactionStatus maps SNAP latestTransactionStatus to unified status
+func (m *Mapper) mapTransactionStatus(status TransactionStatus) pg.Status {
+	switch status {
+	case TransactionStatusSuccess:
+		return pg.StatusSuccess
+	case TransactionStatusInitiated, TransactionStatusPending:
+		return pg.StatusPending
+	case TransactionStatusPaying:
+		return pg.StatusProcessing
+	case TransactionStatusRefunded, TransactionStatusCancelled:
+		return pg.StatusCancelled
+	case TransactionStatusFailed, TransactionStatusNotFound:
+		return pg.StatusFailed
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapPaymentFlagStatus maps SNAP virtual account paymentFlagStatus to unified status
+func (m *Mapper) mapPaymentFlagStatus(status PaymentFlagStatus) pg.Status {
+	switch status {
+	case PaymentFlagSuccess:
+		return pg.StatusSuccess
+	case PaymentFlagRejected:
+		return pg.StatusFailed
+	case PaymentFlagTimeout:
+		return pg.StatusExpired
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapEventType maps unified status to event type
+func (m *Mapper) mapEventType(status pg.Status) string {
+	switch status {
+	case pg.StatusSuccess:
+		return pg.EventPaymentCompleted
+	case pg.StatusFailed:
+		return pg.EventPaymentFailed
+	case pg.StatusCancelled:
+		return pg.EventPaymentCancelled
+	case pg.StatusExpired:
+		return pg.EventPaymentExpired
+	default:
+		return pg.EventPaymentPending
+	}
+}
+
+// mapToCreateVARequest maps unified ChargeParams to Espay CreateVARequest
+func (m *Mapper) mapToCreateVARequest(partnerServiceID, customerNo string, params pg.ChargeParams) *CreateVARequest {
+	req := &CreateVARequest{
+		PartnerServiceID:      partnerServiceID,
+		CustomerNo:            customerNo,
+		VirtualAccountNo:      partnerServiceID + customerNo,
+		VirtualAccountName:    params.Customer.Name,
+		VirtualAccountEmail:   params.Customer.Email,
+		VirtualAccountPhone:   params.Customer.Phone,
+		TrxID:                 params.OrderID,
+		TotalAmount:           Amount{Value: formatAmount(params.Amount), Currency: currencyIDR},
+		VirtualAccountTrxType: "C", // closed amount
+		ExpiredDate:           expiryTime(params).Format(timestampLayout),
+		AdditionalInfo: &VAAdditionalInfo{
+			BankCode:    m.mapBankCode(params.PaymentType),
+			Description: params.Description,
+		},
+	}
+
+	if va := params.VirtualAccount; va != nil {
+		if va.CustomerName != "" {
+			req.VirtualAccountName = va.CustomerName
+		}
+		if va.OpenAmount {
+			req.VirtualAccountTrxType = "O" // open amount
+		}
+	}
+
+	return req
+}
+
+// mapToDebitPaymentRequest maps unified ChargeParams to Espay DebitPaymentRequest
+func (m *Mapper) mapToDebitPaymentRequest(merchantID string, params pg.ChargeParams) *DebitPaymentRequest {
+	req := &DebitPaymentRequest{
+		PartnerReferenceNo: params.OrderID,
+		MerchantID:         merchantID,
+		Amount:             Amount{Value: formatAmount(params.Amount), Currency: currencyIDR},
+		ValidUpTo:          expiryTime(params).Format(timestampLayout),
+		AdditionalInfo: &DebitAdditionalInfo{
+			ProductCode: m.mapProductCode(params.PaymentType),
+			PayerPhone:  params.Customer.Phone,
+			PayerEmail:  params.Customer.Email,
+			PayerName:   params.Customer.Name,
+		},
+	}
+
+	if params.ReturnURL != "" {
+		req.URLParams = []URLParam{
+			{URL: params.ReturnURL, Type: "PAY_RETURN", IsDeeplink: "N"},
+		}
+	}
+
+	return req
+}
+
+// mapVAToChargeResponse maps Espay VAResponse to unified ChargeResponse
+func (m *Mapper) mapVAToChargeResponse(resp *VAResponse, req *CreateVARequest, params pg.ChargeParams) *pg.ChargeResponse {
+	if resp == nil {
+		return nil
+	}
+
+	unified := &pg.ChargeResponse{
+		TransactionID: params.OrderID,
+		OrderID:       params.OrderID,
+		Amount:        params.Amount,
+		Status:        pg.StatusPending,
+		VANumber:      strings.TrimSpace(req.VirtualAccountNo),
+		VABank:        string(params.PaymentType),
+		CreatedAt:     time.Now(),
+		Raw:           toRaw(resp),
+	}
+
+	expiredDate := req.ExpiredDate
+	if data := resp.VirtualAccountData; data != nil {
+		if data.VirtualAccountNo != "" {
+			unified.VANumber = strings.TrimSpace(data.VirtualAccountNo)
+		}
+		if data.ExpiredDate != "" {
+			expiredDate = data.ExpiredDate
+		}
+	}
+
+	if expiry, err := time.Parse(time.RFC3339, expiredDate); err == nil {
+		unified.ExpiryTime = expiry
+	}
+
+	return unified
+}
+
+// mapDebitToChargeResponse maps Espay DebitPaymentResponse to unified ChargeResponse
+func (m *Mapper) mapDebitToChargeResponse(resp *DebitPaymentResponse, req *DebitPaymentRequest, params pg.ChargeParams) *pg.ChargeResponse {
+	if resp == nil {
+		return nil
+	}
+
+	unified := &pg.ChargeResponse{
+		TransactionID: resp.ReferenceNo,
+		OrderID:       params.OrderID,
+		Amount:        params.Amount,
+		Status:        pg.StatusPending,
+		PaymentURL:    resp.WebRedirectURL,
+		CreatedAt:     time.Now(),
+		Raw:           toRaw(resp),
+	}
+
+	if resp.WebRedirectURL != "" {
+		unified.NextActions = append(unified.NextActions, pg.Action{Type: pg.ActionRedirect, URL: resp.WebRedirectURL, Method: "GET"})
+	}
+	if resp.AppRedirectURL != "" {
+		unified.NextActions = append(unified.NextActions, pg.Action{Type: pg.ActionDeeplink, URL: resp.AppRedirectURL, Method: "GET"})
+	}
+
+	if unified.PaymentURL == "" {
+		unified.PaymentURL = resp.AppRedirectURL
+	}
+
+	if expiry, err := time.Parse(time.RFC3339, req.ValidUpTo); err == nil {
+		unified.ExpiryTime = expiry
+	}
+
+	return unified
+}
+
+// mapVAToPaymentStatus maps Espay virtual account data to unified PaymentStatus
+func (m *Mapper) mapVAToPaymentStatus(orderID string, resp *VAResponse) *pg.PaymentStatus {
+	if resp == nil || resp.VirtualAccountData == nil {
+		return nil
+	}
+
+	data := resp.VirtualAccountData
+	status := m.mapPaymentFlagStatus(data.PaymentFlagStatus)
+
+	result := &pg.PaymentStatus{
+		TransactionID: orderID,
+		OrderID:       orderID,
+		Status:        status,
+		Amount:        parseAmount(data.TotalAmount),
+		PaidAmount:    parseAmount(data.PaidAmount),
+	}
+
+	if paidAt, err := time.Parse(time.RFC3339, data.TrxDateTime); err == nil {
+		switch status {
+		case pg.StatusSuccess:
+			result.PaidAt = &paidAt
+		case pg.StatusExpired:
+			result.ExpiredAt = &paidAt
+		}
+	}
+
+	if status == pg.StatusFailed && data.PaymentFlagReason != nil {
+		result.FailureReason = data.PaymentFlagReason.English
+	}
+
+	return result
+}
+
+// mapDebitToPaymentStatus maps Espay DebitStatusResponse to unified PaymentStatus
+func (m *Mapper) mapDebitToPaymentStatus(orderID string, resp *DebitStatusResponse) *pg.PaymentStatus {
+	if resp == nil {
+		return nil
+	}
+
+	status := m.mapTransactionStatus(resp.LatestTransactionStatus)
+	amount := parseAmount(resp.Amount)
+
+	result := &pg.PaymentStatus{
+		TransactionID: resp.OriginalReferenceNo,
+		OrderID:       orderID,
+		Status:        status,
+		Amount:        amount,
+	}
+
+	if status == pg.StatusSuccess {
+		result.PaidAmount = amount
+		if paidAt, err := time.Parse(time.RFC3339, resp.PaidTime); err == nil {
+			result.PaidAt = &paidAt
+		}
+	}
+
+	if status == pg.StatusFailed {
+		result.FailureReason = resp.TransactionStatusDesc
+	}
+
+	return result
+}
+
+// mapDebitNotificationToWebhookEvent maps Espay DebitNotification to unified WebhookEvent
+func (m *Mapper) mapDebitNotificationToWebhookEvent(n *DebitNotification) *pg.WebhookEvent {
+	status := m.mapTransactionStatus(n.LatestTransactionStatus)
+
+	timestamp := time.Now()
+	if finished, err := time.Parse(time.RFC3339, n.FinishedTime); err == nil {
+		timestamp = finished
+	}
+
+	event := &pg.WebhookEvent{
+		OrderID:       n.OriginalPartnerReferenceNo,
+		TransactionID: n.OriginalReferenceNo,
+		Status:        status,
+		Amount:        parseAmount(n.Amount),
+		EventType:     m.mapEventType(status),
+		Timestamp:     timestamp,
+		Raw:           toRaw(n),
+	}
+
+	if productCode, ok := n.AdditionalInfo["productCode"].(string); ok {
+		event.PaymentType = m.unifiedPaymentType(productCode)
+	}
+
+	return event
+}
+
+// expiryTime returns the payment expiry time in WIB
+func expiryTime(params pg.ChargeParams) time.Time {
+	if !params.ExpiryTime.IsZero() {
+		return params.ExpiryTime.In(wib)
+	}
+	return time.Now().In(wib).Add(pg.DefaultExpiryMinutes * time.Minute)
+}
+
+// formatAmount formats amount as SNAP amount value (e.g. "50000.00")
+func formatAmount(amount int64) string {
+	return strconv.FormatInt(amount, 10) + ".00"
+}
+
+// parseAmount parses a SNAP amount value
+func parseAmount(amount *Amount) int64 {
+	if amount == nil || amount.Value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(amount.Value, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(f)
+}
+
+// toRaw converts an Espay payload to a generic map for the Raw field
+func toRaw(v interface{}) map[string]interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil
+	}
+	return raw
+}
@@ -0,0 +1,239 @@
+package espay
+
+// BankCode represents Espay bank codes for virtual accounts
+type BankCode string
+
+const (
+	// BankCodeBCA BCA Virtual Account
+	BankCodeBCA BankCode = "014"
+	// BankCodeBNI BNI Virtual Account
+	BankCodeBNI BankCode = "009"
+	// BankCodeBRI BRI Virtual Account
+	BankCodeBRI BankCode = "002"
+	// BankCodeMandiri Mandiri Virtual Account
+	BankCodeMandiri BankCode = "008"
+	// BankCodePermata Permata Virtual Account
+	BankCodePermata BankCode = "013"
+	// BankCodeCIMB CIMB Niaga Virtual Account
+	BankCodeCIMB BankCode = "022"
+)
+
+// ProductCode represents Espay e-wallet product codes
+type ProductCode string
+
+const (
+	// ProductCodeOVO OVO
+	ProductCodeOVO ProductCode = "OVO"
+	// ProductCodeDANA DANA
+	ProductCodeDANA ProductCode = "DANA"
+	// ProductCodeLinkAja LinkAja
+	ProductCodeLinkAja ProductCode = "LINKAJA"
+	// ProductCodeShopeePay ShopeePay
+	ProductCodeShopeePay ProductCode = "SHOPEEPAY"
+)
+
+// TransactionStatus represents SNAP latestTransactionStatus values
+type TransactionStatus string
+
+const (
+	// TransactionStatusSuccess means the payment is successful
+	TransactionStatusSuccess TransactionStatus = "00"
+	// TransactionStatusInitiated means the payment is created
+	TransactionStatusInitiated TransactionStatus = "01"
+	// TransactionStatusPaying means the payment is in progress
+	TransactionStatusPaying TransactionStatus = "02"
+	// TransactionStatusPending means the payment is waiting for the customer
+	TransactionStatusPending TransactionStatus = "03"
+	// TransactionStatusRefunded means the payment is refunded
+	TransactionStatusRefunded TransactionStatus = "04"
+	// TransactionStatusCancelled means the payment is cancelled
+	TransactionStatusCancelled TransactionStatus = "05"
+	// TransactionStatusFailed means the payment failed
+	TransactionStatusFailed TransactionStatus = "06"
+	// TransactionStatusNotFound means the payment is not found
+	TransactionStatusNotFound TransactionStatus = "07"
+)
+
+// PaymentFlagStatus represents SNAP virtual account paymentFlagStatus values
+type PaymentFlagStatus string
+
+const (
+	// PaymentFlagSuccess means the virtual account is paid
+	PaymentFlagSuccess PaymentFlagStatus = "00"
+	// PaymentFlagRejected means the payment is rejected
+	PaymentFlagRejected PaymentFlagStatus = "01"
+	// PaymentFlagTimeout means the payment timed out
+	PaymentFlagTimeout PaymentFlagStatus = "02"
+)
+
+// SNAP response codes used in callback acknowledgements
+const (
+	responseCodeInquirySuccess  = "2002400"
+	responseCodeBillNotFound    = "4042412"
+	responseCodePaymentSuccess  = "2002500"
+	responseCodeNotifySuccess   = "2005600"
+	responseMessageSuccessful   = "Successful"
+	responseMessageBillNotFound = "Invalid Bill/Virtual Account [Not Found]"
+)
+
+// Amount represents a SNAP amount
+type Amount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// Reason represents a bilingual SNAP reason
+type Reason struct {
+	English   string `json:"english"`
+	Indonesia string `json:"indonesia"`
+}
+
+// VAAdditionalInfo represents additional info of a virtual account request
+type VAAdditionalInfo struct {
+	BankCode    BankCode `json:"bankCode,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// CreateVARequest for Create Virtual Account
+type CreateVARequest struct {
+	PartnerServiceID      string            `json:"partnerServiceId"`
+	CustomerNo            string            `json:"customerNo"`
+	VirtualAccountNo      string            `json:"virtualAccountNo"`
+	VirtualAccountName    string            `json:"virtualAccountName"`
+	VirtualAccountEmail   string            `json:"virtualAccountEmail,omitempty"`
+	VirtualAccountPhone   string            `json:"virtualAccountPhone,omitempty"`
+	TrxID                 string            `json:"trxId"`
+	TotalAmount           Amount            `json:"totalAmount"`
+	VirtualAccountTrxType string            `json:"virtualAccountTrxType"`
+	ExpiredDate           string            `json:"expiredDate"`
+	AdditionalInfo        *VAAdditionalInfo `json:"additionalInfo,omitempty"`
+}
+
+// VirtualAccountData represents virtual account data in SNAP responses and callbacks
+type VirtualAccountData struct {
+	PartnerServiceID   string                 `json:"partnerServiceId"`
+	CustomerNo         string                 `json:"customerNo"`
+	VirtualAccountNo   string                 `json:"virtualAccountNo"`
+	VirtualAccountName string                 `json:"virtualAccountName,omitempty"`
+	InquiryRequestID   string                 `json:"inquiryRequestId,omitempty"`
+	PaymentRequestID   string                 `json:"paymentRequestId,omitempty"`
+	TrxID              string                 `json:"trxId,omitempty"`
+	TotalAmount        *Amount                `json:"totalAmount,omitempty"`
+	PaidAmount         *Amount                `json:"paidAmount,omitempty"`
+	ExpiredDate        string                 `json:"expiredDate,omitempty"`
+	TrxDateTime        string                 `json:"trxDateTime,omitempty"`
+	InquiryStatus      string                 `json:"inquiryStatus,omitempty"`
+	InquiryReason      *Reason                `json:"inquiryReason,omitempty"`
+	PaymentFlagStatus  PaymentFlagStatus      `json:"paymentFlagStatus,omitempty"`
+	PaymentFlagReason  *Reason                `json:"paymentFlagReason,omitempty"`
+	AdditionalInfo     map[string]interface{} `json:"additionalInfo,omitempty"`
+}
+
+// VAResponse from Create, Status and Delete Virtual Account
+type VAResponse struct {
+	ResponseCode       string              `json:"responseCode"`
+	ResponseMessage    string              `json:"responseMessage"`
+	VirtualAccountData *VirtualAccountData `json:"virtualAccountData,omitempty"`
+}
+
+// VARequest for Virtual Account Status and Delete Virtual Account
+type VARequest struct {
+	PartnerServiceID string `json:"partnerServiceId"`
+	CustomerNo       string `json:"customerNo"`
+	VirtualAccountNo string `json:"virtualAccountNo"`
+	TrxID            string `json:"trxId,omitempty"`
+	InquiryRequestID string `json:"inquiryRequestId,omitempty"`
+}
+
+// URLParam represents a redirect URL of a direct debit payment
+type URLParam struct {
+	URL        string `json:"url"`
+	Type       string `json:"type"`
+	IsDeeplink string `json:"isDeeplink"`
+}
+
+// DebitAdditionalInfo represents additional info of a direct debit payment
+type DebitAdditionalInfo struct {
+	ProductCode ProductCode `json:"productCode"`
+	PayerPhone  string      `json:"payerPhone,omitempty"`
+	PayerEmail  string      `json:"payerEmail,omitempty"`
+	PayerName   string      `json:"payerName,omitempty"`
+}
+
+// DebitPaymentRequest for Direct Debit Payment Host to Host (e-wallet)
+type DebitPaymentRequest struct {
+	PartnerReferenceNo string               `json:"partnerReferenceNo"`
+	MerchantID         string               `json:"merchantId"`
+	Amount             Amount               `json:"amount"`
+	URLParams          []URLParam           `json:"urlParams,omitempty"`
+	ValidUpTo          string               `json:"validUpTo"`
+	AdditionalInfo     *DebitAdditionalInfo `json:"additionalInfo"`
+}
+
+// DebitPaymentResponse from Direct Debit Payment Host to Host
+type DebitPaymentResponse struct {
+	ResponseCode       string `json:"responseCode"`
+	ResponseMessage    string `json:"responseMessage"`
+	ReferenceNo        string `json:"referenceNo"`
+	PartnerReferenceNo string `json:"partnerReferenceNo"`
+	WebRedirectURL     string `json:"webRedirectUrl,omitempty"`
+	AppRedirectURL     string `json:"appRedirectUrl,omitempty"`
+}
+
+// DebitStatusRequest for Direct Debit Status
+type DebitStatusRequest struct {
+	OriginalPartnerReferenceNo string `json:"originalPartnerReferenceNo"`
+	MerchantID                 string `json:"merchantId"`
+	ServiceCode                string `json:"serviceCode"`
+}
+
+// DebitStatusResponse from Direct Debit Status
+type DebitStatusResponse struct {
+	ResponseCode               string            `json:"responseCode"`
+	ResponseMessage            string            `json:"responseMessage"`
+	OriginalPartnerReferenceNo string            `json:"originalPartnerReferenceNo"`
+	OriginalReferenceNo        string            `json:"originalReferenceNo"`
+	LatestTransactionStatus    TransactionStatus `json:"latestTransactionStatus"`
+	TransactionStatusDesc      string            `json:"transactionStatusDesc"`
+	Amount                     *Amount           `json:"amount,omitempty"`
+	PaidTime                   string            `json:"paidTime,omitempty"`
+}
+
+// DebitCancelRequest for Direct Debit Cancel
+type DebitCancelRequest struct {
+	OriginalPartnerReferenceNo string `json:"originalPartnerReferenceNo"`
+	MerchantID                 string `json:"merchantId"`
+	Reason                     string `json:"reason,omitempty"`
+}
+
+// DebitCancelResponse from Direct Debit Cancel
+type DebitCancelResponse struct {
+	ResponseCode               string `json:"responseCode"`
+	ResponseMessage            string `json:"responseMessage"`
+	OriginalPartnerReferenceNo string `json:"originalPartnerReferenceNo"`
+	OriginalReferenceNo        string `json:"originalReferenceNo"`
+}
+
+// DebitNotification is the Direct Debit payment notification sent by Espay
+type DebitNotification struct {
+	OriginalPartnerReferenceNo string                 `json:"originalPartnerReferenceNo"`
+	OriginalReferenceNo        string                 `json:"originalReferenceNo"`
+	LatestTransactionStatus    TransactionStatus      `json:"latestTransactionStatus"`
+	TransactionStatusDesc      string                 `json:"transactionStatusDesc"`
+	Amount                     *Amount                `json:"amount,omitempty"`
+	FinishedTime               string                 `json:"finishedTime,omitempty"`
+	AdditionalInfo             map[string]interface{} `json:"additionalInfo,omitempty"`
+}
+
+// CallbackResponse is the acknowledgement of an inquiry, payment or debit notification callback
+type CallbackResponse struct {
+	ResponseCode       string              `json:"responseCode"`
+	ResponseMessage    string              `json:"responseMessage"`
+	VirtualAccountData *VirtualAccountData `json:"virtualAccountData,omitempty"`
+}
+
+// errorResponse is the body of a rejected SNAP request
+type errorResponse struct {
+	ResponseCode    string `json:"responseCode"`
+	ResponseMessage string `json:"responseMessage"`
+}
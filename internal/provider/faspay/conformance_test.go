@@ -0,0 +1,66 @@
+package faspay
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+	"github.com/pandudpn/go-payment-gateway/pgtest"
+)
+
+// conformanceStatuses maps the unified statuses to the Faspay payment status codes
+var conformanceStatuses = map[pg.Status]PaymentStatusCode{
+	pg.StatusSuccess:    StatusSuccess,
+	pg.StatusPending:    StatusUnprocessed,
+	pg.StatusProcessing: StatusInProcess,
+	pg.StatusFailed:     StatusFailed,
+	pg.StatusCancelled:  StatusCancelled,
+	pg.StatusExpired:    StatusExpired,
+}
+
+// jsonHandler answers every request with body and the HTTP status code
+func jsonHandler(code int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	})
+}
+
+func TestFaspay_Conformance(t *testing.T) {
+	pgtest.RunProviderConformance(t, New, pgtest.Stub{
+		Config: pg.ProviderConfig{MerchantID: "31835", ClientKey: "bot31835", ServerKey: "p@ssw0rd"},
+		Charge: pg.ChargeParams{
+			OrderID:     "ORDER-001",
+			Amount:      150000,
+			PaymentType: pg.PaymentTypeVABCA,
+			Customer:    pg.Customer{ID: "CUST-001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890"},
+			Items:       []pg.Item{{ID: "SKU-001", Name: "Kaos Polos", Price: 75000, Quantity: 2}},
+			CallbackURL: "https://example.com/callback",
+		},
+		Reference:    reference("3183540000001234", "ORDER-001"),
+		CreateCharge: jsonHandler(http.StatusOK, `{"response":"Transmisi Info Detil Pembelian","trx_id":"3183540000001234","merchant_id":"31835","merchant":"Toko Budi","bill_no":"ORDER-001","response_code":"00","response_desc":"Sukses"}`),
+		Rejected:     jsonHandler(http.StatusOK, `{"response":"Transmisi Info Detil Pembelian","merchant_id":"31835","bill_no":"ORDER-001","response_code":"52","response_desc":"Bill No Already Exist"}`),
+		Statuses:     []pg.Status{pg.StatusSuccess, pg.StatusPending, pg.StatusProcessing, pg.StatusFailed, pg.StatusCancelled, pg.StatusExpired},
+		Status: func(status pg.Status) http.Handler {
+			return jsonHandler(http.StatusOK, `{"response":"Inquiry Status Payment","trx_id":"3183540000001234","merchant_id":"31835","merchant":"Toko Budi","bill_no":"ORDER-001","payment_reff":"123456","payment_date":"2024-03-01 10:15:30","payment_status_code":"`+string(conformanceStatuses[status])+`","payment_status_desc":"`+string(status)+`","response_code":"00","response_desc":"Sukses"}`)
+		},
+		Cancel: jsonHandler(http.StatusOK, `{"response":"Canceling Payment","trx_id":"3183540000001234","bill_no":"ORDER-001","payment_status_code":"8","payment_status_desc":"Payment Cancelled","response_code":"00","response_desc":"Sukses"}`),
+		Webhook: func(status pg.Status, signed bool) *http.Request {
+			password := "p@ssw0rd"
+			if !signed {
+				password = "wrong-password"
+			}
+			code := string(conformanceStatuses[status])
+			signature := utils.CalculateSHA1(utils.CalculateMD5("bot31835" + password + "ORDER-001" + code))
+			body := `{"request":"Payment Notification","trx_id":"3183540000001234","merchant_id":"31835","merchant":"Toko Budi","bill_no":"ORDER-001","payment_reff":"123456","payment_date":"2024-03-01 10:15:30","payment_status_code":"` + code + `","payment_status_desc":"` + string(status) + `","bill_total":"150000","payment_total":"150000","payment_channel_uid":"702","payment_channel":"BCA Virtual Account","signature":"` + signature + `"}`
+
+			r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			return r
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package faspay
+
+import (
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// ProviderName is the name of the Faspay provider
+	ProviderName = "faspay"
+
+	// Debit (Billing) API URLs
+	sandboxURL    = "https://debit-sandbox.faspay.co.id"
+	productionURL = "https://web.faspay.co.id"
+
+	// Xpress (hosted payment page) URLs
+	xpressSandboxURL    = "https://xpress-sandbox.faspay.co.id"
+	xpressProductionURL = "https://xpress.faspay.co.id"
+
+	// dateLayout is the date format used by Faspay (WIB)
+	dateLayout = "2006-01-02 15:04:05"
+
+	// referenceSeparator separates trx_id and bill_no in an explicit reference
+	referenceSeparator = ":"
+)
+
+// customOptions are the pg.ChargeParams.Custom keys read by the provider, they are not merged into the request body
+var customOptions = []string{"merchant_name", "faspay_xpress"}
+
+// amountLimits are the Faspay amount limits per payment type, a zero Max has no maximum
+// They are overridden with pg.WithAmountLimits
+var amountLimits = map[pg.PaymentType]pg.AmountLimit{
+	pg.PaymentTypeGoPay:     {Min: 10000, Max: 20000000},
+	pg.PaymentTypeOVO:       {Min: 10000, Max: 10000000},
+	pg.PaymentTypeDANA:      {Min: 10000, Max: 20000000},
+	pg.PaymentTypeShopeePay: {Min: 10000, Max: 20000000},
+	pg.PaymentTypeLinkAja:   {Min: 10000, Max: 10000000},
+	pg.PaymentTypeQRIS:      {Min: 1000, Max: 10000000},
+	pg.PaymentTypeVABCA:     {Min: 10000},
+	pg.PaymentTypeVABNI:     {Min: 10000},
+	pg.PaymentTypeVABRI:     {Min: 10000},
+	pg.PaymentTypeVAMandiri: {Min: 10000},
+	pg.PaymentTypeVAPermata: {Min: 10000},
+	pg.PaymentTypeVACIMB:    {Min: 10000},
+	pg.PaymentTypeCC:        {Min: 10000},
+	pg.PaymentTypeAlfamart:  {Min: 10000, Max: 5000000},
+	pg.PaymentTypeIndomaret: {Min: 10000, Max: 5000000},
+}
@@ -0,0 +1,407 @@
+package faspay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+)
+
+const (
+	// API endpoints
+	postDataUri = "/cvr/300011/10"
+	statusUri   = "/cvr/100004/10"
+	cancelUri   = "/cvr/100005/10"
+	xpressUri   = "/v4/post"
+)
+
+func init() {
+	// Register this provider with the pg package
+	pg.RegisterProvider(ProviderName, New)
+}
+
+type faspay struct {
+	config  *pg.ProviderConfig
+	mapper  *Mapper
+	httpCli *http.Client
+}
+
+// New creates a new Faspay provider
+// MerchantID is the Faspay merchant ID, ClientKey is the user ID (e.g. bot31835)
+// and ServerKey is the password
+func New(cfg *pg.ProviderConfig) (pg.Provider, error) {
+	if cfg.ServerKey == "" || cfg.ClientKey == "" || cfg.MerchantID == "" {
+		return nil, pg.ErrMissingCredentials
+	}
+
+	return &faspay{
+		config:  cfg,
+		mapper:  &Mapper{},
+		httpCli: &http.Client{Timeout: getTimeout(cfg), Transport: cfg.Transport},
+	}, nil
+}
+
+// Name returns the provider name
+func (f *faspay) Name() string {
+	return ProviderName
+}
+
+// CreateCharge creates a new bill
+// Credit card payments (or any charge with Custom["faspay_xpress"] set) use the Xpress payment page,
+// other channels use the Debit (Billing) API
+func (f *faspay) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	// Validate parameters
+	if err := f.validateChargeParams(&params); err != nil {
+		return nil, err
+	}
+
+	req := f.mapper.mapToBillRequest(f.config.MerchantID, params)
+
+	var fullURL string
+	if useXpress(params) {
+		req.Request = "Transmisi Info Detil Pembelian"
+		req.Signature = f.xpressSignature(req.BillNo, req.BillTotal)
+		fullURL = f.getXpressURL() + xpressUri
+	} else {
+		req.Signature = f.billSignature(req.BillNo)
+		fullURL = f.getBaseURL() + postDataUri
+	}
+
+	responseBody, err := f.sendRequest(ctx, fullURL, pg.WithCustom(req, params.Custom, customOptions...))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp BillResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if resp.ResponseCode != responseCodeSuccess {
+		return nil, pg.WrapProviderError(ProviderName, resp.ResponseCode, resp.ResponseDesc, nil)
+	}
+
+	return f.mapper.mapToChargeResponse(&resp, req, params), nil
+}
+
+// sendRequest sends a JSON POST request to Faspay and returns the response body
+func (f *faspay) sendRequest(ctx context.Context, fullURL string, payload interface{}) ([]byte, error) {
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+	}
+
+	return responseBody, nil
+}
+
+// GetStatus retrieves payment status
+// Faspay needs the trx_id of the bill, so orderID is a "trx_id:bill_no" reference,
+// returned as the Reference of the charge and of the webhook
+func (f *faspay) GetStatus(ctx context.Context, orderID string) (*pg.PaymentStatus, error) {
+	trxID, billNo, err := parseReference(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &StatusRequest{
+		Request:    "Inquiry Status Payment",
+		TrxID:      trxID,
+		MerchantID: f.config.MerchantID,
+		BillNo:     billNo,
+		Signature:  f.billSignature(billNo),
+	}
+
+	responseBody, err := f.sendRequest(ctx, f.getBaseURL()+statusUri, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var faspayResponse StatusResponse
+	if err := json.Unmarshal(responseBody, &faspayResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if faspayResponse.ResponseCode != responseCodeSuccess {
+		return nil, pg.WrapProviderError(ProviderName, faspayResponse.ResponseCode, faspayResponse.ResponseDesc, nil)
+	}
+
+	return f.mapper.mapToPaymentStatus(billNo, &faspayResponse), nil
+}
+
+// Cancel cancels an unpaid bill
+// orderID accepts the same formats as GetStatus
+func (f *faspay) Cancel(ctx context.Context, orderID string) error {
+	trxID, billNo, err := parseReference(orderID)
+	if err != nil {
+		return err
+	}
+
+	req := &CancelRequest{
+		Request:       "Canceling Payment",
+		TrxID:         trxID,
+		MerchantID:    f.config.MerchantID,
+		Merchant:      f.config.MerchantID,
+		BillNo:        billNo,
+		PaymentCancel: "User requested cancellation",
+		Signature:     f.billSignature(billNo),
+	}
+
+	responseBody, err := f.sendRequest(ctx, f.getBaseURL()+cancelUri, req)
+	if err != nil {
+		return err
+	}
+
+	var resp CancelResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if resp.ResponseCode != responseCodeSuccess {
+		return pg.WrapProviderError(ProviderName, resp.ResponseCode, resp.ResponseDesc, nil)
+	}
+
+	return nil
+}
+
+// VerifyWebhook verifies payment notification signature
+// Faspay signature: SHA1(MD5(user_id + password + bill_no + payment_status_code))
+func (f *faspay) VerifyWebhook(r *http.Request) bool {
+	notification, _, err := f.readNotification(r)
+	if err != nil {
+		return false
+	}
+
+	if notification.Signature == "" || notification.MerchantID != f.config.MerchantID {
+		return false
+	}
+
+	expected := f.notificationSignature(notification.BillNo, string(notification.PaymentStatusCode))
+	return utils.SecureCompare(notification.Signature, expected)
+}
+
+// ParseWebhook parses payment notification payload
+// The returned event carries the acknowledgement Faspay requires, in the same format (JSON or XML)
+// as the notification
+func (f *faspay) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
+	notification, isXML, err := f.readNotification(r)
+	if err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	if notification.BillNo == "" {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	ack, err := f.acknowledgement(notification, isXML)
+	if err != nil {
+		return nil, err
+	}
+
+	event := f.mapper.mapToWebhookEvent(notification)
+	event.Acknowledgement = ack
+
+	return event, nil
+}
+
+// readNotification reads a JSON or XML payment notification and restores the request body
+func (f *faspay) readNotification(r *http.Request) (*Notification, bool, error) {
+	if r.Body == nil {
+		return nil, false, pg.ErrInvalidPayload
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Restore body for subsequent reads
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var notification Notification
+	trimmed := bytes.TrimSpace(body)
+	isXML := bytes.HasPrefix(trimmed, []byte("<"))
+
+	if isXML {
+		err = xml.Unmarshal(trimmed, &notification)
+	} else {
+		err = json.Unmarshal(trimmed, &notification)
+	}
+	if err != nil {
+		return nil, isXML, err
+	}
+
+	return &notification, isXML, nil
+}
+
+// acknowledgement builds the Payment Notification response expected by Faspay
+func (f *faspay) acknowledgement(n *Notification, isXML bool) (*pg.WebhookAcknowledgement, error) {
+	resp := &NotificationResponse{
+		Response:     "Payment Notification",
+		TrxID:        n.TrxID,
+		MerchantID:   n.MerchantID,
+		Merchant:     n.Merchant,
+		BillNo:       n.BillNo,
+		ResponseCode: responseCodeSuccess,
+		ResponseDesc: "Success",
+		ResponseDate: time.Now().In(wib).Format(dateLayout),
+	}
+
+	if isXML {
+		body, err := xml.Marshal(resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal acknowledgement: %w", err)
+		}
+		return &pg.WebhookAcknowledgement{
+			ContentType: "application/xml",
+			Body:        append([]byte(xml.Header), body...),
+		}, nil
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal acknowledgement: %w", err)
+	}
+	return &pg.WebhookAcknowledgement{
+		ContentType: "application/json",
+		Body:        body,
+	}, nil
+}
+
+// GetToken retrieves an access token for the provider
+// Faspay signs each request with user ID and password, so this returns an error
+func (f *faspay) GetToken(ctx context.Context) (*pg.TokenResponse, error) {
+	return nil, fmt.Errorf("GetToken API is not supported by %s. Faspay signs every request with User ID and Password", ProviderName)
+}
+
+// Capabilities returns the payment types and operations supported by Faspay
+// GoPay and credit cards are paid on the Xpress page
+func (f *faspay) Capabilities() pg.Capabilities {
+	types := []pg.PaymentType{
+		pg.PaymentTypeGoPay, pg.PaymentTypeOVO, pg.PaymentTypeDANA, pg.PaymentTypeShopeePay, pg.PaymentTypeLinkAja,
+		pg.PaymentTypeQRIS,
+		pg.PaymentTypeVABCA, pg.PaymentTypeVABNI, pg.PaymentTypeVABRI, pg.PaymentTypeVAMandiri, pg.PaymentTypeVAPermata, pg.PaymentTypeVACIMB,
+		pg.PaymentTypeCC,
+		pg.PaymentTypeAlfamart, pg.PaymentTypeIndomaret,
+	}
+
+	return pg.Capabilities{
+		PaymentTypes: types,
+		Operations:   []pg.Operation{pg.OperationCancel},
+		Limits:       pg.NewLimits(types, amountLimits, f.config.AmountLimits),
+		Currencies:   []string{"IDR"},
+		SandboxURL:   true,
+	}
+}
+
+// billSignature generates signature for Debit API requests
+// Format: SHA1(MD5(user_id + password + bill_no))
+func (f *faspay) billSignature(billNo string) string {
+	return utils.CalculateSHA1(utils.CalculateMD5(f.config.ClientKey + f.config.ServerKey + billNo))
+}
+
+// xpressSignature generates signature for Xpress requests
+// Format: SHA1(MD5(user_id + password + bill_no + bill_total))
+func (f *faspay) xpressSignature(billNo, billTotal string) string {
+	return utils.CalculateSHA1(utils.CalculateMD5(f.config.ClientKey + f.config.ServerKey + billNo + billTotal))
+}
+
+// notificationSignature generates the expected signature of a payment notification
+// Format: SHA1(MD5(user_id + password + bill_no + payment_status_code))
+func (f *faspay) notificationSignature(billNo, statusCode string) string {
+	return utils.CalculateSHA1(utils.CalculateMD5(f.config.ClientKey + f.config.ServerKey + billNo + statusCode))
+}
+
+// parseReference splits a "trx_id:bill_no" reference
+func parseReference(orderID string) (string, string, error) {
+	trxID, billNo, ok := strings.Cut(orderID, referenceSeparator)
+	if !ok || trxID == "" || billNo == "" {
+		return "", "", pg.NewFieldError("OrderID", "must be in trx_id:bill_no format, Faspay needs the trx_id of the bill")
+	}
+	return trxID, billNo, nil
+}
+
+// useXpress returns true if the charge should use the Xpress payment page
+func useXpress(params pg.ChargeParams) bool {
+	if params.PaymentType.IsCreditCard() {
+		return true
+	}
+	xpress, _ := params.Custom["faspay_xpress"].(bool)
+	return xpress
+}
+
+// validateChargeParams validates charge parameters
+// All problems are returned at once in a *pg.ValidationError
+func (f *faspay) validateChargeParams(params *pg.ChargeParams) error {
+	verr := pg.NewValidationError()
+	verr.Merge(params.Validate(f))
+
+	// Validate customer
+	if params.Customer.Name == "" {
+		verr.Add(pg.NewRequiredFieldError("Customer.Name"))
+	}
+	if params.Customer.Phone == "" {
+		verr.Add(pg.NewRequiredFieldError("Customer.Phone"))
+	}
+
+	// Channels without a Debit API code are only offered on the Xpress page
+	if params.PaymentType.IsValid() && !useXpress(*params) && f.mapper.mapChannel(params.PaymentType) == "" {
+		verr.Add(pg.NewFieldError("PaymentType", fmt.Sprintf("payment type %s is only supported through Xpress by %s", params.PaymentType, ProviderName)))
+	}
+
+	return verr.ToError()
+}
+
+// getTimeout returns the timeout duration
+func getTimeout(cfg *pg.ProviderConfig) time.Duration {
+	if cfg.Timeout > 0 {
+		return time.Duration(cfg.Timeout) * time.Second
+	}
+	return 30 * time.Second
+}
+
+// getBaseURL returns the Debit API base URL based on environment
+func (f *faspay) getBaseURL() string {
+	if f.config.Environment == "production" {
+		return productionURL
+	}
+	return sandboxURL
+}
+
+// getXpressURL returns the Xpress base URL based on environment
+func (f *faspay) getXpressURL() string {
+	if f.config.Environment == "production" {
+		return xpressProductionURL
+	}
+	return xpressSandboxURL
+}
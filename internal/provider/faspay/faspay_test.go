@@ -0,0 +1,644 @@
+package faspay
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+)
+
+// rewriteTransport sends every request to the test server regardless of the original host
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestProvider creates a Faspay provider which talks to the given test server
+func newTestProvider(t *testing.T, server *httptest.Server) *faspay {
+	t.Helper()
+
+	cli := &http.Client{Timeout: 10 * time.Second}
+	if server != nil {
+		target, err := url.Parse(server.URL)
+		if err != nil {
+			t.Fatalf("failed to parse server URL: %v", err)
+		}
+		cli.Transport = &rewriteTransport{target: target}
+	}
+
+	return &faspay{
+		config: &pg.ProviderConfig{
+			ServerKey:   "p@ssw0rd",
+			ClientKey:   "bot31835",
+			MerchantID:  "31835",
+			Environment: "sandbox",
+		},
+		mapper:  &Mapper{},
+		httpCli: cli,
+	}
+}
+
+func validChargeParams(paymentType pg.PaymentType) pg.ChargeParams {
+	return pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: paymentType,
+		Customer: pg.Customer{
+			ID:    "CUST-001",
+			Name:  "John Doe",
+			Email: "john@example.com",
+			Phone: "+62812345678",
+		},
+		Items: []pg.Item{
+			{
+				ID:       "ITEM-001",
+				Name:     "Test Product",
+				Price:    25000,
+				Quantity: 2,
+			},
+		},
+		ReturnURL: "https://example.com/return",
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *pg.ProviderConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid config",
+			config:  &pg.ProviderConfig{ServerKey: "pass", ClientKey: "bot1", MerchantID: "1"},
+			wantErr: false,
+		},
+		{
+			name:    "missing password",
+			config:  &pg.ProviderConfig{ClientKey: "bot1", MerchantID: "1"},
+			wantErr: true,
+		},
+		{
+			name:    "missing user id",
+			config:  &pg.ProviderConfig{ServerKey: "pass", MerchantID: "1"},
+			wantErr: true,
+		},
+		{
+			name:    "missing merchant id",
+			config:  &pg.ProviderConfig{ServerKey: "pass", ClientKey: "bot1"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := New(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && provider.Name() != ProviderName {
+				t.Errorf("Name() = %v, want %v", provider.Name(), ProviderName)
+			}
+		})
+	}
+}
+
+func TestGetBaseURL(t *testing.T) {
+	f := newTestProvider(t, nil)
+	if got := f.getBaseURL(); got != sandboxURL {
+		t.Errorf("getBaseURL() = %v, want %v", got, sandboxURL)
+	}
+	if got := f.getXpressURL(); got != xpressSandboxURL {
+		t.Errorf("getXpressURL() = %v, want %v", got, xpressSandboxURL)
+	}
+
+	f.config.Environment = "production"
+	if got := f.getBaseURL(); got != productionURL {
+		t.Errorf("getBaseURL() = %v, want %v", got, productionURL)
+	}
+	if got := f.getXpressURL(); got != xpressProductionURL {
+		t.Errorf("getXpressURL() = %v, want %v", got, xpressProductionURL)
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	f := newTestProvider(t, nil)
+
+	want := utils.CalculateSHA1(utils.CalculateMD5("bot31835p@ssw0rdORDER-001"))
+	if got := f.billSignature("ORDER-001"); got != want {
+		t.Errorf("billSignature() = %v, want %v", got, want)
+	}
+
+	want = utils.CalculateSHA1(utils.CalculateMD5("bot31835p@ssw0rdORDER-0015000000"))
+	if got := f.xpressSignature("ORDER-001", "5000000"); got != want {
+		t.Errorf("xpressSignature() = %v, want %v", got, want)
+	}
+
+	want = utils.CalculateSHA1(utils.CalculateMD5("bot31835p@ssw0rdORDER-0012"))
+	if got := f.notificationSignature("ORDER-001", "2"); got != want {
+		t.Errorf("notificationSignature() = %v, want %v", got, want)
+	}
+}
+
+func TestCreateCharge(t *testing.T) {
+	tests := []struct {
+		name        string
+		paymentType pg.PaymentType
+		wantPath    string
+		wantChannel Channel
+		wantVA      bool
+	}{
+		{name: "BCA virtual account", paymentType: pg.PaymentTypeVABCA, wantPath: postDataUri, wantChannel: ChannelVABCA, wantVA: true},
+		{name: "OVO", paymentType: pg.PaymentTypeOVO, wantPath: postDataUri, wantChannel: ChannelOVO},
+		{name: "QRIS", paymentType: pg.PaymentTypeQRIS, wantPath: postDataUri, wantChannel: ChannelQRIS},
+		{name: "Alfamart", paymentType: pg.PaymentTypeAlfamart, wantPath: postDataUri, wantChannel: ChannelAlfamart, wantVA: true},
+		{name: "credit card via Xpress", paymentType: pg.PaymentTypeCC, wantPath: xpressUri},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("path = %v, want %v", r.URL.Path, tt.wantPath)
+				}
+
+				var req BillRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("failed to decode request: %v", err)
+				}
+				if req.PaymentChannel != tt.wantChannel {
+					t.Errorf("payment_channel = %v, want %v", req.PaymentChannel, tt.wantChannel)
+				}
+				if req.BillTotal != "5000000" {
+					t.Errorf("bill_total = %v, want 5000000", req.BillTotal)
+				}
+				if req.Msisdn != "0812345678" {
+					t.Errorf("msisdn = %v, want 0812345678", req.Msisdn)
+				}
+
+				f := newTestProvider(t, nil)
+				wantSig := f.billSignature(req.BillNo)
+				if tt.wantPath == xpressUri {
+					wantSig = f.xpressSignature(req.BillNo, req.BillTotal)
+				}
+				if req.Signature != wantSig {
+					t.Errorf("signature = %v, want %v", req.Signature, wantSig)
+				}
+
+				_ = json.NewEncoder(w).Encode(BillResponse{
+					Response:     req.Request,
+					TrxID:        "3183570200000001",
+					MerchantID:   req.MerchantID,
+					BillNo:       req.BillNo,
+					ResponseCode: "00",
+					ResponseDesc: "Sukses",
+					RedirectURL:  "https://xpress-sandbox.faspay.co.id/v4/pay/abc",
+				})
+			}))
+			defer server.Close()
+
+			f := newTestProvider(t, server)
+			resp, err := f.CreateCharge(context.Background(), validChargeParams(tt.paymentType))
+			if err != nil {
+				t.Fatalf("CreateCharge() error = %v", err)
+			}
+
+			if resp.TransactionID != "3183570200000001" {
+				t.Errorf("TransactionID = %v, want 3183570200000001", resp.TransactionID)
+			}
+			if resp.Status != pg.StatusPending {
+				t.Errorf("Status = %v, want %v", resp.Status, pg.StatusPending)
+			}
+			if tt.wantVA && resp.VANumber != "3183570200000001" {
+				t.Errorf("VANumber = %v, want 3183570200000001", resp.VANumber)
+			}
+			if resp.PaymentURL == "" {
+				t.Error("PaymentURL should not be empty")
+			}
+			if resp.ExpiryTime.IsZero() {
+				t.Error("ExpiryTime should not be zero")
+			}
+
+			// The reference to check the bill is returned to the caller
+			if resp.Reference != "3183570200000001:ORDER-001" {
+				t.Errorf("Reference = %v, want 3183570200000001:ORDER-001", resp.Reference)
+			}
+		})
+	}
+}
+
+func TestCreateCharge_ProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(BillResponse{
+			ResponseCode: "30",
+			ResponseDesc: "Signature not valid",
+		})
+	}))
+	defer server.Close()
+
+	f := newTestProvider(t, server)
+	_, err := f.CreateCharge(context.Background(), validChargeParams(pg.PaymentTypeVABCA))
+	if err == nil {
+		t.Fatal("CreateCharge() expected error")
+	}
+	if !pg.IsProviderError(err) {
+		t.Errorf("expected provider error, got %v", err)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != statusUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, statusUri)
+		}
+
+		var req StatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.TrxID != "3183570200000001" || req.BillNo != "ORDER-001" {
+			t.Errorf("trx_id/bill_no = %v/%v", req.TrxID, req.BillNo)
+		}
+
+		_ = json.NewEncoder(w).Encode(StatusResponse{
+			TrxID:             req.TrxID,
+			BillNo:            req.BillNo,
+			BillTotal:         "5000000",
+			PaymentTotal:      "5000000",
+			PaymentDate:       "2024-01-15 10:30:00",
+			PaymentStatusCode: StatusSuccess,
+			PaymentStatusDesc: "Payment Sukses",
+			ResponseCode:      "00",
+			ResponseDesc:      "Sukses",
+		})
+	}))
+	defer server.Close()
+
+	f := newTestProvider(t, server)
+	status, err := f.GetStatus(context.Background(), "3183570200000001:ORDER-001")
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+
+	if status.OrderID != "ORDER-001" {
+		t.Errorf("OrderID = %v, want ORDER-001", status.OrderID)
+	}
+	if status.Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", status.Status, pg.StatusSuccess)
+	}
+	if status.Amount != 50000 || status.PaidAmount != 50000 {
+		t.Errorf("Amount/PaidAmount = %v/%v, want 50000", status.Amount, status.PaidAmount)
+	}
+	if status.PaidAt == nil {
+		t.Error("PaidAt should be set")
+	}
+}
+
+func TestGetStatus_UnknownReference(t *testing.T) {
+	f := newTestProvider(t, nil)
+
+	for _, orderID := range []string{"ORDER-UNKNOWN", ":ORDER-001", "TRX:"} {
+		if _, err := f.GetStatus(context.Background(), orderID); err == nil {
+			t.Errorf("GetStatus(%q) expected error", orderID)
+		}
+	}
+}
+
+func TestClient_GetStatus_ChargeReference(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case postDataUri:
+			_ = json.NewEncoder(w).Encode(BillResponse{
+				TrxID:        "3183570200000001",
+				MerchantID:   "31835",
+				BillNo:       "ORDER-001",
+				ResponseCode: "00",
+				ResponseDesc: "Sukses",
+			})
+		case statusUri:
+			var req StatusRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			if req.TrxID != "3183570200000001" || req.BillNo != "ORDER-001" {
+				t.Errorf("trx_id/bill_no = %v/%v, want the charge", req.TrxID, req.BillNo)
+			}
+
+			_ = json.NewEncoder(w).Encode(StatusResponse{
+				TrxID:             req.TrxID,
+				BillNo:            req.BillNo,
+				PaymentStatusCode: StatusUnprocessed,
+				ResponseCode:      "00",
+				ResponseDesc:      "Sukses",
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+	client, err := pg.NewClient(
+		pg.WithProvider(ProviderName),
+		pg.WithServerKey("p@ssw0rd"),
+		pg.WithClientKey("bot31835"),
+		pg.WithMerchantID("31835"),
+		pg.WithTransport(&rewriteTransport{target: target}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	resp, err := client.CreateCharge(context.Background(), validChargeParams(pg.PaymentTypeVABCA))
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+
+	status, err := client.GetStatus(context.Background(), resp.Reference)
+	if err != nil {
+		t.Fatalf("GetStatus(%q) error = %v", resp.Reference, err)
+	}
+	if status.OrderID != "ORDER-001" || status.Status != pg.StatusPending {
+		t.Errorf("status = %+v, want pending ORDER-001", status)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != cancelUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, cancelUri)
+		}
+
+		var req CancelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		_ = json.NewEncoder(w).Encode(CancelResponse{
+			TrxID:             req.TrxID,
+			BillNo:            req.BillNo,
+			PaymentStatusCode: StatusCancelled,
+			ResponseCode:      "00",
+			ResponseDesc:      "Sukses",
+		})
+	}))
+	defer server.Close()
+
+	f := newTestProvider(t, server)
+	if err := f.Cancel(context.Background(), "3183570200000001:ORDER-001"); err != nil {
+		t.Errorf("Cancel() error = %v", err)
+	}
+
+	// A bare bill_no cannot be cancelled, nothing is remembered between calls
+	if err := f.Cancel(context.Background(), "ORDER-001"); err == nil {
+		t.Error("Cancel() expected error without the trx_id")
+	}
+}
+
+func TestGetToken(t *testing.T) {
+	f := newTestProvider(t, nil)
+	if _, err := f.GetToken(context.Background()); err == nil {
+		t.Error("GetToken() expected error")
+	}
+}
+
+func testNotification(f *faspay, statusCode PaymentStatusCode) *Notification {
+	return &Notification{
+		Request:           "Payment Notification",
+		TrxID:             "3183570200000001",
+		MerchantID:        "31835",
+		Merchant:          "Test Merchant",
+		BillNo:            "ORDER-001",
+		PaymentReff:       "123456",
+		PaymentDate:       "2024-01-15 10:30:00",
+		PaymentStatusCode: statusCode,
+		PaymentStatusDesc: "Payment Sukses",
+		BillTotal:         "5000000",
+		PaymentTotal:      "5000000",
+		PaymentChannelUID: "702",
+		PaymentChannel:    "BCA Virtual Account",
+		Signature:         f.notificationSignature("ORDER-001", string(statusCode)),
+	}
+}
+
+func TestWebhook_JSON(t *testing.T) {
+	f := newTestProvider(t, nil)
+
+	body, _ := json.Marshal(testNotification(f, StatusSuccess))
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+
+	if !f.VerifyWebhook(req) {
+		t.Fatal("VerifyWebhook() = false, want true")
+	}
+
+	event, err := f.ParseWebhook(req)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+
+	if event.OrderID != "ORDER-001" {
+		t.Errorf("OrderID = %v, want ORDER-001", event.OrderID)
+	}
+	if event.Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", event.Status, pg.StatusSuccess)
+	}
+	if event.EventType != pg.EventPaymentCompleted {
+		t.Errorf("EventType = %v, want %v", event.EventType, pg.EventPaymentCompleted)
+	}
+	if event.Amount != 50000 {
+		t.Errorf("Amount = %v, want 50000", event.Amount)
+	}
+	if event.PaymentType != pg.PaymentTypeVABCA {
+		t.Errorf("PaymentType = %v, want %v", event.PaymentType, pg.PaymentTypeVABCA)
+	}
+
+	if event.Acknowledgement == nil {
+		t.Fatal("Acknowledgement should be set")
+	}
+	if event.Acknowledgement.ContentType != "application/json" {
+		t.Errorf("ContentType = %v, want application/json", event.Acknowledgement.ContentType)
+	}
+	var ack NotificationResponse
+	if err := json.Unmarshal(event.Acknowledgement.Body, &ack); err != nil {
+		t.Fatalf("failed to decode acknowledgement: %v", err)
+	}
+	if ack.ResponseCode != "00" || ack.BillNo != "ORDER-001" || ack.TrxID != "3183570200000001" {
+		t.Errorf("acknowledgement = %+v", ack)
+	}
+
+	if event.Reference != "3183570200000001:ORDER-001" {
+		t.Errorf("Reference = %v, want 3183570200000001:ORDER-001", event.Reference)
+	}
+}
+
+func TestWebhook_XML(t *testing.T) {
+	f := newTestProvider(t, nil)
+
+	body, _ := xml.Marshal(testNotification(f, StatusExpired))
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(xml.Header+string(body)))
+	req.Header.Set("Content-Type", "application/xml")
+
+	if !f.VerifyWebhook(req) {
+		t.Fatal("VerifyWebhook() = false, want true")
+	}
+
+	event, err := f.ParseWebhook(req)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+
+	if event.Status != pg.StatusExpired {
+		t.Errorf("Status = %v, want %v", event.Status, pg.StatusExpired)
+	}
+	if event.Acknowledgement == nil || event.Acknowledgement.ContentType != "application/xml" {
+		t.Fatalf("Acknowledgement = %+v, want XML", event.Acknowledgement)
+	}
+
+	var ack NotificationResponse
+	if err := xml.Unmarshal(event.Acknowledgement.Body, &ack); err != nil {
+		t.Fatalf("failed to decode acknowledgement: %v", err)
+	}
+	if ack.ResponseCode != "00" || ack.Response != "Payment Notification" {
+		t.Errorf("acknowledgement = %+v", ack)
+	}
+}
+
+func TestVerifyWebhook_Invalid(t *testing.T) {
+	f := newTestProvider(t, nil)
+
+	tampered := testNotification(f, StatusSuccess)
+	tampered.PaymentStatusCode = StatusFailed
+
+	otherMerchant := testNotification(f, StatusSuccess)
+	otherMerchant.MerchantID = "99999"
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "tampered status", body: mustJSON(t, tampered)},
+		{name: "other merchant", body: mustJSON(t, otherMerchant)},
+		{name: "invalid payload", body: "not a notification"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tt.body))
+			if f.VerifyWebhook(req) {
+				t.Error("VerifyWebhook() = true, want false")
+			}
+		})
+	}
+}
+
+func TestParseWebhook_InvalidPayload(t *testing.T) {
+	f := newTestProvider(t, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader("{invalid"))
+	if _, err := f.ParseWebhook(req); err != pg.ErrInvalidPayload {
+		t.Errorf("ParseWebhook() error = %v, want %v", err, pg.ErrInvalidPayload)
+	}
+}
+
+func TestValidateChargeParams(t *testing.T) {
+	f := newTestProvider(t, nil)
+
+	tests := []struct {
+		name    string
+		modify  func(p *pg.ChargeParams)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *pg.ChargeParams) {}, wantErr: false},
+		{name: "missing order id", modify: func(p *pg.ChargeParams) { p.OrderID = "" }, wantErr: true},
+		{name: "missing customer name", modify: func(p *pg.ChargeParams) { p.Customer.Name = "" }, wantErr: true},
+		{name: "invalid email", modify: func(p *pg.ChargeParams) { p.Customer.Email = "invalid" }, wantErr: true},
+		{name: "unsupported payment type", modify: func(p *pg.ChargeParams) { p.PaymentType = pg.PaymentTypeGoPay }, wantErr: true},
+		{
+			name: "unsupported payment type through Xpress",
+			modify: func(p *pg.ChargeParams) {
+				p.PaymentType = pg.PaymentTypeGoPay
+				p.Custom = map[string]interface{}{"faspay_xpress": true}
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validChargeParams(pg.PaymentTypeVABCA)
+			tt.modify(&params)
+			err := f.validateChargeParams(&params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateChargeParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMapper_Amounts(t *testing.T) {
+	if got := formatAmount(50000); got != "5000000" {
+		t.Errorf("formatAmount() = %v, want 5000000", got)
+	}
+
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{in: "5000000", want: 50000},
+		{in: "50000.00", want: 50000},
+		{in: "", want: 0},
+		{in: "abc", want: 0},
+	}
+	for _, tt := range tests {
+		if got := parseAmount(tt.in); got != tt.want {
+			t.Errorf("parseAmount(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMapper_MapStatus(t *testing.T) {
+	m := &Mapper{}
+
+	tests := []struct {
+		code PaymentStatusCode
+		want pg.Status
+	}{
+		{StatusUnprocessed, pg.StatusPending},
+		{StatusInProcess, pg.StatusProcessing},
+		{StatusSuccess, pg.StatusSuccess},
+		{StatusFailed, pg.StatusFailed},
+		{StatusReversal, pg.StatusCancelled},
+		{StatusExpired, pg.StatusExpired},
+		{StatusCancelled, pg.StatusCancelled},
+		{StatusUnknown, pg.StatusPending},
+	}
+
+	for _, tt := range tests {
+		if got := m.mapStatus(tt.code); got != tt.want {
+			t.Errorf("mapStatus(%v) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	return string(b)
+}
@@ -0,0 +1,387 @@
+package faspay
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+// wib is the Western Indonesia Time zone used by Faspay for every date
+var wib = time.FixedZone("WIB", 7*60*60)
+
+// Mapper handles conversion between unified and Faspay-specific types
+type Mapper struct{}
+
+// mapChannel maps unified payment type to Faspay payment channel
+func (m *Mapper) mapChannel(pt pg.PaymentType) Channel {
+	switch pt {
+	case pg.PaymentTypeVABCA:
+		return ChannelVABCA
+	case pg.PaymentTypeVABNI:
+		return ChannelVABNI
+	case pg.PaymentTypeVABRI:
+		return ChannelVABRI
+	case pg.PaymentTypeVAMandiri:
+		return ChannelVAMandiri
+	case pg.PaymentTypeVAPermata:
+		return ChannelVAPermata
+	case pg.PaymentTypeVACIMB:
+		return ChannelVACIMB
+	case pg.PaymentTypeOVO:
+		return ChannelOVO
+	case pg.PaymentTypeDANA:
+		return ChannelDANA
+	case pg.PaymentTypeLinkAja:
+		return ChannelLinkAja
+	case pg.PaymentTypeShopeePay:
+		return ChannelShopeePay
+	case pg.PaymentTypeQRIS:
+		return ChannelQRIS
+	case pg.PaymentTypeAlfamart:
+		return ChannelAlfamart
+	case pg.PaymentTypeIndomaret:
+		return ChannelIndomaret
+	default:
+		return ""
+	}
+}
+
+// unifiedPaymentType maps Faspay payment channel to unified payment type
+func (m *Mapper) unifiedPaymentType(channel string) pg.PaymentType {
+	switch Channel(channel) {
+	case ChannelVABCA:
+		return pg.PaymentTypeVABCA
+	case ChannelVABNI:
+		return pg.PaymentTypeVABNI
+	case ChannelVABRI:
+		return pg.PaymentTypeVABRI
+	case ChannelVAMandiri:
+		return pg.PaymentTypeVAMandiri
+	case ChannelVAPermata:
+		return pg.PaymentTypeVAPermata
+	case ChannelVACIMB:
+		return pg.PaymentTypeVACIMB
+	case ChannelOVO:
+		return pg.PaymentTypeOVO
+	case ChannelDANA:
+		return pg.PaymentTypeDANA
+	case ChannelLinkAja:
+		return pg.PaymentTypeLinkAja
+	case ChannelShopeePay:
+		return pg.PaymentTypeShopeePay
+	case ChannelQRIS:
+		return pg.PaymentTypeQRIS
+	case ChannelAlfamart:
+		return pg.PaymentTypeAlfamart
+	case ChannelIndomaret:
+		return pg.PaymentTypeIndomaret
+	default:
+		return pg.PaymentType(channel)
+	}
+}
+
+// mapStatus maps Faspay payment status code to unified status
+func (m *Mapper) mapStatus(code PaymentStatusCode) pg.Status {
+	switch code {
+	case StatusSuccess:
+		return pg.StatusSuccess
+	case StatusUnprocessed, StatusUnknown:
+		return pg.StatusPending
+	case StatusInProcess:
+		return pg.StatusProcessing
+	case StatusFailed, StatusNoBills:
+		return pg.StatusFailed
+	case StatusReversal, StatusCancelled:
+		return pg.StatusCancelled
+	case StatusExpired:
+		return pg.StatusExpired
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapEventType maps Faspay payment status code to event type
+func (m *Mapper) mapEventType(code PaymentStatusCode) string {
+	switch m.mapStatus(code) {
+	case pg.StatusSuccess:
+		return pg.EventPaymentCompleted
+	case pg.StatusFailed:
+		return pg.EventPaymentFailed
+	case pg.StatusCancelled:
+		return pg.EventPaymentCancelled
+	case pg.StatusExpired:
+		return pg.EventPaymentExpired
+	default:
+		return pg.EventPaymentPending
+	}
+}
+
+// mapToBillRequest maps unified ChargeParams to Faspay BillRequest
+// The signature is not set here since it depends on the merchant credentials
+func (m *Mapper) mapToBillRequest(merchantID string, params pg.ChargeParams) *BillRequest {
+	now := time.Now().In(wib)
+
+	expiry := params.ExpiryTime
+	if expiry.IsZero() {
+		expiry = now.Add(pg.DefaultExpiryMinutes * time.Minute)
+	}
+
+	merchant := merchantID
+	if name, ok := params.Custom["merchant_name"].(string); ok && name != "" {
+		merchant = name
+	}
+
+	custNo := params.Customer.ID
+	if custNo == "" {
+		custNo = params.Customer.Phone
+	}
+
+	req := &BillRequest{
+		Request:        "Post Data Transaction",
+		MerchantID:     merchantID,
+		Merchant:       merchant,
+		BillNo:         params.OrderID,
+		BillReff:       params.OrderID,
+		BillDate:       now.Format(dateLayout),
+		BillExpired:    expiry.In(wib).Format(dateLayout),
+		BillDesc:       billDescription(params),
+		BillCurrency:   "IDR",
+		BillGross:      "0",
+		BillMiscFee:    "0",
+		BillTotal:      formatAmount(params.Amount),
+		CustNo:         custNo,
+		CustName:       params.Customer.Name,
+		PaymentChannel: m.mapChannel(params.PaymentType),
+		PayType:        "1", // full settlement
+		Msisdn:         normalizePhone(params.Customer.Phone),
+		Email:          params.Customer.Email,
+		Terminal:       "10", // web
+		ReturnURL:      params.ReturnURL,
+	}
+
+	// Map billing and shipping addresses
+	if a := params.Customer.Recipient(params.Customer.BillingAddress); a != nil {
+		req.BillingName, req.BillingLastName = pg.SplitName(a.Name)
+		req.BillingAddress = a.Street()
+		req.BillingCity = a.City
+		req.BillingState = a.State
+		req.BillingPostcode = a.PostalCode
+		req.BillingMsisdn = normalizePhone(a.Phone)
+		req.BillingCountryCode = a.Country()
+	}
+	if a := params.Customer.Recipient(params.Customer.ShippingAddress); a != nil {
+		req.ShippingName, req.ShippingLastName = pg.SplitName(a.Name)
+		req.ShippingAddress = a.Street()
+		req.ShippingCity = a.City
+		req.ShippingState = a.State
+		req.ShippingPostcode = a.PostalCode
+		req.ShippingMsisdn = normalizePhone(a.Phone)
+		req.ShippingCountryCode = a.Country()
+	}
+
+	// Map items, Faspay requires at least one item
+	if items := params.LineItems(); len(items) > 0 {
+		req.Items = make([]*Item, len(items))
+		for i, item := range items {
+			req.Items[i] = &Item{
+				Product:     item.Name,
+				Qty:         strconv.FormatInt(item.Quantity, 10),
+				Amount:      formatAmount(item.Price * item.Quantity),
+				PaymentPlan: "01",
+				MerchantID:  merchantID,
+				Tenor:       "00",
+			}
+		}
+	} else {
+		req.Items = []*Item{
+			{
+				Product:     req.BillDesc,
+				Qty:         "1",
+				Amount:      req.BillTotal,
+				PaymentPlan: "01",
+				MerchantID:  merchantID,
+				Tenor:       "00",
+			},
+		}
+	}
+
+	return req
+}
+
+// mapToChargeResponse maps Faspay BillResponse to unified ChargeResponse
+func (m *Mapper) mapToChargeResponse(resp *BillResponse, req *BillRequest, params pg.ChargeParams) *pg.ChargeResponse {
+	if resp == nil {
+		return nil
+	}
+
+	unified := &pg.ChargeResponse{
+		TransactionID: resp.TrxID,
+		OrderID:       params.OrderID,
+		Reference:     reference(resp.TrxID, params.OrderID),
+		Amount:        params.Amount,
+		Status:        pg.StatusPending,
+		PaymentURL:    resp.RedirectURL,
+		CreatedAt:     time.Now(),
+	}
+
+	if expiry, err := time.ParseInLocation(dateLayout, req.BillExpired, wib); err == nil {
+		unified.ExpiryTime = expiry
+	}
+
+	if resp.RedirectURL != "" {
+		unified.NextActions = []pg.Action{{Type: pg.ActionRedirect, URL: resp.RedirectURL, Method: "GET"}}
+	}
+
+	// Faspay uses trx_id as the VA number or the retail payment code
+	if params.PaymentType.IsVirtualAccount() || params.PaymentType.IsRetail() {
+		unified.VANumber = resp.TrxID
+		unified.VABank = string(params.PaymentType)
+	}
+
+	unified.Raw = map[string]interface{}{
+		"response":      resp.Response,
+		"trx_id":        resp.TrxID,
+		"merchant_id":   resp.MerchantID,
+		"merchant":      resp.Merchant,
+		"bill_no":       resp.BillNo,
+		"response_code": resp.ResponseCode,
+		"response_desc": resp.ResponseDesc,
+		"redirect_url":  resp.RedirectURL,
+	}
+
+	return unified
+}
+
+// mapToPaymentStatus maps Faspay StatusResponse to unified PaymentStatus
+func (m *Mapper) mapToPaymentStatus(orderID string, resp *StatusResponse) *pg.PaymentStatus {
+	if resp == nil {
+		return nil
+	}
+
+	status := m.mapStatus(resp.PaymentStatusCode)
+
+	result := &pg.PaymentStatus{
+		TransactionID: resp.TrxID,
+		OrderID:       orderID,
+		Status:        status,
+		Amount:        parseAmount(resp.BillTotal),
+		PaidAmount:    parseAmount(resp.PaymentTotal),
+	}
+
+	if paidAt, err := time.ParseInLocation(dateLayout, resp.PaymentDate, wib); err == nil {
+		switch status {
+		case pg.StatusSuccess:
+			result.PaidAt = &paidAt
+		case pg.StatusCancelled:
+			result.CancelledAt = &paidAt
+		case pg.StatusExpired:
+			result.ExpiredAt = &paidAt
+		}
+	}
+
+	if status == pg.StatusFailed {
+		result.FailureReason = resp.PaymentStatusDesc
+	}
+
+	return result
+}
+
+// mapToWebhookEvent maps Faspay Notification to unified WebhookEvent
+func (m *Mapper) mapToWebhookEvent(n *Notification) *pg.WebhookEvent {
+	timestamp := time.Now()
+	if paymentDate, err := time.ParseInLocation(dateLayout, n.PaymentDate, wib); err == nil {
+		timestamp = paymentDate
+	}
+
+	amount := parseAmount(n.PaymentTotal)
+	if amount == 0 {
+		amount = parseAmount(n.BillTotal)
+	}
+
+	return &pg.WebhookEvent{
+		OrderID:       n.BillNo,
+		TransactionID: n.TrxID,
+		Reference:     reference(n.TrxID, n.BillNo),
+		Status:        m.mapStatus(n.PaymentStatusCode),
+		Amount:        amount,
+		PaymentType:   m.unifiedPaymentType(n.PaymentChannelUID),
+		EventType:     m.mapEventType(n.PaymentStatusCode),
+		Timestamp:     timestamp,
+		Raw: map[string]interface{}{
+			"request":             n.Request,
+			"trx_id":              n.TrxID,
+			"merchant_id":         n.MerchantID,
+			"merchant":            n.Merchant,
+			"bill_no":             n.BillNo,
+			"payment_reff":        n.PaymentReff,
+			"payment_date":        n.PaymentDate,
+			"payment_status_code": string(n.PaymentStatusCode),
+			"payment_status_desc": n.PaymentStatusDesc,
+			"bill_total":          n.BillTotal,
+			"payment_total":       n.PaymentTotal,
+			"payment_channel_uid": n.PaymentChannelUID,
+			"payment_channel":     n.PaymentChannel,
+		},
+	}
+}
+
+// reference returns the "trx_id:bill_no" reference GetStatus and Cancel take
+func reference(trxID, billNo string) string {
+	return trxID + referenceSeparator + billNo
+}
+
+// billDescription returns the bill description required by Faspay
+func billDescription(params pg.ChargeParams) string {
+	if params.Description != "" {
+		return params.Description
+	}
+	if len(params.Items) > 0 {
+		names := make([]string, len(params.Items))
+		for i, item := range params.Items {
+			names[i] = item.Name
+		}
+		return strings.Join(names, ", ")
+	}
+	return "Payment " + params.OrderID
+}
+
+// formatAmount formats amount for Faspay which expects two implied decimal digits
+// e.g. Rp50.000 is sent as "5000000"
+func formatAmount(amount int64) string {
+	return strconv.FormatInt(amount*100, 10)
+}
+
+// parseAmount parses a Faspay amount with two implied decimal digits
+func parseAmount(amount string) int64 {
+	if amount == "" {
+		return 0
+	}
+	// Some responses use a decimal separator instead of implied decimals
+	if strings.Contains(amount, ".") {
+		f, err := strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return 0
+		}
+		return int64(f)
+	}
+	v, err := strconv.ParseInt(amount, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v / 100
+}
+
+// normalizePhone converts phone number to the local format Faspay expects (08xxx)
+func normalizePhone(phone string) string {
+	phone = strings.ReplaceAll(strings.ReplaceAll(phone, " ", ""), "-", "")
+	switch {
+	case strings.HasPrefix(phone, "+62"):
+		return "0" + phone[3:]
+	case strings.HasPrefix(phone, "62"):
+		return "0" + phone[2:]
+	default:
+		return phone
+	}
+}
@@ -0,0 +1,211 @@
+package faspay
+
+import "encoding/xml"
+
+// Channel represents Faspay payment channel codes
+type Channel string
+
+const (
+	// ChannelVABCA Faspay BCA Virtual Account
+	ChannelVABCA Channel = "702"
+	// ChannelVABNI Faspay BNI Virtual Account
+	ChannelVABNI Channel = "801"
+	// ChannelVABRI Faspay BRI Virtual Account
+	ChannelVABRI Channel = "800"
+	// ChannelVAMandiri Faspay Mandiri Virtual Account
+	ChannelVAMandiri Channel = "802"
+	// ChannelVAPermata Faspay Permata Virtual Account
+	ChannelVAPermata Channel = "402"
+	// ChannelVACIMB Faspay CIMB Niaga Virtual Account
+	ChannelVACIMB Channel = "825"
+
+	// ChannelOVO Faspay OVO
+	ChannelOVO Channel = "812"
+	// ChannelDANA Faspay DANA
+	ChannelDANA Channel = "819"
+	// ChannelLinkAja Faspay LinkAja
+	ChannelLinkAja Channel = "302"
+	// ChannelShopeePay Faspay ShopeePay
+	ChannelShopeePay Channel = "713"
+
+	// ChannelQRIS Faspay QRIS
+	ChannelQRIS Channel = "711"
+
+	// ChannelAlfamart Faspay Alfagroup
+	ChannelAlfamart Channel = "707"
+	// ChannelIndomaret Faspay Indomaret
+	ChannelIndomaret Channel = "706"
+)
+
+// PaymentStatusCode represents Faspay payment status codes
+type PaymentStatusCode string
+
+const (
+	// StatusUnprocessed means the bill is not paid yet
+	StatusUnprocessed PaymentStatusCode = "0"
+	// StatusInProcess means the payment is being processed
+	StatusInProcess PaymentStatusCode = "1"
+	// StatusSuccess means the payment is successful
+	StatusSuccess PaymentStatusCode = "2"
+	// StatusFailed means the payment failed
+	StatusFailed PaymentStatusCode = "3"
+	// StatusReversal means the payment was reversed
+	StatusReversal PaymentStatusCode = "4"
+	// StatusNoBills means the bill was not found
+	StatusNoBills PaymentStatusCode = "5"
+	// StatusExpired means the bill is expired
+	StatusExpired PaymentStatusCode = "7"
+	// StatusCancelled means the bill is cancelled
+	StatusCancelled PaymentStatusCode = "8"
+	// StatusUnknown means the payment status is unknown
+	StatusUnknown PaymentStatusCode = "9"
+)
+
+// responseCodeSuccess is the response code of an accepted request
+const responseCodeSuccess = "00"
+
+// Item represents a bill item
+type Item struct {
+	Product     string `json:"product"`
+	Qty         string `json:"qty"`
+	Amount      string `json:"amount"`
+	PaymentPlan string `json:"payment_plan"`
+	MerchantID  string `json:"merchant_id"`
+	Tenor       string `json:"tenor"`
+}
+
+// BillRequest for creating a bill through Debit (Post Data Transaction) or Xpress
+type BillRequest struct {
+	Request        string  `json:"request"`
+	MerchantID     string  `json:"merchant_id"`
+	Merchant       string  `json:"merchant"`
+	BillNo         string  `json:"bill_no"`
+	BillReff       string  `json:"bill_reff,omitempty"`
+	BillDate       string  `json:"bill_date"`
+	BillExpired    string  `json:"bill_expired"`
+	BillDesc       string  `json:"bill_desc"`
+	BillCurrency   string  `json:"bill_currency"`
+	BillGross      string  `json:"bill_gross"`
+	BillMiscFee    string  `json:"bill_miscfee"`
+	BillTotal      string  `json:"bill_total"`
+	CustNo         string  `json:"cust_no"`
+	CustName       string  `json:"cust_name"`
+	PaymentChannel Channel `json:"payment_channel,omitempty"`
+	PayType        string  `json:"pay_type"`
+	Msisdn         string  `json:"msisdn"`
+	Email          string  `json:"email"`
+	Terminal       string  `json:"terminal"`
+	ReturnURL      string  `json:"return_url,omitempty"`
+	Items          []*Item `json:"item"`
+	Signature      string  `json:"signature"`
+
+	BillingName        string `json:"billing_name,omitempty"`
+	BillingLastName    string `json:"billing_lastname,omitempty"`
+	BillingAddress     string `json:"billing_address,omitempty"`
+	BillingCity        string `json:"billing_address_city,omitempty"`
+	BillingState       string `json:"billing_address_state,omitempty"`
+	BillingPostcode    string `json:"billing_address_poscode,omitempty"`
+	BillingMsisdn      string `json:"billing_msisdn,omitempty"`
+	BillingCountryCode string `json:"billing_address_country_code,omitempty"`
+
+	ShippingName        string `json:"receiver_name_for_shipping,omitempty"`
+	ShippingLastName    string `json:"shipping_lastname,omitempty"`
+	ShippingAddress     string `json:"shipping_address,omitempty"`
+	ShippingCity        string `json:"shipping_address_city,omitempty"`
+	ShippingState       string `json:"shipping_address_state,omitempty"`
+	ShippingPostcode    string `json:"shipping_address_poscode,omitempty"`
+	ShippingMsisdn      string `json:"shipping_msisdn,omitempty"`
+	ShippingCountryCode string `json:"shipping_address_country_code,omitempty"`
+}
+
+// BillResponse from Faspay Debit and Xpress
+type BillResponse struct {
+	Response     string `json:"response"`
+	TrxID        string `json:"trx_id"`
+	MerchantID   string `json:"merchant_id"`
+	Merchant     string `json:"merchant"`
+	BillNo       string `json:"bill_no"`
+	ResponseCode string `json:"response_code"`
+	ResponseDesc string `json:"response_desc"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+}
+
+// StatusRequest for Inquiry Payment Status
+type StatusRequest struct {
+	Request    string `json:"request"`
+	TrxID      string `json:"trx_id"`
+	MerchantID string `json:"merchant_id"`
+	BillNo     string `json:"bill_no"`
+	Signature  string `json:"signature"`
+}
+
+// StatusResponse from Inquiry Payment Status
+type StatusResponse struct {
+	Response          string            `json:"response"`
+	TrxID             string            `json:"trx_id"`
+	MerchantID        string            `json:"merchant_id"`
+	Merchant          string            `json:"merchant"`
+	BillNo            string            `json:"bill_no"`
+	BillTotal         string            `json:"bill_total,omitempty"`
+	PaymentTotal      string            `json:"payment_total,omitempty"`
+	PaymentReff       string            `json:"payment_reff"`
+	PaymentDate       string            `json:"payment_date"`
+	PaymentStatusCode PaymentStatusCode `json:"payment_status_code"`
+	PaymentStatusDesc string            `json:"payment_status_desc"`
+	ResponseCode      string            `json:"response_code"`
+	ResponseDesc      string            `json:"response_desc"`
+}
+
+// CancelRequest for Canceling Payment
+type CancelRequest struct {
+	Request       string `json:"request"`
+	TrxID         string `json:"trx_id"`
+	MerchantID    string `json:"merchant_id"`
+	Merchant      string `json:"merchant"`
+	BillNo        string `json:"bill_no"`
+	PaymentCancel string `json:"payment_cancel"`
+	Signature     string `json:"signature"`
+}
+
+// CancelResponse from Canceling Payment
+type CancelResponse struct {
+	Response          string            `json:"response"`
+	TrxID             string            `json:"trx_id"`
+	BillNo            string            `json:"bill_no"`
+	PaymentStatusCode PaymentStatusCode `json:"payment_status_code"`
+	PaymentStatusDesc string            `json:"payment_status_desc"`
+	ResponseCode      string            `json:"response_code"`
+	ResponseDesc      string            `json:"response_desc"`
+}
+
+// Notification is the Payment Notification sent by Faspay, either as JSON or XML
+type Notification struct {
+	XMLName           xml.Name          `json:"-" xml:"faspay"`
+	Request           string            `json:"request" xml:"request"`
+	TrxID             string            `json:"trx_id" xml:"trx_id"`
+	MerchantID        string            `json:"merchant_id" xml:"merchant_id"`
+	Merchant          string            `json:"merchant" xml:"merchant"`
+	BillNo            string            `json:"bill_no" xml:"bill_no"`
+	PaymentReff       string            `json:"payment_reff" xml:"payment_reff"`
+	PaymentDate       string            `json:"payment_date" xml:"payment_date"`
+	PaymentStatusCode PaymentStatusCode `json:"payment_status_code" xml:"payment_status_code"`
+	PaymentStatusDesc string            `json:"payment_status_desc" xml:"payment_status_desc"`
+	BillTotal         string            `json:"bill_total" xml:"bill_total"`
+	PaymentTotal      string            `json:"payment_total" xml:"payment_total"`
+	PaymentChannelUID string            `json:"payment_channel_uid" xml:"payment_channel_uid"`
+	PaymentChannel    string            `json:"payment_channel" xml:"payment_channel"`
+	Signature         string            `json:"signature" xml:"signature"`
+}
+
+// NotificationResponse is the acknowledgement Faspay expects after a Payment Notification
+type NotificationResponse struct {
+	XMLName      xml.Name `json:"-" xml:"faspay"`
+	Response     string   `json:"response" xml:"response"`
+	TrxID        string   `json:"trx_id" xml:"trx_id"`
+	MerchantID   string   `json:"merchant_id" xml:"merchant_id"`
+	Merchant     string   `json:"merchant" xml:"merchant"`
+	BillNo       string   `json:"bill_no" xml:"bill_no"`
+	ResponseCode string   `json:"response_code" xml:"response_code"`
+	ResponseDesc string   `json:"response_desc" xml:"response_desc"`
+	ResponseDate string   `json:"response_date" xml:"response_date"`
+}
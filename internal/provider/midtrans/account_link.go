@@ -0,0 +1,96 @@
+package midtrans
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// GoPay Tokenization endpoints
+	payAccountUri       = "/v2/pay/account"
+	payAccountDetailUri = "/v2/pay/account/%s"
+	payAccountUnbindUri = "/v2/pay/account/%s/unbind"
+)
+
+// LinkAccount links a customer GoPay account using GoPay Tokenization
+// The customer phone number is taken from Customer.Phone
+func (m *midtrans) LinkAccount(ctx context.Context, params pg.LinkParams) (*pg.LinkedAccount, error) {
+	if params.PaymentType != pg.PaymentTypeGoPay {
+		return nil, pg.NewFieldError("PaymentType", fmt.Sprintf("account linking is not supported for %s", params.PaymentType))
+	}
+	if params.Customer.Phone == "" {
+		return nil, pg.NewRequiredFieldError("Customer.Phone")
+	}
+
+	req := m.mapper.mapToPayAccountRequest(params)
+
+	responseBody, err := m.sendCoreRequest(ctx, http.MethodPost, payAccountUri, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.parsePayAccount(responseBody)
+}
+
+// GetLinkedAccount retrieves a linked GoPay account, including its wallet balance
+func (m *midtrans) GetLinkedAccount(ctx context.Context, id string) (*pg.LinkedAccount, error) {
+	responseBody, err := m.sendCoreRequest(ctx, http.MethodGet, fmt.Sprintf(payAccountDetailUri, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.parsePayAccount(responseBody)
+}
+
+// UnlinkAccount unbinds a linked GoPay account
+func (m *midtrans) UnlinkAccount(ctx context.Context, id string) error {
+	_, err := m.sendCoreRequest(ctx, http.MethodPost, fmt.Sprintf(payAccountUnbindUri, id), nil)
+	return err
+}
+
+// resolvePaymentOptionToken fills the GoPay payment option token when charging a linked account
+// The token is not known to the caller, so it is read from the linked account
+func (m *midtrans) resolvePaymentOptionToken(ctx context.Context, params *EWallet) error {
+	if params.Gopay == nil || params.Gopay.AccountID == "" || params.Gopay.PaymentOptionToken != "" {
+		return nil
+	}
+
+	responseBody, err := m.sendCoreRequest(ctx, http.MethodGet, fmt.Sprintf(payAccountDetailUri, params.Gopay.AccountID), nil)
+	if err != nil {
+		return err
+	}
+
+	var resp PayAccountResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if resp.AccountStatus != AccountEnabled {
+		return pg.NewFieldError("EWallet.AccountLinkID", fmt.Sprintf("account is %s", resp.AccountStatus))
+	}
+
+	option := gopayWallet(&resp)
+	if option == nil {
+		return pg.NewFieldError("EWallet.AccountLinkID", "account has no GoPay wallet")
+	}
+	params.Gopay.PaymentOptionToken = option.Token
+
+	return nil
+}
+
+// parsePayAccount parses a Midtrans pay account response
+func (m *midtrans) parsePayAccount(responseBody []byte) (*pg.LinkedAccount, error) {
+	var resp PayAccountResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return m.mapper.mapToLinkedAccount(&resp, raw), nil
+}
@@ -0,0 +1,175 @@
+package midtrans
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+const testPayAccountResponse = `{
+	"status_code": "200",
+	"payment_type": "gopay",
+	"account_id": "acc-123",
+	"account_status": "ENABLED",
+	"metadata": {
+		"payment_options": [
+			{"name": "PAY_LATER", "active": true, "token": "paylater-token"},
+			{"name": "GOPAY_WALLET", "active": true, "balance": {"value": "150000.00", "currency": "IDR"}, "token": "wallet-token"}
+		]
+	}
+}`
+
+func TestMidtrans_LinkAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != payAccountUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req PayAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.GopayPartner.PhoneNumber != "81234567890" || req.GopayPartner.CountryCode != "62" {
+			t.Errorf("GopayPartner = %+v, want 62 81234567890", req.GopayPartner)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"status_code": "201",
+			"payment_type": "gopay",
+			"account_id": "acc-123",
+			"account_status": "PENDING",
+			"actions": [
+				{"name": "activation-deeplink", "method": "GET", "url": "gojek://gopay/account-linking"},
+				{"name": "activation-link-url", "method": "GET", "url": "https://api.midtrans.com/v2/pay/account/gpar_123/link"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	account, err := m.LinkAccount(context.Background(), pg.LinkParams{
+		PaymentType: pg.PaymentTypeGoPay,
+		Customer:    pg.Customer{Phone: "+6281234567890"},
+		RedirectURL: "https://example.com/linked",
+	})
+	if err != nil {
+		t.Fatalf("LinkAccount() error = %v", err)
+	}
+
+	if account.ID != "acc-123" {
+		t.Errorf("ID = %v, want acc-123", account.ID)
+	}
+	if account.Status != pg.LinkPending {
+		t.Errorf("Status = %v, want %v", account.Status, pg.LinkPending)
+	}
+	if account.ActionURL != "https://api.midtrans.com/v2/pay/account/gpar_123/link" {
+		t.Errorf("ActionURL = %v, want activation link", account.ActionURL)
+	}
+}
+
+func TestMidtrans_LinkAccount_Unsupported(t *testing.T) {
+	m := newTestProvider(t, nil, "")
+
+	_, err := m.LinkAccount(context.Background(), pg.LinkParams{
+		PaymentType: pg.PaymentTypeOVO,
+		Customer:    pg.Customer{Phone: "081234567890"},
+		RedirectURL: "https://example.com/linked",
+	})
+	if err == nil {
+		t.Error("expected error for OVO, got nil")
+	}
+}
+
+func TestMidtrans_GetLinkedAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v2/pay/account/acc-123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testPayAccountResponse))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	account, err := m.GetLinkedAccount(context.Background(), "acc-123")
+	if err != nil {
+		t.Fatalf("GetLinkedAccount() error = %v", err)
+	}
+
+	if account.Status != pg.LinkActive {
+		t.Errorf("Status = %v, want %v", account.Status, pg.LinkActive)
+	}
+	if account.Balance == nil || *account.Balance != 150000 {
+		t.Errorf("Balance = %v, want 150000", account.Balance)
+	}
+}
+
+func TestMidtrans_CreateCharge_LinkedAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/pay/account/acc-123":
+			w.Write([]byte(testPayAccountResponse))
+		case r.Method == http.MethodPost && r.URL.Path == chargeUri:
+			var req EWallet
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			if req.Gopay == nil || req.Gopay.AccountID != "acc-123" || req.Gopay.PaymentOptionToken != "wallet-token" {
+				t.Errorf("Gopay = %+v, want acc-123 with wallet-token", req.Gopay)
+			}
+			w.Write([]byte(`{"status_code":"200","transaction_id":"txn-123","order_id":"ORDER-001","gross_amount":"50000.00","payment_type":"gopay","transaction_status":"settlement"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	resp, err := m.CreateCharge(context.Background(), pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: pg.PaymentTypeGoPay,
+		Customer:    pg.Customer{ID: "CUST-001", Name: "John Doe", Email: "john@example.com", Phone: "081234567890"},
+		Items:       []pg.Item{{ID: "ITEM-001", Name: "Product", Price: 50000, Quantity: 1}},
+		EWallet:     &pg.EWALLETParams{AccountLinkID: "acc-123"},
+	})
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if resp.TransactionID != "txn-123" {
+		t.Errorf("TransactionID = %v, want txn-123", resp.TransactionID)
+	}
+}
+
+func TestMidtrans_UnlinkAccount(t *testing.T) {
+	var called bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/pay/account/acc-123/unbind" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		called = true
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status_code":"200","payment_type":"gopay","account_id":"acc-123","account_status":"DISABLED"}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	if err := m.UnlinkAccount(context.Background(), "acc-123"); err != nil {
+		t.Fatalf("UnlinkAccount() error = %v", err)
+	}
+	if !called {
+		t.Error("unbind endpoint was not called")
+	}
+}
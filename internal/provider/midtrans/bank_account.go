@@ -0,0 +1,40 @@
+package midtrans
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// Iris bank account validation endpoint
+	accountValidationUri = "/account_validation"
+)
+
+// ValidateBankAccount looks up the holder name of a bank account using Iris
+func (m *midtrans) ValidateBankAccount(ctx context.Context, bankCode, accountNumber string) (*pg.BankAccount, error) {
+	query := url.Values{}
+	query.Set("bank", irisBankCode(bankCode))
+	query.Set("account", accountNumber)
+
+	fullURL := m.getIrisBaseURL() + accountValidationUri + "?" + query.Encode()
+
+	responseBody, err := m.sendIrisRequest(ctx, http.MethodGet, fullURL, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp IrisAccountValidationResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return m.mapper.mapToBankAccount(bankCode, &resp, raw), nil
+}
@@ -0,0 +1,48 @@
+package midtrans
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+func TestMidtrans_ValidateBankAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/iris/api/v1"+accountValidationUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("bank") != "mandiri" {
+			t.Errorf("bank = %v, want mandiri", r.URL.Query().Get("bank"))
+		}
+		if r.URL.Query().Get("account") != "1234567890" {
+			t.Errorf("account = %v, want 1234567890", r.URL.Query().Get("account"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"account_name":"BUDI SANTOSO","account_no":"1234567890","bank_name":"mandiri"}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	account, err := m.ValidateBankAccount(context.Background(), "008", "1234567890")
+	if err != nil {
+		t.Fatalf("ValidateBankAccount() error = %v", err)
+	}
+
+	if !account.Valid {
+		t.Error("Valid = false, want true")
+	}
+	if account.BankCode != "MANDIRI" {
+		t.Errorf("BankCode = %v, want MANDIRI", account.BankCode)
+	}
+	if account.Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", account.Status, pg.StatusSuccess)
+	}
+	if account.NameMatchScore("Budi Santoso") != 1 {
+		t.Errorf("NameMatchScore() = %v, want 1", account.NameMatchScore("Budi Santoso"))
+	}
+}
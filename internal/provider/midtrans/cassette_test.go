@@ -0,0 +1,325 @@
+package midtrans
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/pgtest"
+)
+
+// newCassetteProvider creates a Midtrans provider which replays testdata/cassettes/<name>.json
+// With PG_RECORD set, it records against the sandbox using MIDTRANS_SERVER_KEY and MIDTRANS_IRIS_KEY
+func newCassetteProvider(t *testing.T, name string) *midtrans {
+	t.Helper()
+
+	mode := pgtest.ModeFromEnv()
+	serverKey, irisKey := "server-key", "iris-key"
+	if mode == pgtest.ModeRecord {
+		serverKey, irisKey = os.Getenv("MIDTRANS_SERVER_KEY"), os.Getenv("MIDTRANS_IRIS_KEY")
+		if serverKey == "" {
+			t.Skip("MIDTRANS_SERVER_KEY is required to record cassettes")
+		}
+	}
+
+	rec, err := pgtest.NewRecorder(
+		filepath.Join("testdata", "cassettes", name+".json"),
+		pgtest.WithMode(mode),
+		pgtest.WithSecrets(serverKey, irisKey),
+	)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	provider, err := New(&pg.ProviderConfig{
+		ServerKey:        serverKey,
+		DisbursementKey:  irisKey,
+		Transport:        rec,
+		InstallmentTerms: map[string][]int{"BNI": {3, 6, 12}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return provider.(*midtrans)
+}
+
+// cassetteChargeParams returns the charge recorded in the charge cassettes
+func cassetteChargeParams(paymentType pg.PaymentType) pg.ChargeParams {
+	return pg.ChargeParams{
+		OrderID:     "ORDER-" + string(paymentType),
+		Amount:      150000,
+		PaymentType: paymentType,
+		Customer: pg.Customer{
+			ID:    "CUST-001",
+			Name:  "Budi Santoso",
+			Email: "budi@example.com",
+			Phone: "081234567890",
+		},
+		Items: []pg.Item{
+			{ID: "SKU-001", Name: "Kaos Polos", Price: 75000, Quantity: 2},
+		},
+		CallbackURL: "https://example.com/finish",
+	}
+}
+
+func TestMidtrans_Cassettes_CreateCharge(t *testing.T) {
+	tests := []struct {
+		name        string
+		paymentType pg.PaymentType
+		card        *pg.CreditCardParams
+		wantStatus  pg.Status
+		wantVA      string
+		wantVABank  string
+		wantQR      bool
+		wantURL     bool
+	}{
+		{name: "charge_gopay", paymentType: pg.PaymentTypeGoPay, wantStatus: pg.StatusPending, wantURL: true},
+		{name: "charge_shopeepay", paymentType: pg.PaymentTypeShopeePay, wantStatus: pg.StatusPending, wantURL: true},
+		{name: "charge_qris", paymentType: pg.PaymentTypeQRIS, wantStatus: pg.StatusPending, wantQR: true},
+		{name: "charge_va_bca", paymentType: pg.PaymentTypeVABCA, wantStatus: pg.StatusPending, wantVA: "12345678901", wantVABank: "bca"},
+		{name: "charge_va_permata", paymentType: pg.PaymentTypeVAPermata, wantStatus: pg.StatusPending, wantVA: "8562000087926752", wantVABank: "permata"},
+		{name: "charge_va_mandiri", paymentType: pg.PaymentTypeVAMandiri, wantStatus: pg.StatusPending, wantVA: "70012-990000000011", wantVABank: "mandiri"},
+		{
+			name:        "charge_credit_card",
+			paymentType: pg.PaymentTypeCC,
+			card:        &pg.CreditCardParams{CardNumber: "481111-1114-a901971f-2f1b-4781-802a-df326fbf0e9c", Secure: true},
+			wantStatus:  pg.StatusPending,
+			wantURL:     true,
+		},
+		{name: "charge_akulaku", paymentType: pg.PaymentTypeAkulaku, wantStatus: pg.StatusPending, wantURL: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newCassetteProvider(t, tt.name)
+
+			params := cassetteChargeParams(tt.paymentType)
+			params.CreditCard = tt.card
+			resp, err := m.CreateCharge(context.Background(), params)
+			if err != nil {
+				t.Fatalf("CreateCharge() error = %v", err)
+			}
+
+			if resp.OrderID != params.OrderID {
+				t.Errorf("OrderID = %v, want %v", resp.OrderID, params.OrderID)
+			}
+			if resp.TransactionID == "" {
+				t.Error("TransactionID is empty")
+			}
+			if resp.Amount != params.Amount {
+				t.Errorf("Amount = %v, want %v", resp.Amount, params.Amount)
+			}
+			if resp.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", resp.Status, tt.wantStatus)
+			}
+			if resp.CreatedAt.IsZero() {
+				t.Error("CreatedAt is zero, want the transaction time")
+			}
+			if resp.VANumber != tt.wantVA || resp.VABank != tt.wantVABank {
+				t.Errorf("VA = %v %v, want %v %v", resp.VABank, resp.VANumber, tt.wantVABank, tt.wantVA)
+			}
+			if tt.wantQR && resp.QRString == "" {
+				t.Error("QRString is empty")
+			}
+			if tt.wantURL && resp.PaymentURL == "" {
+				t.Error("PaymentURL is empty")
+			}
+		})
+	}
+}
+
+func TestMidtrans_Cassettes_GetStatus(t *testing.T) {
+	m := newCassetteProvider(t, "get_status")
+
+	status, err := m.GetStatus(context.Background(), "ORDER-VA_BCA")
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+
+	if status.Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", status.Status, pg.StatusSuccess)
+	}
+	if status.Amount != 150000 {
+		t.Errorf("Amount = %v, want 150000", status.Amount)
+	}
+	if status.PaymentType != pg.PaymentTypeVABCA {
+		t.Errorf("PaymentType = %v, want %v", status.PaymentType, pg.PaymentTypeVABCA)
+	}
+	wantPaidAt := time.Date(2024, 3, 1, 10, 15, 30, 0, wib)
+	if status.PaidAt == nil || !status.PaidAt.Equal(wantPaidAt) {
+		t.Errorf("PaidAt = %v, want %v", status.PaidAt, wantPaidAt)
+	}
+}
+
+func TestMidtrans_Cassettes_Cancel(t *testing.T) {
+	m := newCassetteProvider(t, "cancel")
+
+	if err := m.Cancel(context.Background(), "ORDER-GOPAY"); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+}
+
+func TestMidtrans_Cassettes_AccountLink(t *testing.T) {
+	m := newCassetteProvider(t, "account_link")
+	ctx := context.Background()
+
+	account, err := m.LinkAccount(ctx, pg.LinkParams{
+		PaymentType: pg.PaymentTypeGoPay,
+		Customer:    pg.Customer{Phone: "081234567890"},
+		RedirectURL: "https://example.com/linked",
+	})
+	if err != nil {
+		t.Fatalf("LinkAccount() error = %v", err)
+	}
+	if account.Status != pg.LinkPending || account.ActionURL == "" {
+		t.Errorf("LinkAccount() = %+v, want pending with an activation URL", account)
+	}
+
+	account, err = m.GetLinkedAccount(ctx, account.ID)
+	if err != nil {
+		t.Fatalf("GetLinkedAccount() error = %v", err)
+	}
+	if account.Status != pg.LinkActive {
+		t.Errorf("Status = %v, want %v", account.Status, pg.LinkActive)
+	}
+
+	if err := m.UnlinkAccount(ctx, account.ID); err != nil {
+		t.Fatalf("UnlinkAccount() error = %v", err)
+	}
+}
+
+func TestMidtrans_Cassettes_ValidateBankAccount(t *testing.T) {
+	m := newCassetteProvider(t, "validate_bank_account")
+
+	account, err := m.ValidateBankAccount(context.Background(), "BCA", "1234567890")
+	if err != nil {
+		t.Fatalf("ValidateBankAccount() error = %v", err)
+	}
+	if account.AccountHolder != "BUDI SANTOSO" {
+		t.Errorf("AccountHolder = %v, want BUDI SANTOSO", account.AccountHolder)
+	}
+}
+
+func TestMidtrans_Cassettes_Disbursement(t *testing.T) {
+	m := newCassetteProvider(t, "disbursement")
+	ctx := context.Background()
+
+	created, err := m.CreateDisbursement(ctx, pg.DisbursementParams{
+		ReferenceID:   "DISB-001",
+		BankCode:      "BCA",
+		AccountNumber: "1234567890",
+		AccountHolder: "Budi Santoso",
+		Amount:        100000,
+		Description:   "Refund ORDER-VA_BCA",
+	})
+	if err != nil {
+		t.Fatalf("CreateDisbursement() error = %v", err)
+	}
+	if created.ID == "" || created.Status != pg.StatusPending {
+		t.Errorf("CreateDisbursement() = %+v, want a pending payout", created)
+	}
+
+	disbursement, err := m.GetDisbursement(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetDisbursement() error = %v", err)
+	}
+	if disbursement.Status != pg.StatusSuccess || disbursement.Amount != 100000 {
+		t.Errorf("GetDisbursement() = %+v, want a completed payout of 100000", disbursement)
+	}
+
+	batch, err := m.CreateBatchDisbursement(ctx, pg.BatchDisbursementParams{
+		ReferenceID: "BATCH-001",
+		Disbursements: []pg.DisbursementParams{
+			{ReferenceID: "DISB-002", BankCode: "BNI", AccountNumber: "0987654321", AccountHolder: "Siti Aminah", Amount: 50000},
+			{ReferenceID: "DISB-003", BankCode: "MANDIRI", AccountNumber: "1122334455", AccountHolder: "Agus Salim", Amount: 75000},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateBatchDisbursement() error = %v", err)
+	}
+	if len(batch.Disbursements) != 2 {
+		t.Errorf("len(Disbursements) = %v, want 2", len(batch.Disbursements))
+	}
+}
+
+func TestMidtrans_Cassettes_GetInstallmentOptions(t *testing.T) {
+	m := newCassetteProvider(t, "installment_options")
+
+	plans, err := m.GetInstallmentOptions(context.Background(), "410505", 1200000)
+	if err != nil {
+		t.Fatalf("GetInstallmentOptions() error = %v", err)
+	}
+	if len(plans) != 3 || plans[0].Bank != "BNI" {
+		t.Errorf("GetInstallmentOptions() = %+v, want the 3 BNI terms", plans)
+	}
+}
+
+func TestMidtrans_Cassettes_Subscription(t *testing.T) {
+	m := newCassetteProvider(t, "subscription")
+	ctx := context.Background()
+
+	sub, err := m.CreateSubscription(ctx, pg.SubscriptionParams{
+		ReferenceID:  "SUB-001",
+		Customer:     pg.Customer{ID: "CUST-001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890"},
+		Amount:       50000,
+		PaymentType:  pg.PaymentTypeCC,
+		PaymentToken: "481111-1114-a901971f-2f1b-4781-802a-df326fbf0e9c",
+		Interval:     pg.IntervalMonth,
+		StartAt:      time.Date(2024, 3, 1, 10, 0, 0, 0, wib),
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+	if sub.ID == "" || sub.ReferenceID != "SUB-001" || sub.Status != pg.SubscriptionActive {
+		t.Errorf("CreateSubscription() = %+v, want an active subscription for SUB-001", sub)
+	}
+
+	if _, err := m.GetSubscription(ctx, sub.ID); err != nil {
+		t.Fatalf("GetSubscription() error = %v", err)
+	}
+
+	amount := int64(75000)
+	updated, err := m.UpdateSubscription(ctx, sub.ID, pg.SubscriptionUpdateParams{Amount: amount})
+	if err != nil {
+		t.Fatalf("UpdateSubscription() error = %v", err)
+	}
+	if updated.Amount != amount {
+		t.Errorf("Amount = %v, want %v", updated.Amount, amount)
+	}
+
+	paused, err := m.PauseSubscription(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("PauseSubscription() error = %v", err)
+	}
+	if paused.Status != pg.SubscriptionPaused {
+		t.Errorf("Status = %v, want %v", paused.Status, pg.SubscriptionPaused)
+	}
+
+	if _, err := m.ResumeSubscription(ctx, sub.ID); err != nil {
+		t.Fatalf("ResumeSubscription() error = %v", err)
+	}
+
+	payments, err := m.ListSubscriptionPayments(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("ListSubscriptionPayments() error = %v", err)
+	}
+	if len(payments) != 1 || payments[0].Status != pg.StatusSuccess {
+		t.Errorf("ListSubscriptionPayments() = %+v, want one settled payment", payments)
+	}
+
+	cancelled, err := m.CancelSubscription(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("CancelSubscription() error = %v", err)
+	}
+	if cancelled.Status != pg.SubscriptionCancelled {
+		t.Errorf("Status = %v, want %v", cancelled.Status, pg.SubscriptionCancelled)
+	}
+}
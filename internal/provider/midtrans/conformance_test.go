@@ -0,0 +1,71 @@
+package midtrans
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/pgtest"
+)
+
+// conformanceStatuses maps the unified statuses to the Midtrans transaction statuses
+var conformanceStatuses = map[pg.Status]TransactionStatus{
+	pg.StatusSuccess:   Settlement,
+	pg.StatusPending:   Pending,
+	pg.StatusFailed:    Deny,
+	pg.StatusCancelled: Cancel,
+	pg.StatusExpired:   Expire,
+}
+
+// jsonHandler answers every request with body and the HTTP status code
+func jsonHandler(code int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	})
+}
+
+func TestMidtrans_Conformance(t *testing.T) {
+	pgtest.RunProviderConformance(t, New, pgtest.Stub{
+		Config: pg.ProviderConfig{ServerKey: "server-key"},
+		Charge: pg.ChargeParams{
+			OrderID:     "ORDER-001",
+			Amount:      150000,
+			PaymentType: pg.PaymentTypeVABCA,
+			Customer:    pg.Customer{ID: "CUST-001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890"},
+			Items:       []pg.Item{{ID: "SKU-001", Name: "Kaos Polos", Price: 75000, Quantity: 2}},
+			CallbackURL: "https://example.com/callback",
+		},
+		CreateCharge: jsonHandler(http.StatusOK, `{"status_code":"201","status_message":"Success, Bank Transfer transaction is created","transaction_id":"be4f3e44-d6ee-4355-8c64-c1d1dc7f4590","order_id":"ORDER-001","gross_amount":"150000.00","payment_type":"bank_transfer","transaction_time":"2024-03-01 10:00:00","transaction_status":"pending","va_numbers":[{"bank":"bca","va_number":"12345678901"}]}`),
+		Rejected:     jsonHandler(http.StatusBadRequest, `{"status_code":"400","status_message":"One or more parameters in the payload is invalid.","validation_messages":["gross_amount is not equal to the sum of item_details"]}`),
+		Statuses:     []pg.Status{pg.StatusSuccess, pg.StatusPending, pg.StatusFailed, pg.StatusCancelled, pg.StatusExpired},
+		Status: func(status pg.Status) http.Handler {
+			return jsonHandler(http.StatusOK, `{"status_code":"200","transaction_id":"be4f3e44-d6ee-4355-8c64-c1d1dc7f4590","order_id":"ORDER-001","gross_amount":"150000.00","payment_type":"bank_transfer","transaction_time":"2024-03-01 10:00:00","transaction_status":"`+string(conformanceStatuses[status])+`"}`)
+		},
+		Cancel: jsonHandler(http.StatusOK, `{"status_code":"200","status_message":"Success, transaction is canceled","order_id":"ORDER-001","transaction_status":"cancel"}`),
+		Webhook: func(status pg.Status, signed bool) *http.Request {
+			form := url.Values{
+				"order_id":           {"ORDER-001"},
+				"transaction_status": {string(conformanceStatuses[status])},
+				"gross_amount":       {"150000.00"},
+				"transaction_time":   {"2024-03-01 10:00:00"},
+			}
+			key := "server-key"
+			if !signed {
+				key = "wrong-key"
+			}
+			sum := sha512.Sum512([]byte("ORDER-001" + string(conformanceStatuses[status]) + key))
+
+			r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.Header.Set("X-Signature", hex.EncodeToString(sum[:]))
+			return r
+		},
+	})
+}
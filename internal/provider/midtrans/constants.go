@@ -1,5 +1,9 @@
 package midtrans
 
+import (
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
 const (
 	// ProviderName is the name of the Midtrans provider
 	ProviderName = "midtrans"
@@ -11,4 +15,40 @@ const (
 	// Snap URLs
 	snapSandboxURL    = "https://app.sandbox.midtrans.com"
 	snapProductionURL = "https://app.midtrans.com"
+
+	// Iris (disbursement) URLs
+	irisSandboxURL    = "https://app.sandbox.midtrans.com" + irisPath
+	irisProductionURL = "https://app.midtrans.com" + irisPath
+
+	// irisPath is the path of the Iris API under its host
+	irisPath = "/iris/api/v1"
 )
+
+// maxCustomFields is the number of Midtrans custom fields, which carry the charge metadata
+const maxCustomFields = 3
+
+// maxCustomFieldLength is the maximum length of a Midtrans custom field
+const maxCustomFieldLength = 255
+
+// customOptions are the pg.ChargeParams.Custom keys read by the provider, they are not merged into the request body
+var customOptions = []string{"va_number", "gopay_account_id", "gopay_payment_option_token"}
+
+// amountLimits are the Midtrans amount limits per payment type, a zero Max has no maximum
+// They are overridden with pg.WithAmountLimits
+var amountLimits = map[pg.PaymentType]pg.AmountLimit{
+	pg.PaymentTypeGoPay:     {Min: 10000, Max: 20000000},
+	pg.PaymentTypeShopeePay: {Min: 10000, Max: 20000000},
+	pg.PaymentTypeOVO:       {Min: 10000, Max: 10000000},
+	pg.PaymentTypeDANA:      {Min: 10000, Max: 20000000},
+	pg.PaymentTypeLinkAja:   {Min: 10000, Max: 10000000},
+	pg.PaymentTypeQRIS:      {Min: 1000, Max: 10000000},
+	pg.PaymentTypeVABCA:     {Min: 10000},
+	pg.PaymentTypeVABNI:     {Min: 10000},
+	pg.PaymentTypeVABRI:     {Min: 10000},
+	pg.PaymentTypeVAMandiri: {Min: 10000},
+	pg.PaymentTypeVAPermata: {Min: 10000},
+	pg.PaymentTypeVACIMB:    {Min: 10000},
+	pg.PaymentTypeCC:        {Min: 10000},
+	pg.PaymentTypeAkulaku:   {Min: 10000, Max: 15000000},
+	pg.PaymentTypeKredivo:   {Min: 10000, Max: 30000000},
+}
@@ -0,0 +1,253 @@
+package midtrans
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+)
+
+const (
+	// Iris API endpoints
+	payoutsUri      = "/payouts"
+	payoutDetailUri = "/payouts/%s"
+
+	headerIdempotencyKey = "X-Idempotency-Key"
+	headerIrisSignature  = "Iris-Signature"
+)
+
+// CreateDisbursement sends money to a bank account using Midtrans Iris
+// The returned ID is the Iris reference_no, which is needed for GetDisbursement
+func (m *midtrans) CreateDisbursement(ctx context.Context, params pg.DisbursementParams) (*pg.Disbursement, error) {
+	req := &IrisCreatePayoutsRequest{
+		Payouts: []*IrisPayout{m.mapper.mapToIrisPayout(params)},
+	}
+
+	resp, raw, err := m.createPayouts(ctx, req, params.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Payouts) == 0 {
+		return nil, fmt.Errorf("failed to parse response: no payout returned")
+	}
+
+	return m.mapper.mapToCreatedDisbursement(params, resp.Payouts[0], raw), nil
+}
+
+// GetDisbursement retrieves a payout by its Iris reference_no
+func (m *midtrans) GetDisbursement(ctx context.Context, id string) (*pg.Disbursement, error) {
+	fullURL := fmt.Sprintf(m.getIrisBaseURL()+payoutDetailUri, id)
+
+	responseBody, err := m.sendIrisRequest(ctx, http.MethodGet, fullURL, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp IrisPayoutDetails
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return m.mapper.mapToDisbursement(&resp, raw), nil
+}
+
+// CreateBatchDisbursement sends several payouts in one Iris Create Payouts request
+// Iris has no batch object, so the batch ID is the merchant's ReferenceID
+func (m *midtrans) CreateBatchDisbursement(ctx context.Context, params pg.BatchDisbursementParams) (*pg.BatchDisbursement, error) {
+	req := &IrisCreatePayoutsRequest{
+		Payouts: make([]*IrisPayout, len(params.Disbursements)),
+	}
+	for i, d := range params.Disbursements {
+		req.Payouts[i] = m.mapper.mapToIrisPayout(d)
+	}
+
+	resp, raw, err := m.createPayouts(ctx, req, params.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Payouts) != len(params.Disbursements) {
+		return nil, fmt.Errorf("failed to parse response: expected %d payouts, got %d", len(params.Disbursements), len(resp.Payouts))
+	}
+
+	batch := &pg.BatchDisbursement{
+		ID:            params.ReferenceID,
+		ReferenceID:   params.ReferenceID,
+		Status:        pg.StatusPending,
+		Disbursements: make([]*pg.Disbursement, len(resp.Payouts)),
+		Raw:           raw,
+	}
+	for i, payout := range resp.Payouts {
+		batch.Disbursements[i] = m.mapper.mapToCreatedDisbursement(params.Disbursements[i], payout, nil)
+		batch.TotalAmount += params.Disbursements[i].Amount
+	}
+
+	return batch, nil
+}
+
+// VerifyDisbursementWebhook verifies the Iris-Signature header
+// The signature is SHA512(body + Iris merchant key), see pg.WithDisbursementWebhookKey
+func (m *midtrans) VerifyDisbursementWebhook(r *http.Request) bool {
+	if r.Body == nil || m.config.DisbursementWebhookKey == "" {
+		return false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false
+	}
+
+	// Restore body for subsequent reads
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	signature := r.Header.Get(headerIrisSignature)
+	if signature == "" {
+		return false
+	}
+
+	expected := utils.CalculateSHA512(string(body) + m.config.DisbursementWebhookKey)
+
+	return utils.SecureCompare(strings.ToLower(signature), expected)
+}
+
+// ParseDisbursementWebhook parses an Iris payout notification
+func (m *midtrans) ParseDisbursementWebhook(r *http.Request) (*pg.DisbursementEvent, error) {
+	if r.Body == nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	// Restore body for subsequent reads
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var notification IrisNotification
+	if err := json.Unmarshal(body, &notification); err != nil || notification.ReferenceNo == "" {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(body, &raw)
+
+	disbursement := m.mapper.mapToDisbursement(&IrisPayoutDetails{
+		ReferenceNo:  notification.ReferenceNo,
+		Amount:       notification.Amount,
+		Status:       notification.Status,
+		UpdatedAt:    notification.UpdatedAt,
+		ErrorCode:    notification.ErrorCode,
+		ErrorMessage: notification.ErrorMessage,
+	}, raw)
+
+	timestamp := time.Now()
+	if notification.UpdatedAt != nil {
+		timestamp = *notification.UpdatedAt
+	}
+
+	return &pg.DisbursementEvent{
+		Disbursement: *disbursement,
+		EventType:    pg.DisbursementEventType(disbursement.Status),
+		Timestamp:    timestamp,
+	}, nil
+}
+
+// createPayouts calls Iris Create Payouts
+func (m *midtrans) createPayouts(ctx context.Context, req *IrisCreatePayoutsRequest, idempotencyKey string) (*IrisCreatePayoutsResponse, map[string]interface{}, error) {
+	responseBody, err := m.sendIrisRequest(ctx, http.MethodPost, m.getIrisBaseURL()+payoutsUri, req, idempotencyKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resp IrisCreatePayoutsResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return &resp, raw, nil
+}
+
+// sendIrisRequest sends an authenticated request to Iris and returns the response body
+func (m *midtrans) sendIrisRequest(ctx context.Context, method, fullURL string, payload interface{}, idempotencyKey string) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		bodyBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(headerAuthorization, utils.SetBasicAuthorization(m.disbursementKey(), ""))
+	if idempotencyKey != "" {
+		req.Header.Set(headerIdempotencyKey, idempotencyKey)
+	}
+
+	resp, err := m.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp irisErrorResponse
+		if err := json.Unmarshal(responseBody, &errResp); err == nil && errResp.ErrorMessage != "" {
+			message := errResp.ErrorMessage
+			if len(errResp.Errors) > 0 {
+				message += ": " + strings.Join(errResp.Errors, ", ")
+			}
+			return nil, pg.WrapProviderError(ProviderName, fmt.Sprint(resp.StatusCode), message, nil)
+		}
+		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+	}
+
+	return responseBody, nil
+}
+
+// disbursementKey returns the Iris API key, falling back to the server key
+func (m *midtrans) disbursementKey() string {
+	if m.config.DisbursementKey != "" {
+		return m.config.DisbursementKey
+	}
+	return m.config.ServerKey
+}
+
+// getIrisBaseURL returns the Iris base URL based on environment
+func (m *midtrans) getIrisBaseURL() string {
+	if m.config.BaseURL != "" {
+		return m.config.BaseURL + irisPath
+	}
+	if m.config.Environment == "production" {
+		return irisProductionURL
+	}
+	return irisSandboxURL
+}
@@ -0,0 +1,314 @@
+package midtrans
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+)
+
+// rewriteTransport sends every request to the test server regardless of the original host
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestProvider creates a Midtrans provider which talks to the given test server
+func newTestProvider(t *testing.T, server *httptest.Server, disbursementKey string) *midtrans {
+	t.Helper()
+
+	provider, err := New(&pg.ProviderConfig{
+		ServerKey:       "server-key",
+		DisbursementKey: disbursementKey,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	m := provider.(*midtrans)
+	if server != nil {
+		target, _ := url.Parse(server.URL)
+		m.httpCli = &http.Client{
+			Transport: &rewriteTransport{target: target},
+		}
+	}
+
+	return m
+}
+
+func TestMidtrans_disbursementKey(t *testing.T) {
+	if got := newTestProvider(t, nil, "").disbursementKey(); got != "server-key" {
+		t.Errorf("disbursementKey() = %v, want server-key", got)
+	}
+	if got := newTestProvider(t, nil, "iris-key").disbursementKey(); got != "iris-key" {
+		t.Errorf("disbursementKey() = %v, want iris-key", got)
+	}
+}
+
+func TestMidtrans_CreateDisbursement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/iris/api/v1"+payoutsUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get(headerAuthorization) != utils.SetBasicAuthorization("iris-key", "") {
+			t.Errorf("Authorization = %v, want basic auth with iris-key", r.Header.Get(headerAuthorization))
+		}
+		if r.Header.Get(headerIdempotencyKey) != "DISB-001" {
+			t.Errorf("%s = %v, want DISB-001", headerIdempotencyKey, r.Header.Get(headerIdempotencyKey))
+		}
+
+		var req IrisCreatePayoutsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if len(req.Payouts) != 1 {
+			t.Fatalf("len(Payouts) = %v, want 1", len(req.Payouts))
+		}
+		if req.Payouts[0].BeneficiaryBank != "bca" {
+			t.Errorf("BeneficiaryBank = %v, want bca", req.Payouts[0].BeneficiaryBank)
+		}
+		if req.Payouts[0].Amount != "100000" {
+			t.Errorf("Amount = %v, want 100000", req.Payouts[0].Amount)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"payouts":[{"status":"queued","reference_no":"ref-123"}]}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "iris-key")
+
+	result, err := m.CreateDisbursement(context.Background(), pg.DisbursementParams{
+		ReferenceID:   "DISB-001",
+		BankCode:      "BCA",
+		AccountNumber: "1234567890",
+		AccountHolder: "John Doe",
+		Amount:        100000,
+	})
+	if err != nil {
+		t.Fatalf("CreateDisbursement() error = %v", err)
+	}
+
+	if result.ID != "ref-123" {
+		t.Errorf("ID = %v, want ref-123", result.ID)
+	}
+	if result.ReferenceID != "DISB-001" {
+		t.Errorf("ReferenceID = %v, want DISB-001", result.ReferenceID)
+	}
+	if result.Status != pg.StatusPending {
+		t.Errorf("Status = %v, want %v", result.Status, pg.StatusPending)
+	}
+}
+
+func TestMidtrans_CreateDisbursement_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error_message":"An error occurred when creating payouts","errors":["Beneficiary bank is invalid"]}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	_, err := m.CreateDisbursement(context.Background(), pg.DisbursementParams{ReferenceID: "DISB-001"})
+	if !pg.IsProviderError(err) {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Beneficiary bank is invalid") {
+		t.Errorf("error should contain the Iris errors, got: %v", err)
+	}
+}
+
+func TestMidtrans_CreateBatchDisbursement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"payouts":[{"status":"queued","reference_no":"ref-1"},{"status":"queued","reference_no":"ref-2"}]}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	result, err := m.CreateBatchDisbursement(context.Background(), pg.BatchDisbursementParams{
+		ReferenceID: "BATCH-001",
+		Disbursements: []pg.DisbursementParams{
+			{ReferenceID: "DISB-001", BankCode: "bca", AccountNumber: "1", AccountHolder: "A", Amount: 100000},
+			{ReferenceID: "DISB-002", BankCode: "bni", AccountNumber: "2", AccountHolder: "B", Amount: 50000},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateBatchDisbursement() error = %v", err)
+	}
+
+	if result.ID != "BATCH-001" {
+		t.Errorf("ID = %v, want BATCH-001", result.ID)
+	}
+	if result.TotalAmount != 150000 {
+		t.Errorf("TotalAmount = %v, want 150000", result.TotalAmount)
+	}
+	if len(result.Disbursements) != 2 || result.Disbursements[1].ID != "ref-2" {
+		t.Errorf("Disbursements = %+v, want ref-1 and ref-2", result.Disbursements)
+	}
+}
+
+func TestMidtrans_GetDisbursement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/iris/api/v1/payouts/ref-123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"amount": "100000.0",
+			"beneficiary_name": "John Doe",
+			"beneficiary_account": "1234567890",
+			"bank": "bca",
+			"reference_no": "ref-123",
+			"notes": "Payout",
+			"status": "completed",
+			"created_at": "2024-01-01T10:00:00Z",
+			"updated_at": "2024-01-01T10:05:00Z"
+		}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	result, err := m.GetDisbursement(context.Background(), "ref-123")
+	if err != nil {
+		t.Fatalf("GetDisbursement() error = %v", err)
+	}
+
+	if result.Amount != 100000 {
+		t.Errorf("Amount = %v, want 100000", result.Amount)
+	}
+	if result.Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", result.Status, pg.StatusSuccess)
+	}
+	if result.UpdatedAt.IsZero() {
+		t.Error("UpdatedAt should be set")
+	}
+}
+
+func TestMidtrans_ParseDisbursementWebhook(t *testing.T) {
+	body := `{"reference_no":"ref-123","amount":"100000.0","status":"failed","error_code":"001","error_message":"Account not found"}`
+
+	tests := []struct {
+		name      string
+		signature string
+		wantValid bool
+	}{
+		{
+			name:      "valid signature",
+			signature: utils.CalculateSHA512(body + "iris-merchant-key"),
+			wantValid: true,
+		},
+		{
+			name:      "signed with the API key",
+			signature: utils.CalculateSHA512(body + "iris-key"),
+			wantValid: false,
+		},
+		{
+			name:      "invalid signature",
+			signature: utils.CalculateSHA512(body + "other-key"),
+			wantValid: false,
+		},
+		{
+			name:      "missing signature",
+			wantValid: false,
+		},
+	}
+
+	m := newTestProvider(t, nil, "iris-key")
+	m.config.DisbursementWebhookKey = "iris-merchant-key"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+			if tt.signature != "" {
+				req.Header.Set(headerIrisSignature, tt.signature)
+			}
+
+			if got := m.VerifyDisbursementWebhook(req); got != tt.wantValid {
+				t.Fatalf("VerifyDisbursementWebhook() = %v, want %v", got, tt.wantValid)
+			}
+
+			// Body must still be readable after verification
+			event, err := m.ParseDisbursementWebhook(req)
+			if err != nil {
+				t.Fatalf("ParseDisbursementWebhook() error = %v", err)
+			}
+			if event.ID != "ref-123" {
+				t.Errorf("ID = %v, want ref-123", event.ID)
+			}
+			if event.EventType != pg.EventDisbursementFailed {
+				t.Errorf("EventType = %v, want %v", event.EventType, pg.EventDisbursementFailed)
+			}
+			if event.FailureReason != "Account not found" {
+				t.Errorf("FailureReason = %v, want Account not found", event.FailureReason)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(`{}`))
+	if _, err := m.ParseDisbursementWebhook(req); !errors.Is(err, pg.ErrInvalidPayload) {
+		t.Errorf("ParseDisbursementWebhook() error = %v, want %v", err, pg.ErrInvalidPayload)
+	}
+}
+
+func TestMidtrans_VerifyDisbursementWebhook_Sample(t *testing.T) {
+	// A completed payout notification with its Iris-Signature for a sandbox merchant key
+	body := `{"reference_no":"ibmt44b1pyskt1s0vd","amount":"200000.0","status":"completed","updated_at":"2024-03-01T10:15:30Z"}`
+	signature := "ad062954f4f644d0510a22d286b525635e49769d4fe50137884dc163e0c7537f72331e5cd6a91aaf9f4a3b8812c02fa65d98a869ca7e1f868ae0bd99b1c1cb8b"
+
+	m := newTestProvider(t, nil, "iris-key")
+
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+	req.Header.Set(headerIrisSignature, signature)
+	if m.VerifyDisbursementWebhook(req) {
+		t.Error("VerifyDisbursementWebhook() = true without a merchant key, want false")
+	}
+
+	m.config.DisbursementWebhookKey = "SB-Mid-merchant-Z8dq6eXk1XrTBPaN"
+
+	req = httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+	req.Header.Set(headerIrisSignature, signature)
+	if !m.VerifyDisbursementWebhook(req) {
+		t.Error("VerifyDisbursementWebhook() = false, want true")
+	}
+}
+
+func TestMapper_mapPayoutStatus(t *testing.T) {
+	tests := []struct {
+		status PayoutStatus
+		want   pg.Status
+	}{
+		{PayoutQueued, pg.StatusPending},
+		{PayoutApproved, pg.StatusPending},
+		{PayoutProcessed, pg.StatusProcessing},
+		{PayoutCompleted, pg.StatusSuccess},
+		{PayoutFailed, pg.StatusFailed},
+		{PayoutRejected, pg.StatusCancelled},
+	}
+
+	mapper := &Mapper{}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := mapper.mapPayoutStatus(tt.status); got != tt.want {
+				t.Errorf("mapPayoutStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
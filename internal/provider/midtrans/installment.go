@@ -0,0 +1,57 @@
+package midtrans
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// BIN API endpoint
+	binUri = "/v1/bins/%s"
+)
+
+// GetInstallmentOptions returns the installment plans for a card BIN or a bank code
+// Midtrans does not quote plans, they are built from the terms configured for the issuing bank
+func (m *midtrans) GetInstallmentOptions(ctx context.Context, binOrBank string, amount int64) ([]pg.InstallmentPlan, error) {
+	bank := binOrBank
+	if isBIN(binOrBank) {
+		responseBody, err := m.sendCoreRequest(ctx, http.MethodGet, fmt.Sprintf(binUri, binOrBank), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp BINResponse
+		if err := json.Unmarshal(responseBody, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		bank = resp.Data.BankCode
+	}
+
+	if b, ok := pg.LookupBank(bank); ok {
+		bank = b.Code
+	}
+
+	terms, ok := pg.InstallmentTerms(m.config.InstallmentTerms, bank)
+	if !ok {
+		return []pg.InstallmentPlan{}, nil
+	}
+
+	return pg.NewInstallmentPlans(bank, amount, terms), nil
+}
+
+// isBIN reports whether s looks like a card BIN, 6 to 8 digits
+func isBIN(s string) bool {
+	if len(s) < 6 || len(s) > 8 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
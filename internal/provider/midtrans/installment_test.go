@@ -0,0 +1,98 @@
+package midtrans
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+func TestMidtrans_CreateCharge_CardInstallment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != chargeUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req CardCreateParams
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.PaymentType != PaymentTypeCard || req.CreditCard.TokenID != "card-token" {
+			t.Errorf("request = %+v, want card charge with card-token", req)
+		}
+		if req.CreditCard.Bank != "bni" || req.CreditCard.InstallmentTerm != 6 {
+			t.Errorf("CreditCard = %+v, want bni 6 months", req.CreditCard)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status_code":"201","transaction_id":"txn-789","order_id":"ORDER-005","gross_amount":"1200000.00","payment_type":"credit_card","transaction_status":"pending","redirect_url":"https://api.sandbox.veritrans.co.id/v2/token/rba/redirect/txn-789"}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	params := pg.ChargeParams{
+		OrderID:     "ORDER-005",
+		Amount:      1200000,
+		PaymentType: pg.PaymentTypeCC,
+		Customer:    pg.Customer{ID: "CUST-005", Name: "Jane Doe", Email: "jane@example.com", Phone: "081234567890"},
+		Items:       []pg.Item{{ID: "ITEM-005", Name: "Laptop", Price: 1200000, Quantity: 1}},
+		CreditCard:  &pg.CreditCardParams{CardNumber: "card-token", Secure: true, InstallmentTerm: 6, Bank: "BNI"},
+	}
+
+	resp, err := m.CreateCharge(context.Background(), params)
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if resp.PaymentURL == "" {
+		t.Error("PaymentURL is empty, want 3DS redirect URL")
+	}
+
+	params.CreditCard.InstallmentTerm = 24
+	if _, err := m.CreateCharge(context.Background(), params); err == nil {
+		t.Error("expected error for a term not offered by BNI, got nil")
+	}
+
+	params.CreditCard = nil
+	if _, err := m.CreateCharge(context.Background(), params); err == nil {
+		t.Error("expected error without card token, got nil")
+	}
+}
+
+func TestMidtrans_GetInstallmentOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/bins/410505" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"bin":"410505","bin_type":"CREDIT","brand":"VISA","bank":"BANK NEGARA INDONESIA","bank_code":"BNI"}}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+	m.config.InstallmentTerms = map[string][]int{"BNI": {3, 12}}
+
+	plans, err := m.GetInstallmentOptions(context.Background(), "410505", 1200000)
+	if err != nil {
+		t.Fatalf("GetInstallmentOptions() error = %v", err)
+	}
+
+	if len(plans) != 2 {
+		t.Fatalf("got %d plans, want 2", len(plans))
+	}
+	if plans[1].Tenure != 12 || plans[1].InstallmentAmount != 100000 || plans[1].Bank != "BNI" {
+		t.Errorf("plan = %+v, want BNI 12 x 100000", plans[1])
+	}
+
+	plans, err = m.GetInstallmentOptions(context.Background(), "PERMATA", 1200000)
+	if err != nil {
+		t.Fatalf("GetInstallmentOptions() error = %v", err)
+	}
+	if len(plans) != 0 {
+		t.Errorf("got %d plans for a bank without installments, want 0", len(plans))
+	}
+}
@@ -2,7 +2,10 @@ package midtrans
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pandudpn/go-payment-gateway"
@@ -11,6 +14,28 @@ import (
 // Mapper handles conversion between unified and Midtrans-specific types
 type Mapper struct{}
 
+// wib is the time zone of Midtrans transaction times
+var wib = time.FixedZone("WIB", 7*60*60)
+
+// transactionTimeLayout is the time format of transaction_time
+const transactionTimeLayout = "2006-01-02 15:04:05"
+
+// parseGrossAmount parses gross_amount, which Midtrans sends with two decimals such as "150000.00"
+func parseGrossAmount(value string) int64 {
+	amount, _ := strconv.ParseFloat(value, 64)
+	return int64(amount)
+}
+
+// parseTransactionTime parses a transaction time in WIB, accepting RFC3339 as well
+// Returns the zero time when the value is empty or invalid
+func parseTransactionTime(value string) time.Time {
+	if t, err := time.ParseInLocation(transactionTimeLayout, value, wib); err == nil {
+		return t
+	}
+	t, _ := time.Parse(time.RFC3339, value)
+	return t
+}
+
 // mapPaymentType maps unified payment type to Midtrans payment type
 func (m *Mapper) mapPaymentType(pt pg.PaymentType) string {
 	switch pt {
@@ -32,6 +57,10 @@ func (m *Mapper) mapPaymentType(pt pg.PaymentType) string {
 		return "echannel"
 	case pg.PaymentTypeCC:
 		return "credit_card"
+	case pg.PaymentTypeAkulaku:
+		return "akulaku"
+	case pg.PaymentTypeKredivo:
+		return "kredivo"
 	default:
 		return string(pt)
 	}
@@ -77,37 +106,109 @@ func (m *Mapper) mapPaymentTypeToBank(pt pg.PaymentType) BankCode {
 	}
 }
 
-// mapToEWalletParams maps unified ChargeParams to Midtrans EWallet params
-func (m *Mapper) mapToEWalletParams(params pg.ChargeParams) *EWallet {
-	e := &EWallet{
-		PaymentType:       PaymentType(m.mapPaymentType(params.PaymentType)),
-		TransactionDetails: &TransactionDetail{
-			OrderID:     params.OrderID,
-			GrossAmount: params.Amount,
-		},
-		ItemDetails: make([]*ItemDetail, len(params.Items)),
+// mapCustomerDetails maps the unified customer to Midtrans customer_details
+// Returns nil when the customer has no name
+func (m *Mapper) mapCustomerDetails(customer pg.Customer) *CustomerDetail {
+	if customer.Name == "" {
+		return nil
 	}
 
-	// Map customer details
-	if len(params.Customer.Name) > 0 {
-		// Split name into first and last name
-		firstName := params.Customer.Name
-		lastName := ""
-		if len(params.Customer.Name) > 20 {
-			firstName = params.Customer.Name[:20]
-			lastName = params.Customer.Name[20:]
+	firstName, lastName := pg.SplitName(customer.Name)
+	return &CustomerDetail{
+		FirstName:       firstName,
+		LastName:        lastName,
+		Email:           customer.Email,
+		Phone:           customer.Phone,
+		BillingAddress:  m.mapAddress(customer.Recipient(customer.BillingAddress)),
+		ShippingAddress: m.mapAddress(customer.Recipient(customer.ShippingAddress)),
+	}
+}
+
+// mapCustomFields maps metadata to the Midtrans custom fields as "key=value" in key order
+// Midtrans has three custom fields, entries past the third are dropped
+func (m *Mapper) mapCustomFields(metadata map[string]string) CustomFields {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var fields [maxCustomFields]string
+	for i, k := range keys {
+		if i == maxCustomFields {
+			break
 		}
-		e.CustomerDetails = &CustomerDetail{
-			FirstName: firstName,
-			LastName:  lastName,
-			Email:     params.Customer.Email,
-			Phone:     params.Customer.Phone,
+		fields[i] = k + "=" + metadata[k]
+	}
+
+	return CustomFields{CustomField1: fields[0], CustomField2: fields[1], CustomField3: fields[2]}
+}
+
+// mapMetadata maps the custom fields back to metadata
+// Fields not set by mapCustomFields are kept under their own name
+func (m *Mapper) mapMetadata(c CustomFields) map[string]string {
+	var metadata map[string]string
+	for i, field := range []string{c.CustomField1, c.CustomField2, c.CustomField3} {
+		if field == "" {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		if k, v, ok := strings.Cut(field, "="); ok && k != "" {
+			metadata[k] = v
+		} else {
+			metadata[fmt.Sprintf("custom_field%d", i+1)] = field
 		}
 	}
+	return metadata
+}
+
+// mapAddress maps a unified address to a Midtrans address, Midtrans expects ISO 3166-1 alpha-3 country codes
+func (m *Mapper) mapAddress(address *pg.Address) *Address {
+	if address == nil {
+		return nil
+	}
 
-	// Map items
-	for i, item := range params.Items {
-		e.ItemDetails[i] = &ItemDetail{
+	firstName, lastName := pg.SplitName(address.Name)
+	return &Address{
+		FirstName:   firstName,
+		LastName:    lastName,
+		Phone:       address.Phone,
+		Address:     address.Street(),
+		City:        address.City,
+		PostalCode:  address.PostalCode,
+		CountryCode: countryCodeAlpha3(address.Country()),
+	}
+}
+
+// countryCodeAlpha3 converts an ISO 3166-1 alpha-2 country code to alpha-3
+func countryCodeAlpha3(code string) string {
+	switch code {
+	case "ID":
+		return "IDN"
+	case "SG":
+		return "SGP"
+	case "MY":
+		return "MYS"
+	case "PH":
+		return "PHL"
+	case "TH":
+		return "THA"
+	case "VN":
+		return "VNM"
+	default:
+		return code
+	}
+}
+
+// mapItemDetails maps the items and adjustments to Midtrans item_details
+// Discounts become negative price items so the details add up to gross_amount
+func (m *Mapper) mapItemDetails(params pg.ChargeParams) []*ItemDetail {
+	items := params.LineItems()
+	details := make([]*ItemDetail, len(items))
+	for i, item := range items {
+		details[i] = &ItemDetail{
 			ID:       item.ID,
 			Name:     item.Name,
 			Price:    item.Price,
@@ -115,6 +216,23 @@ func (m *Mapper) mapToEWalletParams(params pg.ChargeParams) *EWallet {
 			Category: item.Category,
 		}
 	}
+	return details
+}
+
+// mapToEWalletParams maps unified ChargeParams to Midtrans EWallet params
+func (m *Mapper) mapToEWalletParams(params pg.ChargeParams) *EWallet {
+	e := &EWallet{
+		PaymentType:       PaymentType(m.mapPaymentType(params.PaymentType)),
+		TransactionDetails: &TransactionDetail{
+			OrderID:     params.OrderID,
+			GrossAmount: params.Amount,
+		},
+		ItemDetails: m.mapItemDetails(params),
+	}
+
+	// Map customer details
+	e.CustomerDetails = m.mapCustomerDetails(params.Customer)
+	e.CustomFields = m.mapCustomFields(params.Metadata)
 
 	// Set e-wallet specific details
 	var ewalletDetail *EWalletDetail
@@ -123,10 +241,17 @@ func (m *Mapper) mapToEWalletParams(params pg.ChargeParams) *EWallet {
 		ewalletDetail = &EWalletDetail{
 			CallbackURL: params.CallbackURL,
 		}
+		// Charge a linked account, the payment option token is resolved by the provider
+		if params.EWallet != nil && params.EWallet.AccountLinkID != "" {
+			ewalletDetail.AccountID = params.EWallet.AccountLinkID
+		}
 		// Check for custom GoPay parameters
 		if accountID, ok := params.Custom["gopay_account_id"].(string); ok {
 			ewalletDetail.AccountID = accountID
 		}
+		if token, ok := params.Custom["gopay_payment_option_token"].(string); ok {
+			ewalletDetail.PaymentOptionToken = token
+		}
 	case pg.PaymentTypeShopeePay:
 		ewalletDetail = &EWalletDetail{
 			CallbackURL: params.CallbackURL,
@@ -153,35 +278,12 @@ func (m *Mapper) mapToBankTransferParams(params pg.ChargeParams) *BankTransferCr
 			OrderID:     params.OrderID,
 			GrossAmount: params.Amount,
 		},
-		ItemDetails: make([]*ItemDetail, len(params.Items)),
+		ItemDetails: m.mapItemDetails(params),
 	}
 
 	// Map customer details
-	if len(params.Customer.Name) > 0 {
-		firstName := params.Customer.Name
-		lastName := ""
-		if len(params.Customer.Name) > 20 {
-			firstName = params.Customer.Name[:20]
-			lastName = params.Customer.Name[20:]
-		}
-		bt.CustomerDetails = &CustomerDetail{
-			FirstName: firstName,
-			LastName:  lastName,
-			Email:     params.Customer.Email,
-			Phone:     params.Customer.Phone,
-		}
-	}
-
-	// Map items
-	for i, item := range params.Items {
-		bt.ItemDetails[i] = &ItemDetail{
-			ID:       item.ID,
-			Name:     item.Name,
-			Price:    item.Price,
-			Quantity: item.Quantity,
-			Category: item.Category,
-		}
-	}
+	bt.CustomerDetails = m.mapCustomerDetails(params.Customer)
+	bt.CustomFields = m.mapCustomFields(params.Metadata)
 
 	// Set bank transfer details
 	bank := m.mapPaymentTypeToBank(params.PaymentType)
@@ -189,6 +291,14 @@ func (m *Mapper) mapToBankTransferParams(params pg.ChargeParams) *BankTransferCr
 		Bank: bank,
 	}
 
+	if va := params.VirtualAccount; va != nil {
+		bankTransfer.VANumber = va.VANumber
+		// Only Permata shows a custom name, other banks display the merchant name
+		if va.CustomerName != "" && bank == BankPermata {
+			bankTransfer.Permata = &PermataDetail{RecipientName: strings.ToUpper(va.CustomerName)}
+		}
+	}
+
 	// Get custom VA number if provided
 	if vaNumber, ok := params.Custom["va_number"].(string); ok {
 		bankTransfer.VANumber = vaNumber
@@ -208,17 +318,66 @@ func (m *Mapper) mapToBankTransferParams(params pg.ChargeParams) *BankTransferCr
 	return bt
 }
 
+// mapToPaylaterParams maps unified ChargeParams to Midtrans PaylaterCreateParams
+func (m *Mapper) mapToPaylaterParams(params pg.ChargeParams) *PaylaterCreateParams {
+	p := &PaylaterCreateParams{
+		PaymentType: PaymentType(m.mapPaymentType(params.PaymentType)),
+		TransactionDetails: &TransactionDetail{
+			OrderID:     params.OrderID,
+			GrossAmount: params.Amount,
+		},
+		ItemDetails: m.mapItemDetails(params),
+	}
+
+	p.CustomerDetails = m.mapCustomerDetails(params.Customer)
+	p.CustomFields = m.mapCustomFields(params.Metadata)
+
+	return p
+}
+
+// mapToCardParams maps unified ChargeParams to Midtrans CardCreateParams
+// CreditCard.CardNumber is the token from the Midtrans card tokenization
+func (m *Mapper) mapToCardParams(params pg.ChargeParams) *CardCreateParams {
+	p := &CardCreateParams{
+		PaymentType: PaymentTypeCard,
+		TransactionDetails: &TransactionDetail{
+			OrderID:     params.OrderID,
+			GrossAmount: params.Amount,
+		},
+		CreditCard: &CreditCardDetail{
+			TokenID:        params.CreditCard.CardNumber,
+			Authentication: params.CreditCard.Secure,
+			SaveTokenID:    params.CreditCard.SaveCard,
+		},
+		ItemDetails: m.mapItemDetails(params),
+	}
+
+	if params.CreditCard.IsInstallment() {
+		p.CreditCard.Bank = m.mapCardBank(params.CreditCard.Bank)
+		p.CreditCard.InstallmentTerm = params.CreditCard.InstallmentTerm
+	}
+
+	p.CustomerDetails = m.mapCustomerDetails(params.Customer)
+	p.CustomFields = m.mapCustomFields(params.Metadata)
+
+	return p
+}
+
+// mapCardBank maps a unified bank code to a Midtrans acquiring bank code
+func (m *Mapper) mapCardBank(bank string) string {
+	if b, ok := pg.LookupBank(bank); ok {
+		bank = b.Code
+	}
+	return strings.ToLower(bank)
+}
+
 // mapToChargeResponse maps Midtrans ChargeResponse to unified ChargeResponse
 func (m *Mapper) mapToChargeResponse(resp *ChargeResponse) *pg.ChargeResponse {
 	if resp == nil {
 		return nil
 	}
 
-	// Parse gross amount
-	var amount int64
-	if resp.GrossAmount != "" {
-		amount, _ = strconv.ParseInt(resp.GrossAmount, 10, 64)
-	}
+	amount := parseGrossAmount(resp.GrossAmount)
 
 	unified := &pg.ChargeResponse{
 		TransactionID: resp.TransactionID,
@@ -227,18 +386,14 @@ func (m *Mapper) mapToChargeResponse(resp *ChargeResponse) *pg.ChargeResponse {
 		Status:        m.mapStatus(resp.TransactionStatus),
 		PaymentURL:    resp.RedirectURL,
 		CreatedAt:     resp.TransactionTime,
-		VABank:        string(resp.Bank),
 	}
 
-	// Extract payment URL from actions if available
-	if len(resp.Actions) > 0 {
-		for _, action := range resp.Actions {
-			if action.URL != "" {
-				unified.PaymentURL = action.URL
-				break
-			}
-		}
+	// Map actions, the payment URL is the web checkout or else the app deeplink
+	unified.NextActions = m.mapToNextActions(resp)
+	if url := pg.PreferredURL(unified.NextActions, false); url != "" {
+		unified.PaymentURL = url
 	}
+	unified.QRString = resp.QRString
 
 	// Set VA specific fields
 	if resp.PermataVANumber != "" {
@@ -263,17 +418,52 @@ func (m *Mapper) mapToChargeResponse(resp *ChargeResponse) *pg.ChargeResponse {
 	return unified
 }
 
+// mapToNextActions maps the redirect URL and actions of a Midtrans charge to unified next actions
+func (m *Mapper) mapToNextActions(resp *ChargeResponse) []pg.Action {
+	var actions []pg.Action
+
+	if resp.RedirectURL != "" {
+		actions = append(actions, pg.Action{Type: pg.ActionRedirect, URL: resp.RedirectURL, Method: "GET"})
+	}
+
+	for _, action := range resp.Actions {
+		switch action.Name {
+		case "deeplink-redirect":
+			actions = append(actions, pg.Action{Type: pg.ActionDeeplink, URL: action.URL, Method: action.Method})
+		case "generate-qr-code", "generate-qr-code-v2":
+			actions = append(actions, pg.Action{Type: pg.ActionShowQR, URL: action.URL, Method: action.Method, QRString: resp.QRString})
+		case "get-status":
+			actions = append(actions, pg.Action{Type: pg.ActionCheckStatus, URL: action.URL, Method: action.Method})
+		}
+	}
+
+	// QRIS may return the QR content without an image action
+	if resp.QRString != "" {
+		if !hasAction(actions, pg.ActionShowQR) {
+			actions = append(actions, pg.Action{Type: pg.ActionShowQR, QRString: resp.QRString})
+		}
+	}
+
+	return actions
+}
+
+// hasAction reports whether actions contain an action of the given type
+func hasAction(actions []pg.Action, actionType pg.ActionType) bool {
+	for _, action := range actions {
+		if action.Type == actionType {
+			return true
+		}
+	}
+	return false
+}
+
 // mapToPaymentStatus maps Midtrans response to unified PaymentStatus
 func (m *Mapper) mapToPaymentStatus(orderID string, resp *ChargeResponse) *pg.PaymentStatus {
 	if resp == nil {
 		return nil
 	}
 
-	// Parse gross amount
-	var amount int64
-	if resp.GrossAmount != "" {
-		amount, _ = strconv.ParseInt(resp.GrossAmount, 10, 64)
-	}
+	amount := parseGrossAmount(resp.GrossAmount)
 
 	paidAt := resp.TransactionTime
 	if resp.TransactionStatus != Settlement && resp.TransactionStatus != Capture {
@@ -287,10 +477,36 @@ func (m *Mapper) mapToPaymentStatus(orderID string, resp *ChargeResponse) *pg.Pa
 		Amount:        amount,
 		PaidAmount:    amount,
 		PaidAt:        &paidAt,
-		PaymentType:   m.unifiedPaymentType(string(resp.PaymentType)),
+		PaymentType:   m.chargePaymentType(resp),
+		Metadata:      m.mapMetadata(resp.CustomFields),
 	}
 }
 
+// chargePaymentType returns the unified payment type of a charge
+// Bank transfers are told apart by the bank of their VA number
+func (m *Mapper) chargePaymentType(resp *ChargeResponse) pg.PaymentType {
+	switch {
+	case resp.PaymentType == PaymentTypeMandiri:
+		return pg.PaymentTypeVAMandiri
+	case resp.PaymentType == PaymentTypeBCA && resp.PermataVANumber != "":
+		return pg.PaymentTypeVAPermata
+	case resp.PaymentType == PaymentTypeBCA && len(resp.VANumbers) > 0:
+		switch resp.VANumbers[0].Bank {
+		case BankBCA:
+			return pg.PaymentTypeVABCA
+		case BankBNI:
+			return pg.PaymentTypeVABNI
+		case BankBRI:
+			return pg.PaymentTypeVABRI
+		case BankPermata:
+			return pg.PaymentTypeVAPermata
+		case BankCIMB:
+			return pg.PaymentTypeVACIMB
+		}
+	}
+	return m.unifiedPaymentType(string(resp.PaymentType))
+}
+
 // unifiedPaymentType maps Midtrans payment type to unified payment type
 func (m *Mapper) unifiedPaymentType(pt string) pg.PaymentType {
 	switch pt {
@@ -308,6 +524,10 @@ func (m *Mapper) unifiedPaymentType(pt string) pg.PaymentType {
 		return pg.PaymentTypeQRIS
 	case "credit_card":
 		return pg.PaymentTypeCC
+	case "akulaku":
+		return pg.PaymentTypeAkulaku
+	case "kredivo":
+		return pg.PaymentTypeKredivo
 	default:
 		return pg.PaymentType(pt)
 	}
@@ -330,3 +550,317 @@ func (m *Mapper) mapEventType(status TransactionStatus) string {
 		return pg.EventPaymentPending
 	}
 }
+
+// mapPayoutStatus maps Iris payout status to unified status
+func (m *Mapper) mapPayoutStatus(status PayoutStatus) pg.Status {
+	switch status {
+	case PayoutProcessed:
+		return pg.StatusProcessing
+	case PayoutCompleted:
+		return pg.StatusSuccess
+	case PayoutFailed:
+		return pg.StatusFailed
+	case PayoutRejected:
+		return pg.StatusCancelled
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapToIrisPayout maps unified DisbursementParams to an Iris payout
+func (m *Mapper) mapToIrisPayout(params pg.DisbursementParams) *IrisPayout {
+	notes := params.Description
+	if notes == "" {
+		notes = params.ReferenceID
+	}
+
+	return &IrisPayout{
+		BeneficiaryName:    params.AccountHolder,
+		BeneficiaryAccount: params.AccountNumber,
+		BeneficiaryBank:    irisBankCode(params.BankCode),
+		BeneficiaryEmail:   params.Email,
+		Amount:             strconv.FormatInt(params.Amount, 10),
+		Notes:              notes,
+	}
+}
+
+// mapToCreatedDisbursement maps a created Iris payout back to unified Disbursement
+func (m *Mapper) mapToCreatedDisbursement(params pg.DisbursementParams, payout *IrisCreatedPayout, raw map[string]interface{}) *pg.Disbursement {
+	return &pg.Disbursement{
+		ID:            payout.ReferenceNo,
+		ReferenceID:   params.ReferenceID,
+		BankCode:      params.BankCode,
+		AccountNumber: params.AccountNumber,
+		AccountHolder: params.AccountHolder,
+		Amount:        params.Amount,
+		Description:   params.Description,
+		Status:        m.mapPayoutStatus(payout.Status),
+		Raw:           raw,
+	}
+}
+
+// mapToDisbursement maps Iris payout details to unified Disbursement
+func (m *Mapper) mapToDisbursement(resp *IrisPayoutDetails, raw map[string]interface{}) *pg.Disbursement {
+	if resp == nil {
+		return nil
+	}
+
+	amount, _ := strconv.ParseFloat(resp.Amount, 64)
+
+	d := &pg.Disbursement{
+		ID:            resp.ReferenceNo,
+		BankCode:      resp.Bank,
+		AccountNumber: resp.BeneficiaryAccount,
+		AccountHolder: resp.BeneficiaryName,
+		Amount:        int64(amount),
+		Description:   resp.Notes,
+		Status:        m.mapPayoutStatus(resp.Status),
+		FailureReason: resp.ErrorMessage,
+		Raw:           raw,
+	}
+
+	if resp.CreatedAt != nil {
+		d.CreatedAt = *resp.CreatedAt
+	}
+	if resp.UpdatedAt != nil {
+		d.UpdatedAt = *resp.UpdatedAt
+	}
+
+	return d
+}
+
+// mapToBankAccount maps Iris account validation response to unified BankAccount
+func (m *Mapper) mapToBankAccount(bankCode string, resp *IrisAccountValidationResponse, raw map[string]interface{}) *pg.BankAccount {
+	if resp == nil {
+		return nil
+	}
+
+	return &pg.BankAccount{
+		BankCode:      pg.NormalizeBankCode(bankCode),
+		AccountNumber: resp.AccountNo,
+		AccountHolder: resp.AccountName,
+		Valid:         resp.AccountName != "",
+		Status:        pg.StatusSuccess,
+		Raw:           raw,
+	}
+}
+
+// irisBankCode maps a bank code to the lower-case code used by Iris
+func irisBankCode(bankCode string) string {
+	return strings.ToLower(pg.NormalizeBankCode(bankCode))
+}
+
+// subscriptionTimeLayout is the time format of the Subscription API
+const subscriptionTimeLayout = "2006-01-02 15:04:05 -0700"
+
+// mapSubscriptionStatus maps Midtrans subscription status to unified status
+func (m *Mapper) mapSubscriptionStatus(status SubscriptionStatus) pg.SubscriptionStatus {
+	switch status {
+	case SubscriptionActive:
+		return pg.SubscriptionActive
+	case SubscriptionInactive:
+		return pg.SubscriptionPaused
+	case SubscriptionCancelled:
+		return pg.SubscriptionCancelled
+	default:
+		return pg.SubscriptionPending
+	}
+}
+
+// mapToSubscriptionSchedule maps a unified interval to a Midtrans schedule
+// Midtrans has no yearly unit, so years are sent as 12 months
+func (m *Mapper) mapToSubscriptionSchedule(interval pg.Interval, count int) *SubscriptionSchedule {
+	if count <= 0 {
+		count = 1
+	}
+
+	schedule := &SubscriptionSchedule{Interval: count}
+	switch interval {
+	case pg.IntervalDay:
+		schedule.IntervalUnit = "day"
+	case pg.IntervalWeek:
+		schedule.IntervalUnit = "week"
+	case pg.IntervalYear:
+		schedule.IntervalUnit = "month"
+		schedule.Interval = count * 12
+	default:
+		schedule.IntervalUnit = "month"
+	}
+
+	return schedule
+}
+
+// mapToSubscriptionRequest maps unified SubscriptionParams to Midtrans SubscriptionRequest
+func (m *Mapper) mapToSubscriptionRequest(params pg.SubscriptionParams) *SubscriptionRequest {
+	name := params.Name
+	if name == "" {
+		name = params.ReferenceID
+	}
+
+	schedule := m.mapToSubscriptionSchedule(params.Interval, params.IntervalCount)
+	schedule.MaxInterval = params.TotalCycles
+	if !params.StartAt.IsZero() {
+		schedule.StartTime = params.StartAt.Format(subscriptionTimeLayout)
+	}
+
+	req := &SubscriptionRequest{
+		Name:        name,
+		Amount:      strconv.FormatInt(params.Amount, 10),
+		Currency:    "IDR",
+		PaymentType: "credit_card",
+		Token:       params.PaymentToken,
+		Schedule:    schedule,
+		Metadata: map[string]interface{}{
+			"reference_id": params.ReferenceID,
+		},
+		CustomerDetails: &SubscriptionCustomer{
+			FirstName: params.Customer.Name,
+			Email:     params.Customer.Email,
+			Phone:     params.Customer.Phone,
+		},
+	}
+
+	if params.Description != "" {
+		req.Metadata["description"] = params.Description
+	}
+
+	if params.PaymentType == pg.PaymentTypeGoPay {
+		req.PaymentType = "gopay"
+		if accountID, ok := params.Custom["gopay_account_id"].(string); ok {
+			req.GoPay = &SubscriptionGoPay{AccountID: accountID}
+		}
+	}
+
+	return req
+}
+
+// mapToSubscriptionUpdateRequest maps unified SubscriptionUpdateParams to Midtrans SubscriptionRequest
+func (m *Mapper) mapToSubscriptionUpdateRequest(params pg.SubscriptionUpdateParams) *SubscriptionRequest {
+	req := &SubscriptionRequest{
+		Name:  params.Name,
+		Token: params.PaymentToken,
+	}
+
+	if params.Amount > 0 {
+		req.Amount = strconv.FormatInt(params.Amount, 10)
+		req.Currency = "IDR"
+	}
+	if params.Interval != "" {
+		req.Schedule = m.mapToSubscriptionSchedule(params.Interval, params.IntervalCount)
+	}
+
+	return req
+}
+
+// mapToSubscription maps Midtrans SubscriptionResponse to unified Subscription
+func (m *Mapper) mapToSubscription(resp *SubscriptionResponse, raw map[string]interface{}) *pg.Subscription {
+	if resp == nil {
+		return nil
+	}
+
+	amount, _ := strconv.ParseFloat(resp.Amount, 64)
+
+	sub := &pg.Subscription{
+		ID:            resp.ID,
+		Name:          resp.Name,
+		Amount:        int64(amount),
+		IntervalCount: resp.Schedule.Interval,
+		Status:        m.mapSubscriptionStatus(resp.Status),
+		Raw:           raw,
+	}
+
+	if referenceID, ok := resp.Metadata["reference_id"].(string); ok {
+		sub.ReferenceID = referenceID
+	}
+
+	switch resp.Schedule.IntervalUnit {
+	case "day":
+		sub.Interval = pg.IntervalDay
+	case "week":
+		sub.Interval = pg.IntervalWeek
+	default:
+		sub.Interval = pg.IntervalMonth
+	}
+
+	if next, err := time.Parse(subscriptionTimeLayout, resp.Schedule.NextExecutionAt); err == nil {
+		sub.NextChargeAt = &next
+	}
+	if created, err := time.Parse(subscriptionTimeLayout, resp.CreatedAt); err == nil {
+		sub.CreatedAt = created
+	}
+
+	return sub
+}
+
+// mapAccountStatus maps Midtrans pay account status to unified link status
+func (m *Mapper) mapAccountStatus(status AccountStatus) pg.LinkStatus {
+	switch status {
+	case AccountEnabled:
+		return pg.LinkActive
+	case AccountDisabled:
+		return pg.LinkUnlinked
+	case AccountExpired:
+		return pg.LinkExpired
+	default:
+		return pg.LinkPending
+	}
+}
+
+// mapToPayAccountRequest maps unified LinkParams to Midtrans PayAccountRequest
+func (m *Mapper) mapToPayAccountRequest(params pg.LinkParams) *PayAccountRequest {
+	phone := strings.TrimPrefix(params.Customer.Phone, "+")
+	phone = strings.TrimPrefix(phone, "62")
+	phone = strings.TrimPrefix(phone, "0")
+
+	return &PayAccountRequest{
+		PaymentType: PaymentTypeGopay,
+		GopayPartner: &GopayPartner{
+			PhoneNumber: phone,
+			CountryCode: "62",
+			RedirectURL: params.RedirectURL,
+		},
+	}
+}
+
+// mapToLinkedAccount maps Midtrans PayAccountResponse to unified LinkedAccount
+func (m *Mapper) mapToLinkedAccount(resp *PayAccountResponse, raw map[string]interface{}) *pg.LinkedAccount {
+	if resp == nil {
+		return nil
+	}
+
+	account := &pg.LinkedAccount{
+		ID:          resp.AccountID,
+		PaymentType: pg.PaymentTypeGoPay,
+		Status:      m.mapAccountStatus(resp.AccountStatus),
+		Raw:         raw,
+	}
+
+	// Prefer the web link, the deeplink only works on mobile
+	for _, action := range resp.Actions {
+		if action.Name == "activation-link-url" || account.ActionURL == "" {
+			account.ActionURL = action.URL
+		}
+	}
+
+	if option := gopayWallet(resp); option != nil && option.Balance != nil {
+		if balance, err := strconv.ParseFloat(option.Balance.Value, 64); err == nil {
+			value := int64(balance)
+			account.Balance = &value
+		}
+	}
+
+	return account
+}
+
+// gopayWallet returns the GOPAY_WALLET payment option of a linked account
+func gopayWallet(resp *PayAccountResponse) *PaymentOption {
+	if resp.Metadata == nil {
+		return nil
+	}
+	for _, option := range resp.Metadata.PaymentOptions {
+		if option.Name == "GOPAY_WALLET" {
+			return option
+		}
+	}
+	return nil
+}
@@ -24,6 +24,11 @@ const (
 	headerAuthorization = "Authorization"
 )
 
+func init() {
+	// Register this provider with the pg package
+	pg.RegisterProvider(ProviderName, New)
+}
+
 type midtrans struct {
 	config   *pg.ProviderConfig
 	mapper   *Mapper
@@ -39,7 +44,7 @@ func New(cfg *pg.ProviderConfig) (pg.Provider, error) {
 	return &midtrans{
 		config:  cfg,
 		mapper:  &Mapper{},
-		httpCli: &http.Client{Timeout: getTimeout(cfg)},
+		httpCli: &http.Client{Timeout: getTimeout(cfg), Transport: cfg.Transport},
 	}, nil
 }
 
@@ -61,10 +66,19 @@ func (m *midtrans) CreateCharge(ctx context.Context, params pg.ChargeParams) (*p
 
 	if params.PaymentType.IsEWallet() || params.PaymentType == pg.PaymentTypeQRIS {
 		ewalletParams := m.mapper.mapToEWalletParams(params)
-		responseBody, err = m.createChargeEWallet(ctx, ewalletParams)
+		if err := m.resolvePaymentOptionToken(ctx, ewalletParams); err != nil {
+			return nil, err
+		}
+		responseBody, err = m.createChargeEWallet(ctx, pg.WithCustom(ewalletParams, params.Custom, customOptions...))
+	} else if params.PaymentType.IsPaylater() {
+		paylaterParams := m.mapper.mapToPaylaterParams(params)
+		responseBody, err = m.sendCoreRequest(ctx, http.MethodPost, chargeUri, pg.WithCustom(paylaterParams, params.Custom, customOptions...))
+	} else if params.PaymentType.IsCreditCard() {
+		cardParams := m.mapper.mapToCardParams(params)
+		responseBody, err = m.sendCoreRequest(ctx, http.MethodPost, chargeUri, pg.WithCustom(cardParams, params.Custom, customOptions...))
 	} else if params.PaymentType.IsVirtualAccount() {
 		bankParams := m.mapper.mapToBankTransferParams(params)
-		responseBody, err = m.createChargeBankTransfer(ctx, bankParams)
+		responseBody, err = m.createChargeBankTransfer(ctx, pg.WithCustom(bankParams, params.Custom, customOptions...))
 	} else {
 		return nil, pg.NewFieldError("PaymentType", fmt.Sprintf("payment type %s is not yet supported", params.PaymentType))
 	}
@@ -89,7 +103,7 @@ func (m *midtrans) CreateCharge(ctx context.Context, params pg.ChargeParams) (*p
 }
 
 // createChargeEWallet creates e-wallet charge
-func (m *midtrans) createChargeEWallet(ctx context.Context, params *EWallet) ([]byte, error) {
+func (m *midtrans) createChargeEWallet(ctx context.Context, params interface{}) ([]byte, error) {
 	baseURL := m.getBaseURL()
 
 	// Build request
@@ -121,14 +135,14 @@ func (m *midtrans) createChargeEWallet(ctx context.Context, params *EWallet) ([]
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return nil, apiError(resp.StatusCode, responseBody)
 	}
 
 	return responseBody, nil
 }
 
 // createChargeBankTransfer creates bank transfer charge
-func (m *midtrans) createChargeBankTransfer(ctx context.Context, params *BankTransferCreateParams) ([]byte, error) {
+func (m *midtrans) createChargeBankTransfer(ctx context.Context, params interface{}) ([]byte, error) {
 	baseURL := m.getBaseURL()
 
 	// Build request
@@ -160,7 +174,7 @@ func (m *midtrans) createChargeBankTransfer(ctx context.Context, params *BankTra
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return nil, apiError(resp.StatusCode, responseBody)
 	}
 
 	return responseBody, nil
@@ -191,7 +205,7 @@ func (m *midtrans) GetStatus(ctx context.Context, orderID string) (*pg.PaymentSt
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return nil, apiError(resp.StatusCode, responseBody)
 	}
 
 	var midtransResponse ChargeResponse
@@ -235,7 +249,7 @@ func (m *midtrans) Cancel(ctx context.Context, orderID string) error {
 	responseBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("cancel failed: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return apiError(resp.StatusCode, responseBody)
 	}
 
 	return nil
@@ -267,6 +281,54 @@ func (m *midtrans) GetToken(ctx context.Context) (*pg.TokenResponse, error) {
 	return nil, fmt.Errorf("GetToken API is not supported by %s. Midtrans uses Basic Auth with Server Key for authentication", ProviderName)
 }
 
+// Capabilities returns the payment types and operations supported by Midtrans
+// Midtrans has no retail or direct debit channels
+func (m *midtrans) Capabilities() pg.Capabilities {
+	types := []pg.PaymentType{
+		pg.PaymentTypeGoPay, pg.PaymentTypeShopeePay, pg.PaymentTypeOVO, pg.PaymentTypeDANA, pg.PaymentTypeLinkAja,
+		pg.PaymentTypeQRIS,
+		pg.PaymentTypeVABCA, pg.PaymentTypeVABNI, pg.PaymentTypeVABRI, pg.PaymentTypeVAMandiri, pg.PaymentTypeVAPermata, pg.PaymentTypeVACIMB,
+		pg.PaymentTypeCC,
+		pg.PaymentTypeAkulaku, pg.PaymentTypeKredivo,
+	}
+
+	return pg.Capabilities{
+		PaymentTypes: types,
+		Operations:   []pg.Operation{pg.OperationCancel, pg.OperationBatchDisbursement, pg.OperationDisbursementWebhook},
+		Limits:       pg.NewLimits(types, amountLimits, m.config.AmountLimits),
+		Currencies:   []string{"IDR"},
+		SandboxURL:   true,
+	}
+}
+
+// Do sends an authenticated request to the Core API and decodes the response into out
+// A status_code of 400 or above in the response body is returned as a *pg.ProviderError
+func (m *midtrans) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	responseBody, err := m.sendCoreRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	// Midtrans reports some errors with HTTP 200 and the real status in the body
+	var status struct {
+		StatusCode    string `json:"status_code"`
+		StatusMessage string `json:"status_message"`
+	}
+	if err := json.Unmarshal(responseBody, &status); err == nil {
+		if code, _ := strconv.Atoi(status.StatusCode); code >= http.StatusBadRequest {
+			return pg.WrapProviderError(ProviderName, status.StatusCode, status.StatusMessage, nil)
+		}
+	}
+
+	if out == nil || len(responseBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(responseBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
 // ParseWebhook parses webhook payload
 func (m *midtrans) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
 	if err := r.ParseForm(); err != nil {
@@ -277,17 +339,10 @@ func (m *midtrans) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
 	transactionStatus := r.FormValue("transaction_status")
 	fraudStatus := r.FormValue("fraud_status")
 
-	// Parse gross amount
-	var amount int64
-	if grossAmount := r.FormValue("gross_amount"); grossAmount != "" {
-		amount, _ = strconv.ParseInt(grossAmount, 10, 64)
-	}
+	amount := parseGrossAmount(r.FormValue("gross_amount"))
 
 	// Parse transaction time
-	var timestamp time.Time
-	if transactionTime := r.FormValue("transaction_time"); transactionTime != "" {
-		timestamp, _ = time.Parse(time.RFC3339, transactionTime)
-	}
+	timestamp := parseTransactionTime(r.FormValue("transaction_time"))
 
 	// Map status
 	var mappedStatus pg.Status
@@ -325,44 +380,74 @@ func (m *midtrans) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
 		EventType:     m.mapper.mapEventType(TransactionStatus(transactionStatus)),
 		Timestamp:     timestamp,
 		FraudStatus:    fraudStatus,
+		SubscriptionID: r.FormValue("subscription_id"),
+		Metadata: m.mapper.mapMetadata(CustomFields{
+			CustomField1: r.FormValue("custom_field1"),
+			CustomField2: r.FormValue("custom_field2"),
+			CustomField3: r.FormValue("custom_field3"),
+		}),
 		Raw:           raw,
 	}, nil
 }
 
 // validateChargeParams validates charge parameters
+// All problems are returned at once in a *pg.ValidationError
 func (m *midtrans) validateChargeParams(params *pg.ChargeParams) error {
-	// Validate order ID
-	if err := utils.ValidateOrderID(params.OrderID); err != nil {
-		return err
-	}
-
-	// Validate amount
-	if err := utils.MinAmount(params.Amount, params.PaymentType); err != nil {
-		return err
-	}
+	verr := pg.NewValidationError()
+	verr.Merge(params.Validate(m))
 
 	// Validate customer
 	if params.Customer.ID == "" {
-		return pg.NewRequiredFieldError("Customer.ID")
+		verr.Add(pg.NewRequiredFieldError("Customer.ID"))
 	}
-	if err := utils.ValidateEmail(params.Customer.Email, "Customer.Email"); err != nil {
-		return err
+	if params.Customer.Phone == "" {
+		verr.Add(pg.NewRequiredFieldError("Customer.Phone"))
 	}
-	if err := utils.ValidatePhone(params.Customer.Phone, "Customer.Phone"); err != nil {
-		return err
+
+	// Midtrans only offers Akulaku and Kredivo, the tenure is picked on their page
+	if params.PaymentType.IsPaylater() && params.Paylater != nil && params.Paylater.Tenure > 0 {
+		verr.Add(pg.NewFieldError("Paylater.Tenure", fmt.Sprintf("tenure is chosen by the customer on %s", ProviderName)))
 	}
 
-	// Validate payment type
-	if err := utils.ValidatePaymentType(params.PaymentType); err != nil {
-		return err
+	// Card charges need a token, installments a term enabled for the bank
+	if params.PaymentType.IsCreditCard() {
+		if params.CreditCard == nil || params.CreditCard.CardNumber == "" {
+			verr.Add(pg.NewRequiredFieldError("CreditCard.CardNumber"))
+		}
+		verr.Merge(params.CreditCard.ValidateInstallment(m.config.InstallmentTerms))
+	}
+
+	// Midtrans VAs are always closed amount and single use
+	if va := params.VirtualAccount; va != nil {
+		if va.OpenAmount || va.MinAmount > 0 || va.MaxAmount > 0 {
+			verr.Add(pg.NewFieldError("VirtualAccount.OpenAmount", fmt.Sprintf("open amount VAs are not supported by %s", ProviderName)))
+		}
+		if va.Reusable {
+			verr.Add(pg.NewFieldError("VirtualAccount.Reusable", fmt.Sprintf("reusable VAs are not supported by %s", ProviderName)))
+		}
 	}
 
 	// Validate items
 	if len(params.Items) == 0 {
-		return pg.NewRequiredFieldError("Items")
+		verr.Add(pg.NewRequiredFieldError("Items"))
 	}
 
-	return nil
+	// Metadata is kept in the custom fields as "key=value"
+	if len(params.Metadata) > maxCustomFields {
+		verr.Add(pg.NewFieldError("Metadata", fmt.Sprintf("%s keeps at most %d metadata entries", ProviderName, maxCustomFields)))
+	}
+	for k, v := range params.Metadata {
+		if len(k)+len(v)+1 > maxCustomFieldLength {
+			verr.Add(&pg.FieldError{
+				Field:   "Metadata." + k,
+				Code:    pg.CodeTooLong,
+				Message: fmt.Sprintf("key and value must be at most %d characters", maxCustomFieldLength-1),
+				Err:     pg.ErrInvalidParameter,
+			})
+		}
+	}
+
+	return verr.ToError()
 }
 
 // getTimeout returns the timeout duration
@@ -375,6 +460,9 @@ func getTimeout(cfg *pg.ProviderConfig) time.Duration {
 
 // getBaseURL returns the base URL based on environment
 func (m *midtrans) getBaseURL() string {
+	if m.config.BaseURL != "" {
+		return m.config.BaseURL
+	}
 	if m.config.SnapMode {
 		if m.config.Environment == "production" {
 			return snapProductionURL
@@ -387,3 +475,15 @@ func (m *midtrans) getBaseURL() string {
 	}
 	return sandboxURL
 }
+
+// apiError returns the error of a failed Core API response
+// Error bodies carry a status_message, they are returned as *pg.ProviderError
+func apiError(statusCode int, body []byte) error {
+	var errResp struct {
+		StatusMessage string `json:"status_message"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.StatusMessage != "" {
+		return pg.WrapProviderError(ProviderName, fmt.Sprint(statusCode), errResp.StatusMessage, nil)
+	}
+	return fmt.Errorf("API error: status=%d, body=%s", statusCode, string(body))
+}
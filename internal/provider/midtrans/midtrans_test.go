@@ -1,8 +1,12 @@
 package midtrans
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -111,6 +115,37 @@ func TestMidtrans_getBaseURL(t *testing.T) {
 	}
 }
 
+func TestMidtrans_BaseURLOverride(t *testing.T) {
+	provider := &midtrans{config: &pg.ProviderConfig{SnapMode: true, BaseURL: "http://localhost:8080"}}
+
+	if got := provider.getBaseURL(); got != "http://localhost:8080" {
+		t.Errorf("getBaseURL() = %v, want http://localhost:8080", got)
+	}
+	if got := provider.getCoreBaseURL(); got != "http://localhost:8080" {
+		t.Errorf("getCoreBaseURL() = %v, want http://localhost:8080", got)
+	}
+	if got := provider.getIrisBaseURL(); got != "http://localhost:8080/iris/api/v1" {
+		t.Errorf("getIrisBaseURL() = %v, want http://localhost:8080/iris/api/v1", got)
+	}
+}
+
+func TestMidtrans_Capabilities(t *testing.T) {
+	caps := (&midtrans{config: &pg.ProviderConfig{}}).Capabilities()
+
+	if caps.SupportsPaymentType(pg.PaymentTypeAlfamart) || caps.SupportsPaymentType(pg.PaymentTypeDDBRI) {
+		t.Error("retail and direct debit should not be supported")
+	}
+	if !caps.SupportsPaymentType(pg.PaymentTypeGoPay) || !caps.SupportsOperation(pg.OperationCancel) {
+		t.Error("GoPay and cancel should be supported")
+	}
+	if limit, ok := caps.Limit(pg.PaymentTypeQRIS); !ok || limit.Min != pg.MinAmountQRIS {
+		t.Errorf("QRIS limit = %+v, want min %d", limit, pg.MinAmountQRIS)
+	}
+	if !caps.SandboxURL {
+		t.Error("SandboxURL = false, want true")
+	}
+}
+
 func TestMidtrans_CreateCharge(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -377,8 +412,8 @@ func TestMapper_mapToEWalletParams(t *testing.T) {
 	if result.CustomerDetails == nil {
 		t.Error("CustomerDetails is nil")
 	} else {
-		if result.CustomerDetails.FirstName != "John Doe" {
-			t.Errorf("FirstName = %v, want 'John Doe'", result.CustomerDetails.FirstName)
+		if result.CustomerDetails.FirstName != "John" || result.CustomerDetails.LastName != "Doe" {
+			t.Errorf("name = %v %v, want 'John' 'Doe'", result.CustomerDetails.FirstName, result.CustomerDetails.LastName)
 		}
 	}
 
@@ -441,6 +476,214 @@ func TestMapper_mapToBankTransferParams(t *testing.T) {
 	}
 }
 
+func TestMapper_mapToBankTransferParams_VirtualAccount(t *testing.T) {
+	mapper := &Mapper{}
+
+	result := mapper.mapToBankTransferParams(pg.ChargeParams{
+		OrderID:        "ORDER-003",
+		Amount:         100000,
+		PaymentType:    pg.PaymentTypeVAPermata,
+		Customer:       pg.Customer{ID: "CUST-003", Name: "Jane Doe"},
+		VirtualAccount: &pg.VirtualAccountParams{VANumber: "1234567890", CustomerName: "Toko Jane"},
+	})
+
+	if result.BankTransfer.VANumber != "1234567890" {
+		t.Errorf("VANumber = %v, want 1234567890", result.BankTransfer.VANumber)
+	}
+	if result.BankTransfer.Permata == nil || result.BankTransfer.Permata.RecipientName != "TOKO JANE" {
+		t.Errorf("Permata = %+v, want recipient TOKO JANE", result.BankTransfer.Permata)
+	}
+}
+
+func TestMidtrans_validateChargeParams_VirtualAccount(t *testing.T) {
+	m := newTestProvider(t, nil, "")
+
+	tests := []struct {
+		name    string
+		va      *pg.VirtualAccountParams
+		wantErr bool
+	}{
+		{name: "custom number", va: &pg.VirtualAccountParams{VANumber: "1234567890"}},
+		{name: "open amount", va: &pg.VirtualAccountParams{OpenAmount: true}, wantErr: true},
+		{name: "reusable", va: &pg.VirtualAccountParams{Reusable: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := pg.ChargeParams{
+				OrderID:        "ORDER-003",
+				Amount:         100000,
+				PaymentType:    pg.PaymentTypeVABNI,
+				Customer:       pg.Customer{ID: "CUST-003", Email: "jane@example.com", Phone: "081234567890"},
+				Items:          []pg.Item{{ID: "ITEM-003", Name: "Product", Price: 100000, Quantity: 1}},
+				VirtualAccount: tt.va,
+			}
+
+			err := m.validateChargeParams(&params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateChargeParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMidtrans_validateChargeParams_Aggregated(t *testing.T) {
+	m := newTestProvider(t, nil, "")
+
+	params := pg.ChargeParams{
+		Amount:      5000,
+		PaymentType: pg.PaymentTypeAlfamart,
+		Customer:    pg.Customer{Email: "invalid"},
+	}
+
+	err := m.validateChargeParams(&params)
+	var verr *pg.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("validateChargeParams() error = %v, want *pg.ValidationError", err)
+	}
+
+	got := make(map[string]pg.ErrorCode)
+	for _, fe := range verr.Errors {
+		got[fe.Field] = fe.Code
+	}
+	want := map[string]pg.ErrorCode{
+		"OrderID":        pg.CodeRequired,
+		"PaymentType":    pg.CodeUnsupported,
+		"Amount":         pg.CodeMinAmount,
+		"Customer.Email": pg.CodeInvalidFormat,
+		"Customer.ID":    pg.CodeRequired,
+		"Customer.Phone": pg.CodeRequired,
+		"Items":          pg.CodeRequired,
+	}
+	for field, code := range want {
+		if got[field] != code {
+			t.Errorf("%s code = %q, want %q", field, got[field], code)
+		}
+	}
+}
+
+func TestMidtrans_CreateCharge_Paylater(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != chargeUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req PaylaterCreateParams
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.PaymentType != PaymentTypeAkulaku {
+			t.Errorf("PaymentType = %v, want %v", req.PaymentType, PaymentTypeAkulaku)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status_code":"201","transaction_id":"txn-456","order_id":"ORDER-004","gross_amount":"300000.00","payment_type":"akulaku","transaction_status":"pending","redirect_url":"https://mobilepay.akulaku.com/pay/123"}`))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	params := pg.ChargeParams{
+		OrderID:     "ORDER-004",
+		Amount:      300000,
+		PaymentType: pg.PaymentTypeAkulaku,
+		Customer:    pg.Customer{ID: "CUST-004", Name: "Jane Doe", Email: "jane@example.com", Phone: "081234567890"},
+		Items:       []pg.Item{{ID: "ITEM-004", Name: "Phone", Price: 300000, Quantity: 1}},
+	}
+
+	resp, err := m.CreateCharge(context.Background(), params)
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if resp.PaymentURL != "https://mobilepay.akulaku.com/pay/123" {
+		t.Errorf("PaymentURL = %v, want Akulaku URL", resp.PaymentURL)
+	}
+
+	params.Paylater = &pg.PaylaterParams{Tenure: 3}
+	if _, err := m.CreateCharge(context.Background(), params); err == nil {
+		t.Error("expected error for tenure, got nil")
+	}
+
+	params.Paylater = nil
+	params.PaymentType = pg.PaymentTypeAtome
+	if _, err := m.CreateCharge(context.Background(), params); err == nil {
+		t.Error("expected error for Atome, got nil")
+	}
+}
+
+func TestMapper_mapItemDetails_Adjustments(t *testing.T) {
+	mapper := &Mapper{}
+
+	details := mapper.mapItemDetails(pg.ChargeParams{
+		Amount: 105000,
+		Items:  []pg.Item{{ID: "ITEM-001", Name: "Product", Price: 50000, Quantity: 2}},
+		Adjustments: []pg.Adjustment{
+			{Type: pg.AdjustmentDiscount, Name: "Promo", Amount: 10000},
+			{Type: pg.AdjustmentShipping, Amount: 15000},
+		},
+	})
+
+	if len(details) != 3 {
+		t.Fatalf("got %d item details, want 3", len(details))
+	}
+	if details[1].Name != "Promo" || details[1].Price != -10000 || details[1].Quantity != 1 {
+		t.Errorf("discount = %+v, want Promo at -10000", details[1])
+	}
+	if details[2].ID != "shipping" || details[2].Name != "Shipping" {
+		t.Errorf("shipping = %+v, want shipping line", details[2])
+	}
+
+	var total int64
+	for _, d := range details {
+		total += d.Price * d.Quantity
+	}
+	if total != 105000 {
+		t.Errorf("item details total = %v, want gross amount 105000", total)
+	}
+}
+
+func TestMapper_mapCustomerDetails(t *testing.T) {
+	mapper := &Mapper{}
+
+	if mapper.mapCustomerDetails(pg.Customer{Email: "john@example.com"}) != nil {
+		t.Error("customer without name should not be mapped")
+	}
+
+	details := mapper.mapCustomerDetails(pg.Customer{
+		Name:  "Muhammad Rizky Pratama",
+		Email: "rizky@example.com",
+		Phone: "+6281234567890",
+		BillingAddress: &pg.Address{
+			Line1:      "Jl. Sudirman No. 1",
+			Line2:      "Tanah Abang",
+			City:       "Jakarta",
+			PostalCode: "10220",
+		},
+		ShippingAddress: &pg.Address{
+			Name:        "Siti Nurhaliza",
+			Line1:       "Jl. Orchard No. 2",
+			City:        "Singapore",
+			PostalCode:  "238801",
+			CountryCode: "SG",
+		},
+	})
+
+	if details.FirstName != "Muhammad Rizky" || details.LastName != "Pratama" {
+		t.Errorf("name = %v %v, want Muhammad Rizky Pratama", details.FirstName, details.LastName)
+	}
+	billing := details.BillingAddress
+	if billing == nil || billing.FirstName != "Muhammad Rizky" || billing.Phone != "+6281234567890" {
+		t.Fatalf("BillingAddress = %+v, want customer name and phone", billing)
+	}
+	if billing.Address != "Jl. Sudirman No. 1, Tanah Abang" || billing.CountryCode != "IDN" {
+		t.Errorf("BillingAddress = %+v, want joined street in IDN", billing)
+	}
+	shipping := details.ShippingAddress
+	if shipping == nil || shipping.FirstName != "Siti" || shipping.LastName != "Nurhaliza" || shipping.CountryCode != "SGP" {
+		t.Errorf("ShippingAddress = %+v, want Siti Nurhaliza in SGP", shipping)
+	}
+}
+
 func TestMapper_mapToChargeResponse(t *testing.T) {
 	mapper := &Mapper{}
 
@@ -477,6 +720,58 @@ func TestMapper_mapToChargeResponse(t *testing.T) {
 	if result.PaymentURL != resp.RedirectURL {
 		t.Errorf("PaymentURL = %v, want %v", result.PaymentURL, resp.RedirectURL)
 	}
+
+	// bank is the acquiring bank of a card charge, not a VA bank
+	card := mapper.mapToChargeResponse(&ChargeResponse{OrderID: "ORDER-002", PaymentType: PaymentTypeCard, Bank: BankBNI, TransactionStatus: Capture})
+	if card.VABank != "" || card.VANumber != "" {
+		t.Errorf("card VABank/VANumber = %q/%q, want empty", card.VABank, card.VANumber)
+	}
+
+	va := mapper.mapToChargeResponse(&ChargeResponse{OrderID: "ORDER-003", PaymentType: PaymentTypeBCA, VANumbers: []*BankTransfer{{Bank: BankBRI, VANumber: "8808123"}}})
+	if va.VABank != string(BankBRI) || va.VANumber != "8808123" {
+		t.Errorf("VA VABank/VANumber = %q/%q, want %s/8808123", va.VABank, va.VANumber, BankBRI)
+	}
+}
+
+func TestMapper_mapToNextActions(t *testing.T) {
+	mapper := &Mapper{}
+
+	resp := &ChargeResponse{
+		StatusCode:        "201",
+		TransactionID:     "txn-123",
+		OrderID:           "ORDER-001",
+		GrossAmount:       "50000.00",
+		PaymentType:       PaymentTypeGopay,
+		TransactionStatus: Pending,
+		Actions: []*Action{
+			{Name: "generate-qr-code", Method: "GET", URL: "https://api.midtrans.com/v2/gopay/txn-123/qr-code"},
+			{Name: "deeplink-redirect", Method: "GET", URL: "https://gopay.co.id/app/partner/web/checkout?id=123"},
+			{Name: "get-status", Method: "GET", URL: "https://api.midtrans.com/v2/txn-123/status"},
+			{Name: "cancel", Method: "POST", URL: "https://api.midtrans.com/v2/txn-123/cancel"},
+		},
+	}
+
+	result := mapper.mapToChargeResponse(resp)
+
+	want := []pg.ActionType{pg.ActionShowQR, pg.ActionDeeplink, pg.ActionCheckStatus}
+	if len(result.NextActions) != len(want) {
+		t.Fatalf("NextActions = %+v, want %v", result.NextActions, want)
+	}
+	for i, actionType := range want {
+		if result.NextActions[i].Type != actionType {
+			t.Errorf("NextActions[%d].Type = %v, want %v", i, result.NextActions[i].Type, actionType)
+		}
+	}
+
+	// Without a web checkout the deeplink is the payment URL, never the QR image
+	if result.PaymentURL != resp.Actions[1].URL {
+		t.Errorf("PaymentURL = %v, want the deeplink", result.PaymentURL)
+	}
+
+	qris := mapper.mapToChargeResponse(&ChargeResponse{StatusCode: "201", QRString: "00020101021226"})
+	if action, ok := qris.Action(pg.ActionShowQR); !ok || action.QRString != "00020101021226" {
+		t.Errorf("QR action = %+v, want the QR string", action)
+	}
 }
 
 func TestMapper_mapToPaymentStatus(t *testing.T) {
@@ -516,6 +811,55 @@ func TestMapper_mapToPaymentStatus(t *testing.T) {
 	}
 }
 
+func TestChargeResponse_UnmarshalJSON(t *testing.T) {
+	body := `{"status_code":"200","transaction_id":"txn-123","order_id":"ORDER-001","gross_amount":"150000.00","payment_type":"gopay","transaction_time":"2024-03-01 10:00:00","transaction_status":"settlement"}`
+
+	var resp ChargeResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if want := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC); !resp.TransactionTime.Equal(want) {
+		t.Errorf("TransactionTime = %v, want %v", resp.TransactionTime, want)
+	}
+	if resp.OrderID != "ORDER-001" || resp.TransactionStatus != Settlement {
+		t.Errorf("ChargeResponse = %+v", resp)
+	}
+
+	status := (&Mapper{}).mapToPaymentStatus("ORDER-001", &resp)
+	if status.Amount != 150000 || status.PaidAt == nil || !status.PaidAt.Equal(resp.TransactionTime) {
+		t.Errorf("mapToPaymentStatus() = %+v, want 150000 paid at the transaction time", status)
+	}
+}
+
+func TestMapper_chargePaymentType(t *testing.T) {
+	mapper := &Mapper{}
+
+	tests := []struct {
+		name string
+		resp *ChargeResponse
+		want pg.PaymentType
+	}{
+		{"BCA VA", &ChargeResponse{PaymentType: PaymentTypeBCA, VANumbers: []*BankTransfer{{Bank: BankBCA, VANumber: "123"}}}, pg.PaymentTypeVABCA},
+		{"BNI VA", &ChargeResponse{PaymentType: PaymentTypeBCA, VANumbers: []*BankTransfer{{Bank: BankBNI, VANumber: "123"}}}, pg.PaymentTypeVABNI},
+		{"BRI VA", &ChargeResponse{PaymentType: PaymentTypeBCA, VANumbers: []*BankTransfer{{Bank: BankBRI, VANumber: "123"}}}, pg.PaymentTypeVABRI},
+		{"CIMB VA", &ChargeResponse{PaymentType: PaymentTypeBCA, VANumbers: []*BankTransfer{{Bank: BankCIMB, VANumber: "123"}}}, pg.PaymentTypeVACIMB},
+		{"Permata VA", &ChargeResponse{PaymentType: PaymentTypeBCA, PermataVANumber: "123"}, pg.PaymentTypeVAPermata},
+		{"Mandiri bill", &ChargeResponse{PaymentType: PaymentTypeMandiri, BillKey: "123"}, pg.PaymentTypeVAMandiri},
+		{"GoPay", &ChargeResponse{PaymentType: PaymentTypeGopay}, pg.PaymentTypeGoPay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mapper.chargePaymentType(tt.resp); got != tt.want {
+				t.Errorf("chargePaymentType() = %v, want %v", got, tt.want)
+			}
+			if got := mapper.mapToPaymentStatus("ORDER-001", tt.resp).PaymentType; got != tt.want {
+				t.Errorf("mapToPaymentStatus() PaymentType = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMapper_unifiedPaymentType(t *testing.T) {
 	mapper := &Mapper{}
 
@@ -624,4 +968,111 @@ func TestMidtrans_ParseWebhook(t *testing.T) {
 	if event.EventType != pg.EventPaymentCompleted {
 		t.Errorf("EventType = %v, want %v", event.EventType, pg.EventPaymentCompleted)
 	}
+
+	// Midtrans sends gross_amount with decimals and transaction_time in WIB
+	req = httptest.NewRequest("POST", "/webhook", nil)
+	req.Form = map[string][]string{
+		"order_id":           {"ORDER-002"},
+		"transaction_status": {"settlement"},
+		"gross_amount":       {"150000.00"},
+		"transaction_time":   {"2024-03-01 10:00:00"},
+	}
+
+	event, err = provider.ParseWebhook(req)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if event.Amount != 150000 {
+		t.Errorf("Amount = %v, want 150000", event.Amount)
+	}
+	if want := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC); !event.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", event.Timestamp, want)
+	}
+}
+
+func TestMapper_mapCustomFields(t *testing.T) {
+	mapper := &Mapper{}
+
+	fields := mapper.mapCustomFields(map[string]string{"store": "JKT-01", "cart_id": "CART-9"})
+	if fields.CustomField1 != "cart_id=CART-9" || fields.CustomField2 != "store=JKT-01" || fields.CustomField3 != "" {
+		t.Errorf("mapCustomFields() = %+v, want entries in key order", fields)
+	}
+
+	metadata := mapper.mapMetadata(fields)
+	if len(metadata) != 2 || metadata["cart_id"] != "CART-9" || metadata["store"] != "JKT-01" {
+		t.Errorf("mapMetadata() = %v, want the original metadata", metadata)
+	}
+
+	// Custom fields set outside the SDK keep their field name
+	metadata = mapper.mapMetadata(CustomFields{CustomField3: "legacy"})
+	if metadata["custom_field3"] != "legacy" {
+		t.Errorf("mapMetadata() = %v, want custom_field3", metadata)
+	}
+
+	if mapper.mapMetadata(CustomFields{}) != nil {
+		t.Error("mapMetadata() of empty custom fields should be nil")
+	}
+}
+
+func TestMidtrans_validateChargeParams_Metadata(t *testing.T) {
+	m := newTestProvider(t, nil, "")
+
+	params := pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: pg.PaymentTypeGoPay,
+		Customer:    pg.Customer{ID: "CUST-001", Name: "John Doe", Email: "john@example.com", Phone: "+6281234567890"},
+		Items:       []pg.Item{{ID: "ITEM-001", Name: "Product", Price: 50000, Quantity: 1}},
+		Metadata:    map[string]string{"a": "1", "b": "2", "c": "3", "d": strings.Repeat("x", 300)},
+	}
+
+	err := m.validateChargeParams(&params)
+	var verr *pg.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("validateChargeParams() error = %v, want *pg.ValidationError", err)
+	}
+
+	got := make(map[string]pg.ErrorCode)
+	for _, fe := range verr.Errors {
+		got[fe.Field] = fe.Code
+	}
+	if got["Metadata"] != pg.CodeInvalid || got["Metadata.d"] != pg.CodeTooLong || len(got) != 2 {
+		t.Errorf("errors = %v, want too many entries and a too long entry", got)
+	}
+}
+
+func TestMidtrans_Do(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "server-key" {
+			t.Errorf("Authorization = %v, want server key basic auth", r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/point_inquiry/tok-123":
+			w.Write([]byte(`{"status_code": "200", "point_balance": 1500}`))
+		default:
+			// Midtrans reports errors with HTTP 200
+			w.Write([]byte(`{"status_code": "404", "status_message": "Transaction doesn't exist."}`))
+		}
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	var out struct {
+		PointBalance int64 `json:"point_balance"`
+	}
+	if err := m.Do(context.Background(), http.MethodGet, "/v1/point_inquiry/tok-123", nil, &out); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if out.PointBalance != 1500 {
+		t.Errorf("PointBalance = %v, want 1500", out.PointBalance)
+	}
+
+	err := m.Do(context.Background(), http.MethodGet, "/v2/unknown/status", nil, nil)
+	var providerErr *pg.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Code != "404" {
+		t.Errorf("Do() error = %v, want provider error 404", err)
+	}
 }
@@ -0,0 +1,179 @@
+package midtrans
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+)
+
+const (
+	// Subscription API endpoints
+	subscriptionsUri       = "/v1/subscriptions"
+	subscriptionUri        = "/v1/subscriptions/%s"
+	subscriptionDisableUri = "/v1/subscriptions/%s/disable"
+	subscriptionEnableUri  = "/v1/subscriptions/%s/enable"
+	subscriptionCancelUri  = "/v1/subscriptions/%s/cancel"
+)
+
+// CreateSubscription creates a card or GoPay subscription
+// For GoPay, the GoPay account ID is read from Custom["gopay_account_id"]
+func (m *midtrans) CreateSubscription(ctx context.Context, params pg.SubscriptionParams) (*pg.Subscription, error) {
+	req := m.mapper.mapToSubscriptionRequest(params)
+
+	responseBody, err := m.sendCoreRequest(ctx, http.MethodPost, subscriptionsUri, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := m.parseSubscription(responseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	// The reference is kept in metadata, but fall back in case it is not echoed
+	if sub.ReferenceID == "" {
+		sub.ReferenceID = params.ReferenceID
+	}
+
+	return sub, nil
+}
+
+// GetSubscription retrieves a subscription by its Midtrans ID
+func (m *midtrans) GetSubscription(ctx context.Context, id string) (*pg.Subscription, error) {
+	responseBody, err := m.sendCoreRequest(ctx, http.MethodGet, fmt.Sprintf(subscriptionUri, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.parseSubscription(responseBody)
+}
+
+// UpdateSubscription updates the name, amount, token or schedule of a subscription
+func (m *midtrans) UpdateSubscription(ctx context.Context, id string, params pg.SubscriptionUpdateParams) (*pg.Subscription, error) {
+	req := m.mapper.mapToSubscriptionUpdateRequest(params)
+
+	if _, err := m.sendCoreRequest(ctx, http.MethodPatch, fmt.Sprintf(subscriptionUri, id), req); err != nil {
+		return nil, err
+	}
+
+	return m.GetSubscription(ctx, id)
+}
+
+// PauseSubscription disables a subscription
+func (m *midtrans) PauseSubscription(ctx context.Context, id string) (*pg.Subscription, error) {
+	return m.changeSubscription(ctx, subscriptionDisableUri, id)
+}
+
+// ResumeSubscription enables a disabled subscription
+func (m *midtrans) ResumeSubscription(ctx context.Context, id string) (*pg.Subscription, error) {
+	return m.changeSubscription(ctx, subscriptionEnableUri, id)
+}
+
+// CancelSubscription cancels a subscription
+func (m *midtrans) CancelSubscription(ctx context.Context, id string) (*pg.Subscription, error) {
+	return m.changeSubscription(ctx, subscriptionCancelUri, id)
+}
+
+// ListSubscriptionPayments returns the status of every transaction charged by a subscription
+func (m *midtrans) ListSubscriptionPayments(ctx context.Context, id string) ([]*pg.PaymentStatus, error) {
+	responseBody, err := m.sendCoreRequest(ctx, http.MethodGet, fmt.Sprintf(subscriptionUri, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp SubscriptionResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	payments := make([]*pg.PaymentStatus, 0, len(resp.TransactionIDs))
+	for _, transactionID := range resp.TransactionIDs {
+		status, err := m.GetStatus(ctx, transactionID)
+		if err != nil {
+			return nil, err
+		}
+		status.SubscriptionID = id
+		payments = append(payments, status)
+	}
+
+	return payments, nil
+}
+
+// changeSubscription calls a subscription action endpoint and returns the updated subscription
+// The action endpoints only answer with a status message
+func (m *midtrans) changeSubscription(ctx context.Context, uri, id string) (*pg.Subscription, error) {
+	if _, err := m.sendCoreRequest(ctx, http.MethodPost, fmt.Sprintf(uri, id), nil); err != nil {
+		return nil, err
+	}
+
+	return m.GetSubscription(ctx, id)
+}
+
+// parseSubscription parses a Midtrans subscription response
+func (m *midtrans) parseSubscription(responseBody []byte) (*pg.Subscription, error) {
+	var resp SubscriptionResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return m.mapper.mapToSubscription(&resp, raw), nil
+}
+
+// sendCoreRequest sends an authenticated request to the Core API host, also in Snap mode
+// It is used by the Subscription and GoPay Tokenization APIs
+func (m *midtrans) sendCoreRequest(ctx context.Context, method, uri string, payload interface{}) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		bodyBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, m.getCoreBaseURL()+uri, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(headerAuthorization, utils.SetBasicAuthorization(m.config.ServerKey, ""))
+
+	resp, err := m.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, apiError(resp.StatusCode, responseBody)
+	}
+
+	return responseBody, nil
+}
+
+// getCoreBaseURL returns the Core API base URL based on environment
+func (m *midtrans) getCoreBaseURL() string {
+	if m.config.BaseURL != "" {
+		return m.config.BaseURL
+	}
+	if m.config.Environment == "production" {
+		return productionURL
+	}
+	return sandboxURL
+}
@@ -0,0 +1,207 @@
+package midtrans
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+const testSubscriptionResponse = `{
+	"id": "sub-123",
+	"name": "SUB-001",
+	"amount": "50000",
+	"currency": "IDR",
+	"created_at": "2024-01-01 10:00:00 +0700",
+	"schedule": {
+		"interval": 1,
+		"interval_unit": "month",
+		"max_interval": 12,
+		"start_time": "2024-01-01 10:00:00 +0700",
+		"next_execution_at": "2024-02-01 10:00:00 +0700"
+	},
+	"status": "%s",
+	"token": "481111-1114-token",
+	"payment_type": "credit_card",
+	"transaction_ids": ["txn-1"],
+	"metadata": {"reference_id": "SUB-001"}
+}`
+
+func TestMidtrans_CreateSubscription(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != subscriptionsUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req SubscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Amount != "50000" {
+			t.Errorf("Amount = %v, want 50000", req.Amount)
+		}
+		if req.Schedule.IntervalUnit != "month" || req.Schedule.Interval != 12 {
+			t.Errorf("Schedule = %+v, want 12 month", req.Schedule)
+		}
+		if req.Schedule.StartTime != "2024-01-01 10:00:00 +0700" {
+			t.Errorf("StartTime = %v", req.Schedule.StartTime)
+		}
+		if req.Token != "481111-1114-token" {
+			t.Errorf("Token = %v, want 481111-1114-token", req.Token)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(fmtSubscription("active")))
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	sub, err := m.CreateSubscription(context.Background(), pg.SubscriptionParams{
+		ReferenceID:  "SUB-001",
+		Customer:     pg.Customer{ID: "CUST-001", Name: "John Doe", Email: "john@example.com"},
+		Amount:       50000,
+		PaymentToken: "481111-1114-token",
+		Interval:     pg.IntervalYear,
+		StartAt:      time.Date(2024, 1, 1, 10, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+
+	if sub.ID != "sub-123" {
+		t.Errorf("ID = %v, want sub-123", sub.ID)
+	}
+	if sub.ReferenceID != "SUB-001" {
+		t.Errorf("ReferenceID = %v, want SUB-001", sub.ReferenceID)
+	}
+	if sub.Status != pg.SubscriptionActive {
+		t.Errorf("Status = %v, want %v", sub.Status, pg.SubscriptionActive)
+	}
+	if sub.NextChargeAt == nil {
+		t.Error("NextChargeAt should be set")
+	}
+}
+
+func TestMidtrans_PauseSubscription(t *testing.T) {
+	var actionCalled bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/subscriptions/sub-123/disable":
+			actionCalled = true
+			w.Write([]byte(`{"status_message": "Subscription is updated."}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/subscriptions/sub-123":
+			w.Write([]byte(fmtSubscription("inactive")))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	sub, err := m.PauseSubscription(context.Background(), "sub-123")
+	if err != nil {
+		t.Fatalf("PauseSubscription() error = %v", err)
+	}
+
+	if !actionCalled {
+		t.Error("disable endpoint was not called")
+	}
+	if sub.Status != pg.SubscriptionPaused {
+		t.Errorf("Status = %v, want %v", sub.Status, pg.SubscriptionPaused)
+	}
+}
+
+func TestMidtrans_ListSubscriptionPayments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/subscriptions/sub-123":
+			w.Write([]byte(fmtSubscription("active")))
+		case "/v2/txn-1/status":
+			w.Write([]byte(`{"status_code":"200","transaction_id":"txn-1","order_id":"order-1","gross_amount":"50000.00","transaction_status":"settlement"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	m := newTestProvider(t, server, "")
+
+	payments, err := m.ListSubscriptionPayments(context.Background(), "sub-123")
+	if err != nil {
+		t.Fatalf("ListSubscriptionPayments() error = %v", err)
+	}
+
+	if len(payments) != 1 {
+		t.Fatalf("len(payments) = %v, want 1", len(payments))
+	}
+	if payments[0].SubscriptionID != "sub-123" {
+		t.Errorf("SubscriptionID = %v, want sub-123", payments[0].SubscriptionID)
+	}
+	if payments[0].Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", payments[0].Status, pg.StatusSuccess)
+	}
+}
+
+func TestMidtrans_ParseWebhook_Subscription(t *testing.T) {
+	m := newTestProvider(t, nil, "")
+
+	req := httptest.NewRequest("POST", "/webhook", nil)
+	req.Form = map[string][]string{
+		"order_id":           {"SUB-001-1"},
+		"transaction_status": {"capture"},
+		"gross_amount":       {"50000.00"},
+		"subscription_id":    {"sub-123"},
+	}
+
+	event, err := m.ParseWebhook(req)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if event.SubscriptionID != "sub-123" {
+		t.Errorf("SubscriptionID = %v, want sub-123", event.SubscriptionID)
+	}
+	if event.EventType != pg.EventPaymentCompleted {
+		t.Errorf("EventType = %v, want %v", event.EventType, pg.EventPaymentCompleted)
+	}
+}
+
+func TestMapper_mapToSubscriptionSchedule(t *testing.T) {
+	tests := []struct {
+		interval     pg.Interval
+		count        int
+		wantUnit     string
+		wantInterval int
+	}{
+		{pg.IntervalDay, 0, "day", 1},
+		{pg.IntervalWeek, 2, "week", 2},
+		{pg.IntervalMonth, 3, "month", 3},
+		{pg.IntervalYear, 1, "month", 12},
+	}
+
+	mapper := &Mapper{}
+	for _, tt := range tests {
+		t.Run(string(tt.interval), func(t *testing.T) {
+			schedule := mapper.mapToSubscriptionSchedule(tt.interval, tt.count)
+			if schedule.IntervalUnit != tt.wantUnit || schedule.Interval != tt.wantInterval {
+				t.Errorf("schedule = %d %s, want %d %s", schedule.Interval, schedule.IntervalUnit, tt.wantInterval, tt.wantUnit)
+			}
+		})
+	}
+}
+
+// fmtSubscription returns the test subscription response with the given status
+func fmtSubscription(status string) string {
+	return fmt.Sprintf(testSubscriptionResponse, status)
+}
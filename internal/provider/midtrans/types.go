@@ -1,6 +1,9 @@
 package midtrans
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // TransactionStatus status of payment transaction
 type TransactionStatus string
@@ -89,6 +92,12 @@ const (
 
 	// PaymentTypeCard is payment type for Credit Card or Debit Card from Midtrans Core API
 	PaymentTypeCard PaymentType = "credit_card"
+
+	// PaymentTypeAkulaku is payment type Akulaku PayLater from Midtrans Core API
+	PaymentTypeAkulaku PaymentType = "akulaku"
+
+	// PaymentTypeKredivo is payment type Kredivo from Midtrans Core API
+	PaymentTypeKredivo PaymentType = "kredivo"
 )
 
 type BankCode string
@@ -127,10 +136,23 @@ type TransactionDetail struct {
 
 // CustomerDetail details of customer
 type CustomerDetail struct {
-	FirstName string `json:"first_name,omitempty"`
-	LastName  string `json:"last_name,omitempty"`
-	Email     string `json:"email,omitempty"`
-	Phone     string `json:"phone,omitempty"`
+	FirstName       string   `json:"first_name,omitempty"`
+	LastName        string   `json:"last_name,omitempty"`
+	Email           string   `json:"email,omitempty"`
+	Phone           string   `json:"phone,omitempty"`
+	BillingAddress  *Address `json:"billing_address,omitempty"`
+	ShippingAddress *Address `json:"shipping_address,omitempty"`
+}
+
+// Address details a billing or shipping address of Customer
+type Address struct {
+	FirstName   string `json:"first_name,omitempty"`
+	LastName    string `json:"last_name,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	Address     string `json:"address,omitempty"`
+	City        string `json:"city,omitempty"`
+	PostalCode  string `json:"postal_code,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
 }
 
 // ItemDetail details items purchased by Customer
@@ -144,8 +166,9 @@ type ItemDetail struct {
 
 // EWalletDetail details e-wallet payment
 type EWalletDetail struct {
-	CallbackURL string `json:"callback_url,omitempty"`
-	AccountID   string `json:"account_id,omitempty"`
+	CallbackURL        string `json:"callback_url,omitempty"`
+	AccountID          string `json:"account_id,omitempty"`
+	PaymentOptionToken string `json:"payment_option_token,omitempty"`
 }
 
 // EWallet define payload for e-wallet charge
@@ -159,12 +182,19 @@ type EWallet struct {
 	OVO                *EWalletDetail    `json:"ovo,omitempty"`
 	DANA               *EWalletDetail    `json:"dana,omitempty"`
 	LinkAja            *EWalletDetail    `json:"linkaja,omitempty"`
+	CustomFields
 }
 
 // BankTransfer charge details using bank transfer
 type BankTransfer struct {
-	Bank     BankCode `json:"bank"`
-	VANumber string   `json:"va_number,omitempty"`
+	Bank     BankCode       `json:"bank"`
+	VANumber string         `json:"va_number,omitempty"`
+	Permata  *PermataDetail `json:"permata,omitempty"`
+}
+
+// PermataDetail holds the Permata VA display options
+type PermataDetail struct {
+	RecipientName string `json:"recipient_name,omitempty"`
 }
 
 // EChannel charge details using Mandiri Bill Payment
@@ -181,6 +211,47 @@ type BankTransferCreateParams struct {
 	CustomerDetails   *CustomerDetail   `json:"customer_details,omitempty"`
 	BankTransfer      *BankTransfer      `json:"bank_transfer,omitempty"`
 	EChannel          *EChannel          `json:"echannel,omitempty"`
+	CustomFields
+}
+
+// PaylaterCreateParams charge details using Akulaku or Kredivo
+// The customer is redirected to the paylater page to pick a tenure
+type PaylaterCreateParams struct {
+	PaymentType        PaymentType        `json:"payment_type"`
+	TransactionDetails *TransactionDetail `json:"transaction_details"`
+	ItemDetails        []*ItemDetail      `json:"item_details"`
+	CustomerDetails    *CustomerDetail    `json:"customer_details,omitempty"`
+	CustomFields
+}
+
+// CreditCardDetail card charge options of the Core API
+type CreditCardDetail struct {
+	TokenID         string `json:"token_id"`
+	Bank            string `json:"bank,omitempty"`
+	InstallmentTerm int    `json:"installment_term,omitempty"`
+	Authentication  bool   `json:"authentication,omitempty"`
+	SaveTokenID     bool   `json:"save_token_id,omitempty"`
+}
+
+// CardCreateParams for Core API card charges
+type CardCreateParams struct {
+	PaymentType        PaymentType        `json:"payment_type"`
+	TransactionDetails *TransactionDetail `json:"transaction_details"`
+	CreditCard         *CreditCardDetail  `json:"credit_card"`
+	ItemDetails        []*ItemDetail      `json:"item_details"`
+	CustomerDetails    *CustomerDetail    `json:"customer_details,omitempty"`
+	CustomFields
+}
+
+// BINResponse from the BIN API
+type BINResponse struct {
+	Data struct {
+		BIN      string `json:"bin"`
+		BINType  string `json:"bin_type"`
+		Brand    string `json:"brand"`
+		Bank     string `json:"bank"`
+		BankCode string `json:"bank_code"`
+	} `json:"data"`
 }
 
 // Action to make payments redirect
@@ -190,6 +261,14 @@ type Action struct {
 	URL    string `json:"url"`
 }
 
+// CustomFields are the free text fields Midtrans keeps with a transaction
+// They carry pg.ChargeParams.Metadata as "key=value", see Mapper.mapCustomFields
+type CustomFields struct {
+	CustomField1 string `json:"custom_field1,omitempty"`
+	CustomField2 string `json:"custom_field2,omitempty"`
+	CustomField3 string `json:"custom_field3,omitempty"`
+}
+
 // ChargeResponse charge response from Midtrans
 type ChargeResponse struct {
 	StatusCode           string           `json:"status_code"`
@@ -203,9 +282,234 @@ type ChargeResponse struct {
 	FraudStatus          string           `json:"fraud_status"`
 	RedirectURL          string           `json:"redirect_url"`
 	Actions              []*Action         `json:"actions"`
+	QRString             string           `json:"qr_string,omitempty"`
 	BillKey              string           `json:"bill_key"`
 	BillerCode           string           `json:"biller_code"`
 	PermataVANumber      string           `json:"permata_va_number"`
 	VANumbers            []*BankTransfer  `json:"va_numbers"`
 	Bank                 BankCode         `json:"bank"`
+	CustomFields
+}
+
+// UnmarshalJSON decodes transaction_time, which Midtrans sends as "2006-01-02 15:04:05" in WIB
+func (r *ChargeResponse) UnmarshalJSON(data []byte) error {
+	type alias ChargeResponse
+	aux := struct {
+		*alias
+		TransactionTime string `json:"transaction_time"`
+	}{alias: (*alias)(r)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.TransactionTime = parseTransactionTime(aux.TransactionTime)
+	return nil
+}
+
+// PayoutStatus status of an Iris payout
+type PayoutStatus string
+
+const (
+	// PayoutQueued means the payout is waiting for approval
+	PayoutQueued PayoutStatus = "queued"
+	// PayoutApproved means the payout is approved
+	PayoutApproved PayoutStatus = "approved"
+	// PayoutRejected means the payout is rejected by the approver
+	PayoutRejected PayoutStatus = "rejected"
+	// PayoutProcessed means the payout is sent to the bank
+	PayoutProcessed PayoutStatus = "processed"
+	// PayoutCompleted means the money has been received by the beneficiary
+	PayoutCompleted PayoutStatus = "completed"
+	// PayoutFailed means the payout failed
+	PayoutFailed PayoutStatus = "failed"
+)
+
+// IrisPayout is a payout of an Iris Create Payouts request
+type IrisPayout struct {
+	BeneficiaryName    string `json:"beneficiary_name"`
+	BeneficiaryAccount string `json:"beneficiary_account"`
+	BeneficiaryBank    string `json:"beneficiary_bank"`
+	BeneficiaryEmail   string `json:"beneficiary_email,omitempty"`
+	Amount             string `json:"amount"`
+	Notes              string `json:"notes"`
+}
+
+// IrisCreatePayoutsRequest for Iris Create Payouts
+type IrisCreatePayoutsRequest struct {
+	Payouts []*IrisPayout `json:"payouts"`
+}
+
+// IrisCreatedPayout is a payout of an Iris Create Payouts response
+type IrisCreatedPayout struct {
+	Status      PayoutStatus `json:"status"`
+	ReferenceNo string       `json:"reference_no"`
+}
+
+// IrisCreatePayoutsResponse from Iris Create Payouts
+type IrisCreatePayoutsResponse struct {
+	Payouts []*IrisCreatedPayout `json:"payouts"`
+}
+
+// IrisPayoutDetails from Iris Get Payout Details
+type IrisPayoutDetails struct {
+	Amount             string       `json:"amount"`
+	BeneficiaryName    string       `json:"beneficiary_name"`
+	BeneficiaryAccount string       `json:"beneficiary_account"`
+	Bank               string       `json:"bank"`
+	ReferenceNo        string       `json:"reference_no"`
+	Notes              string       `json:"notes"`
+	BeneficiaryEmail   string       `json:"beneficiary_email,omitempty"`
+	Status             PayoutStatus `json:"status"`
+	CreatedBy          string       `json:"created_by,omitempty"`
+	CreatedAt          *time.Time   `json:"created_at,omitempty"`
+	UpdatedAt          *time.Time   `json:"updated_at,omitempty"`
+	ErrorCode          string       `json:"error_code,omitempty"`
+	ErrorMessage       string       `json:"error_message,omitempty"`
+}
+
+// IrisNotification is the payout status notification sent by Iris
+type IrisNotification struct {
+	ReferenceNo  string       `json:"reference_no"`
+	Amount       string       `json:"amount"`
+	Status       PayoutStatus `json:"status"`
+	UpdatedAt    *time.Time   `json:"updated_at,omitempty"`
+	ErrorCode    string       `json:"error_code,omitempty"`
+	ErrorMessage string       `json:"error_message,omitempty"`
+}
+
+// irisErrorResponse is the body of a rejected Iris request
+type irisErrorResponse struct {
+	ErrorMessage string   `json:"error_message"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+// IrisAccountValidationResponse from Iris Validate Bank Account
+type IrisAccountValidationResponse struct {
+	AccountName string `json:"account_name"`
+	AccountNo   string `json:"account_no"`
+	BankName    string `json:"bank_name"`
+}
+
+// SubscriptionStatus status of a Midtrans subscription
+type SubscriptionStatus string
+
+const (
+	// SubscriptionActive means the subscription is charged every interval
+	SubscriptionActive SubscriptionStatus = "active"
+	// SubscriptionInactive means the subscription is disabled
+	SubscriptionInactive SubscriptionStatus = "inactive"
+	// SubscriptionCancelled means the subscription is cancelled
+	SubscriptionCancelled SubscriptionStatus = "canceled"
+)
+
+// SubscriptionSchedule is the schedule of a Midtrans subscription
+type SubscriptionSchedule struct {
+	Interval            int    `json:"interval"`
+	IntervalUnit        string `json:"interval_unit"`
+	MaxInterval         int    `json:"max_interval,omitempty"`
+	StartTime           string `json:"start_time,omitempty"`
+	CurrentInterval     int    `json:"current_interval,omitempty"`
+	PreviousExecutionAt string `json:"previous_execution_at,omitempty"`
+	NextExecutionAt     string `json:"next_execution_at,omitempty"`
+}
+
+// SubscriptionCustomer is the customer of a Midtrans subscription
+type SubscriptionCustomer struct {
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+}
+
+// SubscriptionGoPay holds the GoPay account of a GoPay subscription
+type SubscriptionGoPay struct {
+	AccountID string `json:"account_id"`
+}
+
+// SubscriptionRequest for Midtrans Create and Update Subscription
+type SubscriptionRequest struct {
+	Name            string                 `json:"name,omitempty"`
+	Amount          string                 `json:"amount,omitempty"`
+	Currency        string                 `json:"currency,omitempty"`
+	PaymentType     string                 `json:"payment_type,omitempty"`
+	Token           string                 `json:"token,omitempty"`
+	Schedule        *SubscriptionSchedule  `json:"schedule,omitempty"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	CustomerDetails *SubscriptionCustomer  `json:"customer_details,omitempty"`
+	GoPay           *SubscriptionGoPay     `json:"gopay,omitempty"`
+}
+
+// SubscriptionResponse from Midtrans Subscription API
+type SubscriptionResponse struct {
+	ID              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	Amount          string                 `json:"amount"`
+	Currency        string                 `json:"currency"`
+	CreatedAt       string                 `json:"created_at"`
+	Schedule        SubscriptionSchedule   `json:"schedule"`
+	Status          SubscriptionStatus     `json:"status"`
+	Token           string                 `json:"token"`
+	PaymentType     string                 `json:"payment_type"`
+	TransactionIDs  []string               `json:"transaction_ids,omitempty"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	CustomerDetails *SubscriptionCustomer  `json:"customer_details,omitempty"`
+	StatusMessage   string                 `json:"status_message,omitempty"`
+}
+
+// AccountStatus status of a GoPay linked account
+type AccountStatus string
+
+const (
+	// AccountPending means the customer has not finished the linking
+	AccountPending AccountStatus = "PENDING"
+	// AccountEnabled means the account is linked
+	AccountEnabled AccountStatus = "ENABLED"
+	// AccountDisabled means the account is unlinked
+	AccountDisabled AccountStatus = "DISABLED"
+	// AccountExpired means the linking expired
+	AccountExpired AccountStatus = "EXPIRED"
+)
+
+// GopayPartner holds the GoPay account to link
+type GopayPartner struct {
+	PhoneNumber string `json:"phone_number"`
+	CountryCode string `json:"country_code"`
+	RedirectURL string `json:"redirect_url,omitempty"`
+}
+
+// PayAccountRequest for Midtrans Create Pay Account
+type PayAccountRequest struct {
+	PaymentType  PaymentType   `json:"payment_type"`
+	GopayPartner *GopayPartner `json:"gopay_partner"`
+}
+
+// PaymentOption is a payment option of a linked GoPay account
+type PaymentOption struct {
+	Name    string      `json:"name"`
+	Active  bool        `json:"active"`
+	Balance *AmountInfo `json:"balance,omitempty"`
+	Token   string      `json:"token"`
+}
+
+// AmountInfo is an amount with its currency
+type AmountInfo struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// PayAccountMetadata is the metadata of a linked GoPay account
+type PayAccountMetadata struct {
+	PaymentOptions []*PaymentOption `json:"payment_options,omitempty"`
+}
+
+// PayAccountResponse from Midtrans Pay Account API
+type PayAccountResponse struct {
+	StatusCode    string              `json:"status_code"`
+	StatusMessage string              `json:"status_message,omitempty"`
+	PaymentType   PaymentType         `json:"payment_type"`
+	AccountID     string              `json:"account_id"`
+	AccountStatus AccountStatus       `json:"account_status"`
+	Actions       []*Action           `json:"actions,omitempty"`
+	Metadata      *PayAccountMetadata `json:"metadata,omitempty"`
 }
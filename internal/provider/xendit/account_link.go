@@ -0,0 +1,80 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// Payment Method API endpoints
+	paymentMethodsUri      = "/v2/payment_methods"
+	paymentMethodUri       = "/v2/payment_methods/%s"
+	paymentMethodExpireUri = "/v2/payment_methods/%s/expire"
+)
+
+// LinkAccount links a customer OVO, DANA or ShopeePay account as a reusable payment method,
+// or starts binding a direct debit bank account
+// Customer.ID must be a Xendit customer ID
+func (x *xendit) LinkAccount(ctx context.Context, params pg.LinkParams) (*pg.LinkedAccount, error) {
+	if params.PaymentType.IsDirectDebit() {
+		return x.linkBankAccount(ctx, params)
+	}
+
+	switch params.PaymentType {
+	case pg.PaymentTypeOVO, pg.PaymentTypeDANA, pg.PaymentTypeShopeePay:
+	default:
+		return nil, pg.NewFieldError("PaymentType", fmt.Sprintf("account linking is not supported for %s", params.PaymentType))
+	}
+	if params.Customer.ID == "" {
+		return nil, pg.NewRequiredFieldError("Customer.ID")
+	}
+
+	req := x.mapper.mapToPaymentMethodRequest(params)
+
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+paymentMethodsUri, req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return x.parsePaymentMethod(responseBody)
+}
+
+// GetLinkedAccount retrieves a linked payment method, including the e-wallet balance when available
+func (x *xendit) GetLinkedAccount(ctx context.Context, id string) (*pg.LinkedAccount, error) {
+	responseBody, err := x.sendRequest(ctx, http.MethodGet, fmt.Sprintf(x.getBaseURL()+paymentMethodUri, id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return x.parsePaymentMethod(responseBody)
+}
+
+// UnlinkAccount expires a linked payment method, or revokes a pending linked account token
+func (x *xendit) UnlinkAccount(ctx context.Context, id string) error {
+	if strings.HasPrefix(id, "lat-") {
+		id, _, _ = strings.Cut(id, linkIDSeparator)
+		_, err := x.sendRequest(ctx, http.MethodDelete, fmt.Sprintf(x.getBaseURL()+linkedAccountTokenUri, id), nil, "")
+		return err
+	}
+
+	_, err := x.sendRequest(ctx, http.MethodPost, fmt.Sprintf(x.getBaseURL()+paymentMethodExpireUri, id), nil, "")
+	return err
+}
+
+// parsePaymentMethod parses a Xendit payment method response
+func (x *xendit) parsePaymentMethod(responseBody []byte) (*pg.LinkedAccount, error) {
+	var resp PaymentMethodResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return x.mapper.mapToLinkedAccount(&resp, raw), nil
+}
@@ -0,0 +1,154 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+func TestXendit_LinkAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != paymentMethodsUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req CreatePaymentMethodRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Reusability != "MULTIPLE_USE" || req.CustomerID != "cust-123" {
+			t.Errorf("request = %+v, want reusable for cust-123", req)
+		}
+		if req.EWallet.ChannelCode != EWalletDANA {
+			t.Errorf("ChannelCode = %v, want %v", req.EWallet.ChannelCode, EWalletDANA)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "pm-123",
+			"type": "EWALLET",
+			"customer_id": "cust-123",
+			"reusability": "MULTIPLE_USE",
+			"status": "REQUIRES_ACTION",
+			"ewallet": {"channel_code": "DANA"},
+			"actions": [{"action": "AUTH", "url_type": "WEB", "url": "https://link.dana.id/auth", "method": "GET"}]
+		}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	account, err := x.LinkAccount(context.Background(), pg.LinkParams{
+		PaymentType: pg.PaymentTypeDANA,
+		Customer:    pg.Customer{ID: "cust-123"},
+		RedirectURL: "https://example.com/linked",
+	})
+	if err != nil {
+		t.Fatalf("LinkAccount() error = %v", err)
+	}
+
+	if account.ID != "pm-123" {
+		t.Errorf("ID = %v, want pm-123", account.ID)
+	}
+	if account.PaymentType != pg.PaymentTypeDANA {
+		t.Errorf("PaymentType = %v, want %v", account.PaymentType, pg.PaymentTypeDANA)
+	}
+	if account.Status != pg.LinkPending {
+		t.Errorf("Status = %v, want %v", account.Status, pg.LinkPending)
+	}
+	if account.ActionURL != "https://link.dana.id/auth" {
+		t.Errorf("ActionURL = %v, want auth URL", account.ActionURL)
+	}
+}
+
+func TestXendit_GetLinkedAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v2/payment_methods/pm-123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "pm-123",
+			"status": "ACTIVE",
+			"ewallet": {"channel_code": "OVO", "account": {"name": "John Doe", "account_details": "0812****7890", "balance": 250000}}
+		}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	account, err := x.GetLinkedAccount(context.Background(), "pm-123")
+	if err != nil {
+		t.Fatalf("GetLinkedAccount() error = %v", err)
+	}
+
+	if account.Status != pg.LinkActive {
+		t.Errorf("Status = %v, want %v", account.Status, pg.LinkActive)
+	}
+	if account.AccountName != "0812****7890" {
+		t.Errorf("AccountName = %v, want 0812****7890", account.AccountName)
+	}
+	if account.Balance == nil || *account.Balance != 250000 {
+		t.Errorf("Balance = %v, want 250000", account.Balance)
+	}
+}
+
+func TestXendit_UnlinkAccount(t *testing.T) {
+	var called bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/payment_methods/pm-123/expire" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		called = true
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "pm-123", "status": "EXPIRED"}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	if err := x.UnlinkAccount(context.Background(), "pm-123"); err != nil {
+		t.Fatalf("UnlinkAccount() error = %v", err)
+	}
+	if !called {
+		t.Error("expire endpoint was not called")
+	}
+}
+
+func TestXendit_UnlinkAccount_PendingToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/linked_account_tokens/lat-123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "lat-123", "is_deleted": true}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	if err := x.UnlinkAccount(context.Background(), "lat-123:cust-123"); err != nil {
+		t.Fatalf("UnlinkAccount() error = %v", err)
+	}
+}
+
+func TestMapper_mapToEWalletRequest_LinkedAccount(t *testing.T) {
+	mapper := &Mapper{}
+
+	req := mapper.mapToEWalletRequest(pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: pg.PaymentTypeOVO,
+		EWallet:     &pg.EWALLETParams{AccountLinkID: "pm-123"},
+	})
+
+	if req.PaymentMethodID != "pm-123" {
+		t.Errorf("PaymentMethodID = %v, want pm-123", req.PaymentMethodID)
+	}
+}
@@ -0,0 +1,39 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// Bank account name validation endpoint
+	bankAccountDataUri = "/bank_account_data_requests"
+)
+
+// ValidateBankAccount looks up the holder name of a bank account
+// Xendit may answer with a PENDING status when the bank does not reply in time
+func (x *xendit) ValidateBankAccount(ctx context.Context, bankCode, accountNumber string) (*pg.BankAccount, error) {
+	req := &BankAccountDataRequest{
+		BankAccountNumber: accountNumber,
+		BankCode:          pg.NormalizeBankCode(bankCode),
+	}
+
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+bankAccountDataUri, req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp BankAccountDataResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return x.mapper.mapToBankAccount(&resp, raw), nil
+}
@@ -0,0 +1,78 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+func TestXendit_ValidateBankAccount(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		wantValid  bool
+		wantStatus pg.Status
+		wantHolder string
+	}{
+		{
+			name:       "account found",
+			response:   `{"bank_code":"BCA","bank_account_number":"1234567890","bank_account_holder_name":"BUDI SANTOSO","status":"SUCCESS"}`,
+			wantValid:  true,
+			wantStatus: pg.StatusSuccess,
+			wantHolder: "BUDI SANTOSO",
+		},
+		{
+			name:       "inquiry still running",
+			response:   `{"bank_code":"BCA","bank_account_number":"1234567890","status":"PENDING"}`,
+			wantStatus: pg.StatusPending,
+		},
+		{
+			name:       "account not found",
+			response:   `{"bank_code":"BCA","bank_account_number":"1234567890","status":"FAILURE","failure_reason":"INVALID_ACCOUNT"}`,
+			wantStatus: pg.StatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != bankAccountDataUri {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+
+				var req BankAccountDataRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("failed to decode request: %v", err)
+				}
+				if req.BankCode != "BCA" {
+					t.Errorf("BankCode = %v, want BCA", req.BankCode)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			x := newTestProvider(t, server)
+
+			account, err := x.ValidateBankAccount(context.Background(), "014", "1234567890")
+			if err != nil {
+				t.Fatalf("ValidateBankAccount() error = %v", err)
+			}
+
+			if account.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", account.Valid, tt.wantValid)
+			}
+			if account.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", account.Status, tt.wantStatus)
+			}
+			if account.AccountHolder != tt.wantHolder {
+				t.Errorf("AccountHolder = %v, want %v", account.AccountHolder, tt.wantHolder)
+			}
+		})
+	}
+}
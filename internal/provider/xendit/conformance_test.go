@@ -0,0 +1,62 @@
+package xendit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/pgtest"
+)
+
+// conformanceStatuses maps the unified statuses to the Xendit invoice statuses
+var conformanceStatuses = map[pg.Status]PaymentStatus{
+	pg.StatusSuccess: StatusPaid,
+	pg.StatusPending: StatusPending,
+	pg.StatusExpired: StatusExpired,
+}
+
+// jsonHandler answers every request with body and the HTTP status code
+func jsonHandler(code int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	})
+}
+
+func TestXendit_Conformance(t *testing.T) {
+	invoice := func(status PaymentStatus) string {
+		return `{"id":"65f1b8e2c4a7d3001f8e9a10","external_id":"ORDER-001","user_id":"5f27a14a9bf05c73dd040bc8","status":"` + string(status) + `","amount":150000,"invoice_url":"https://checkout-staging.xendit.co/web/65f1b8e2c4a7d3001f8e9a10","created":"2024-03-01T03:00:00.000Z","updated":"2024-03-01T03:15:42.000Z","currency":"IDR"}`
+	}
+
+	pgtest.RunProviderConformance(t, New, pgtest.Stub{
+		Config: pg.ProviderConfig{ServerKey: "xnd_development_test", ClientKey: "callback-token"},
+		Charge: pg.ChargeParams{
+			OrderID:     "ORDER-001",
+			Amount:      150000,
+			PaymentType: pg.PaymentTypeVABCA,
+			Customer:    pg.Customer{ID: "CUST-001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890"},
+			Items:       []pg.Item{{ID: "SKU-001", Name: "Kaos Polos", Price: 75000, Quantity: 2}},
+			CallbackURL: "https://example.com/callback",
+		},
+		CreateCharge: jsonHandler(http.StatusOK, `{"id":"65f1b9a0c4a7d3001f8e9b20","owner_id":"5f27a14a9bf05c73dd040bc8","external_id":"ORDER-001","bank_code":"BCA","merchant_code":"10766","name":"Budi Santoso","account_number":"107669999123456","expected_amount":150000,"is_single_use":true,"is_closed":true,"status":"PENDING","currency":"IDR"}`),
+		Rejected:     jsonHandler(http.StatusBadRequest, `{"error_code":"API_VALIDATION_ERROR","message":"\"expected_amount\" must be greater than or equal to 10000"}`),
+		Statuses:     []pg.Status{pg.StatusSuccess, pg.StatusPending, pg.StatusExpired},
+		Status: func(status pg.Status) http.Handler {
+			return jsonHandler(http.StatusOK, invoice(conformanceStatuses[status]))
+		},
+		Cancel: jsonHandler(http.StatusOK, invoice(StatusExpired)),
+		Webhook: func(status pg.Status, signed bool) *http.Request {
+			r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(invoice(conformanceStatuses[status])))
+			r.Header.Set("Content-Type", "application/json")
+			if signed {
+				r.Header.Set("X-Callback-Token", "callback-token")
+			} else {
+				r.Header.Set("X-Callback-Token", "wrong-token")
+			}
+			return r
+		},
+	})
+}
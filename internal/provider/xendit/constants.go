@@ -1,5 +1,9 @@
 package xendit
 
+import (
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
 const (
 	// ProviderName is the name of the Xendit provider
 	ProviderName = "xendit"
@@ -8,3 +12,33 @@ const (
 	sandboxURL    = "https://api.xendit.co"
 	productionURL = "https://api.xendit.co"
 )
+
+// customOptions are the pg.ChargeParams.Custom keys read by the provider, they are not merged into the request body
+var customOptions = []string{"va_number", "enable_otp", "authentication_id"}
+
+// amountLimits are the Xendit amount limits per payment type, a zero Max has no maximum
+// They are overridden with pg.WithAmountLimits
+var amountLimits = map[pg.PaymentType]pg.AmountLimit{
+	pg.PaymentTypeGoPay:        {Min: 10000, Max: 20000000},
+	pg.PaymentTypeOVO:          {Min: 10000, Max: 10000000},
+	pg.PaymentTypeDANA:         {Min: 10000, Max: 20000000},
+	pg.PaymentTypeShopeePay:    {Min: 10000, Max: 20000000},
+	pg.PaymentTypeLinkAja:      {Min: 10000, Max: 10000000},
+	pg.PaymentTypeQRIS:         {Min: 1000, Max: 10000000},
+	pg.PaymentTypeVABCA:        {Min: 10000, Max: 999999999999},
+	pg.PaymentTypeVABNI:        {Min: 10000, Max: 50000000000},
+	pg.PaymentTypeVABRI:        {Min: 10000, Max: 50000000000},
+	pg.PaymentTypeVAMandiri:    {Min: 10000, Max: 50000000000},
+	pg.PaymentTypeVAPermata:    {Min: 10000, Max: 9999999999},
+	pg.PaymentTypeVACIMB:       {Min: 10000, Max: 50000000000},
+	pg.PaymentTypeCC:           {Min: 10000},
+	pg.PaymentTypeAlfamart:     {Min: 10000, Max: 2500000},
+	pg.PaymentTypeIndomaret:    {Min: 10000, Max: 5000000},
+	pg.PaymentTypeKredivo:      {Min: 10000, Max: 30000000},
+	pg.PaymentTypeAkulaku:      {Min: 10000, Max: 15000000},
+	pg.PaymentTypeAtome:        {Min: 10000, Max: 6000000},
+	pg.PaymentTypeIndodana:     {Min: 10000, Max: 25000000},
+	pg.PaymentTypeDDBRI:        {Min: 10000},
+	pg.PaymentTypeDDMandiri:    {Min: 10000},
+	pg.PaymentTypeDDBCAOneKlik: {Min: 10000, Max: 20000000},
+}
@@ -0,0 +1,153 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// Direct Debit API endpoints
+	linkedAccountTokenAuthUri     = "/linked_account_tokens/auth"
+	linkedAccountTokenUri         = "/linked_account_tokens/%s"
+	linkedAccountTokenOTPUri      = "/linked_account_tokens/%s/validate_otp"
+	linkedAccountTokenAccountsUri = "/linked_account_tokens/%s/accounts"
+	debitPaymentMethodsUri        = "/payment_methods"
+	directDebitsUri               = "/direct_debits"
+	directDebitOTPUri             = "/direct_debits/%s/validate_otp/"
+
+	headerDirectDebitIdempotencyKey = "Idempotency-key"
+
+	// linkIDSeparator separates the linked account token and its customer in a pending LinkedAccount.ID
+	linkIDSeparator = ":"
+)
+
+// linkBankAccount starts a linked account tokenization for a direct debit bank account
+// BRI and Mandiri send an OTP to the customer, BCA OneKlik returns an authorizer URL
+func (x *xendit) linkBankAccount(ctx context.Context, params pg.LinkParams) (*pg.LinkedAccount, error) {
+	if params.Customer.ID == "" {
+		return nil, pg.NewRequiredFieldError("Customer.ID")
+	}
+
+	req := x.mapper.mapToLinkedAccountTokenRequest(params)
+
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+linkedAccountTokenAuthUri, req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp LinkedAccountTokenResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	customerID := resp.CustomerID
+	if customerID == "" {
+		customerID = params.Customer.ID
+	}
+
+	account := x.mapper.mapToPendingLinkedAccount(&resp, raw)
+	account.ID = resp.ID + linkIDSeparator + customerID
+
+	return account, nil
+}
+
+// ConfirmLink validates the linking OTP when given, then creates a direct debit payment method
+// for the first account behind the token. id is the pending LinkedAccount.ID, "<token ID>:<customer ID>",
+// so any client can confirm it. The returned ID is used as DirectDebit.AccountLinkID
+func (x *xendit) ConfirmLink(ctx context.Context, id, code string) (*pg.LinkedAccount, error) {
+	id, customerID, _ := strings.Cut(id, linkIDSeparator)
+
+	if code != "" {
+		responseBody, err := x.sendRequest(ctx, http.MethodPost, fmt.Sprintf(x.getBaseURL()+linkedAccountTokenOTPUri, id), &ValidateOTPRequest{OTPCode: code}, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var resp LinkedAccountTokenResponse
+		if err := json.Unmarshal(responseBody, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if resp.CustomerID != "" {
+			customerID = resp.CustomerID
+		}
+	}
+
+	if customerID == "" {
+		return nil, pg.NewFieldError("ID", "must be the LinkedAccount.ID returned by LinkAccount, or confirmed with the OTP")
+	}
+
+	responseBody, err := x.sendRequest(ctx, http.MethodGet, fmt.Sprintf(x.getBaseURL()+linkedAccountTokenAccountsUri, id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []*LinkedAccountResponse
+	if err := json.Unmarshal(responseBody, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(accounts) == 0 {
+		return nil, pg.WrapProviderError(ProviderName, "ACCOUNT_NOT_FOUND", "no account is linked to the token", nil)
+	}
+
+	req := x.mapper.mapToDebitPaymentMethodRequest(customerID, accounts[0])
+
+	responseBody, err = x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+debitPaymentMethodsUri, req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DebitPaymentMethodResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return x.mapper.mapToDirectDebitLinkedAccount(&resp, accounts[0], raw), nil
+}
+
+// createDirectDebitCharge debits a linked bank account
+// The order ID is sent as idempotency key so retries do not debit twice
+func (x *xendit) createDirectDebitCharge(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	req := x.mapper.mapToDirectDebitRequest(params)
+	headers := map[string]string{headerDirectDebitIdempotencyKey: params.OrderID}
+
+	responseBody, err := x.sendRequestWithHeaders(ctx, http.MethodPost, x.getBaseURL()+directDebitsUri, pg.WithCustom(req, params.Custom, customOptions...), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return x.parseDirectDebit(responseBody)
+}
+
+// ValidateChargeOTP submits the OTP of a direct debit payment, only the TransactionID and OTP are used
+func (x *xendit) ValidateChargeOTP(ctx context.Context, params pg.ChargeOTPParams) (*pg.ChargeResponse, error) {
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, fmt.Sprintf(x.getBaseURL()+directDebitOTPUri, params.TransactionID), &ValidateOTPRequest{OTPCode: params.OTP}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return x.parseDirectDebit(responseBody)
+}
+
+// parseDirectDebit parses a Xendit direct debit payment response
+func (x *xendit) parseDirectDebit(responseBody []byte) (*pg.ChargeResponse, error) {
+	var resp DirectDebitResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return x.mapper.mapToChargeResponseFromDirectDebit(&resp, raw), nil
+}
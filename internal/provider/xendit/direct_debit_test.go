@@ -0,0 +1,237 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+func TestXendit_LinkBankAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != linkedAccountTokenAuthUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req CreateLinkedAccountTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.ChannelCode != DirectDebitBRI || req.CustomerID != "cust-123" {
+			t.Errorf("request = %+v, want DC_BRI for cust-123", req)
+		}
+		if req.Properties.CardLastFour != "1234" || req.Properties.CardExpiry != "06/30" {
+			t.Errorf("Properties = %+v, want card details from Custom", req.Properties)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "lat-123", "customer_id": "cust-123", "channel_code": "DC_BRI", "authorizer_url": null, "status": "PENDING"}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	account, err := x.LinkAccount(context.Background(), pg.LinkParams{
+		PaymentType: pg.PaymentTypeDDBRI,
+		Customer:    pg.Customer{ID: "cust-123", Phone: "+6281234567890", Email: "john@example.com"},
+		RedirectURL: "https://example.com/linked",
+		Custom:      map[string]interface{}{"card_last_four": "1234", "card_expiry": "06/30"},
+	})
+	if err != nil {
+		t.Fatalf("LinkAccount() error = %v", err)
+	}
+
+	// The customer travels with the token, so ConfirmLink needs no state
+	if account.ID != "lat-123:cust-123" || account.Status != pg.LinkPending {
+		t.Errorf("account = %+v, want pending lat-123:cust-123", account)
+	}
+	if account.PaymentType != pg.PaymentTypeDDBRI {
+		t.Errorf("PaymentType = %v, want %v", account.PaymentType, pg.PaymentTypeDDBRI)
+	}
+	if len(account.NextActions) != 1 || account.NextActions[0].Type != pg.ActionValidateOTP {
+		t.Errorf("NextActions = %+v, want OTP validation", account.NextActions)
+	}
+}
+
+func TestXendit_ConfirmLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/linked_account_tokens/lat-123/validate_otp":
+			var req ValidateOTPRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.OTPCode != "333000" {
+				t.Errorf("OTPCode = %v, want 333000", req.OTPCode)
+			}
+			w.Write([]byte(`{"id": "lat-123", "customer_id": "cust-123", "channel_code": "DC_BRI", "status": "SUCCESS"}`))
+		case "/linked_account_tokens/lat-123/accounts":
+			w.Write([]byte(`[{"id": "la-123", "channel_code": "DC_BRI", "type": "DEBIT_CARD", "properties": {"card_last_four": "1234", "card_expiry": "06/30"}}]`))
+		case debitPaymentMethodsUri:
+			var req CreateDebitPaymentMethodRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.CustomerID != "cust-123" || req.Type != "DEBIT_CARD" || req.Properties.ID != "la-123" {
+				t.Errorf("request = %+v, want DEBIT_CARD la-123 for cust-123", req)
+			}
+			w.Write([]byte(`{"id": "pm-123", "customer_id": "cust-123", "type": "DEBIT_CARD", "status": "ACTIVE", "properties": {"id": "la-123"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	// Each confirmation runs on a fresh provider, as after a restart or on another instance
+	for _, code := range []string{"333000", ""} {
+		x := newTestProvider(t, server)
+
+		account, err := x.ConfirmLink(context.Background(), "lat-123:cust-123", code)
+		if err != nil {
+			t.Fatalf("ConfirmLink(%q) error = %v", code, err)
+		}
+
+		if account.ID != "pm-123" || account.Status != pg.LinkActive {
+			t.Errorf("account = %+v, want active pm-123", account)
+		}
+		if account.AccountName != "1234" {
+			t.Errorf("AccountName = %v, want 1234", account.AccountName)
+		}
+	}
+}
+
+func TestXendit_ConfirmLink_UnknownToken(t *testing.T) {
+	x := newTestProvider(t, nil)
+
+	if _, err := x.ConfirmLink(context.Background(), "lat-unknown", ""); err == nil {
+		t.Error("ConfirmLink() expected error for an unknown token without OTP")
+	}
+}
+
+func TestXendit_CreateCharge_DirectDebit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != directDebitsUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get(headerDirectDebitIdempotencyKey); got != "ORDER-DD-1" {
+			t.Errorf("Idempotency-key = %v, want ORDER-DD-1", got)
+		}
+
+		var req CreateDirectDebitRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.PaymentMethodID != "pm-123" || req.Amount != 50000 || !req.EnableOTP {
+			t.Errorf("request = %+v, want OTP debit of 50000 from pm-123", req)
+		}
+		if len(req.Basket) != 1 {
+			t.Errorf("Basket = %d items, want 1", len(req.Basket))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "ddpy-123",
+			"reference_id": "ORDER-DD-1",
+			"channel_code": "DC_BRI",
+			"payment_method_id": "pm-123",
+			"currency": "IDR",
+			"amount": 50000,
+			"status": "PENDING",
+			"is_otp_required": true,
+			"otp_mobile_number": "+6281234567890",
+			"required_action": "VALIDATE_OTP"
+		}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	resp, err := x.CreateCharge(context.Background(), pg.ChargeParams{
+		OrderID:     "ORDER-DD-1",
+		Amount:      50000,
+		PaymentType: pg.PaymentTypeDDBRI,
+		Customer:    pg.Customer{ID: "cust-123", Email: "john@example.com"},
+		Items:       []pg.Item{{ID: "item-1", Name: "Product", Price: 50000, Quantity: 1}},
+		DirectDebit: &pg.DirectDebitParams{AccountLinkID: "pm-123"},
+	})
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+
+	if resp.TransactionID != "ddpy-123" || resp.Status != pg.StatusPending {
+		t.Errorf("response = %+v, want pending ddpy-123", resp)
+	}
+	if len(resp.NextActions) != 1 || resp.NextActions[0].Type != pg.ActionValidateOTP {
+		t.Errorf("NextActions = %+v, want OTP validation", resp.NextActions)
+	}
+}
+
+func TestMapper_mapToDirectDebitRequest_Adjustments(t *testing.T) {
+	mapper := &Mapper{}
+
+	req := mapper.mapToDirectDebitRequest(pg.ChargeParams{
+		OrderID:     "ORDER-DD-3",
+		Amount:      55000,
+		PaymentType: pg.PaymentTypeDDBRI,
+		Items:       []pg.Item{{ID: "item-1", Name: "Product", Price: 50000, Quantity: 1}},
+		Adjustments: []pg.Adjustment{{Type: pg.AdjustmentDiscount, Amount: 5000}, {Type: pg.AdjustmentShipping, Amount: 10000}},
+		DirectDebit: &pg.DirectDebitParams{AccountLinkID: "pm-123"},
+	})
+
+	if len(req.Basket) != 3 {
+		t.Fatalf("Basket = %d items, want 3", len(req.Basket))
+	}
+
+	var total float64
+	for _, item := range req.Basket {
+		total += item.Price * float64(item.Quantity)
+	}
+	if total != req.Amount {
+		t.Errorf("Basket total = %v, want %v", total, req.Amount)
+	}
+	if req.Basket[1].Type != string(pg.AdjustmentDiscount) || req.Basket[1].Price != -5000 {
+		t.Errorf("Basket[1] = %+v, want the discount", req.Basket[1])
+	}
+}
+
+func TestXendit_CreateCharge_DirectDebitRequiresAccount(t *testing.T) {
+	x := newTestProvider(t, nil)
+
+	_, err := x.CreateCharge(context.Background(), pg.ChargeParams{
+		OrderID:     "ORDER-DD-2",
+		Amount:      50000,
+		PaymentType: pg.PaymentTypeDDMandiri,
+		Customer:    pg.Customer{ID: "cust-123", Email: "john@example.com"},
+		Items:       []pg.Item{{ID: "item-1", Name: "Product", Price: 50000, Quantity: 1}},
+	})
+	if err == nil {
+		t.Error("CreateCharge() expected error without DirectDebit.AccountLinkID")
+	}
+}
+
+func TestXendit_ValidateChargeOTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/direct_debits/ddpy-123/validate_otp/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "ddpy-123", "reference_id": "ORDER-DD-1", "amount": 50000, "status": "COMPLETED", "required_action": null}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	resp, err := x.ValidateChargeOTP(context.Background(), pg.ChargeOTPParams{TransactionID: "ddpy-123", OTP: "123456"})
+	if err != nil {
+		t.Fatalf("ValidateChargeOTP() error = %v", err)
+	}
+
+	if resp.Status != pg.StatusSuccess {
+		t.Errorf("Status = %v, want %v", resp.Status, pg.StatusSuccess)
+	}
+	if len(resp.NextActions) != 0 {
+		t.Errorf("NextActions = %+v, want none", resp.NextActions)
+	}
+}
@@ -0,0 +1,182 @@
+package xendit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+)
+
+const (
+	// Disbursement API endpoints
+	disbursementUri       = "/disbursements"
+	disbursementStatusUri = "/disbursements/%s"
+	batchDisbursementUri  = "/batch_disbursements"
+
+	headerIdempotencyKey = "X-IDEMPOTENCY-KEY"
+)
+
+// CreateDisbursement sends money to a bank account using the Xendit Disbursement API
+func (x *xendit) CreateDisbursement(ctx context.Context, params pg.DisbursementParams) (*pg.Disbursement, error) {
+	req := x.mapper.mapToDisbursementRequest(params)
+
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+disbursementUri, req, params.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return x.parseDisbursement(responseBody)
+}
+
+// GetDisbursement retrieves a disbursement by its Xendit ID
+func (x *xendit) GetDisbursement(ctx context.Context, id string) (*pg.Disbursement, error) {
+	fullURL := fmt.Sprintf(x.getBaseURL()+disbursementStatusUri, id)
+
+	responseBody, err := x.sendRequest(ctx, http.MethodGet, fullURL, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return x.parseDisbursement(responseBody)
+}
+
+// CreateBatchDisbursement sends a batch of disbursements
+// Xendit batches need approval in the dashboard unless auto-approval is enabled
+func (x *xendit) CreateBatchDisbursement(ctx context.Context, params pg.BatchDisbursementParams) (*pg.BatchDisbursement, error) {
+	req := x.mapper.mapToBatchDisbursementRequest(params)
+
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+batchDisbursementUri, req, params.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp BatchDisbursementResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return x.mapper.mapToBatchDisbursement(&resp, raw), nil
+}
+
+// VerifyDisbursementWebhook verifies the X-Callback-Token of a disbursement callback
+func (x *xendit) VerifyDisbursementWebhook(r *http.Request) bool {
+	return x.VerifyWebhook(r)
+}
+
+// ParseDisbursementWebhook parses a disbursement callback
+func (x *xendit) ParseDisbursementWebhook(r *http.Request) (*pg.DisbursementEvent, error) {
+	if r.Body == nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	// Restore body for subsequent reads
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	// Batch disbursement callbacks report the whole batch, not a single disbursement
+	if _, ok := raw["disbursements"]; ok {
+		return nil, pg.ErrInvalidWebhookType
+	}
+
+	var resp DisbursementResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.ID == "" {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	disbursement := x.mapper.mapToDisbursement(&resp, raw)
+
+	timestamp := time.Now()
+	if resp.Updated != nil {
+		timestamp = *resp.Updated
+	}
+
+	return &pg.DisbursementEvent{
+		Disbursement: *disbursement,
+		EventType:    pg.DisbursementEventType(disbursement.Status),
+		Timestamp:    timestamp,
+	}, nil
+}
+
+// parseDisbursement parses a Xendit disbursement response
+func (x *xendit) parseDisbursement(responseBody []byte) (*pg.Disbursement, error) {
+	var resp DisbursementResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return x.mapper.mapToDisbursement(&resp, raw), nil
+}
+
+// sendRequest sends an authenticated request to Xendit and returns the response body
+// idempotencyKey is sent as X-IDEMPOTENCY-KEY when not empty
+func (x *xendit) sendRequest(ctx context.Context, method, fullURL string, payload interface{}, idempotencyKey string) ([]byte, error) {
+	var headers map[string]string
+	if idempotencyKey != "" {
+		headers = map[string]string{headerIdempotencyKey: idempotencyKey}
+	}
+	return x.sendRequestWithHeaders(ctx, method, fullURL, payload, headers)
+}
+
+// sendRequestWithHeaders sends an authenticated request to Xendit with extra headers
+func (x *xendit) sendRequestWithHeaders(ctx context.Context, method, fullURL string, payload interface{}, headers map[string]string) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		bodyBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(headerAuthorization, utils.SetBasicAuthorization(x.config.ServerKey, ""))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := x.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, apiError(resp.StatusCode, responseBody)
+	}
+
+	return responseBody, nil
+}
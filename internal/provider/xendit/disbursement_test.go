@@ -0,0 +1,221 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+// rewriteTransport sends every request to the test server regardless of the original host
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestProvider creates a Xendit provider which talks to the given test server
+func newTestProvider(t *testing.T, server *httptest.Server) *xendit {
+	t.Helper()
+
+	provider, err := New(&pg.ProviderConfig{
+		ServerKey: "xnd_development_test",
+		ClientKey: "callback-token",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	x := provider.(*xendit)
+	if server != nil {
+		target, _ := url.Parse(server.URL)
+		x.httpCli = &http.Client{
+			Transport: &rewriteTransport{target: target},
+		}
+	}
+
+	return x
+}
+
+func TestXendit_CreateDisbursement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != disbursementUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get(headerIdempotencyKey) != "DISB-001" {
+			t.Errorf("%s = %v, want DISB-001", headerIdempotencyKey, r.Header.Get(headerIdempotencyKey))
+		}
+
+		var req CreateDisbursementRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.BankCode != "BCA" {
+			t.Errorf("BankCode = %v, want BCA", req.BankCode)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "disb-123",
+			"external_id": "DISB-001",
+			"amount": 100000,
+			"bank_code": "BCA",
+			"account_holder_name": "John Doe",
+			"disbursement_description": "Payout",
+			"status": "PENDING"
+		}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	result, err := x.CreateDisbursement(context.Background(), pg.DisbursementParams{
+		ReferenceID:   "DISB-001",
+		BankCode:      "bca",
+		AccountNumber: "1234567890",
+		AccountHolder: "John Doe",
+		Amount:        100000,
+		Description:   "Payout",
+	})
+	if err != nil {
+		t.Fatalf("CreateDisbursement() error = %v", err)
+	}
+
+	if result.ID != "disb-123" {
+		t.Errorf("ID = %v, want disb-123", result.ID)
+	}
+	if result.Status != pg.StatusPending {
+		t.Errorf("Status = %v, want %v", result.Status, pg.StatusPending)
+	}
+}
+
+func TestXendit_CreateDisbursement_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error_code": "DUPLICATE_TRANSACTION_ERROR", "message": "duplicate"}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	_, err := x.CreateDisbursement(context.Background(), pg.DisbursementParams{ReferenceID: "DISB-001"})
+	if !pg.IsProviderError(err) {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+
+	var providerErr *pg.ProviderError
+	if errors.As(err, &providerErr) && providerErr.Code != "DUPLICATE_TRANSACTION_ERROR" {
+		t.Errorf("Code = %v, want DUPLICATE_TRANSACTION_ERROR", providerErr.Code)
+	}
+}
+
+func TestXendit_CreateBatchDisbursement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != batchDisbursementUri {
+			t.Errorf("path = %v, want %v", r.URL.Path, batchDisbursementUri)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "batch-123",
+			"reference": "BATCH-001",
+			"status": "NEEDS_APPROVAL",
+			"total_uploaded_amount": 150000
+		}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	result, err := x.CreateBatchDisbursement(context.Background(), pg.BatchDisbursementParams{
+		ReferenceID: "BATCH-001",
+		Disbursements: []pg.DisbursementParams{
+			{ReferenceID: "DISB-001", BankCode: "BCA", AccountNumber: "1", AccountHolder: "A", Amount: 100000},
+			{ReferenceID: "DISB-002", BankCode: "BNI", AccountNumber: "2", AccountHolder: "B", Amount: 50000},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateBatchDisbursement() error = %v", err)
+	}
+
+	if result.ID != "batch-123" {
+		t.Errorf("ID = %v, want batch-123", result.ID)
+	}
+	if result.TotalAmount != 150000 {
+		t.Errorf("TotalAmount = %v, want 150000", result.TotalAmount)
+	}
+	if result.Status != pg.StatusPending {
+		t.Errorf("Status = %v, want %v", result.Status, pg.StatusPending)
+	}
+}
+
+func TestXendit_ParseDisbursementWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus pg.Status
+		wantEvent  string
+		wantErr    error
+	}{
+		{
+			name:       "completed disbursement",
+			body:       `{"id":"disb-123","external_id":"DISB-001","amount":100000,"status":"COMPLETED","updated":"2024-01-01T10:00:00.000Z"}`,
+			wantStatus: pg.StatusSuccess,
+			wantEvent:  pg.EventDisbursementCompleted,
+		},
+		{
+			name:       "failed disbursement",
+			body:       `{"id":"disb-123","external_id":"DISB-001","amount":100000,"status":"FAILED","failure_code":"INVALID_DESTINATION"}`,
+			wantStatus: pg.StatusFailed,
+			wantEvent:  pg.EventDisbursementFailed,
+		},
+		{
+			name:    "batch callback",
+			body:    `{"id":"batch-123","status":"COMPLETED","disbursements":[]}`,
+			wantErr: pg.ErrInvalidWebhookType,
+		},
+		{
+			name:    "invalid payload",
+			body:    `not json`,
+			wantErr: pg.ErrInvalidPayload,
+		},
+	}
+
+	x := newTestProvider(t, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhook", strings.NewReader(tt.body))
+			req.Header.Set("X-Callback-Token", "callback-token")
+
+			if !x.VerifyDisbursementWebhook(req) {
+				t.Fatal("VerifyDisbursementWebhook() = false, want true")
+			}
+
+			event, err := x.ParseDisbursementWebhook(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseDisbursementWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if event.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", event.Status, tt.wantStatus)
+			}
+			if event.EventType != tt.wantEvent {
+				t.Errorf("EventType = %v, want %v", event.EventType, tt.wantEvent)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// Card API endpoints
+	cardChargesUri      = "/credit_card_charges"
+	cardChargeOptionUri = "/credit_card_charges/option"
+)
+
+// createCardCharge charges a tokenized card, in installments when CreditCard.InstallmentTerm is set
+func (x *xendit) createCardCharge(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	req := x.mapper.mapToCardChargeRequest(params)
+
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+cardChargesUri, pg.WithCustom(req, params.Custom, customOptions...), params.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CardChargeResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return x.mapper.mapToChargeResponseFromCard(&resp, raw), nil
+}
+
+// GetInstallmentOptions returns the installment plans for a card BIN or a bank code
+// BINs are quoted by Xendit, bank codes use the terms configured for the bank
+func (x *xendit) GetInstallmentOptions(ctx context.Context, binOrBank string, amount int64) ([]pg.InstallmentPlan, error) {
+	bank, ok := pg.LookupBank(binOrBank)
+	if ok {
+		terms, ok := pg.InstallmentTerms(x.config.InstallmentTerms, bank.Code)
+		if !ok {
+			return []pg.InstallmentPlan{}, nil
+		}
+		return pg.NewInstallmentPlans(bank.Code, amount, terms), nil
+	}
+
+	query := url.Values{}
+	query.Set("bin", binOrBank)
+	query.Set("amount", fmt.Sprintf("%d", amount))
+
+	responseBody, err := x.sendRequest(ctx, http.MethodGet, x.getBaseURL()+cardChargeOptionUri+"?"+query.Encode(), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CardChargeOptionResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return x.mapper.mapToCardInstallmentPlans(resp.Installments, amount), nil
+}
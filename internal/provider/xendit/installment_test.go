@@ -0,0 +1,99 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+func TestXendit_CreateCharge_CardInstallment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != cardChargesUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req CreateCardChargeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.TokenID != "card-token" || req.AuthenticationID != "auth-123" {
+			t.Errorf("request = %+v, want card-token with auth-123", req)
+		}
+		if req.Installment == nil || req.Installment.Count != 3 || req.Installment.Interval != "month" {
+			t.Errorf("Installment = %+v, want 3 months", req.Installment)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "charge-123", "external_id": "ORDER-CC-1", "status": "CAPTURED", "authorized_amount": 1200000, "capture_amount": 1200000, "card_type": "CREDIT"}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	params := pg.ChargeParams{
+		OrderID:     "ORDER-CC-1",
+		Amount:      1200000,
+		PaymentType: pg.PaymentTypeCC,
+		Customer:    pg.Customer{ID: "cust-123", Email: "john@example.com"},
+		Items:       []pg.Item{{ID: "item-1", Name: "Laptop", Price: 1200000, Quantity: 1}},
+		CreditCard:  &pg.CreditCardParams{CardNumber: "card-token", InstallmentTerm: 3, Bank: "BRI"},
+		Custom:      map[string]interface{}{"authentication_id": "auth-123"},
+	}
+
+	resp, err := x.CreateCharge(context.Background(), params)
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if resp.TransactionID != "charge-123" || resp.Status != pg.StatusSuccess {
+		t.Errorf("response = %+v, want captured charge-123", resp)
+	}
+
+	params.CreditCard.Bank = ""
+	if _, err := x.CreateCharge(context.Background(), params); err == nil {
+		t.Error("expected error for an installment without bank, got nil")
+	}
+}
+
+func TestXendit_GetInstallmentOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != cardChargeOptionUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("bin") != "520000" || r.URL.Query().Get("amount") != "600000" {
+			t.Errorf("query = %v, want bin 520000 and amount 600000", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"bin": "520000", "installments": [
+			{"issuer": "BRI", "terms": [3, 6], "interval": "month", "minimum_amount": 500000},
+			{"issuer": "BCA", "terms": [3, 6, 12], "interval": "month", "minimum_amount": 1000000}
+		]}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	plans, err := x.GetInstallmentOptions(context.Background(), "520000", 600000)
+	if err != nil {
+		t.Fatalf("GetInstallmentOptions() error = %v", err)
+	}
+
+	if len(plans) != 2 {
+		t.Fatalf("got %d plans, want 2 BRI plans", len(plans))
+	}
+	if plans[1].Bank != "BRI" || plans[1].Tenure != 6 || plans[1].InstallmentAmount != 100000 {
+		t.Errorf("plan = %+v, want BRI 6 x 100000", plans[1])
+	}
+
+	plans, err = x.GetInstallmentOptions(context.Background(), "MANDIRI", 600000)
+	if err != nil {
+		t.Fatalf("GetInstallmentOptions() error = %v", err)
+	}
+	if len(plans) != 3 {
+		t.Errorf("got %d plans for MANDIRI, want the 3 default terms", len(plans))
+	}
+}
@@ -40,6 +40,16 @@ func (m *Mapper) mapPaymentType(pt pg.PaymentType) (string, string) {
 		return "RETAIL_OUTLET", string(RetailAlfamart)
 	case pg.PaymentTypeIndomaret:
 		return "RETAIL_OUTLET", string(RetailIndomaret)
+	case pg.PaymentTypeKredivo:
+		return "PAYLATER", string(PaylaterKredivo)
+	case pg.PaymentTypeAkulaku:
+		return "PAYLATER", string(PaylaterAkulaku)
+	case pg.PaymentTypeAtome:
+		return "PAYLATER", string(PaylaterAtome)
+	case pg.PaymentTypeIndodana:
+		return "PAYLATER", string(PaylaterIndodana)
+	case pg.PaymentTypeDDBRI, pg.PaymentTypeDDMandiri, pg.PaymentTypeDDBCAOneKlik:
+		return "DIRECT_DEBIT", string(m.mapDirectDebitChannel(pt))
 	default:
 		return "", ""
 	}
@@ -48,12 +58,14 @@ func (m *Mapper) mapPaymentType(pt pg.PaymentType) (string, string) {
 // mapStatus maps Xendit status to unified status
 func (m *Mapper) mapStatus(status PaymentStatus) pg.Status {
 	switch status {
-	case StatusPaid:
+	case StatusPaid, StatusSettled:
 		return pg.StatusSuccess
 	case StatusPending:
 		return pg.StatusPending
 	case StatusFailed:
 		return pg.StatusFailed
+	case StatusExpired:
+		return pg.StatusExpired
 	default:
 		return pg.StatusPending
 	}
@@ -69,6 +81,7 @@ func (m *Mapper) mapToEWalletRequest(params pg.ChargeParams) *CreateEWalletReque
 		EWalletCode: EWalletCode(code),
 		CallbackURL: params.CallbackURL,
 		Currency:    "IDR",
+		Metadata:    params.Metadata,
 	}
 
 	if params.ReturnURL != "" {
@@ -83,6 +96,11 @@ func (m *Mapper) mapToEWalletRequest(params pg.ChargeParams) *CreateEWalletReque
 		req.Phone = params.Customer.Phone
 	}
 
+	// Charge a linked account without asking the customer again
+	if params.EWallet != nil && params.EWallet.AccountLinkID != "" {
+		req.PaymentMethodID = params.EWallet.AccountLinkID
+	}
+
 	return req
 }
 
@@ -96,7 +114,31 @@ func (m *Mapper) mapToVARequest(params pg.ChargeParams) *CreateVAResquest {
 		Name:           params.Customer.Name,
 		ExpectedAmount: float64(params.Amount),
 		IsClosed:       true,
+		IsSingleUse:    true,
 		Currency:       "IDR",
+		Description:    params.Description,
+		Metadata:       params.Metadata,
+	}
+
+	if !params.ExpiryTime.IsZero() {
+		expiry := params.ExpiryTime
+		req.ExpirationDate = &expiry
+	}
+
+	if va := params.VirtualAccount; va != nil {
+		if va.CustomerName != "" {
+			req.Name = va.CustomerName
+		}
+		if va.VANumber != "" {
+			req.VANumber = va.VANumber
+		}
+		req.IsSingleUse = !va.Reusable
+
+		// Open VAs accept any amount, a non-zero amount is only suggested to the customer
+		if va.OpenAmount {
+			req.IsClosed = false
+			req.SuggestedAmount = params.Amount > 0
+		}
 	}
 
 	// Set custom VA number if provided
@@ -107,6 +149,79 @@ func (m *Mapper) mapToVARequest(params pg.ChargeParams) *CreateVAResquest {
 	return req
 }
 
+// mapToUpdateVARequest maps unified VirtualAccountUpdateParams to Xendit UpdateVARequest
+func (m *Mapper) mapToUpdateVARequest(params pg.VirtualAccountUpdateParams) *UpdateVARequest {
+	req := &UpdateVARequest{
+		ExpectedAmount: float64(params.Amount),
+		Description:    params.Description,
+	}
+
+	if !params.ExpiryTime.IsZero() {
+		expiry := params.ExpiryTime
+		req.ExpirationDate = &expiry
+	}
+
+	if params.Reusable != nil {
+		singleUse := !*params.Reusable
+		req.IsSingleUse = &singleUse
+	}
+
+	return req
+}
+
+// mapVAPaymentToWebhookEvent maps a Xendit VA payment callback to unified WebhookEvent
+// Amount is this payment only, PaidAmount is set from the pg.PaymentLedger
+func (m *Mapper) mapVAPaymentToWebhookEvent(callback *VAPaymentCallback, raw map[string]interface{}) *pg.WebhookEvent {
+	event := &pg.WebhookEvent{
+		OrderID:       callback.ExternalID,
+		TransactionID: callback.PaymentID,
+		Status:        pg.StatusSuccess,
+		Amount:        int64(callback.Amount),
+		PaymentType:   m.unifiedPaymentType("VIRTUAL_ACCOUNT", string(callback.BankCode)),
+		EventType:     pg.EventPaymentCompleted,
+		Raw:           raw,
+	}
+
+	if callback.TransactionTimestamp != nil {
+		event.Timestamp = *callback.TransactionTimestamp
+	}
+
+	return event
+}
+
+// mapCustomerAddresses maps the billing and shipping addresses of the customer
+// The billing address is the primary address
+func (m *Mapper) mapCustomerAddresses(customer pg.Customer) []*CustomerAddress {
+	var addresses []*CustomerAddress
+
+	if a := customer.BillingAddress; a != nil {
+		addresses = append(addresses, &CustomerAddress{
+			Category:    "BILLING",
+			StreetLine1: a.Line1,
+			StreetLine2: a.Line2,
+			City:        a.City,
+			State:       a.State,
+			PostalCode:  a.PostalCode,
+			Country:     a.Country(),
+			IsPrimary:   true,
+		})
+	}
+	if a := customer.ShippingAddress; a != nil {
+		addresses = append(addresses, &CustomerAddress{
+			Category:    "SHIPPING",
+			StreetLine1: a.Line1,
+			StreetLine2: a.Line2,
+			City:        a.City,
+			State:       a.State,
+			PostalCode:  a.PostalCode,
+			Country:     a.Country(),
+			IsPrimary:   customer.BillingAddress == nil,
+		})
+	}
+
+	return addresses
+}
+
 // mapToInvoiceRequest maps unified ChargeParams to Xendit Invoice request
 func (m *Mapper) mapToInvoiceRequest(params pg.ChargeParams) *CreateInvoiceRequest {
 	methodType, _ := m.mapPaymentType(params.PaymentType)
@@ -124,14 +239,18 @@ func (m *Mapper) mapToInvoiceRequest(params pg.ChargeParams) *CreateInvoiceReque
 		Currency:     "IDR",
 		PaymentMethod: paymentMethods,
 		Description:  params.Description,
+		Metadata:     params.Metadata,
 	}
 
 	// Set customer details
 	if params.Customer.Name != "" || params.Customer.Email != "" {
+		givenNames, surname := pg.SplitName(params.Customer.Name)
 		req.Customer = &CustomerDetail{
-			GivenNames:   params.Customer.Name,
+			GivenNames:   givenNames,
+			Surname:      surname,
 			Email:        params.Customer.Email,
 			MobileNumber: params.Customer.Phone,
+			Addresses:    m.mapCustomerAddresses(params.Customer),
 		}
 	}
 
@@ -148,6 +267,14 @@ func (m *Mapper) mapToInvoiceRequest(params pg.ChargeParams) *CreateInvoiceReque
 		}
 	}
 
+	// Set adjustments, discounts are negative fees
+	for _, a := range params.Adjustments {
+		req.Fees = append(req.Fees, &Fee{
+			Type:  a.Label(),
+			Value: float64(a.SignedAmount()),
+		})
+	}
+
 	return req
 }
 
@@ -164,6 +291,13 @@ func (m *Mapper) mapToChargeResponse(resp *InvoiceResponse, _ pg.PaymentType) *p
 		Status:        m.mapStatus(resp.Status),
 		PaymentURL:    resp.PaymentURL,
 	}
+	if unified.PaymentURL == "" {
+		unified.PaymentURL = resp.InvoiceURL
+	}
+
+	if unified.PaymentURL != "" {
+		unified.NextActions = []pg.Action{{Type: pg.ActionRedirect, URL: unified.PaymentURL, Method: "GET"}}
+	}
 
 	// Set created time
 	if resp.Created != nil {
@@ -213,12 +347,28 @@ func (m *Mapper) mapToChargeResponseFromEWallet(resp *EWalletResponse, _ pg.Paym
 		Amount:        int64(resp.Amount),
 		Status:        m.mapStatus(resp.Status),
 		PaymentURL:    resp.PaymentURL,
+		QRString:      resp.QRString,
 	}
 
 	if resp.RedirectURL != "" {
 		unified.PaymentURL = resp.RedirectURL
 	}
 
+	if resp.Actions != nil {
+		unified.NextActions = m.mapCheckoutActions(resp.Actions.DesktopWebCheckoutURL, resp.Actions.MobileWebCheckoutURL, resp.Actions.MobileDeeplinkCheckoutURL)
+		if resp.Actions.QRCheckoutString != "" {
+			unified.QRString = resp.Actions.QRCheckoutString
+		}
+	} else if unified.PaymentURL != "" {
+		unified.NextActions = []pg.Action{{Type: pg.ActionRedirect, URL: unified.PaymentURL, Method: "GET"}}
+	}
+	if unified.QRString != "" {
+		unified.NextActions = append(unified.NextActions, pg.Action{Type: pg.ActionShowQR, QRString: unified.QRString})
+	}
+	if url := pg.PreferredURL(unified.NextActions, false); url != "" {
+		unified.PaymentURL = url
+	}
+
 	if resp.Created != nil {
 		unified.CreatedAt = *resp.Created
 	}
@@ -231,7 +381,7 @@ func (m *Mapper) mapToPaymentStatus(orderID string, resp interface{}) *pg.Paymen
 	switch r := resp.(type) {
 	case *InvoiceResponse:
 		var paidAt *time.Time
-		if r.Status == StatusPaid {
+		if r.Status == StatusPaid || r.Status == StatusSettled {
 			paidAt = r.Updated
 		}
 
@@ -242,6 +392,7 @@ func (m *Mapper) mapToPaymentStatus(orderID string, resp interface{}) *pg.Paymen
 			Amount:        int64(r.Amount),
 			PaidAmount:    int64(r.Amount),
 			PaidAt:        paidAt,
+			Metadata:      r.Metadata,
 		}
 	case *VAResponse:
 		var paidAt *time.Time
@@ -257,6 +408,7 @@ func (m *Mapper) mapToPaymentStatus(orderID string, resp interface{}) *pg.Paymen
 			Amount:        int64(r.ExpectedAmount),
 			PaidAmount:    int64(r.ExpectedAmount),
 			PaidAt:        paidAt,
+			Metadata:      r.Metadata,
 		}
 	}
 
@@ -306,3 +458,727 @@ func (m *Mapper) unifiedPaymentType(methodType, methodCode string) pg.PaymentTyp
 	}
 	return pg.PaymentType(methodType)
 }
+
+// mapDisbursementStatus maps Xendit disbursement status to unified status
+func (m *Mapper) mapDisbursementStatus(status DisbursementStatus) pg.Status {
+	switch status {
+	case DisbursementCompleted:
+		return pg.StatusSuccess
+	case DisbursementFailed:
+		return pg.StatusFailed
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapBatchDisbursementStatus maps Xendit batch disbursement status to unified status
+func (m *Mapper) mapBatchDisbursementStatus(status BatchDisbursementStatus) pg.Status {
+	switch status {
+	case BatchApproved, BatchUploading:
+		return pg.StatusProcessing
+	case BatchCompleted:
+		return pg.StatusSuccess
+	case BatchDeleted:
+		return pg.StatusCancelled
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapToDisbursementRequest maps unified DisbursementParams to Xendit CreateDisbursementRequest
+func (m *Mapper) mapToDisbursementRequest(params pg.DisbursementParams) *CreateDisbursementRequest {
+	req := &CreateDisbursementRequest{
+		ExternalID:        params.ReferenceID,
+		Amount:            params.Amount,
+		BankCode:          pg.NormalizeBankCode(params.BankCode),
+		AccountHolderName: params.AccountHolder,
+		AccountNumber:     params.AccountNumber,
+		Description:       disbursementDescription(params),
+	}
+
+	if params.Email != "" {
+		req.EmailTo = []string{params.Email}
+	}
+
+	return req
+}
+
+// mapToBatchDisbursementRequest maps unified BatchDisbursementParams to Xendit CreateBatchDisbursementRequest
+func (m *Mapper) mapToBatchDisbursementRequest(params pg.BatchDisbursementParams) *CreateBatchDisbursementRequest {
+	req := &CreateBatchDisbursementRequest{
+		Reference:     params.ReferenceID,
+		Disbursements: make([]*BatchDisbursementItem, len(params.Disbursements)),
+	}
+
+	for i, d := range params.Disbursements {
+		req.Disbursements[i] = &BatchDisbursementItem{
+			Amount:            d.Amount,
+			BankCode:          pg.NormalizeBankCode(d.BankCode),
+			BankAccountName:   d.AccountHolder,
+			BankAccountNumber: d.AccountNumber,
+			Description:       disbursementDescription(d),
+			ExternalID:        d.ReferenceID,
+		}
+		if d.Email != "" {
+			req.Disbursements[i].EmailTo = []string{d.Email}
+		}
+	}
+
+	return req
+}
+
+// mapToDisbursement maps Xendit DisbursementResponse to unified Disbursement
+func (m *Mapper) mapToDisbursement(resp *DisbursementResponse, raw map[string]interface{}) *pg.Disbursement {
+	if resp == nil {
+		return nil
+	}
+
+	d := &pg.Disbursement{
+		ID:            resp.ID,
+		ReferenceID:   resp.ExternalID,
+		BankCode:      resp.BankCode,
+		AccountNumber: resp.AccountNumber,
+		AccountHolder: resp.AccountHolderName,
+		Amount:        resp.Amount,
+		Description:   resp.DisbursementDescription,
+		Status:        m.mapDisbursementStatus(resp.Status),
+		FailureReason: resp.FailureCode,
+		Raw:           raw,
+	}
+
+	if resp.Created != nil {
+		d.CreatedAt = *resp.Created
+	}
+	if resp.Updated != nil {
+		d.UpdatedAt = *resp.Updated
+	}
+
+	return d
+}
+
+// mapToBatchDisbursement maps Xendit BatchDisbursementResponse to unified BatchDisbursement
+func (m *Mapper) mapToBatchDisbursement(resp *BatchDisbursementResponse, raw map[string]interface{}) *pg.BatchDisbursement {
+	if resp == nil {
+		return nil
+	}
+
+	return &pg.BatchDisbursement{
+		ID:          resp.ID,
+		ReferenceID: resp.Reference,
+		Status:      m.mapBatchDisbursementStatus(resp.Status),
+		TotalAmount: resp.TotalUploadedAmount,
+		Raw:         raw,
+	}
+}
+
+// disbursementDescription returns the description Xendit requires for a disbursement
+func disbursementDescription(params pg.DisbursementParams) string {
+	if params.Description != "" {
+		return params.Description
+	}
+	return "Disbursement " + params.ReferenceID
+}
+
+// mapToBankAccount maps Xendit BankAccountDataResponse to unified BankAccount
+func (m *Mapper) mapToBankAccount(resp *BankAccountDataResponse, raw map[string]interface{}) *pg.BankAccount {
+	if resp == nil {
+		return nil
+	}
+
+	account := &pg.BankAccount{
+		BankCode:      pg.NormalizeBankCode(resp.BankCode),
+		AccountNumber: resp.BankAccountNumber,
+		AccountHolder: resp.BankAccountHolderName,
+		FailureReason: resp.FailureReason,
+		Raw:           raw,
+	}
+
+	switch resp.Status {
+	case BankAccountDataSuccess:
+		account.Valid = true
+		account.Status = pg.StatusSuccess
+	case BankAccountDataFailure:
+		account.Status = pg.StatusFailed
+	default:
+		account.Status = pg.StatusPending
+	}
+
+	return account
+}
+
+// mapPlanStatus maps Xendit recurring plan status to unified subscription status
+func (m *Mapper) mapPlanStatus(status RecurringPlanStatus) pg.SubscriptionStatus {
+	switch status {
+	case PlanActive:
+		return pg.SubscriptionActive
+	case PlanPaused:
+		return pg.SubscriptionPaused
+	case PlanInactive:
+		return pg.SubscriptionCancelled
+	default:
+		return pg.SubscriptionPending
+	}
+}
+
+// mapCycleStatus maps Xendit recurring cycle status to unified payment status
+func (m *Mapper) mapCycleStatus(status RecurringCycleStatus) pg.Status {
+	switch status {
+	case CycleSucceeded:
+		return pg.StatusSuccess
+	case CycleFailed:
+		return pg.StatusFailed
+	case CycleCancelled:
+		return pg.StatusCancelled
+	case CyclePending, CycleRetrying:
+		return pg.StatusProcessing
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapToRecurringPlanRequest maps unified SubscriptionParams to Xendit CreateRecurringPlanRequest
+func (m *Mapper) mapToRecurringPlanRequest(params pg.SubscriptionParams) *CreateRecurringPlanRequest {
+	intervalCount := params.IntervalCount
+	if intervalCount <= 0 {
+		intervalCount = 1
+	}
+
+	req := &CreateRecurringPlanRequest{
+		ReferenceID:     params.ReferenceID,
+		CustomerID:      params.Customer.ID,
+		RecurringAction: "PAYMENT",
+		Currency:        "IDR",
+		Amount:          params.Amount,
+		PaymentMethods: []*RecurringPaymentMethod{
+			{PaymentMethodID: params.PaymentToken, Rank: 1},
+		},
+		Schedule: &RecurringSchedule{
+			ReferenceID:     params.ReferenceID,
+			Interval:        string(params.Interval),
+			IntervalCount:   intervalCount,
+			TotalRecurrence: params.TotalCycles,
+		},
+		FailedCycleAction: "STOP",
+		Description:       params.Description,
+	}
+
+	if !params.StartAt.IsZero() {
+		startAt := params.StartAt
+		req.Schedule.AnchorDate = &startAt
+	}
+	if params.Name != "" {
+		req.Metadata = map[string]interface{}{"name": params.Name}
+	}
+	if action, ok := params.Custom["failed_cycle_action"].(string); ok {
+		req.FailedCycleAction = action
+	}
+
+	return req
+}
+
+// mapToSubscription maps Xendit RecurringPlanResponse to unified Subscription
+func (m *Mapper) mapToSubscription(resp *RecurringPlanResponse, raw map[string]interface{}) *pg.Subscription {
+	if resp == nil {
+		return nil
+	}
+
+	sub := &pg.Subscription{
+		ID:          resp.ID,
+		ReferenceID: resp.ReferenceID,
+		Amount:      resp.Amount,
+		Status:      m.mapPlanStatus(resp.Status),
+		Raw:         raw,
+	}
+
+	if name, ok := resp.Metadata["name"].(string); ok {
+		sub.Name = name
+	}
+	if resp.Schedule != nil {
+		sub.Interval = pg.Interval(resp.Schedule.Interval)
+		sub.IntervalCount = resp.Schedule.IntervalCount
+	}
+	for _, action := range resp.Actions {
+		if action.Action == "AUTH" {
+			sub.ActionURL = action.URL
+		}
+	}
+	if resp.Created != nil {
+		sub.CreatedAt = *resp.Created
+	}
+
+	return sub
+}
+
+// mapCycleToPaymentStatus maps a Xendit recurring cycle to unified PaymentStatus
+func (m *Mapper) mapCycleToPaymentStatus(cycle *RecurringCycleResponse, raw map[string]interface{}) *pg.PaymentStatus {
+	if cycle == nil {
+		return nil
+	}
+
+	status := &pg.PaymentStatus{
+		TransactionID:  cycle.ID,
+		OrderID:        cycle.ReferenceID,
+		Status:         m.mapCycleStatus(cycle.Status),
+		Amount:         cycle.Amount,
+		SubscriptionID: cycle.PlanID,
+		Raw:            raw,
+	}
+
+	if status.Status == pg.StatusSuccess {
+		status.PaidAmount = cycle.Amount
+		status.PaidAt = cycle.Updated
+	}
+
+	return status
+}
+
+// mapPaymentMethodStatus maps Xendit payment method status to unified link status
+func (m *Mapper) mapPaymentMethodStatus(status PaymentMethodStatus) pg.LinkStatus {
+	switch status {
+	case PaymentMethodActive:
+		return pg.LinkActive
+	case PaymentMethodInactive:
+		return pg.LinkUnlinked
+	case PaymentMethodExpired:
+		return pg.LinkExpired
+	case PaymentMethodFailed:
+		return pg.LinkFailed
+	default:
+		return pg.LinkPending
+	}
+}
+
+// mapToPaymentMethodRequest maps unified LinkParams to Xendit CreatePaymentMethodRequest
+func (m *Mapper) mapToPaymentMethodRequest(params pg.LinkParams) *CreatePaymentMethodRequest {
+	_, code := m.mapPaymentType(params.PaymentType)
+
+	req := &CreatePaymentMethodRequest{
+		Type:        "EWALLET",
+		Reusability: "MULTIPLE_USE",
+		CustomerID:  params.Customer.ID,
+		EWallet: &PaymentMethodEWallet{
+			ChannelCode: EWalletCode(code),
+			ChannelProperties: &PaymentMethodChannelProperties{
+				SuccessReturnURL: params.RedirectURL,
+				FailureReturnURL: params.RedirectURL,
+				CancelReturnURL:  params.RedirectURL,
+			},
+		},
+	}
+
+	// OVO links are authorized on the customer phone
+	if params.PaymentType == pg.PaymentTypeOVO {
+		req.EWallet.ChannelProperties.MobileNumber = params.Customer.Phone
+	}
+
+	return req
+}
+
+// mapToLinkedAccount maps Xendit PaymentMethodResponse to unified LinkedAccount
+func (m *Mapper) mapToLinkedAccount(resp *PaymentMethodResponse, raw map[string]interface{}) *pg.LinkedAccount {
+	if resp == nil {
+		return nil
+	}
+
+	account := &pg.LinkedAccount{
+		ID:     resp.ID,
+		Status: m.mapPaymentMethodStatus(resp.Status),
+		Raw:    raw,
+	}
+
+	for _, action := range resp.Actions {
+		if action.Action == "AUTH" {
+			account.ActionURL = action.URL
+		}
+	}
+
+	if resp.EWallet != nil {
+		switch resp.EWallet.ChannelCode {
+		case EWalletOVO:
+			account.PaymentType = pg.PaymentTypeOVO
+		case EWalletDANA:
+			account.PaymentType = pg.PaymentTypeDANA
+		case EWalletShopeePay:
+			account.PaymentType = pg.PaymentTypeShopeePay
+		case EWalletGoPay:
+			account.PaymentType = pg.PaymentTypeGoPay
+		}
+
+		if acc := resp.EWallet.Account; acc != nil {
+			account.AccountName = acc.AccountDetails
+			if account.AccountName == "" {
+				account.AccountName = acc.Name
+			}
+			if acc.Balance != nil {
+				balance := int64(*acc.Balance)
+				account.Balance = &balance
+			}
+		}
+	}
+
+	return account
+}
+
+// mapPaylaterChargeStatus maps Xendit paylater charge status to unified status
+func (m *Mapper) mapPaylaterChargeStatus(status string) pg.Status {
+	switch status {
+	case "SUCCEEDED":
+		return pg.StatusSuccess
+	case "FAILED":
+		return pg.StatusFailed
+	case "VOIDED", "REFUNDED":
+		return pg.StatusCancelled
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapToPaylaterPlanRequest maps unified ChargeParams to Xendit CreatePaylaterPlanRequest
+func (m *Mapper) mapToPaylaterPlanRequest(params pg.ChargeParams) *CreatePaylaterPlanRequest {
+	_, code := m.mapPaymentType(params.PaymentType)
+
+	req := &CreatePaylaterPlanRequest{
+		CustomerID:  params.Customer.ID,
+		ChannelCode: PaylaterChannelCode(code),
+		Currency:    "IDR",
+		Amount:      float64(params.Amount),
+		OrderItems:  make([]*PaylaterOrderItem, len(params.Items)),
+	}
+
+	for i, item := range params.Items {
+		req.OrderItems[i] = &PaylaterOrderItem{
+			Type:          "PHYSICAL_PRODUCT",
+			ReferenceID:   item.ID,
+			Name:          item.Name,
+			NetUnitAmount: float64(item.Price),
+			Quantity:      item.Quantity,
+			URL:           item.URL,
+			Category:      item.Category,
+		}
+	}
+
+	return req
+}
+
+// mapToPaylaterChargeRequest maps unified ChargeParams to Xendit CreatePaylaterChargeRequest
+func (m *Mapper) mapToPaylaterChargeRequest(planID string, params pg.ChargeParams) *CreatePaylaterChargeRequest {
+	req := &CreatePaylaterChargeRequest{
+		PlanID:             planID,
+		ReferenceID:        params.OrderID,
+		CheckoutMethod:     "ONE_TIME_PAYMENT",
+		SuccessRedirectURL: params.ReturnURL,
+		FailureRedirectURL: params.ReturnURL,
+		Metadata:           params.Metadata,
+	}
+
+	if params.Paylater != nil && params.Paylater.FailureURL != "" {
+		req.FailureRedirectURL = params.Paylater.FailureURL
+	}
+
+	return req
+}
+
+// mapToInstallmentPlans maps Xendit paylater plan options to unified installment plans
+func (m *Mapper) mapToInstallmentPlans(options []*PaylaterPlanOption) []pg.InstallmentPlan {
+	plans := make([]pg.InstallmentPlan, 0, len(options))
+	for _, option := range options {
+		plans = append(plans, pg.InstallmentPlan{
+			Tenure:            option.TotalRecurrence,
+			InstallmentAmount: int64(option.InstallmentAmount),
+			DownPayment:       int64(option.DownpaymentAmount),
+			TotalAmount:       int64(option.TotalAmount),
+			InterestRate:      option.InterestRate,
+			Description:       option.Description,
+		})
+	}
+	return plans
+}
+
+// mapCheckoutActions maps Xendit checkout URLs to unified next actions
+// The desktop page is preferred over the mobile page as web checkout
+func (m *Mapper) mapCheckoutActions(desktopURL, mobileURL, deeplinkURL string) []pg.Action {
+	var actions []pg.Action
+
+	webURL := desktopURL
+	if webURL == "" {
+		webURL = mobileURL
+	}
+	if webURL != "" {
+		actions = append(actions, pg.Action{Type: pg.ActionRedirect, URL: webURL, Method: "GET"})
+	}
+	if deeplinkURL != "" {
+		actions = append(actions, pg.Action{Type: pg.ActionDeeplink, URL: deeplinkURL, Method: "GET"})
+	}
+
+	return actions
+}
+
+// mapToChargeResponseFromPaylater maps Xendit paylater plan and charge to unified ChargeResponse
+func (m *Mapper) mapToChargeResponseFromPaylater(plan *PaylaterPlanResponse, resp *PaylaterChargeResponse) *pg.ChargeResponse {
+	unified := &pg.ChargeResponse{
+		TransactionID:    resp.ID,
+		OrderID:          resp.ReferenceID,
+		Amount:           int64(resp.Amount),
+		Status:           m.mapPaylaterChargeStatus(resp.Status),
+		InstallmentPlans: m.mapToInstallmentPlans(plan.Options),
+	}
+
+	if resp.Actions != nil {
+		unified.NextActions = m.mapCheckoutActions(resp.Actions.DesktopWebCheckoutURL, resp.Actions.MobileWebCheckoutURL, resp.Actions.MobileDeeplinkCheckoutURL)
+		unified.PaymentURL = pg.PreferredURL(unified.NextActions, false)
+	}
+	if resp.Created != nil {
+		unified.CreatedAt = *resp.Created
+	}
+
+	return unified
+}
+
+// mapDirectDebitChannel maps a unified direct debit payment type to a Xendit channel code
+func (m *Mapper) mapDirectDebitChannel(pt pg.PaymentType) DirectDebitChannelCode {
+	switch pt {
+	case pg.PaymentTypeDDBRI:
+		return DirectDebitBRI
+	case pg.PaymentTypeDDMandiri:
+		return DirectDebitMandiri
+	case pg.PaymentTypeDDBCAOneKlik:
+		return DirectDebitBCAOneKlik
+	default:
+		return ""
+	}
+}
+
+// mapDirectDebitPaymentType maps a Xendit direct debit channel code to a unified payment type
+func (m *Mapper) mapDirectDebitPaymentType(code DirectDebitChannelCode) pg.PaymentType {
+	switch code {
+	case DirectDebitBRI:
+		return pg.PaymentTypeDDBRI
+	case DirectDebitMandiri:
+		return pg.PaymentTypeDDMandiri
+	case DirectDebitBCAOneKlik:
+		return pg.PaymentTypeDDBCAOneKlik
+	default:
+		return ""
+	}
+}
+
+// mapDirectDebitStatus maps a Xendit direct debit status to a unified status
+func (m *Mapper) mapDirectDebitStatus(status DirectDebitStatus) pg.Status {
+	switch status {
+	case DirectDebitCompleted:
+		return pg.StatusSuccess
+	case DirectDebitFailed:
+		return pg.StatusFailed
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapToLinkedAccountTokenRequest maps unified LinkParams to a Xendit linked account token request
+// BRI debit cards need the card last four digits and expiry in Custom["card_last_four"] and Custom["card_expiry"]
+func (m *Mapper) mapToLinkedAccountTokenRequest(params pg.LinkParams) *CreateLinkedAccountTokenRequest {
+	props := &LinkedAccountTokenProperties{
+		AccountMobileNumber: params.Customer.Phone,
+		AccountEmail:        params.Customer.Email,
+		SuccessRedirectURL:  params.RedirectURL,
+		FailureRedirectURL:  params.RedirectURL,
+	}
+
+	if v, ok := params.Custom["card_last_four"].(string); ok {
+		props.CardLastFour = v
+	}
+	if v, ok := params.Custom["card_expiry"].(string); ok {
+		props.CardExpiry = v
+	}
+
+	return &CreateLinkedAccountTokenRequest{
+		CustomerID:  params.Customer.ID,
+		ChannelCode: m.mapDirectDebitChannel(params.PaymentType),
+		Properties:  props,
+	}
+}
+
+// mapToPendingLinkedAccount maps a Xendit linked account token to a pending unified LinkedAccount
+// Channels without an authorizer URL send an OTP to the customer instead
+func (m *Mapper) mapToPendingLinkedAccount(resp *LinkedAccountTokenResponse, raw map[string]interface{}) *pg.LinkedAccount {
+	account := &pg.LinkedAccount{
+		ID:          resp.ID,
+		PaymentType: m.mapDirectDebitPaymentType(resp.ChannelCode),
+		Status:      pg.LinkPending,
+		ActionURL:   resp.AuthorizerURL,
+		Raw:         raw,
+	}
+
+	if resp.AuthorizerURL != "" {
+		account.NextActions = []pg.Action{{Type: pg.ActionRedirect, URL: resp.AuthorizerURL, Method: "GET"}}
+	} else {
+		account.NextActions = []pg.Action{{Type: pg.ActionValidateOTP}}
+	}
+
+	return account
+}
+
+// mapToDebitPaymentMethodRequest maps a Xendit linked account to a direct debit payment method request
+func (m *Mapper) mapToDebitPaymentMethodRequest(customerID string, account *LinkedAccountResponse) *CreateDebitPaymentMethodRequest {
+	return &CreateDebitPaymentMethodRequest{
+		CustomerID: customerID,
+		Type:       account.Type,
+		Properties: &LinkedAccountProperties{ID: account.ID},
+	}
+}
+
+// mapToDirectDebitLinkedAccount maps a Xendit direct debit payment method to an active unified LinkedAccount
+func (m *Mapper) mapToDirectDebitLinkedAccount(resp *DebitPaymentMethodResponse, account *LinkedAccountResponse, raw map[string]interface{}) *pg.LinkedAccount {
+	linked := &pg.LinkedAccount{
+		ID:          resp.ID,
+		PaymentType: m.mapDirectDebitPaymentType(account.ChannelCode),
+		Status:      pg.LinkActive,
+		Raw:         raw,
+	}
+
+	if resp.Status != "" && resp.Status != "ACTIVE" {
+		linked.Status = pg.LinkFailed
+	}
+
+	if props := account.Properties; props != nil {
+		linked.AccountName = props.Description
+		if props.CardLastFour != "" {
+			linked.AccountName = props.CardLastFour
+		}
+	}
+
+	return linked
+}
+
+// mapToDirectDebitRequest maps unified ChargeParams to a Xendit direct debit payment request
+// The OTP step can be skipped with Custom["enable_otp"] = false where the bank allows it
+func (m *Mapper) mapToDirectDebitRequest(params pg.ChargeParams) *CreateDirectDebitRequest {
+	req := &CreateDirectDebitRequest{
+		ReferenceID:        params.OrderID,
+		PaymentMethodID:    params.DirectDebit.AccountLinkID,
+		Currency:           "IDR",
+		Amount:             float64(params.Amount),
+		CallbackURL:        params.CallbackURL,
+		EnableOTP:          true,
+		Description:        params.Description,
+		SuccessRedirectURL: params.ReturnURL,
+		FailureRedirectURL: params.ReturnURL,
+		Metadata:           params.Metadata,
+	}
+
+	if v, ok := params.Custom["enable_otp"].(bool); ok {
+		req.EnableOTP = v
+	}
+
+	for _, item := range params.LineItems() {
+		req.Basket = append(req.Basket, &DirectDebitBasketItem{
+			ReferenceID: item.ID,
+			Name:        item.Name,
+			Market:      "ID",
+			Type:        item.Category,
+			Quantity:    item.Quantity,
+			Price:       float64(item.Price),
+		})
+	}
+
+	return req
+}
+
+// mapToChargeResponseFromDirectDebit maps a Xendit direct debit payment to unified ChargeResponse
+func (m *Mapper) mapToChargeResponseFromDirectDebit(resp *DirectDebitResponse, raw map[string]interface{}) *pg.ChargeResponse {
+	unified := &pg.ChargeResponse{
+		TransactionID: resp.ID,
+		OrderID:       resp.ReferenceID,
+		Amount:        int64(resp.Amount),
+		Status:        m.mapDirectDebitStatus(resp.Status),
+		PaymentURL:    resp.CheckoutURL,
+		Raw:           raw,
+	}
+
+	switch {
+	case resp.RequiredAction == "VALIDATE_OTP" || (resp.IsOTPRequired && resp.Status == DirectDebitPending && resp.CheckoutURL == ""):
+		unified.NextActions = []pg.Action{{Type: pg.ActionValidateOTP}}
+	case resp.CheckoutURL != "":
+		unified.NextActions = []pg.Action{{Type: pg.ActionRedirect, URL: resp.CheckoutURL, Method: "GET"}}
+	}
+
+	if resp.OTPExpirationTimestamp != nil {
+		unified.ExpiryTime = *resp.OTPExpirationTimestamp
+	}
+	if resp.Created != nil {
+		unified.CreatedAt = *resp.Created
+	}
+	if resp.Updated != nil {
+		unified.UpdatedAt = *resp.Updated
+	}
+
+	return unified
+}
+
+// mapCardChargeStatus maps a Xendit card charge status to a unified status
+func (m *Mapper) mapCardChargeStatus(status string) pg.Status {
+	switch status {
+	case "CAPTURED":
+		return pg.StatusSuccess
+	case "FAILED":
+		return pg.StatusFailed
+	default:
+		return pg.StatusPending
+	}
+}
+
+// mapToCardChargeRequest maps unified ChargeParams to a Xendit card charge request
+// CreditCard.CardNumber is the Xendit card token, the 3DS authentication ID is read from Custom["authentication_id"]
+func (m *Mapper) mapToCardChargeRequest(params pg.ChargeParams) *CreateCardChargeRequest {
+	req := &CreateCardChargeRequest{
+		TokenID:    params.CreditCard.CardNumber,
+		ExternalID: params.OrderID,
+		Amount:     float64(params.Amount),
+		Capture:    true,
+		Descriptor: params.Description,
+		Metadata:   params.Metadata,
+	}
+
+	if v, ok := params.Custom["authentication_id"].(string); ok {
+		req.AuthenticationID = v
+	}
+
+	if params.CreditCard.IsInstallment() {
+		req.Installment = &CardInstallment{
+			Count:    params.CreditCard.InstallmentTerm,
+			Interval: "month",
+		}
+	}
+
+	return req
+}
+
+// mapToChargeResponseFromCard maps a Xendit card charge to unified ChargeResponse
+func (m *Mapper) mapToChargeResponseFromCard(resp *CardChargeResponse, raw map[string]interface{}) *pg.ChargeResponse {
+	unified := &pg.ChargeResponse{
+		TransactionID: resp.ID,
+		OrderID:       resp.ExternalID,
+		Amount:        int64(resp.AuthorizedAmount),
+		Status:        m.mapCardChargeStatus(resp.Status),
+		Raw:           raw,
+	}
+
+	if resp.Created != nil {
+		unified.CreatedAt = *resp.Created
+	}
+
+	return unified
+}
+
+// mapToCardInstallmentPlans maps Xendit issuer installment options to unified plans
+// Issuers with a minimum above amount are left out
+func (m *Mapper) mapToCardInstallmentPlans(options []*CardInstallmentOption, amount int64) []pg.InstallmentPlan {
+	plans := make([]pg.InstallmentPlan, 0)
+	for _, option := range options {
+		if option.MinimumAmount > float64(amount) {
+			continue
+		}
+		plans = append(plans, pg.NewInstallmentPlans(option.Issuer, amount, option.Terms)...)
+	}
+	return plans
+}
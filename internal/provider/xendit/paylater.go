@@ -0,0 +1,63 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// PayLater API endpoints
+	paylaterPlansUri   = "/paylater/plans"
+	paylaterChargesUri = "/paylater/charges"
+)
+
+// createPaylaterCharge initiates a paylater plan and charges it
+// The plan lists the installment options, the customer picks one on the checkout page
+func (x *xendit) createPaylaterCharge(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	planReq := x.mapper.mapToPaylaterPlanRequest(params)
+
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+paylaterPlansUri, planReq, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var plan PaylaterPlanResponse
+	if err := json.Unmarshal(responseBody, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if params.Paylater != nil && params.Paylater.Tenure > 0 && !hasTenure(plan.Options, params.Paylater.Tenure) {
+		return nil, pg.NewFieldError("Paylater.Tenure", fmt.Sprintf("tenure %d is not offered by %s", params.Paylater.Tenure, plan.ChannelCode))
+	}
+
+	chargeReq := x.mapper.mapToPaylaterChargeRequest(plan.ID, params)
+
+	responseBody, err = x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+paylaterChargesUri, pg.WithCustom(chargeReq, params.Custom, customOptions...), params.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PaylaterChargeResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	unified := x.mapper.mapToChargeResponseFromPaylater(&plan, &resp)
+	_ = json.Unmarshal(responseBody, &unified.Raw)
+
+	return unified, nil
+}
+
+// hasTenure reports whether a plan offers a monthly tenure
+func hasTenure(options []*PaylaterPlanOption, tenure int) bool {
+	for _, option := range options {
+		if option.TotalRecurrence == tenure {
+			return true
+		}
+	}
+	return false
+}
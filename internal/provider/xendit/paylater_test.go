@@ -0,0 +1,113 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+const testPaylaterPlanResponse = `{
+	"id": "plpl-123",
+	"customer_id": "cust-123",
+	"channel_code": "ID_KREDIVO",
+	"currency": "IDR",
+	"amount": 300000,
+	"options": [
+		{"downpayment_amount": 0, "installment_amount": 300000, "interest_rate": 0, "total_amount": 300000, "interval": "MONTH", "interval_count": 1, "total_recurrence": 1, "description": "30 days"},
+		{"downpayment_amount": 0, "installment_amount": 105000, "interest_rate": 2.6, "total_amount": 315000, "interval": "MONTH", "interval_count": 1, "total_recurrence": 3, "description": "3 months"}
+	]
+}`
+
+func paylaterChargeParams(tenure int) pg.ChargeParams {
+	return pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      300000,
+		PaymentType: pg.PaymentTypeKredivo,
+		Customer:    pg.Customer{ID: "cust-123", Email: "john@example.com"},
+		Items:       []pg.Item{{ID: "ITEM-001", Name: "Phone", Price: 300000, Quantity: 1, Category: "Electronics"}},
+		ReturnURL:   "https://example.com/return",
+		Paylater:    &pg.PaylaterParams{Tenure: tenure},
+	}
+}
+
+func TestXendit_CreateCharge_Paylater(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case paylaterPlansUri:
+			var req CreatePaylaterPlanRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			if req.ChannelCode != PaylaterKredivo || req.CustomerID != "cust-123" {
+				t.Errorf("plan request = %+v, want ID_KREDIVO for cust-123", req)
+			}
+			if len(req.OrderItems) != 1 || req.OrderItems[0].NetUnitAmount != 300000 {
+				t.Errorf("OrderItems = %+v, want one item", req.OrderItems)
+			}
+			w.Write([]byte(testPaylaterPlanResponse))
+		case paylaterChargesUri:
+			var req CreatePaylaterChargeRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			if req.PlanID != "plpl-123" || req.ReferenceID != "ORDER-001" {
+				t.Errorf("charge request = %+v, want plpl-123 for ORDER-001", req)
+			}
+			w.Write([]byte(`{
+				"id": "plc-123",
+				"plan_id": "plpl-123",
+				"reference_id": "ORDER-001",
+				"channel_code": "ID_KREDIVO",
+				"amount": 300000,
+				"status": "PENDING",
+				"actions": {"desktop_web_checkout_url": "https://kredivo.com/checkout/123"}
+			}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	resp, err := x.CreateCharge(context.Background(), paylaterChargeParams(3))
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+
+	if resp.TransactionID != "plc-123" {
+		t.Errorf("TransactionID = %v, want plc-123", resp.TransactionID)
+	}
+	if resp.PaymentURL != "https://kredivo.com/checkout/123" {
+		t.Errorf("PaymentURL = %v, want checkout URL", resp.PaymentURL)
+	}
+	if len(resp.InstallmentPlans) != 2 {
+		t.Fatalf("len(InstallmentPlans) = %v, want 2", len(resp.InstallmentPlans))
+	}
+	if plan := resp.InstallmentPlans[1]; plan.Tenure != 3 || plan.InstallmentAmount != 105000 || plan.TotalAmount != 315000 {
+		t.Errorf("InstallmentPlans[1] = %+v, want 3 x 105000", plan)
+	}
+}
+
+func TestXendit_CreateCharge_Paylater_UnknownTenure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != paylaterPlansUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testPaylaterPlanResponse))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	if _, err := x.CreateCharge(context.Background(), paylaterChargeParams(12)); err == nil {
+		t.Error("expected error for tenure 12, got nil")
+	}
+}
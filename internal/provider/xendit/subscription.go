@@ -0,0 +1,238 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// Recurring API endpoints
+	recurringPlansUri       = "/recurring/plans"
+	recurringPlanUri        = "/recurring/plans/%s"
+	recurringDeactivateUri  = "/recurring/plans/%s/deactivate"
+	recurringCyclesUri      = "/recurring/plans/%s/cycles"
+	recurringScheduleUri    = "/recurring/schedules/%s"
+	recurringCallbackPrefix = "recurring."
+)
+
+// CreateSubscription creates a Xendit recurring plan
+// Customer.ID must be a Xendit customer ID and PaymentToken a payment method ID
+func (x *xendit) CreateSubscription(ctx context.Context, params pg.SubscriptionParams) (*pg.Subscription, error) {
+	req := x.mapper.mapToRecurringPlanRequest(params)
+
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, x.getBaseURL()+recurringPlansUri, req, params.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return x.parseSubscription(responseBody)
+}
+
+// GetSubscription retrieves a recurring plan
+func (x *xendit) GetSubscription(ctx context.Context, id string) (*pg.Subscription, error) {
+	responseBody, err := x.sendRequest(ctx, http.MethodGet, fmt.Sprintf(x.getBaseURL()+recurringPlanUri, id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return x.parseSubscription(responseBody)
+}
+
+// UpdateSubscription updates the amount and payment method of a plan,
+// and its schedule when an interval is given
+func (x *xendit) UpdateSubscription(ctx context.Context, id string, params pg.SubscriptionUpdateParams) (*pg.Subscription, error) {
+	var sub *pg.Subscription
+
+	if params.Amount > 0 || params.PaymentToken != "" || params.Name != "" {
+		req := &UpdateRecurringPlanRequest{
+			Amount:      params.Amount,
+			Description: params.Name,
+		}
+		if params.Amount > 0 {
+			req.Currency = "IDR"
+		}
+		if params.PaymentToken != "" {
+			req.PaymentMethods = []*RecurringPaymentMethod{
+				{PaymentMethodID: params.PaymentToken, Rank: 1},
+			}
+		}
+
+		responseBody, err := x.sendRequest(ctx, http.MethodPatch, fmt.Sprintf(x.getBaseURL()+recurringPlanUri, id), req, "")
+		if err != nil {
+			return nil, err
+		}
+		if sub, err = x.parseSubscription(responseBody); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Interval == "" {
+		if sub == nil {
+			return x.GetSubscription(ctx, id)
+		}
+		return sub, nil
+	}
+
+	// The schedule is a separate resource, look up its ID on the plan
+	responseBody, err := x.sendRequest(ctx, http.MethodGet, fmt.Sprintf(x.getBaseURL()+recurringPlanUri, id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var plan RecurringPlanResponse
+	if err := json.Unmarshal(responseBody, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	intervalCount := params.IntervalCount
+	if intervalCount <= 0 {
+		intervalCount = 1
+	}
+
+	scheduleReq := &UpdateRecurringScheduleRequest{
+		Interval:      string(params.Interval),
+		IntervalCount: intervalCount,
+	}
+	if _, err := x.sendRequest(ctx, http.MethodPatch, fmt.Sprintf(x.getBaseURL()+recurringScheduleUri, plan.ScheduleID), scheduleReq, ""); err != nil {
+		return nil, err
+	}
+
+	return x.GetSubscription(ctx, id)
+}
+
+// PauseSubscription is not supported by Xendit recurring plans
+func (x *xendit) PauseSubscription(ctx context.Context, id string) (*pg.Subscription, error) {
+	return nil, pg.ErrUnimplemented
+}
+
+// ResumeSubscription is not supported by Xendit recurring plans
+func (x *xendit) ResumeSubscription(ctx context.Context, id string) (*pg.Subscription, error) {
+	return nil, pg.ErrUnimplemented
+}
+
+// CancelSubscription deactivates a recurring plan
+func (x *xendit) CancelSubscription(ctx context.Context, id string) (*pg.Subscription, error) {
+	responseBody, err := x.sendRequest(ctx, http.MethodPost, fmt.Sprintf(x.getBaseURL()+recurringDeactivateUri, id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return x.parseSubscription(responseBody)
+}
+
+// ListSubscriptionPayments returns the cycles of a recurring plan
+func (x *xendit) ListSubscriptionPayments(ctx context.Context, id string) ([]*pg.PaymentStatus, error) {
+	responseBody, err := x.sendRequest(ctx, http.MethodGet, fmt.Sprintf(x.getBaseURL()+recurringCyclesUri, id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var list RecurringCycleList
+	if err := json.Unmarshal(responseBody, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	payments := make([]*pg.PaymentStatus, len(list.Data))
+	for i, cycle := range list.Data {
+		payments[i] = x.mapper.mapCycleToPaymentStatus(cycle, nil)
+	}
+
+	return payments, nil
+}
+
+// parseSubscription parses a Xendit recurring plan response
+func (x *xendit) parseSubscription(responseBody []byte) (*pg.Subscription, error) {
+	var resp RecurringPlanResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var raw map[string]interface{}
+	_ = json.Unmarshal(responseBody, &raw)
+
+	return x.mapper.mapToSubscription(&resp, raw), nil
+}
+
+// isRecurringCallback reports whether a JSON callback is a recurring.* event
+func isRecurringCallback(data map[string]interface{}) bool {
+	event, _ := data["event"].(string)
+	return strings.HasPrefix(event, recurringCallbackPrefix)
+}
+
+// parseRecurringWebhook parses recurring.plan.* and recurring.cycle.* callbacks
+// Cycle callbacks are reported as payment events of the cycle's charge
+func (x *xendit) parseRecurringWebhook(body []byte, raw map[string]interface{}) (*pg.WebhookEvent, error) {
+	var callback RecurringCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	timestamp := time.Now()
+	if callback.Created != nil {
+		timestamp = *callback.Created
+	}
+
+	if strings.HasPrefix(callback.Event, "recurring.plan.") {
+		var plan RecurringPlanResponse
+		if err := json.Unmarshal(callback.Data, &plan); err != nil || plan.ID == "" {
+			return nil, pg.ErrInvalidPayload
+		}
+
+		eventType := pg.EventSubscriptionActivated
+		switch {
+		case plan.Status == PlanPaused || callback.Event == "recurring.plan.paused":
+			eventType = pg.EventSubscriptionPaused
+		case plan.Status == PlanInactive:
+			eventType = pg.EventSubscriptionCancelled
+		}
+
+		return &pg.WebhookEvent{
+			OrderID:        plan.ReferenceID,
+			TransactionID:  plan.ID,
+			Status:         pg.StatusPending,
+			Amount:         plan.Amount,
+			EventType:      eventType,
+			Timestamp:      timestamp,
+			SubscriptionID: plan.ID,
+			Raw:            raw,
+		}, nil
+	}
+
+	var cycle RecurringCycleResponse
+	if err := json.Unmarshal(callback.Data, &cycle); err != nil || cycle.ID == "" {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	status := x.mapper.mapCycleToPaymentStatus(&cycle, raw)
+
+	return &pg.WebhookEvent{
+		OrderID:        status.OrderID,
+		TransactionID:  status.TransactionID,
+		Status:         status.Status,
+		Amount:         status.Amount,
+		EventType:      cycleEventType(status.Status),
+		Timestamp:      timestamp,
+		SubscriptionID: status.SubscriptionID,
+		Raw:            raw,
+	}, nil
+}
+
+// cycleEventType maps the unified status of a cycle to its payment event type
+func cycleEventType(status pg.Status) string {
+	switch status {
+	case pg.StatusSuccess:
+		return pg.EventPaymentCompleted
+	case pg.StatusFailed:
+		return pg.EventPaymentFailed
+	case pg.StatusCancelled:
+		return pg.EventPaymentCancelled
+	default:
+		return pg.EventPaymentPending
+	}
+}
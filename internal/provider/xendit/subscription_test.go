@@ -0,0 +1,245 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+func TestXendit_CreateSubscription(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != recurringPlansUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req CreateRecurringPlanRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.CustomerID != "cust-123" {
+			t.Errorf("CustomerID = %v, want cust-123", req.CustomerID)
+		}
+		if len(req.PaymentMethods) != 1 || req.PaymentMethods[0].PaymentMethodID != "pm-123" {
+			t.Errorf("PaymentMethods = %+v, want pm-123", req.PaymentMethods)
+		}
+		if req.Schedule.Interval != "MONTH" || req.Schedule.IntervalCount != 1 {
+			t.Errorf("Schedule = %+v, want 1 MONTH", req.Schedule)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "repl-123",
+			"reference_id": "SUB-001",
+			"customer_id": "cust-123",
+			"currency": "IDR",
+			"amount": 50000,
+			"status": "REQUIRES_ACTION",
+			"schedule_id": "resc-123",
+			"schedule": {"id": "resc-123", "interval": "MONTH", "interval_count": 1},
+			"actions": [{"action": "AUTH", "url_type": "WEB", "url": "https://linking.xendit.co/auth", "method": "GET"}]
+		}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	sub, err := x.CreateSubscription(context.Background(), pg.SubscriptionParams{
+		ReferenceID:  "SUB-001",
+		Customer:     pg.Customer{ID: "cust-123"},
+		Amount:       50000,
+		PaymentToken: "pm-123",
+		Interval:     pg.IntervalMonth,
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+
+	if sub.ID != "repl-123" {
+		t.Errorf("ID = %v, want repl-123", sub.ID)
+	}
+	if sub.Status != pg.SubscriptionPending {
+		t.Errorf("Status = %v, want %v", sub.Status, pg.SubscriptionPending)
+	}
+	if sub.ActionURL != "https://linking.xendit.co/auth" {
+		t.Errorf("ActionURL = %v, want auth URL", sub.ActionURL)
+	}
+	if sub.Interval != pg.IntervalMonth {
+		t.Errorf("Interval = %v, want %v", sub.Interval, pg.IntervalMonth)
+	}
+}
+
+func TestXendit_UpdateSubscription_Schedule(t *testing.T) {
+	var scheduleUpdated bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPatch && r.URL.Path == "/recurring/schedules/resc-123":
+			var req UpdateRecurringScheduleRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.Interval != "WEEK" || req.IntervalCount != 2 {
+				t.Errorf("schedule request = %+v, want 2 WEEK", req)
+			}
+			scheduleUpdated = true
+			w.Write([]byte(`{"id": "resc-123"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/recurring/plans/repl-123":
+			interval := "MONTH"
+			if scheduleUpdated {
+				interval = "WEEK"
+			}
+			w.Write([]byte(`{"id":"repl-123","status":"ACTIVE","schedule_id":"resc-123","schedule":{"interval":"` + interval + `","interval_count":2}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	sub, err := x.UpdateSubscription(context.Background(), "repl-123", pg.SubscriptionUpdateParams{
+		Interval:      pg.IntervalWeek,
+		IntervalCount: 2,
+	})
+	if err != nil {
+		t.Fatalf("UpdateSubscription() error = %v", err)
+	}
+
+	if !scheduleUpdated {
+		t.Error("schedule was not updated")
+	}
+	if sub.Interval != pg.IntervalWeek {
+		t.Errorf("Interval = %v, want %v", sub.Interval, pg.IntervalWeek)
+	}
+}
+
+func TestXendit_PauseSubscription_Unsupported(t *testing.T) {
+	x := newTestProvider(t, nil)
+
+	if _, err := x.PauseSubscription(context.Background(), "repl-123"); !errors.Is(err, pg.ErrUnimplemented) {
+		t.Errorf("PauseSubscription() error = %v, want %v", err, pg.ErrUnimplemented)
+	}
+	if _, err := x.ResumeSubscription(context.Background(), "repl-123"); !errors.Is(err, pg.ErrUnimplemented) {
+		t.Errorf("ResumeSubscription() error = %v, want %v", err, pg.ErrUnimplemented)
+	}
+}
+
+func TestXendit_ListSubscriptionPayments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/recurring/plans/repl-123/cycles" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"data": [
+				{"id": "recy-1", "plan_id": "repl-123", "reference_id": "SUB-001", "status": "SUCCEEDED", "amount": 50000, "cycle_number": 1},
+				{"id": "recy-2", "plan_id": "repl-123", "reference_id": "SUB-001", "status": "SCHEDULED", "amount": 50000, "cycle_number": 2}
+			],
+			"has_more": false
+		}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	payments, err := x.ListSubscriptionPayments(context.Background(), "repl-123")
+	if err != nil {
+		t.Fatalf("ListSubscriptionPayments() error = %v", err)
+	}
+
+	if len(payments) != 2 {
+		t.Fatalf("len(payments) = %v, want 2", len(payments))
+	}
+	if payments[0].Status != pg.StatusSuccess || payments[0].PaidAmount != 50000 {
+		t.Errorf("first payment = %+v, want paid", payments[0])
+	}
+	if payments[1].Status != pg.StatusPending {
+		t.Errorf("Status = %v, want %v", payments[1].Status, pg.StatusPending)
+	}
+	if payments[1].SubscriptionID != "repl-123" {
+		t.Errorf("SubscriptionID = %v, want repl-123", payments[1].SubscriptionID)
+	}
+}
+
+func TestXendit_ParseWebhook_Recurring(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantEvent  string
+		wantStatus pg.Status
+		wantTxnID  string
+	}{
+		{
+			name:       "cycle succeeded",
+			body:       `{"event":"recurring.cycle.succeeded","created":"2024-02-01T10:00:00Z","data":{"id":"recy-1","plan_id":"repl-123","reference_id":"SUB-001","status":"SUCCEEDED","amount":50000}}`,
+			wantEvent:  pg.EventPaymentCompleted,
+			wantStatus: pg.StatusSuccess,
+			wantTxnID:  "recy-1",
+		},
+		{
+			name:       "cycle failed",
+			body:       `{"event":"recurring.cycle.failed","data":{"id":"recy-2","plan_id":"repl-123","reference_id":"SUB-001","status":"FAILED","amount":50000}}`,
+			wantEvent:  pg.EventPaymentFailed,
+			wantStatus: pg.StatusFailed,
+			wantTxnID:  "recy-2",
+		},
+		{
+			name:       "plan activated",
+			body:       `{"event":"recurring.plan.activated","data":{"id":"repl-123","reference_id":"SUB-001","status":"ACTIVE","amount":50000}}`,
+			wantEvent:  pg.EventSubscriptionActivated,
+			wantStatus: pg.StatusPending,
+			wantTxnID:  "repl-123",
+		},
+		{
+			name:       "plan paused",
+			body:       `{"event":"recurring.plan.paused","data":{"id":"repl-123","reference_id":"SUB-001","status":"PAUSED","amount":50000}}`,
+			wantEvent:  pg.EventSubscriptionPaused,
+			wantStatus: pg.StatusPending,
+			wantTxnID:  "repl-123",
+		},
+		{
+			name:       "plan inactivated",
+			body:       `{"event":"recurring.plan.inactivated","data":{"id":"repl-123","reference_id":"SUB-001","status":"INACTIVE","amount":50000}}`,
+			wantEvent:  pg.EventSubscriptionCancelled,
+			wantStatus: pg.StatusPending,
+			wantTxnID:  "repl-123",
+		},
+	}
+
+	x := newTestProvider(t, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhook", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			event, err := x.ParseWebhook(req)
+			if err != nil {
+				t.Fatalf("ParseWebhook() error = %v", err)
+			}
+
+			if event.EventType != tt.wantEvent {
+				t.Errorf("EventType = %v, want %v", event.EventType, tt.wantEvent)
+			}
+			if event.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", event.Status, tt.wantStatus)
+			}
+			if event.TransactionID != tt.wantTxnID {
+				t.Errorf("TransactionID = %v, want %v", event.TransactionID, tt.wantTxnID)
+			}
+			if event.SubscriptionID != "repl-123" {
+				t.Errorf("SubscriptionID = %v, want repl-123", event.SubscriptionID)
+			}
+			if event.OrderID != "SUB-001" {
+				t.Errorf("OrderID = %v, want SUB-001", event.OrderID)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package xendit
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // PaymentStatus represents Xendit payment status
 type PaymentStatus string
@@ -12,6 +15,10 @@ const (
 	StatusPaid PaymentStatus = "PAID"
 	// StatusFailed means payment failed
 	StatusFailed PaymentStatus = "FAILED"
+	// StatusSettled means a paid invoice has been settled to the balance
+	StatusSettled PaymentStatus = "SETTLED"
+	// StatusExpired means the invoice expired before it was paid
+	StatusExpired PaymentStatus = "EXPIRED"
 )
 
 // PaymentChannel represents Xendit payment channels
@@ -98,6 +105,7 @@ type CreateInvoiceRequest struct {
 	ShouldSendEmail   bool                `json:"should_send_email,omitempty"`
 	ForUserID         string              `json:"for_user_id,omitempty"`
 	Platform          *Platform           `json:"platform,omitempty"`
+	Metadata          map[string]string   `json:"metadata,omitempty"`
 }
 
 // PaymentMethod represents payment method details
@@ -110,9 +118,23 @@ type PaymentMethod struct {
 // CustomerDetail for Xendit
 type CustomerDetail struct {
 	GivenNames string `json:"given_names,omitempty"`
+	Surname    string `json:"surname,omitempty"`
 	Email      string `json:"email,omitempty"`
 	MobileNumber string `json:"mobile_number,omitempty"`
 	CustomerID string `json:"customer_id,omitempty"`
+	Addresses  []*CustomerAddress `json:"addresses,omitempty"`
+}
+
+// CustomerAddress is an address of CustomerDetail
+type CustomerAddress struct {
+	Category    string `json:"category,omitempty"`
+	StreetLine1 string `json:"street_line1,omitempty"`
+	StreetLine2 string `json:"street_line2,omitempty"`
+	City        string `json:"city,omitempty"`
+	State       string `json:"state,omitempty"`
+	PostalCode  string `json:"postal_code,omitempty"`
+	Country     string `json:"country,omitempty"`
+	IsPrimary   bool   `json:"is_primary,omitempty"`
 }
 
 // CustomerNotification for notifications
@@ -224,6 +246,28 @@ type VAResponse struct {
 	Payment           *PaymentDetails  `json:"payment,omitempty"`
 }
 
+// UpdateVARequest for Xendit Update Fixed Virtual Account
+type UpdateVARequest struct {
+	ExpectedAmount float64    `json:"expected_amount,omitempty"`
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+	IsSingleUse    *bool      `json:"is_single_use,omitempty"`
+	Description    string     `json:"description,omitempty"`
+}
+
+// VAPaymentCallback is sent by Xendit for every payment into a fixed virtual account
+type VAPaymentCallback struct {
+	ID                       string     `json:"id"`
+	PaymentID                string     `json:"payment_id"`
+	CallbackVirtualAccountID string     `json:"callback_virtual_account_id"`
+	ExternalID               string     `json:"external_id"`
+	BankCode                 BankCode   `json:"bank_code"`
+	MerchantCode             string     `json:"merchant_code"`
+	AccountNumber            string     `json:"account_number"`
+	Amount                   float64    `json:"amount"`
+	SenderName               string     `json:"sender_name,omitempty"`
+	TransactionTimestamp     *time.Time `json:"transaction_timestamp,omitempty"`
+}
+
 // CreateEWalletRequest for creating e-wallet payment
 type CreateEWalletRequest struct {
 	ExternalID       string                `json:"external_id"`
@@ -235,6 +279,7 @@ type CreateEWalletRequest struct {
 	BusinessID       string                `json:"batch_id,omitempty"`
 	Currency         string                `json:"currency,omitempty"`
 	ChannelProperties *ChannelProperties    `json:"channel_properties,omitempty"`
+	PaymentMethodID  string                `json:"payment_method_id,omitempty"`
 	Metadata         map[string]string     `json:"metadata,omitempty"`
 }
 
@@ -246,6 +291,14 @@ type ChannelProperties struct {
 	 RedeemPoints      bool   `json:"redeem_points,omitempty"`
 }
 
+// EWalletActions are the checkout URLs of an e-wallet charge
+type EWalletActions struct {
+	DesktopWebCheckoutURL     string `json:"desktop_web_checkout_url,omitempty"`
+	MobileWebCheckoutURL      string `json:"mobile_web_checkout_url,omitempty"`
+	MobileDeeplinkCheckoutURL string `json:"mobile_deeplink_checkout_url,omitempty"`
+	QRCheckoutString          string `json:"qr_checkout_string,omitempty"`
+}
+
 // EWalletResponse from Xendit
 type EWalletResponse struct {
 	ID                string              `json:"id,omitempty"`
@@ -256,7 +309,589 @@ type EWalletResponse struct {
 	CallbackURL       string              `json:"callback_url,omitempty"`
 	PaymentURL        string              `json:"payment_url,omitempty"`
 	RedirectURL       string              `json:"redirect_url,omitempty"`
+	QRString          string              `json:"qr_string,omitempty"`
+	Actions           *EWalletActions     `json:"actions,omitempty"`
 	Created           *time.Time          `json:"created,omitempty"`
 	Updated           *time.Time          `json:"updated,omitempty"`
 	Metadata          map[string]string   `json:"metadata,omitempty"`
 }
+
+// DisbursementStatus represents Xendit disbursement status
+type DisbursementStatus string
+
+const (
+	// DisbursementPending means the disbursement is being processed
+	DisbursementPending DisbursementStatus = "PENDING"
+	// DisbursementCompleted means the money has been sent
+	DisbursementCompleted DisbursementStatus = "COMPLETED"
+	// DisbursementFailed means the disbursement failed
+	DisbursementFailed DisbursementStatus = "FAILED"
+)
+
+// BatchDisbursementStatus represents Xendit batch disbursement status
+type BatchDisbursementStatus string
+
+const (
+	// BatchNeedsApproval means the batch is waiting for approval in the dashboard
+	BatchNeedsApproval BatchDisbursementStatus = "NEEDS_APPROVAL"
+	// BatchApproved means the batch is approved and being processed
+	BatchApproved BatchDisbursementStatus = "APPROVED"
+	// BatchUploading means the batch is being uploaded
+	BatchUploading BatchDisbursementStatus = "UPLOADING"
+	// BatchCompleted means every disbursement of the batch has been processed
+	BatchCompleted BatchDisbursementStatus = "COMPLETED"
+	// BatchDeleted means the batch was deleted before approval
+	BatchDeleted BatchDisbursementStatus = "DELETED"
+)
+
+// CreateDisbursementRequest for Xendit Disbursement API
+type CreateDisbursementRequest struct {
+	ExternalID        string   `json:"external_id"`
+	Amount            int64    `json:"amount"`
+	BankCode          string   `json:"bank_code"`
+	AccountHolderName string   `json:"account_holder_name"`
+	AccountNumber     string   `json:"account_number"`
+	Description       string   `json:"description"`
+	EmailTo           []string `json:"email_to,omitempty"`
+}
+
+// DisbursementResponse from Xendit Disbursement API and disbursement callbacks
+type DisbursementResponse struct {
+	ID                      string             `json:"id"`
+	UserID                  string             `json:"user_id"`
+	ExternalID              string             `json:"external_id"`
+	Amount                  int64              `json:"amount"`
+	BankCode                string             `json:"bank_code"`
+	AccountHolderName       string             `json:"account_holder_name"`
+	AccountNumber           string             `json:"account_number,omitempty"`
+	DisbursementDescription string             `json:"disbursement_description"`
+	Status                  DisbursementStatus `json:"status"`
+	FailureCode             string             `json:"failure_code,omitempty"`
+	Created                 *time.Time         `json:"created,omitempty"`
+	Updated                 *time.Time         `json:"updated,omitempty"`
+}
+
+// BatchDisbursementItem is a disbursement of a Xendit batch disbursement
+type BatchDisbursementItem struct {
+	Amount            int64    `json:"amount"`
+	BankCode          string   `json:"bank_code"`
+	BankAccountName   string   `json:"bank_account_name"`
+	BankAccountNumber string   `json:"bank_account_number"`
+	Description       string   `json:"description"`
+	ExternalID        string   `json:"external_id"`
+	EmailTo           []string `json:"email_to,omitempty"`
+}
+
+// CreateBatchDisbursementRequest for Xendit Batch Disbursement API
+type CreateBatchDisbursementRequest struct {
+	Reference     string                   `json:"reference"`
+	Disbursements []*BatchDisbursementItem `json:"disbursements"`
+}
+
+// BatchDisbursementResponse from Xendit Batch Disbursement API
+type BatchDisbursementResponse struct {
+	ID                  string                  `json:"id"`
+	Reference           string                  `json:"reference"`
+	Status              BatchDisbursementStatus `json:"status"`
+	TotalUploadedCount  int64                   `json:"total_uploaded_count"`
+	TotalUploadedAmount int64                   `json:"total_uploaded_amount"`
+	Created             *time.Time              `json:"created,omitempty"`
+}
+
+// errorResponse is the body of a rejected Xendit request
+type errorResponse struct {
+	ErrorCode string `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// BankAccountDataStatus status of a bank account data request
+type BankAccountDataStatus string
+
+const (
+	// BankAccountDataPending means the inquiry is still running
+	BankAccountDataPending BankAccountDataStatus = "PENDING"
+	// BankAccountDataSuccess means the account was found
+	BankAccountDataSuccess BankAccountDataStatus = "SUCCESS"
+	// BankAccountDataFailure means the account could not be validated
+	BankAccountDataFailure BankAccountDataStatus = "FAILURE"
+)
+
+// BankAccountDataRequest for Xendit bank account name validation
+type BankAccountDataRequest struct {
+	BankAccountNumber string `json:"bank_account_number"`
+	BankCode          string `json:"bank_code"`
+}
+
+// BankAccountDataResponse from Xendit bank account name validation
+type BankAccountDataResponse struct {
+	ID                    string                `json:"id,omitempty"`
+	BankCode              string                `json:"bank_code"`
+	BankAccountNumber     string                `json:"bank_account_number"`
+	BankAccountHolderName string                `json:"bank_account_holder_name,omitempty"`
+	Status                BankAccountDataStatus `json:"status"`
+	FailureReason         string                `json:"failure_reason,omitempty"`
+}
+
+// RecurringPlanStatus status of a recurring plan
+type RecurringPlanStatus string
+
+const (
+	// PlanRequiresAction means the customer has to authorize the plan
+	PlanRequiresAction RecurringPlanStatus = "REQUIRES_ACTION"
+	// PlanPending means the plan is being activated
+	PlanPending RecurringPlanStatus = "PENDING"
+	// PlanActive means the plan creates a cycle every interval
+	PlanActive RecurringPlanStatus = "ACTIVE"
+	// PlanPaused means the plan stops creating cycles until it is resumed
+	PlanPaused RecurringPlanStatus = "PAUSED"
+	// PlanInactive means the plan is deactivated
+	PlanInactive RecurringPlanStatus = "INACTIVE"
+)
+
+// RecurringCycleStatus status of a recurring cycle
+type RecurringCycleStatus string
+
+const (
+	// CycleScheduled means the cycle is not charged yet
+	CycleScheduled RecurringCycleStatus = "SCHEDULED"
+	// CyclePending means the cycle is being charged
+	CyclePending RecurringCycleStatus = "PENDING"
+	// CycleRetrying means a charge attempt failed and will be retried
+	CycleRetrying RecurringCycleStatus = "RETRYING"
+	// CycleSucceeded means the cycle was paid
+	CycleSucceeded RecurringCycleStatus = "SUCCEEDED"
+	// CycleFailed means every charge attempt failed
+	CycleFailed RecurringCycleStatus = "FAILED"
+	// CycleCancelled means the cycle was cancelled
+	CycleCancelled RecurringCycleStatus = "CANCELLED"
+)
+
+// RecurringPaymentMethod is a payment method of a recurring plan
+type RecurringPaymentMethod struct {
+	PaymentMethodID string `json:"payment_method_id"`
+	Rank            int    `json:"rank"`
+}
+
+// RecurringSchedule is the schedule of a recurring plan
+type RecurringSchedule struct {
+	ID              string     `json:"id,omitempty"`
+	ReferenceID     string     `json:"reference_id,omitempty"`
+	Interval        string     `json:"interval,omitempty"`
+	IntervalCount   int        `json:"interval_count,omitempty"`
+	TotalRecurrence int        `json:"total_recurrence,omitempty"`
+	AnchorDate      *time.Time `json:"anchor_date,omitempty"`
+}
+
+// RecurringAction is an action the customer has to take to activate a plan
+type RecurringAction struct {
+	Action  string `json:"action"`
+	URLType string `json:"url_type"`
+	URL     string `json:"url"`
+	Method  string `json:"method"`
+}
+
+// CreateRecurringPlanRequest for Xendit Create Recurring Plan
+type CreateRecurringPlanRequest struct {
+	ReferenceID       string                    `json:"reference_id"`
+	CustomerID        string                    `json:"customer_id"`
+	RecurringAction   string                    `json:"recurring_action"`
+	Currency          string                    `json:"currency"`
+	Amount            int64                     `json:"amount"`
+	PaymentMethods    []*RecurringPaymentMethod `json:"payment_methods"`
+	Schedule          *RecurringSchedule        `json:"schedule"`
+	FailedCycleAction string                    `json:"failed_cycle_action,omitempty"`
+	Description       string                    `json:"description,omitempty"`
+	Metadata          map[string]interface{}    `json:"metadata,omitempty"`
+}
+
+// UpdateRecurringPlanRequest for Xendit Update Recurring Plan
+type UpdateRecurringPlanRequest struct {
+	Currency       string                    `json:"currency,omitempty"`
+	Amount         int64                     `json:"amount,omitempty"`
+	PaymentMethods []*RecurringPaymentMethod `json:"payment_methods,omitempty"`
+	Description    string                    `json:"description,omitempty"`
+}
+
+// UpdateRecurringScheduleRequest for Xendit Update Recurring Schedule
+type UpdateRecurringScheduleRequest struct {
+	Interval      string `json:"interval"`
+	IntervalCount int    `json:"interval_count"`
+}
+
+// RecurringPlanResponse from Xendit Recurring Plan API
+type RecurringPlanResponse struct {
+	ID          string                 `json:"id"`
+	ReferenceID string                 `json:"reference_id"`
+	CustomerID  string                 `json:"customer_id"`
+	Currency    string                 `json:"currency"`
+	Amount      int64                  `json:"amount"`
+	Status      RecurringPlanStatus    `json:"status"`
+	ScheduleID  string                 `json:"schedule_id"`
+	Schedule    *RecurringSchedule     `json:"schedule,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Actions     []*RecurringAction     `json:"actions,omitempty"`
+	Created     *time.Time             `json:"created,omitempty"`
+	Updated     *time.Time             `json:"updated,omitempty"`
+}
+
+// RecurringCycleResponse from Xendit Recurring Cycle API and cycle callbacks
+type RecurringCycleResponse struct {
+	ID                 string               `json:"id"`
+	PlanID             string               `json:"plan_id"`
+	ReferenceID        string               `json:"reference_id"`
+	CustomerID         string               `json:"customer_id"`
+	Status             RecurringCycleStatus `json:"status"`
+	Currency           string               `json:"currency"`
+	Amount             int64                `json:"amount"`
+	CycleNumber        int                  `json:"cycle_number"`
+	AttemptCount       int                  `json:"attempt_count"`
+	ScheduledTimestamp *time.Time           `json:"scheduled_timestamp,omitempty"`
+	Created            *time.Time           `json:"created,omitempty"`
+	Updated            *time.Time           `json:"updated,omitempty"`
+}
+
+// RecurringCycleList from Xendit List Recurring Cycles
+type RecurringCycleList struct {
+	Data    []*RecurringCycleResponse `json:"data"`
+	HasMore bool                      `json:"has_more"`
+}
+
+// RecurringCallback is a recurring.plan.* or recurring.cycle.* callback
+type RecurringCallback struct {
+	Event      string          `json:"event"`
+	BusinessID string          `json:"business_id"`
+	Created    *time.Time      `json:"created,omitempty"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// PaymentMethodStatus status of a Xendit payment method
+type PaymentMethodStatus string
+
+const (
+	// PaymentMethodRequiresAction means the customer must authorize the payment method
+	PaymentMethodRequiresAction PaymentMethodStatus = "REQUIRES_ACTION"
+	// PaymentMethodPending means the payment method is being processed
+	PaymentMethodPending PaymentMethodStatus = "PENDING"
+	// PaymentMethodActive means the payment method can be charged
+	PaymentMethodActive PaymentMethodStatus = "ACTIVE"
+	// PaymentMethodInactive means the payment method was deactivated
+	PaymentMethodInactive PaymentMethodStatus = "INACTIVE"
+	// PaymentMethodExpired means the payment method was expired
+	PaymentMethodExpired PaymentMethodStatus = "EXPIRED"
+	// PaymentMethodFailed means the payment method could not be created
+	PaymentMethodFailed PaymentMethodStatus = "FAILED"
+)
+
+// PaymentMethodChannelProperties for a linked e-wallet payment method
+type PaymentMethodChannelProperties struct {
+	SuccessReturnURL string `json:"success_return_url,omitempty"`
+	FailureReturnURL string `json:"failure_return_url,omitempty"`
+	CancelReturnURL  string `json:"cancel_return_url,omitempty"`
+	MobileNumber     string `json:"mobile_number,omitempty"`
+}
+
+// PaymentMethodAccount is the e-wallet account behind a payment method
+type PaymentMethodAccount struct {
+	Name           string   `json:"name,omitempty"`
+	AccountDetails string   `json:"account_details,omitempty"`
+	Balance        *float64 `json:"balance,omitempty"`
+	PointBalance   *float64 `json:"point_balance,omitempty"`
+}
+
+// PaymentMethodEWallet is the e-wallet part of a payment method
+type PaymentMethodEWallet struct {
+	ChannelCode       EWalletCode                     `json:"channel_code"`
+	ChannelProperties *PaymentMethodChannelProperties `json:"channel_properties,omitempty"`
+	Account           *PaymentMethodAccount           `json:"account,omitempty"`
+}
+
+// PaymentMethodAction is an action the customer must take on a payment method
+type PaymentMethodAction struct {
+	Action  string `json:"action"`
+	URLType string `json:"url_type"`
+	URL     string `json:"url"`
+	Method  string `json:"method"`
+}
+
+// CreatePaymentMethodRequest for Xendit Create Payment Method
+type CreatePaymentMethodRequest struct {
+	Type        string                `json:"type"`
+	Reusability string                `json:"reusability"`
+	CustomerID  string                `json:"customer_id,omitempty"`
+	EWallet     *PaymentMethodEWallet `json:"ewallet"`
+	Metadata    map[string]string     `json:"metadata,omitempty"`
+}
+
+// PaymentMethodResponse from Xendit Payment Method API
+type PaymentMethodResponse struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	CustomerID  string                 `json:"customer_id"`
+	Reusability string                 `json:"reusability"`
+	Status      PaymentMethodStatus    `json:"status"`
+	EWallet     *PaymentMethodEWallet  `json:"ewallet,omitempty"`
+	Actions     []*PaymentMethodAction `json:"actions,omitempty"`
+	FailureCode string                 `json:"failure_code,omitempty"`
+	Created     *time.Time             `json:"created,omitempty"`
+	Updated     *time.Time             `json:"updated,omitempty"`
+}
+
+// PaylaterChannelCode is a Xendit paylater channel
+type PaylaterChannelCode string
+
+const (
+	// PaylaterKredivo Xendit Kredivo
+	PaylaterKredivo PaylaterChannelCode = "ID_KREDIVO"
+	// PaylaterAkulaku Xendit Akulaku
+	PaylaterAkulaku PaylaterChannelCode = "ID_AKULAKU"
+	// PaylaterAtome Xendit Atome
+	PaylaterAtome PaylaterChannelCode = "ID_ATOME"
+	// PaylaterIndodana Xendit Indodana
+	PaylaterIndodana PaylaterChannelCode = "ID_INDODANA"
+)
+
+// PaylaterOrderItem is an order item of a paylater plan
+type PaylaterOrderItem struct {
+	Type          string  `json:"type"`
+	ReferenceID   string  `json:"reference_id"`
+	Name          string  `json:"name"`
+	NetUnitAmount float64 `json:"net_unit_amount"`
+	Quantity      int64   `json:"quantity"`
+	URL           string  `json:"url"`
+	Category      string  `json:"category"`
+}
+
+// CreatePaylaterPlanRequest for Xendit Initiate PayLater Plans
+type CreatePaylaterPlanRequest struct {
+	CustomerID  string               `json:"customer_id"`
+	ChannelCode PaylaterChannelCode  `json:"channel_code"`
+	Currency    string               `json:"currency"`
+	Amount      float64              `json:"amount"`
+	OrderItems  []*PaylaterOrderItem `json:"order_items"`
+}
+
+// PaylaterPlanOption is an installment option of a paylater plan
+type PaylaterPlanOption struct {
+	DownpaymentAmount float64 `json:"downpayment_amount"`
+	InstallmentAmount float64 `json:"installment_amount"`
+	InterestRate      float64 `json:"interest_rate"`
+	TotalAmount       float64 `json:"total_amount"`
+	Interval          string  `json:"interval"`
+	IntervalCount     int     `json:"interval_count"`
+	TotalRecurrence   int     `json:"total_recurrence"`
+	Description       string  `json:"description"`
+}
+
+// PaylaterPlanResponse from Xendit PayLater Plans API
+type PaylaterPlanResponse struct {
+	ID          string                `json:"id"`
+	CustomerID  string                `json:"customer_id"`
+	ChannelCode PaylaterChannelCode   `json:"channel_code"`
+	Currency    string                `json:"currency"`
+	Amount      float64               `json:"amount"`
+	Options     []*PaylaterPlanOption `json:"options"`
+}
+
+// CreatePaylaterChargeRequest for Xendit Create PayLater Charge
+type CreatePaylaterChargeRequest struct {
+	PlanID             string            `json:"plan_id"`
+	ReferenceID        string            `json:"reference_id"`
+	CheckoutMethod     string            `json:"checkout_method"`
+	SuccessRedirectURL string            `json:"success_redirect_url,omitempty"`
+	FailureRedirectURL string            `json:"failure_redirect_url,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+// PaylaterActions are the checkout URLs of a paylater charge
+type PaylaterActions struct {
+	DesktopWebCheckoutURL     string `json:"desktop_web_checkout_url,omitempty"`
+	MobileWebCheckoutURL      string `json:"mobile_web_checkout_url,omitempty"`
+	MobileDeeplinkCheckoutURL string `json:"mobile_deeplink_checkout_url,omitempty"`
+}
+
+// PaylaterChargeResponse from Xendit PayLater Charges API
+type PaylaterChargeResponse struct {
+	ID          string              `json:"id"`
+	CustomerID  string              `json:"customer_id"`
+	PlanID      string              `json:"plan_id"`
+	ReferenceID string              `json:"reference_id"`
+	ChannelCode PaylaterChannelCode `json:"channel_code"`
+	Currency    string              `json:"currency"`
+	Amount      float64             `json:"amount"`
+	Status      string              `json:"status"`
+	Actions     *PaylaterActions    `json:"actions,omitempty"`
+	Created     *time.Time          `json:"created,omitempty"`
+}
+
+// DirectDebitChannelCode is a Xendit direct debit channel
+type DirectDebitChannelCode string
+
+const (
+	DirectDebitBRI        DirectDebitChannelCode = "DC_BRI"
+	DirectDebitMandiri    DirectDebitChannelCode = "DC_MANDIRI"
+	DirectDebitBCAOneKlik DirectDebitChannelCode = "BCA_ONEKLIK"
+)
+
+// DirectDebitStatus is the status of a Xendit direct debit payment
+type DirectDebitStatus string
+
+const (
+	DirectDebitPending   DirectDebitStatus = "PENDING"
+	DirectDebitCompleted DirectDebitStatus = "COMPLETED"
+	DirectDebitFailed    DirectDebitStatus = "FAILED"
+)
+
+// LinkedAccountTokenProperties for a Xendit linked account token
+type LinkedAccountTokenProperties struct {
+	AccountMobileNumber string `json:"account_mobile_number,omitempty"`
+	CardLastFour        string `json:"card_last_four,omitempty"`
+	CardExpiry          string `json:"card_expiry,omitempty"`
+	AccountEmail        string `json:"account_email,omitempty"`
+	SuccessRedirectURL  string `json:"success_redirect_url,omitempty"`
+	FailureRedirectURL  string `json:"failure_redirect_url,omitempty"`
+}
+
+// CreateLinkedAccountTokenRequest for Xendit Initialize Linked Account Tokenization
+type CreateLinkedAccountTokenRequest struct {
+	CustomerID  string                        `json:"customer_id"`
+	ChannelCode DirectDebitChannelCode        `json:"channel_code"`
+	Properties  *LinkedAccountTokenProperties `json:"properties,omitempty"`
+}
+
+// LinkedAccountTokenResponse from Xendit Linked Account Tokenization API
+type LinkedAccountTokenResponse struct {
+	ID            string                 `json:"id"`
+	CustomerID    string                 `json:"customer_id"`
+	ChannelCode   DirectDebitChannelCode `json:"channel_code"`
+	AuthorizerURL string                 `json:"authorizer_url"`
+	Status        string                 `json:"status"`
+}
+
+// ValidateOTPRequest for Xendit OTP validation endpoints
+type ValidateOTPRequest struct {
+	OTPCode string `json:"otp_code"`
+}
+
+// LinkedAccountProperties of a Xendit linked bank account or debit card
+type LinkedAccountProperties struct {
+	ID           string `json:"id,omitempty"`
+	CardLastFour string `json:"card_last_four,omitempty"`
+	CardExpiry   string `json:"card_expiry,omitempty"`
+	Currency     string `json:"currency,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// LinkedAccountResponse is an account returned by Xendit Get Accessible Accounts
+type LinkedAccountResponse struct {
+	ID          string                   `json:"id"`
+	ChannelCode DirectDebitChannelCode   `json:"channel_code"`
+	Type        string                   `json:"type"`
+	Properties  *LinkedAccountProperties `json:"properties"`
+}
+
+// CreateDebitPaymentMethodRequest for the Xendit direct debit Payment Methods API
+type CreateDebitPaymentMethodRequest struct {
+	CustomerID string                   `json:"customer_id"`
+	Type       string                   `json:"type"`
+	Properties *LinkedAccountProperties `json:"properties"`
+}
+
+// DebitPaymentMethodResponse from the Xendit direct debit Payment Methods API
+type DebitPaymentMethodResponse struct {
+	ID         string                   `json:"id"`
+	CustomerID string                   `json:"customer_id"`
+	Type       string                   `json:"type"`
+	Status     string                   `json:"status"`
+	Properties *LinkedAccountProperties `json:"properties"`
+}
+
+// DirectDebitBasketItem is an item of a Xendit direct debit payment
+type DirectDebitBasketItem struct {
+	ReferenceID string  `json:"reference_id"`
+	Name        string  `json:"name"`
+	Market      string  `json:"market"`
+	Type        string  `json:"type"`
+	Quantity    int64   `json:"quantity"`
+	Price       float64 `json:"price"`
+}
+
+// CreateDirectDebitRequest for Xendit Create Direct Debit Payment
+type CreateDirectDebitRequest struct {
+	ReferenceID        string                   `json:"reference_id"`
+	PaymentMethodID    string                   `json:"payment_method_id"`
+	Currency           string                   `json:"currency"`
+	Amount             float64                  `json:"amount"`
+	CallbackURL        string                   `json:"callback_url,omitempty"`
+	EnableOTP          bool                     `json:"enable_otp"`
+	Description        string                   `json:"description,omitempty"`
+	Basket             []*DirectDebitBasketItem `json:"basket,omitempty"`
+	SuccessRedirectURL string                   `json:"success_redirect_url,omitempty"`
+	FailureRedirectURL string                   `json:"failure_redirect_url,omitempty"`
+	Metadata           map[string]string        `json:"metadata,omitempty"`
+}
+
+// DirectDebitResponse from Xendit Direct Debit API
+type DirectDebitResponse struct {
+	ID                     string                 `json:"id"`
+	ReferenceID            string                 `json:"reference_id"`
+	ChannelCode            DirectDebitChannelCode `json:"channel_code"`
+	PaymentMethodID        string                 `json:"payment_method_id"`
+	Currency               string                 `json:"currency"`
+	Amount                 float64                `json:"amount"`
+	Status                 DirectDebitStatus      `json:"status"`
+	FailureCode            string                 `json:"failure_code,omitempty"`
+	IsOTPRequired          bool                   `json:"is_otp_required"`
+	OTPMobileNumber        string                 `json:"otp_mobile_number,omitempty"`
+	OTPExpirationTimestamp *time.Time             `json:"otp_expiration_timestamp,omitempty"`
+	RequiredAction         string                 `json:"required_action,omitempty"`
+	CheckoutURL            string                 `json:"checkout_url,omitempty"`
+	Created                *time.Time             `json:"created,omitempty"`
+	Updated                *time.Time             `json:"updated,omitempty"`
+}
+
+// CardInstallment is the installment of a Xendit card charge
+type CardInstallment struct {
+	Count    int    `json:"count"`
+	Interval string `json:"interval"`
+}
+
+// CreateCardChargeRequest for Xendit Create Card Charge
+type CreateCardChargeRequest struct {
+	TokenID          string           `json:"token_id"`
+	ExternalID       string           `json:"external_id"`
+	Amount           float64          `json:"amount"`
+	AuthenticationID string           `json:"authentication_id,omitempty"`
+	Capture          bool             `json:"capture"`
+	Installment      *CardInstallment `json:"installment,omitempty"`
+	Descriptor       string           `json:"descriptor,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// CardChargeResponse from Xendit Card Charge API
+type CardChargeResponse struct {
+	ID               string     `json:"id"`
+	ExternalID       string     `json:"external_id"`
+	Status           string     `json:"status"`
+	AuthorizedAmount float64    `json:"authorized_amount"`
+	CaptureAmount    float64    `json:"capture_amount"`
+	CardType         string     `json:"card_type"`
+	MaskedCardNumber string     `json:"masked_card_number"`
+	ChargeType       string     `json:"charge_type"`
+	FailureReason    string     `json:"failure_reason,omitempty"`
+	Created          *time.Time `json:"created,omitempty"`
+}
+
+// CardInstallmentOption is the installment offer of a card issuer
+type CardInstallmentOption struct {
+	Issuer        string  `json:"issuer"`
+	Terms         []int   `json:"terms"`
+	Interval      string  `json:"interval"`
+	MinimumAmount float64 `json:"minimum_amount"`
+}
+
+// CardChargeOptionResponse from Xendit Get Charge Option
+type CardChargeOptionResponse struct {
+	BIN          string                   `json:"bin"`
+	Installments []*CardInstallmentOption `json:"installments"`
+}
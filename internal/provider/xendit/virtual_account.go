@@ -0,0 +1,68 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+const (
+	// Fixed Virtual Account endpoints
+	vaDetailUri = "/callback_virtual_accounts/%s"
+)
+
+// UpdateVirtualAccount updates the expected amount, expiry or reusability of a fixed virtual account
+func (x *xendit) UpdateVirtualAccount(ctx context.Context, id string, params pg.VirtualAccountUpdateParams) (*pg.ChargeResponse, error) {
+	req := x.mapper.mapToUpdateVARequest(params)
+
+	responseBody, err := x.sendRequest(ctx, http.MethodPatch, fmt.Sprintf(x.getBaseURL()+vaDetailUri, id), req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp VAResponse
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	unified := x.mapper.mapToChargeResponseFromVA(&resp, "")
+	_ = json.Unmarshal(responseBody, &unified.Raw)
+
+	return unified, nil
+}
+
+// isVAPaymentCallback reports whether a callback is a fixed virtual account payment
+func isVAPaymentCallback(data map[string]interface{}) bool {
+	_, ok := data["callback_virtual_account_id"]
+	return ok
+}
+
+// parseVAPaymentWebhook parses a fixed virtual account payment callback
+// Each payment into an open or reusable VA is its own event, redelivered callbacks keep their payment_id
+// Xendit does not report the total paid into the VA, it comes from the configured pg.PaymentLedger
+func (x *xendit) parseVAPaymentWebhook(ctx context.Context, body []byte, raw map[string]interface{}) (*pg.WebhookEvent, error) {
+	var callback VAPaymentCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+
+	event := x.mapper.mapVAPaymentToWebhookEvent(&callback, raw)
+
+	if x.config.PaymentLedger != nil {
+		paidAmount, err := x.config.PaymentLedger(ctx, pg.VAPayment{
+			VirtualAccountID: callback.CallbackVirtualAccountID,
+			OrderID:          callback.ExternalID,
+			PaymentID:        callback.PaymentID,
+			Amount:           event.Amount,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record payment %s of VA %s: %w", callback.PaymentID, callback.CallbackVirtualAccountID, err)
+		}
+		event.PaidAmount = paidAmount
+	}
+
+	return event, nil
+}
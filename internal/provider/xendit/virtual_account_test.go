@@ -0,0 +1,200 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+)
+
+func TestMapper_mapToVARequest_Options(t *testing.T) {
+	mapper := &Mapper{}
+
+	tests := []struct {
+		name          string
+		va            *pg.VirtualAccountParams
+		amount        int64
+		wantClosed    bool
+		wantSingleUse bool
+		wantSuggested bool
+	}{
+		{name: "default", amount: 50000, wantClosed: true, wantSingleUse: true},
+		{name: "reusable closed", va: &pg.VirtualAccountParams{Reusable: true}, amount: 50000, wantClosed: true},
+		{name: "open top-up", va: &pg.VirtualAccountParams{OpenAmount: true, Reusable: true}},
+		{name: "open with suggestion", va: &pg.VirtualAccountParams{OpenAmount: true}, amount: 50000, wantSingleUse: true, wantSuggested: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mapper.mapToVARequest(pg.ChargeParams{
+				OrderID:        "ORDER-001",
+				Amount:         tt.amount,
+				PaymentType:    pg.PaymentTypeVABCA,
+				Customer:       pg.Customer{Name: "John Doe"},
+				VirtualAccount: tt.va,
+			})
+
+			if req.IsClosed != tt.wantClosed {
+				t.Errorf("IsClosed = %v, want %v", req.IsClosed, tt.wantClosed)
+			}
+			if req.IsSingleUse != tt.wantSingleUse {
+				t.Errorf("IsSingleUse = %v, want %v", req.IsSingleUse, tt.wantSingleUse)
+			}
+			if req.SuggestedAmount != tt.wantSuggested {
+				t.Errorf("SuggestedAmount = %v, want %v", req.SuggestedAmount, tt.wantSuggested)
+			}
+		})
+	}
+
+	req := mapper.mapToVARequest(pg.ChargeParams{
+		OrderID:        "ORDER-001",
+		PaymentType:    pg.PaymentTypeVABNI,
+		Customer:       pg.Customer{Name: "John Doe"},
+		VirtualAccount: &pg.VirtualAccountParams{VANumber: "9999000001", CustomerName: "Toko Budi"},
+	})
+	if req.VANumber != "9999000001" || req.Name != "Toko Budi" {
+		t.Errorf("request = %+v, want custom number and name", req)
+	}
+}
+
+func TestXendit_validateChargeParams_OpenVA(t *testing.T) {
+	x := newTestProvider(t, nil)
+
+	params := pg.ChargeParams{
+		OrderID:        "ORDER-001",
+		PaymentType:    pg.PaymentTypeVABCA,
+		Customer:       pg.Customer{ID: "CUST-001", Email: "john@example.com"},
+		Items:          []pg.Item{{Name: "Top up", Price: 0, Quantity: 1}},
+		VirtualAccount: &pg.VirtualAccountParams{OpenAmount: true, Reusable: true},
+	}
+	if err := x.validateChargeParams(&params); err != nil {
+		t.Errorf("validateChargeParams() error = %v, want nil for open VA without amount", err)
+	}
+
+	params.VirtualAccount.MinAmount = 10000
+	if err := x.validateChargeParams(&params); err == nil {
+		t.Error("expected error for min amount, got nil")
+	}
+}
+
+func TestXendit_UpdateVirtualAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/callback_virtual_accounts/va-123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["expected_amount"] != float64(75000) {
+			t.Errorf("expected_amount = %v, want 75000", req["expected_amount"])
+		}
+		if req["is_single_use"] != false {
+			t.Errorf("is_single_use = %v, want false", req["is_single_use"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"va-123","external_id":"ORDER-001","bank_code":"BCA","account_number":"107669999001","expected_amount":75000,"is_closed":true,"status":"ACTIVE"}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	reusable := true
+	resp, err := x.UpdateVirtualAccount(context.Background(), "va-123", pg.VirtualAccountUpdateParams{Amount: 75000, Reusable: &reusable})
+	if err != nil {
+		t.Fatalf("UpdateVirtualAccount() error = %v", err)
+	}
+
+	if resp.Amount != 75000 {
+		t.Errorf("Amount = %v, want 75000", resp.Amount)
+	}
+	if resp.VANumber != "107669999001" {
+		t.Errorf("VANumber = %v, want 107669999001", resp.VANumber)
+	}
+}
+
+func TestXendit_ParseWebhook_VAPayment(t *testing.T) {
+	x := newTestProvider(t, nil)
+
+	// The ledger stands in for the merchant database
+	recorded := map[string]bool{}
+	totals := map[string]int64{}
+	x.config.PaymentLedger = func(ctx context.Context, payment pg.VAPayment) (int64, error) {
+		if payment.VirtualAccountID != "va-123" || payment.OrderID != "TOPUP-CUST-001" {
+			t.Errorf("payment = %+v, want one into va-123", payment)
+		}
+		if !recorded[payment.PaymentID] {
+			recorded[payment.PaymentID] = true
+			totals[payment.VirtualAccountID] += payment.Amount
+		}
+		return totals[payment.VirtualAccountID], nil
+	}
+
+	payments := []struct {
+		paymentID      string
+		amount         int64
+		wantPaidAmount int64
+	}{
+		{paymentID: "pay-1", amount: 50000, wantPaidAmount: 50000},
+		{paymentID: "pay-2", amount: 25000, wantPaidAmount: 75000},
+		{paymentID: "pay-2", amount: 25000, wantPaidAmount: 75000}, // redelivered
+	}
+
+	for _, p := range payments {
+		body, _ := json.Marshal(map[string]interface{}{
+			"id":                          "cb-" + p.paymentID,
+			"payment_id":                  p.paymentID,
+			"callback_virtual_account_id": "va-123",
+			"external_id":                 "TOPUP-CUST-001",
+			"bank_code":                   "BCA",
+			"account_number":              "107669999001",
+			"amount":                      p.amount,
+			"transaction_timestamp":       "2024-01-01T10:00:00Z",
+		})
+
+		req := httptest.NewRequest("POST", "/webhook", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+
+		event, err := x.ParseWebhook(req)
+		if err != nil {
+			t.Fatalf("ParseWebhook() error = %v", err)
+		}
+
+		if event.TransactionID != p.paymentID {
+			t.Errorf("TransactionID = %v, want %v", event.TransactionID, p.paymentID)
+		}
+		if event.Status != pg.StatusSuccess || event.EventType != pg.EventPaymentCompleted {
+			t.Errorf("event = %v %v, want completed", event.Status, event.EventType)
+		}
+		if event.Amount != p.amount {
+			t.Errorf("Amount = %v, want %v", event.Amount, p.amount)
+		}
+		if event.PaidAmount != p.wantPaidAmount {
+			t.Errorf("PaidAmount = %v, want %v", event.PaidAmount, p.wantPaidAmount)
+		}
+		if event.PaymentType != pg.PaymentTypeVABCA {
+			t.Errorf("PaymentType = %v, want %v", event.PaymentType, pg.PaymentTypeVABCA)
+		}
+	}
+}
+
+func TestXendit_ParseWebhook_VAPaymentLedgerError(t *testing.T) {
+	x := newTestProvider(t, nil)
+	x.config.PaymentLedger = func(ctx context.Context, payment pg.VAPayment) (int64, error) {
+		return 0, errors.New("database unavailable")
+	}
+
+	body := `{"id":"cb-1","payment_id":"pay-1","callback_virtual_account_id":"va-123","external_id":"TOPUP-CUST-001","bank_code":"BCA","amount":50000}`
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// The webhook fails so Xendit redelivers it instead of the payment being lost
+	if _, err := x.ParseWebhook(req); err == nil {
+		t.Error("ParseWebhook() expected error when the payment cannot be recorded")
+	}
+}
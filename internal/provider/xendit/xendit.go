@@ -45,7 +45,7 @@ func New(cfg *pg.ProviderConfig) (pg.Provider, error) {
 	return &xendit{
 		config:  cfg,
 		mapper:  &Mapper{},
-		httpCli: &http.Client{Timeout: getTimeout(cfg)},
+		httpCli: &http.Client{Timeout: getTimeout(cfg), Transport: cfg.Transport},
 	}, nil
 }
 
@@ -67,7 +67,7 @@ func (x *xendit) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.
 	// Route to appropriate payment method
 	if params.PaymentType.IsEWallet() || params.PaymentType == pg.PaymentTypeQRIS {
 		ewalletReq := x.mapper.mapToEWalletRequest(params)
-		responseBody, err = x.createEWalletCharge(ctx, ewalletReq)
+		responseBody, err = x.createEWalletCharge(ctx, pg.WithCustom(ewalletReq, params.Custom, customOptions...))
 		if err != nil {
 			return nil, err
 		}
@@ -78,9 +78,15 @@ func (x *xendit) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.
 		}
 
 		return x.mapper.mapToChargeResponseFromEWallet(&resp, params.PaymentType), nil
+	} else if params.PaymentType.IsPaylater() {
+		return x.createPaylaterCharge(ctx, params)
+	} else if params.PaymentType.IsDirectDebit() {
+		return x.createDirectDebitCharge(ctx, params)
+	} else if params.PaymentType.IsCreditCard() && params.CreditCard != nil && params.CreditCard.CardNumber != "" {
+		return x.createCardCharge(ctx, params)
 	} else if params.PaymentType.IsVirtualAccount() {
 		vaReq := x.mapper.mapToVARequest(params)
-		responseBody, err = x.createVA(ctx, vaReq)
+		responseBody, err = x.createVA(ctx, pg.WithCustom(vaReq, params.Custom, customOptions...))
 		if err != nil {
 			return nil, err
 		}
@@ -94,7 +100,7 @@ func (x *xendit) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.
 	} else {
 		// Use Invoice API as fallback
 		invoiceReq := x.mapper.mapToInvoiceRequest(params)
-		responseBody, err = x.createInvoice(ctx, invoiceReq)
+		responseBody, err = x.createInvoice(ctx, pg.WithCustom(invoiceReq, params.Custom, customOptions...))
 		if err != nil {
 			return nil, err
 		}
@@ -109,7 +115,7 @@ func (x *xendit) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.
 }
 
 // createInvoice creates an invoice
-func (x *xendit) createInvoice(ctx context.Context, params *CreateInvoiceRequest) ([]byte, error) {
+func (x *xendit) createInvoice(ctx context.Context, params interface{}) ([]byte, error) {
 	baseURL := x.getBaseURL()
 	fullURL := baseURL + invoiceUri
 
@@ -139,14 +145,14 @@ func (x *xendit) createInvoice(ctx context.Context, params *CreateInvoiceRequest
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return nil, apiError(resp.StatusCode, responseBody)
 	}
 
 	return responseBody, nil
 }
 
 // createVA creates a virtual account
-func (x *xendit) createVA(ctx context.Context, params *CreateVAResquest) ([]byte, error) {
+func (x *xendit) createVA(ctx context.Context, params interface{}) ([]byte, error) {
 	baseURL := x.getBaseURL()
 	fullURL := baseURL + vaUri
 
@@ -176,14 +182,14 @@ func (x *xendit) createVA(ctx context.Context, params *CreateVAResquest) ([]byte
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return nil, apiError(resp.StatusCode, responseBody)
 	}
 
 	return responseBody, nil
 }
 
 // createEWalletCharge creates an e-wallet charge
-func (x *xendit) createEWalletCharge(ctx context.Context, params *CreateEWalletRequest) ([]byte, error) {
+func (x *xendit) createEWalletCharge(ctx context.Context, params interface{}) ([]byte, error) {
 	baseURL := x.getBaseURL()
 	fullURL := baseURL + ewalletUri
 
@@ -213,7 +219,7 @@ func (x *xendit) createEWalletCharge(ctx context.Context, params *CreateEWalletR
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return nil, apiError(resp.StatusCode, responseBody)
 	}
 
 	return responseBody, nil
@@ -244,7 +250,7 @@ func (x *xendit) GetStatus(ctx context.Context, orderID string) (*pg.PaymentStat
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return nil, apiError(resp.StatusCode, responseBody)
 	}
 
 	var xenditResponse InvoiceResponse
@@ -287,7 +293,7 @@ func (x *xendit) Cancel(ctx context.Context, orderID string) error {
 
 	if resp.StatusCode != http.StatusOK {
 		responseBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("cancel failed: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return apiError(resp.StatusCode, responseBody)
 	}
 
 	return nil
@@ -310,6 +316,44 @@ func (x *xendit) GetToken(ctx context.Context) (*pg.TokenResponse, error) {
 	return nil, fmt.Errorf("GetToken API is not supported by %s. Xendit uses Basic Auth with API Key for authentication", ProviderName)
 }
 
+// Capabilities returns the payment types and operations supported by Xendit
+// Xendit uses the same base URL for sandbox and production, the API key selects the environment
+func (x *xendit) Capabilities() pg.Capabilities {
+	types := []pg.PaymentType{
+		pg.PaymentTypeGoPay, pg.PaymentTypeOVO, pg.PaymentTypeDANA, pg.PaymentTypeShopeePay, pg.PaymentTypeLinkAja,
+		pg.PaymentTypeQRIS,
+		pg.PaymentTypeVABCA, pg.PaymentTypeVABNI, pg.PaymentTypeVABRI, pg.PaymentTypeVAMandiri, pg.PaymentTypeVAPermata, pg.PaymentTypeVACIMB,
+		pg.PaymentTypeCC,
+		pg.PaymentTypeAlfamart, pg.PaymentTypeIndomaret,
+		pg.PaymentTypeKredivo, pg.PaymentTypeAkulaku, pg.PaymentTypeAtome, pg.PaymentTypeIndodana,
+		pg.PaymentTypeDDBRI, pg.PaymentTypeDDMandiri, pg.PaymentTypeDDBCAOneKlik,
+	}
+
+	return pg.Capabilities{
+		PaymentTypes: types,
+		Operations:   []pg.Operation{pg.OperationCancel, pg.OperationBatchDisbursement, pg.OperationDisbursementWebhook},
+		Limits:       pg.NewLimits(types, amountLimits, x.config.AmountLimits),
+		Currencies:   []string{"IDR"},
+		SandboxURL:   false,
+	}
+}
+
+// Do sends an authenticated request to the Xendit API and decodes the response into out
+func (x *xendit) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	responseBody, err := x.sendRequestWithHeaders(ctx, method, x.getBaseURL()+path, body, nil)
+	if err != nil {
+		return err
+	}
+
+	if out == nil || len(responseBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(responseBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
 // ParseWebhook parses webhook payload
 func (x *xendit) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
 	if err := r.ParseForm(); err != nil {
@@ -324,6 +368,12 @@ func (x *xendit) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
 			return nil, pg.ErrInvalidPayload
 		}
 		if err := json.Unmarshal(body, &webhookData); err == nil {
+			if isRecurringCallback(webhookData) {
+				return x.parseRecurringWebhook(body, webhookData)
+			}
+			if isVAPaymentCallback(webhookData) {
+				return x.parseVAPaymentWebhook(r.Context(), body, webhookData)
+			}
 			return x.parseWebhookJSON(webhookData)
 		}
 	}
@@ -346,12 +396,14 @@ func (x *xendit) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
 	// Map status
 	var mappedStatus pg.Status
 	switch PaymentStatus(status) {
-	case StatusPaid:
+	case StatusPaid, StatusSettled:
 		mappedStatus = pg.StatusSuccess
 	case StatusPending:
 		mappedStatus = pg.StatusPending
 	case StatusFailed:
 		mappedStatus = pg.StatusFailed
+	case StatusExpired:
+		mappedStatus = pg.StatusExpired
 	default:
 		mappedStatus = pg.StatusPending
 	}
@@ -386,12 +438,14 @@ func (x *xendit) parseWebhookJSON(data map[string]interface{}) (*pg.WebhookEvent
 	// Map status
 	var mappedStatus pg.Status
 	switch PaymentStatus(status) {
-	case StatusPaid:
+	case StatusPaid, StatusSettled:
 		mappedStatus = pg.StatusSuccess
 	case StatusPending:
 		mappedStatus = pg.StatusPending
 	case StatusFailed:
 		mappedStatus = pg.StatusFailed
+	case StatusExpired:
+		mappedStatus = pg.StatusExpired
 	default:
 		mappedStatus = pg.StatusPending
 	}
@@ -403,10 +457,27 @@ func (x *xendit) parseWebhookJSON(data map[string]interface{}) (*pg.WebhookEvent
 		Amount:        amount,
 		EventType:     x.mapEventType(PaymentStatus(status)),
 		Timestamp:     timestamp,
+		Metadata:      mapMetadata(data["metadata"]),
 		Raw:           data,
 	}, nil
 }
 
+// mapMetadata converts the metadata object of a webhook payload, non-string values are dropped
+func mapMetadata(v interface{}) map[string]string {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(obj) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]string, len(obj))
+	for k, val := range obj {
+		if str, ok := val.(string); ok {
+			metadata[k] = str
+		}
+	}
+	return metadata
+}
+
 // getRawForm converts form data to raw map
 func getRawForm(r *http.Request) map[string]interface{} {
 	raw := make(map[string]interface{})
@@ -424,10 +495,12 @@ func getRawForm(r *http.Request) map[string]interface{} {
 // mapEventType maps Xendit status to event type
 func (x *xendit) mapEventType(status PaymentStatus) string {
 	switch status {
-	case StatusPaid:
+	case StatusPaid, StatusSettled:
 		return pg.EventPaymentCompleted
 	case StatusFailed:
 		return pg.EventPaymentFailed
+	case StatusExpired:
+		return pg.EventPaymentExpired
 	case StatusPending:
 		return pg.EventPaymentPending
 	default:
@@ -436,36 +509,39 @@ func (x *xendit) mapEventType(status PaymentStatus) string {
 }
 
 // validateChargeParams validates charge parameters
+// All problems are returned at once in a *pg.ValidationError
 func (x *xendit) validateChargeParams(params *pg.ChargeParams) error {
-	// Validate order ID
-	if err := utils.ValidateOrderID(params.OrderID); err != nil {
-		return err
-	}
+	verr := pg.NewValidationError()
+	verr.Merge(params.Validate(x))
 
-	// Validate amount
-	if err := utils.MinAmount(params.Amount, params.PaymentType); err != nil {
-		return err
+	// Validate virtual account options
+	verr.Merge(params.VirtualAccount.Validate())
+	if va := params.VirtualAccount; va != nil && (va.MinAmount > 0 || va.MaxAmount > 0) {
+		verr.Add(pg.NewFieldError("VirtualAccount.MinAmount", fmt.Sprintf("min and max amounts are not supported by %s", ProviderName)))
 	}
 
 	// Validate customer
 	if params.Customer.ID == "" {
-		return pg.NewRequiredFieldError("Customer.ID")
+		verr.Add(pg.NewRequiredFieldError("Customer.ID"))
 	}
-	if err := utils.ValidateEmail(params.Customer.Email, "Customer.Email"); err != nil {
-		return err
+
+	// Validate items
+	if len(params.Items) == 0 {
+		verr.Add(pg.NewRequiredFieldError("Items"))
 	}
 
-	// Validate payment type
-	if err := utils.ValidatePaymentType(params.PaymentType); err != nil {
-		return err
+	// Validate card installment, installments need a card token
+	if params.CreditCard.IsInstallment() && params.CreditCard.CardNumber == "" {
+		verr.Add(pg.NewRequiredFieldError("CreditCard.CardNumber"))
 	}
+	verr.Merge(params.CreditCard.ValidateInstallment(x.config.InstallmentTerms))
 
-	// Validate items
-	if len(params.Items) == 0 {
-		return pg.NewRequiredFieldError("Items")
+	// Validate direct debit account
+	if params.PaymentType.IsDirectDebit() && (params.DirectDebit == nil || params.DirectDebit.AccountLinkID == "") {
+		verr.Add(pg.NewRequiredFieldError("DirectDebit.AccountLinkID"))
 	}
 
-	return nil
+	return verr.ToError()
 }
 
 // getTimeout returns the timeout duration
@@ -478,5 +554,18 @@ func getTimeout(cfg *pg.ProviderConfig) time.Duration {
 
 // getBaseURL returns the base URL
 func (x *xendit) getBaseURL() string {
+	if x.config.BaseURL != "" {
+		return x.config.BaseURL
+	}
 	return sandboxURL // Xendit uses same URL for both environments
 }
+
+// apiError returns the error of a failed response
+// Error bodies carry an error_code, they are returned as *pg.ProviderError
+func apiError(statusCode int, body []byte) error {
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.ErrorCode != "" {
+		return pg.WrapProviderError(ProviderName, errResp.ErrorCode, errResp.Message, nil)
+	}
+	return fmt.Errorf("API error: status=%d, body=%s", statusCode, string(body))
+}
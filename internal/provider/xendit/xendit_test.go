@@ -1,8 +1,12 @@
 package xendit
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -73,6 +77,44 @@ func TestXendit_getBaseURL(t *testing.T) {
 	}
 }
 
+func TestXendit_Capabilities(t *testing.T) {
+	caps := (&xendit{config: &pg.ProviderConfig{}}).Capabilities()
+
+	if caps.SandboxURL {
+		t.Error("SandboxURL = true, want false as Xendit shares one URL")
+	}
+	if !caps.SupportsPaymentType(pg.PaymentTypeIndomaret) || !caps.SupportsPaymentType(pg.PaymentTypeDDBCAOneKlik) {
+		t.Error("retail and direct debit should be supported")
+	}
+	if caps.SupportsOperation(pg.OperationToken) {
+		t.Error("token should not be supported")
+	}
+}
+
+func TestXendit_validateChargeParams_AmountLimits(t *testing.T) {
+	params := pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      15000000,
+		PaymentType: pg.PaymentTypeOVO,
+		Customer:    pg.Customer{ID: "cust-123", Email: "john@example.com"},
+		Items:       []pg.Item{{ID: "item-1", Name: "Product", Price: 15000000, Quantity: 1}},
+	}
+
+	x := &xendit{config: &pg.ProviderConfig{}}
+	err := x.validateChargeParams(&params)
+	if !errors.Is(err, pg.ErrMaxAmount) {
+		t.Fatalf("validateChargeParams() error = %v, want %v", err, pg.ErrMaxAmount)
+	}
+	if !strings.Contains(err.Error(), "Rp10000000 for OVO on xendit") {
+		t.Errorf("error = %v, want the OVO limit", err)
+	}
+
+	x.config.AmountLimits = map[pg.PaymentType]pg.AmountLimit{pg.PaymentTypeOVO: {Min: 10000, Max: 20000000}}
+	if err := x.validateChargeParams(&params); err != nil {
+		t.Errorf("validateChargeParams() error = %v with overridden limit", err)
+	}
+}
+
 func TestXendit_CreateCharge_EWallet(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -230,8 +272,8 @@ func TestXendit_CreateCharge_Invoice(t *testing.T) {
 	if invoiceReq.Customer == nil {
 		t.Error("Customer should not be nil")
 	} else {
-		if invoiceReq.Customer.GivenNames != params.Customer.Name {
-			t.Errorf("Customer.GivenNames = %v, want %v", invoiceReq.Customer.GivenNames, params.Customer.Name)
+		if invoiceReq.Customer.GivenNames != "Bob" || invoiceReq.Customer.Surname != "Smith" {
+			t.Errorf("Customer name = %v %v, want Bob Smith", invoiceReq.Customer.GivenNames, invoiceReq.Customer.Surname)
 		}
 	}
 
@@ -287,8 +329,10 @@ func TestMapper_mapStatus(t *testing.T) {
 		expectedStatus pg.Status
 	}{
 		{"Paid", StatusPaid, pg.StatusSuccess},
+		{"Settled", StatusSettled, pg.StatusSuccess},
 		{"Pending", StatusPending, pg.StatusPending},
 		{"Failed", StatusFailed, pg.StatusFailed},
+		{"Expired", StatusExpired, pg.StatusExpired},
 	}
 
 	for _, tt := range tests {
@@ -301,6 +345,31 @@ func TestMapper_mapStatus(t *testing.T) {
 	}
 }
 
+func TestMapper_mapToInvoiceRequest_Adjustments(t *testing.T) {
+	mapper := &Mapper{}
+
+	req := mapper.mapToInvoiceRequest(pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      96000,
+		PaymentType: pg.PaymentTypeAlfamart,
+		Items:       []pg.Item{{Name: "Product", Price: 100000, Quantity: 1}},
+		Adjustments: []pg.Adjustment{
+			{Type: pg.AdjustmentDiscount, Amount: 15000},
+			{Type: pg.AdjustmentTax, Name: "VAT", Amount: 11000},
+		},
+	})
+
+	if len(req.Fees) != 2 {
+		t.Fatalf("got %d fees, want 2", len(req.Fees))
+	}
+	if req.Fees[0].Type != "Discount" || req.Fees[0].Value != -15000 {
+		t.Errorf("Fees[0] = %+v, want Discount -15000", req.Fees[0])
+	}
+	if req.Fees[1].Type != "VAT" || req.Fees[1].Value != 11000 {
+		t.Errorf("Fees[1] = %+v, want VAT 11000", req.Fees[1])
+	}
+}
+
 func TestMapper_mapToChargeResponse(t *testing.T) {
 	mapper := &Mapper{}
 
@@ -336,6 +405,16 @@ func TestMapper_mapToChargeResponse(t *testing.T) {
 	if result.PaymentURL != resp.PaymentURL {
 		t.Errorf("PaymentURL = %v, want %v", result.PaymentURL, resp.PaymentURL)
 	}
+
+	// Invoices only carry invoice_url, it is the checkout page
+	resp = &InvoiceResponse{ID: "invoice-124", ExternalID: "ORDER-002", Amount: 50000, Status: StatusPending, InvoiceURL: "https://checkout.xendit.co/web/invoice-124"}
+	result = mapper.mapToChargeResponse(resp, pg.PaymentTypeCC)
+	if result.PaymentURL != resp.InvoiceURL {
+		t.Errorf("PaymentURL = %v, want %v", result.PaymentURL, resp.InvoiceURL)
+	}
+	if action, ok := result.Action(pg.ActionRedirect); !ok || action.URL != resp.InvoiceURL {
+		t.Errorf("NextActions = %+v, want a redirect to the invoice", result.NextActions)
+	}
 }
 
 func TestMapper_mapToChargeResponseFromVA(t *testing.T) {
@@ -403,6 +482,37 @@ func TestMapper_mapToChargeResponseFromEWallet(t *testing.T) {
 	}
 }
 
+func TestMapper_mapToChargeResponseFromEWallet_Actions(t *testing.T) {
+	mapper := &Mapper{}
+
+	resp := &EWalletResponse{
+		ID:         "ewc-123",
+		ExternalID: "ORDER-001",
+		Amount:     50000,
+		Status:     StatusPending,
+		Actions: &EWalletActions{
+			MobileWebCheckoutURL:      "https://ewallet-mock.xendit.co/web",
+			MobileDeeplinkCheckoutURL: "shopeeid://main",
+			QRCheckoutString:          "00020101021226",
+		},
+	}
+
+	result := mapper.mapToChargeResponseFromEWallet(resp, pg.PaymentTypeShopeePay)
+
+	if result.PaymentURL != "https://ewallet-mock.xendit.co/web" {
+		t.Errorf("PaymentURL = %v, want the web checkout", result.PaymentURL)
+	}
+	if action, ok := result.Action(pg.ActionDeeplink); !ok || action.URL != "shopeeid://main" {
+		t.Errorf("deeplink action = %+v, want shopeeid://main", action)
+	}
+	if result.QRString != "00020101021226" {
+		t.Errorf("QRString = %v, want the QR checkout string", result.QRString)
+	}
+	if _, ok := result.Action(pg.ActionShowQR); !ok {
+		t.Error("missing QR action")
+	}
+}
+
 func TestMapper_mapToPaymentStatus(t *testing.T) {
 	mapper := &Mapper{}
 
@@ -431,6 +541,17 @@ func TestMapper_mapToPaymentStatus(t *testing.T) {
 		}
 	})
 
+	t.Run("settled InvoiceResponse", func(t *testing.T) {
+		now := time.Now()
+		resp := &InvoiceResponse{ID: "invoice-124", ExternalID: "ORDER-003", Amount: 50000, Status: StatusSettled}
+		resp.Updated = &now
+
+		result := mapper.mapToPaymentStatus("ORDER-003", resp)
+		if result.Status != pg.StatusSuccess || result.PaidAt == nil {
+			t.Errorf("Status = %v, PaidAt = %v, want SUCCESS with the paid time", result.Status, result.PaidAt)
+		}
+	})
+
 	t.Run("VAResponse", func(t *testing.T) {
 		resp := &VAResponse{
 			ID:             "va-123",
@@ -567,6 +688,17 @@ func TestXendit_ParseWebhook(t *testing.T) {
 	if event.EventType != pg.EventPaymentCompleted {
 		t.Errorf("EventType = %v, want %v", event.EventType, pg.EventPaymentCompleted)
 	}
+
+	// Expired invoices are not reported as pending
+	req = httptest.NewRequest("POST", "/webhook", nil)
+	req.Form = map[string][]string{"external_id": {"ORDER-002"}, "status": {"EXPIRED"}, "amount": {"50000"}}
+	event, err = provider.ParseWebhook(req)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if event.Status != pg.StatusExpired || event.EventType != pg.EventPaymentExpired {
+		t.Errorf("Status/EventType = %v/%v, want %v/%v", event.Status, event.EventType, pg.StatusExpired, pg.EventPaymentExpired)
+	}
 }
 
 func TestXendit_mapEventType(t *testing.T) {
@@ -581,7 +713,9 @@ func TestXendit_mapEventType(t *testing.T) {
 		expectedType string
 	}{
 		{"Paid", StatusPaid, pg.EventPaymentCompleted},
+		{"Settled", StatusSettled, pg.EventPaymentCompleted},
 		{"Failed", StatusFailed, pg.EventPaymentFailed},
+		{"Expired", StatusExpired, pg.EventPaymentExpired},
 		{"Pending", StatusPending, pg.EventPaymentPending},
 	}
 
@@ -737,3 +871,171 @@ func TestXendit_GetStatus(t *testing.T) {
 		t.Errorf("Status = %v, want %v", result.Status, pg.StatusSuccess)
 	}
 }
+
+func TestMapper_mapToInvoiceRequest_Addresses(t *testing.T) {
+	mapper := &Mapper{}
+
+	req := mapper.mapToInvoiceRequest(pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: pg.PaymentTypeQRIS,
+		Customer: pg.Customer{
+			Name:            "John Doe",
+			Email:           "john@example.com",
+			ShippingAddress: &pg.Address{Line1: "Jl. Sudirman No. 1", City: "Jakarta", State: "DKI Jakarta", PostalCode: "10220"},
+		},
+	})
+
+	if req.Customer == nil || len(req.Customer.Addresses) != 1 {
+		t.Fatalf("Customer = %+v, want one address", req.Customer)
+	}
+	address := req.Customer.Addresses[0]
+	if address.Category != "SHIPPING" || !address.IsPrimary {
+		t.Errorf("address = %+v, want primary shipping address", address)
+	}
+	if address.StreetLine1 != "Jl. Sudirman No. 1" || address.State != "DKI Jakarta" || address.Country != "ID" {
+		t.Errorf("address = %+v, want Jl. Sudirman in ID", address)
+	}
+}
+
+func TestXendit_CreateCharge_MetadataAndCustom(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != vaUri {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if metadata, _ := body["metadata"].(map[string]interface{}); metadata["cart_id"] != "CART-9" {
+			t.Errorf("metadata = %v, want cart_id", body["metadata"])
+		}
+		if body["virtual_account_number"] != "9999000001" {
+			t.Errorf("virtual_account_number = %v, want 9999000001", body["virtual_account_number"])
+		}
+		if _, ok := body["va_number"]; ok {
+			t.Error("provider option va_number should not be merged")
+		}
+		if body["alternative_display_types"] == nil {
+			t.Error("custom field alternative_display_types was not merged")
+		}
+		if _, ok := body["description"]; ok {
+			t.Error("description should be removed by a nil custom value")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "va-123", "external_id": "ORDER-001", "bank_code": "BCA", "account_number": "9999000001", "status": "PENDING", "metadata": {"cart_id": "CART-9"}}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	_, err := x.CreateCharge(context.Background(), pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: pg.PaymentTypeVABCA,
+		Customer:    pg.Customer{ID: "CUST-001", Name: "John Doe", Email: "john@example.com"},
+		Items:       []pg.Item{{ID: "ITEM-001", Name: "Product", Price: 50000, Quantity: 1}},
+		Description: "Order",
+		Metadata:    map[string]string{"cart_id": "CART-9"},
+		Custom: map[string]interface{}{
+			"va_number":                 "9999000001",
+			"alternative_display_types": []string{"QR_CODE"},
+			"description":               nil,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+}
+
+func TestXendit_ParseWebhook_Metadata(t *testing.T) {
+	provider := &xendit{
+		config: &pg.ProviderConfig{},
+		mapper: &Mapper{},
+	}
+
+	body := `{"external_id": "ORDER-001", "status": "PAID", "amount": 50000, "metadata": {"cart_id": "CART-9", "attempt": 2}}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	event, err := provider.ParseWebhook(req)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if len(event.Metadata) != 1 || event.Metadata["cart_id"] != "CART-9" {
+		t.Errorf("Metadata = %v, want only the string entry cart_id", event.Metadata)
+	}
+}
+
+func TestXendit_Do(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "xnd_development_test" {
+			t.Errorf("Authorization = %v, want secret key basic auth", r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/balance" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": "NOT_FOUND", "message": "Not found"}`))
+			return
+		}
+		w.Write([]byte(`{"balance": 1241231}`))
+	}))
+	defer server.Close()
+
+	x := newTestProvider(t, server)
+
+	var out struct {
+		Balance int64 `json:"balance"`
+	}
+	if err := x.Do(context.Background(), http.MethodGet, "/balance", nil, &out); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if out.Balance != 1241231 {
+		t.Errorf("Balance = %v, want 1241231", out.Balance)
+	}
+
+	err := x.Do(context.Background(), http.MethodGet, "/unknown", nil, nil)
+	var providerErr *pg.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Code != "NOT_FOUND" {
+		t.Errorf("Do() error = %v, want provider error NOT_FOUND", err)
+	}
+}
+
+func TestXendit_PreviewCharge(t *testing.T) {
+	client, err := pg.NewClient(
+		pg.WithProvider(ProviderName),
+		pg.WithServerKey("xnd_development_secret"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	preview, err := client.PreviewCharge(context.Background(), pg.ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: pg.PaymentTypeVABCA,
+		Customer:    pg.Customer{ID: "CUST-001", Name: "John Doe", Email: "john@example.com"},
+		Items:       []pg.Item{{ID: "ITEM-001", Name: "Product", Price: 50000, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("PreviewCharge() error = %v", err)
+	}
+
+	if preview.Method != http.MethodPost || preview.URL != productionURL+vaUri {
+		t.Errorf("request = %s %s, want POST %s", preview.Method, preview.URL, productionURL+vaUri)
+	}
+	if got := preview.Header.Get(headerAuthorization); got != pg.Redacted {
+		t.Errorf("Authorization = %v, want %v", got, pg.Redacted)
+	}
+
+	var body CreateVAResquest
+	if err := json.Unmarshal(preview.Body, &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.ExternalID != "ORDER-001" || body.BankCode != BankBCA || body.ExpectedAmount != 50000 {
+		t.Errorf("body = %+v, want the mapped VA request", body)
+	}
+}
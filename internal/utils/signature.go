@@ -2,6 +2,8 @@ package utils
 
 import (
 	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/hex"
@@ -181,6 +183,11 @@ func hmacEqual(a, b []byte) bool {
 	return result == 0
 }
 
+// SecureCompare compares two signature strings in constant time
+func SecureCompare(a, b string) bool {
+	return hmacEqual([]byte(a), []byte(b))
+}
+
 // CalculateSHA512 calculates SHA512 hash of a string
 func CalculateSHA512(data string) string {
 	h := sha512.New()
@@ -195,6 +202,20 @@ func CalculateSHA256(data string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// CalculateMD5 calculates MD5 hash of a string
+func CalculateMD5(data string) string {
+	h := md5.New()
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CalculateSHA1 calculates SHA1 hash of a string
+func CalculateSHA1(data string) string {
+	h := sha1.New()
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // CalculateHMACSHA512 calculates HMAC-SHA512 of data with key
 func CalculateHMACSHA512(key, data string) string {
 	h := hmac.New(sha512.New, []byte(key))
@@ -0,0 +1,215 @@
+// Package webhook builds provider webhooks signed the way the providers sign them
+// It is used by pg-mock and pgctl to send notifications the SDK accepts
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/utils"
+)
+
+// dokuNotificationTarget is the Request-Target the SDK verifies Doku notifications with
+const dokuNotificationTarget = "/payments/v2"
+
+// midtransTimeLayout is the format of the Midtrans transaction_time, in WIB
+const midtransTimeLayout = "2006-01-02 15:04:05"
+
+// wib is the time zone of Midtrans transaction times
+var wib = time.FixedZone("WIB", 7*60*60)
+
+// statuses are the provider statuses of the unified statuses, per provider
+var statuses = map[string]map[pg.Status]string{
+	"midtrans": {
+		pg.StatusSuccess:   "settlement",
+		pg.StatusPending:   "pending",
+		pg.StatusFailed:    "deny",
+		pg.StatusCancelled: "cancel",
+		pg.StatusExpired:   "expire",
+	},
+	"xendit": {
+		pg.StatusSuccess: "PAID",
+		pg.StatusPending: "PENDING",
+		pg.StatusFailed:  "FAILED",
+		pg.StatusExpired: "EXPIRED",
+	},
+	"doku": {
+		pg.StatusSuccess:   "SUCCESS",
+		pg.StatusPending:   "PENDING",
+		pg.StatusFailed:    "FAILED",
+		pg.StatusCancelled: "CANCELLED",
+	},
+}
+
+// Keys are the credentials a notification is signed with
+type Keys struct {
+	// ServerKey signs Midtrans and Doku notifications
+	ServerKey string
+
+	// ClientKey is the Xendit callback token and the Doku client ID
+	ClientKey string
+}
+
+// Notification is a payment status change
+type Notification struct {
+	Provider      string
+	TransactionID string
+	OrderID       string
+
+	// Status is in the format of the provider, see Status
+	Status string
+
+	Amount      int64
+	PaymentType string
+	Channel     string
+	Time        time.Time
+}
+
+// Status returns the status of provider which maps to the unified status
+func Status(provider string, status pg.Status) (string, bool) {
+	s, ok := statuses[provider][status]
+	return s, ok
+}
+
+// Supported reports whether notifications of provider can be signed
+func Supported(provider string) bool {
+	_, ok := statuses[provider]
+	return ok
+}
+
+// New returns the webhook of n to url
+//   - Midtrans: a form with SHA512(order_id + transaction_status + server key) in X-Signature
+//   - Xendit: an invoice callback with the client key in X-Callback-Token
+//   - Doku: a JSON notification with the HMAC signature of a request to /payments/v2
+func New(n Notification, keys Keys, url string) (*http.Request, error) {
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+
+	switch n.Provider {
+	case "midtrans":
+		return newMidtrans(n, keys, url)
+	case "xendit":
+		return newXendit(n, keys, url)
+	case "doku":
+		return newDoku(n, keys, url)
+	default:
+		return nil, fmt.Errorf("signing webhooks of %s is not supported", n.Provider)
+	}
+}
+
+func newMidtrans(n Notification, keys Keys, callback string) (*http.Request, error) {
+	form := url.Values{
+		"transaction_id":     {n.TransactionID},
+		"order_id":           {n.OrderID},
+		"status_code":        {"200"},
+		"gross_amount":       {fmt.Sprintf("%d.00", n.Amount)},
+		"payment_type":       {n.PaymentType},
+		"transaction_status": {n.Status},
+		"transaction_time":   {n.Time.In(wib).Format(midtransTimeLayout)},
+		"fraud_status":       {"accept"},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, callback, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Signature", utils.CalculateSHA512(n.OrderID+n.Status+keys.ServerKey))
+	return req, nil
+}
+
+func newXendit(n Notification, keys Keys, callback string) (*http.Request, error) {
+	body := map[string]interface{}{
+		"id":              n.TransactionID,
+		"external_id":     n.OrderID,
+		"status":          n.Status,
+		"amount":          n.Amount,
+		"currency":        "IDR",
+		"payment_method":  n.PaymentType,
+		"payment_channel": n.Channel,
+		"updated":         n.Time.UTC().Format(time.RFC3339),
+	}
+	if n.Status == "PAID" {
+		body["paid_amount"] = n.Amount
+		body["paid_at"] = n.Time.UTC().Format(time.RFC3339)
+	}
+
+	req, _, err := newJSONRequest(callback, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Callback-Token", keys.ClientKey)
+	return req, nil
+}
+
+func newDoku(n Notification, keys Keys, callback string) (*http.Request, error) {
+	body := map[string]interface{}{
+		"transaction_id":     n.OrderID,
+		"transaction_status": n.Status,
+		"amount":             n.Amount,
+		"payment_type":       n.PaymentType,
+	}
+	if n.Status == "SUCCESS" {
+		body["payment_date_time"] = n.Time.UTC().Format(time.RFC3339)
+	}
+
+	req, payload, err := newJSONRequest(callback, body)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 16)
+	rand.Read(b)
+	requestID := hex.EncodeToString(b)
+	timestamp := n.Time.UTC().Format(time.RFC3339)
+
+	req.Header.Set("Client-Id", keys.ClientKey)
+	req.Header.Set("Request-Id", requestID)
+	req.Header.Set("Request-Timestamp", timestamp)
+	req.Header.Set("Signature", DokuSignature(keys, requestID, timestamp, dokuNotificationTarget, payload))
+	return req, nil
+}
+
+// DokuSignature is the Doku request signature, "HMACSHA256=" + base64(HMAC-SHA256(server key, components))
+// The Digest component is only signed for a request with a body
+func DokuSignature(keys Keys, requestID, timestamp, target string, body []byte) string {
+	component := "Client-Id:" + keys.ClientKey + "\n" +
+		"Request-Id:" + requestID + "\n" +
+		"Request-Timestamp:" + timestamp + "\n" +
+		"Request-Target:" + target
+	if len(body) > 0 {
+		digest := sha256.Sum256(body)
+		component += "\nDigest:" + base64.StdEncoding.EncodeToString(digest[:])
+	}
+
+	h := hmac.New(sha256.New, []byte(keys.ServerKey))
+	h.Write([]byte(component))
+	return "HMACSHA256=" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// newJSONRequest returns a POST of body as JSON, and the JSON
+func newJSONRequest(url string, body interface{}) (*http.Request, []byte, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, b, nil
+}
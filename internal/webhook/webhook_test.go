@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	pg "github.com/pandudpn/go-payment-gateway"
+	"github.com/pandudpn/go-payment-gateway/internal/provider/doku"
+	"github.com/pandudpn/go-payment-gateway/internal/provider/midtrans"
+	"github.com/pandudpn/go-payment-gateway/internal/provider/xendit"
+)
+
+// factories are the providers whose notifications are signed
+var factories = map[string]pg.ProviderFactory{"midtrans": midtrans.New, "xendit": xendit.New, "doku": doku.New}
+
+func TestNew(t *testing.T) {
+	keys := Keys{ServerKey: "server-key", ClientKey: "client-key"}
+
+	for provider, factory := range factories {
+		for unified := range statuses[provider] {
+			t.Run(provider+"_"+string(unified), func(t *testing.T) {
+				p, err := factory(&pg.ProviderConfig{ServerKey: keys.ServerKey, ClientKey: keys.ClientKey})
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+
+				status, _ := Status(provider, unified)
+				req, err := New(Notification{
+					Provider:      provider,
+					TransactionID: "TRX-001",
+					OrderID:       "ORDER-001",
+					Status:        status,
+					Amount:        150000,
+					PaymentType:   "bank_transfer",
+					Time:          time.Date(2024, 3, 1, 3, 15, 30, 0, time.UTC),
+				}, keys, "http://localhost/webhook")
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+
+				if !p.VerifyWebhook(req) {
+					t.Fatal("VerifyWebhook() = false, want true")
+				}
+				event, err := p.ParseWebhook(req)
+				if err != nil {
+					t.Fatalf("ParseWebhook() error = %v", err)
+				}
+				if event.OrderID != "ORDER-001" || event.Status != unified || event.Amount != 150000 {
+					t.Errorf("ParseWebhook() = %+v, want %s of ORDER-001", event, unified)
+				}
+			})
+		}
+	}
+}
+
+func TestNew_WrongKey(t *testing.T) {
+	for provider, factory := range factories {
+		p, err := factory(&pg.ProviderConfig{ServerKey: "server-key", ClientKey: "client-key"})
+		if err != nil {
+			t.Fatalf("New(%s) error = %v", provider, err)
+		}
+
+		status, _ := Status(provider, pg.StatusSuccess)
+		req, err := New(Notification{Provider: provider, OrderID: "ORDER-001", Status: status, Amount: 150000},
+			Keys{ServerKey: "wrong-key", ClientKey: "wrong-token"}, "http://localhost/webhook")
+		if err != nil {
+			t.Fatalf("New(%s) error = %v", provider, err)
+		}
+		if p.VerifyWebhook(req) {
+			t.Errorf("%s VerifyWebhook() = true, want false for a wrong key", provider)
+		}
+	}
+}
+
+func TestNew_Unsupported(t *testing.T) {
+	if Supported("duitku") {
+		t.Error("Supported(duitku) = true, want false")
+	}
+	if _, err := New(Notification{Provider: "duitku"}, Keys{}, "http://localhost/webhook"); err == nil {
+		t.Error("New() error = nil, want an error for an unsupported provider")
+	}
+}
@@ -0,0 +1,90 @@
+package pg
+
+import (
+	"fmt"
+)
+
+// AmountLimit is the amount range accepted for a payment type
+type AmountLimit struct {
+	// Min is the minimum amount
+	Min int64 `json:"min"`
+
+	// Max is the maximum amount, zero means no maximum
+	Max int64 `json:"max,omitempty"`
+}
+
+// Allows returns true if amount is inside the limit
+func (l AmountLimit) Allows(amount int64) bool {
+	if amount < l.Min {
+		return false
+	}
+	return l.Max == 0 || amount <= l.Max
+}
+
+// Validate checks amount against the limit of pt on provider
+// The returned ValidationError names the limit, and matches ErrMinAmount or ErrMaxAmount with errors.Is
+func (l AmountLimit) Validate(provider string, pt PaymentType, amount int64) error {
+	verr := NewValidationError()
+
+	on := ""
+	if provider != "" {
+		on = " on " + provider
+	}
+
+	if amount < l.Min {
+		verr.Add(&FieldError{
+			Field:   "Amount",
+			Code:    CodeMinAmount,
+			Message: fmt.Sprintf("must be at least Rp%d for %s%s", l.Min, pt, on),
+			Err:     ErrMinAmount,
+		})
+	} else if l.Max > 0 && amount > l.Max {
+		verr.Add(&FieldError{
+			Field:   "Amount",
+			Code:    CodeMaxAmount,
+			Message: fmt.Sprintf("must be at most Rp%d for %s%s", l.Max, pt, on),
+			Err:     ErrMaxAmount,
+		})
+	}
+
+	return verr.ToError()
+}
+
+// DefaultMinAmount returns the default minimum amount of pt
+// It is used for payment types missing from the provider limits
+func DefaultMinAmount(pt PaymentType) int64 {
+	switch {
+	case pt.IsEWallet():
+		return MinAmountEWallet
+	case pt.IsVirtualAccount():
+		return MinAmountVA
+	case pt == PaymentTypeQRIS:
+		return MinAmountQRIS
+	case pt == PaymentTypeCC:
+		return MinAmountCC
+	case pt.IsRetail():
+		return MinAmountRetail
+	case pt.IsPaylater():
+		return MinAmountPaylater
+	case pt.IsDirectDebit():
+		return MinAmountDirectDebit
+	default:
+		return MinAmountEWallet
+	}
+}
+
+// NewLimits returns the amount limit of every payment type in types
+// overrides replace defaults entry by entry, and types missing from both get DefaultMinAmount without a maximum
+func NewLimits(types []PaymentType, defaults, overrides map[PaymentType]AmountLimit) map[PaymentType]AmountLimit {
+	limits := make(map[PaymentType]AmountLimit, len(types))
+	for _, pt := range types {
+		if l, ok := overrides[pt]; ok {
+			limits[pt] = l
+		} else if l, ok := defaults[pt]; ok {
+			limits[pt] = l
+		} else {
+			limits[pt] = AmountLimit{Min: DefaultMinAmount(pt)}
+		}
+	}
+	return limits
+}
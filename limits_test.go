@@ -0,0 +1,91 @@
+package pg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAmountLimit_Allows(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  AmountLimit
+		amount int64
+		want   bool
+	}{
+		{name: "below min", limit: AmountLimit{Min: 10000}, amount: 9999, want: false},
+		{name: "no max", limit: AmountLimit{Min: 10000}, amount: 100000000, want: true},
+		{name: "at max", limit: AmountLimit{Min: 10000, Max: 10000000}, amount: 10000000, want: true},
+		{name: "above max", limit: AmountLimit{Min: 10000, Max: 10000000}, amount: 10000001, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.Allows(tt.amount); got != tt.want {
+				t.Errorf("Allows(%d) = %v, want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmountLimit_Validate(t *testing.T) {
+	limit := AmountLimit{Min: 10000, Max: 10000000}
+
+	tests := []struct {
+		name    string
+		amount  int64
+		wantErr error
+		wantMsg string
+	}{
+		{name: "inside", amount: 50000},
+		{name: "below min", amount: 5000, wantErr: ErrMinAmount, wantMsg: "at least Rp10000 for OVO on xendit"},
+		{name: "above max", amount: 15000000, wantErr: ErrMaxAmount, wantMsg: "at most Rp10000000 for OVO on xendit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limit.Validate("xendit", PaymentTypeOVO, tt.amount)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			if !IsValidationError(err) {
+				t.Fatalf("Validate() error = %T, want *ValidationError", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Validate() error = %v, want contain %v", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestNewLimits(t *testing.T) {
+	defaults := map[PaymentType]AmountLimit{
+		PaymentTypeOVO:   {Min: 10000, Max: 10000000},
+		PaymentTypeVABCA: {Min: 10000},
+	}
+	overrides := map[PaymentType]AmountLimit{
+		PaymentTypeOVO: {Min: 20000, Max: 2000000},
+	}
+
+	limits := NewLimits([]PaymentType{PaymentTypeOVO, PaymentTypeVABCA, PaymentTypeQRIS}, defaults, overrides)
+
+	if limits[PaymentTypeOVO] != (AmountLimit{Min: 20000, Max: 2000000}) {
+		t.Errorf("OVO limit = %+v, want override", limits[PaymentTypeOVO])
+	}
+	if limits[PaymentTypeVABCA] != (AmountLimit{Min: 10000}) {
+		t.Errorf("VA_BCA limit = %+v, want default", limits[PaymentTypeVABCA])
+	}
+	if limits[PaymentTypeQRIS] != (AmountLimit{Min: MinAmountQRIS}) {
+		t.Errorf("QRIS limit = %+v, want global minimum", limits[PaymentTypeQRIS])
+	}
+	if _, ok := limits[PaymentTypeDANA]; ok {
+		t.Error("DANA limit should not be set")
+	}
+}
@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"net/http"
 	"os"
 	"time"
 )
@@ -25,6 +26,14 @@ type Config struct {
 	// PrivateKey is the RSA private key for asymmetric signature (Doku)
 	PrivateKey string
 
+	// DisbursementKey is the API key for disbursements when the provider uses a separate one (Midtrans Iris)
+	// It defaults to ServerKey
+	DisbursementKey string
+
+	// DisbursementWebhookKey verifies disbursement notifications signed with a key other than the API key,
+	// e.g. the Midtrans Iris merchant key
+	DisbursementWebhookKey string
+
 	// Timeout is the HTTP request timeout
 	Timeout time.Duration
 
@@ -33,6 +42,27 @@ type Config struct {
 
 	// LogEnabled indicates if logging is enabled
 	LogEnabled bool
+
+	// InstallmentTerms overrides the allowed card installment terms per bank code, e.g. {"BCA": {3, 6, 12}}
+	// Banks not listed keep DefaultInstallmentTerms
+	InstallmentTerms map[string][]int
+
+	// AmountLimits overrides the provider amount limits per payment type, e.g. {PaymentTypeOVO: {Min: 10000, Max: 2000000}}
+	// Payment types not listed keep the provider limits
+	AmountLimits map[PaymentType]AmountLimit
+
+	// Transport is the HTTP transport providers send requests with, http.DefaultTransport when nil
+	Transport http.RoundTripper
+
+	// BaseURL replaces the provider API host, e.g. to point the client at a mock server
+	// Midtrans, Xendit and Doku support it
+	BaseURL string
+
+	// BillResolver answers the virtual account inquiries of providers which ask before a payment (Espay)
+	BillResolver BillResolver
+
+	// PaymentLedger accumulates the payments into open amount and reusable virtual accounts (Xendit)
+	PaymentLedger PaymentLedger
 }
 
 // Option is a function that configures the client
@@ -84,6 +114,21 @@ func WithPrivateKey(key string) Option {
 	}
 }
 
+// WithDisbursementKey sets the API key used for disbursements
+func WithDisbursementKey(key string) Option {
+	return func(c *Config) {
+		c.DisbursementKey = key
+	}
+}
+
+// WithDisbursementWebhookKey sets the key disbursement notifications are verified with
+// Midtrans Iris signs them with the merchant key from the Iris dashboard, not the API key
+func WithDisbursementWebhookKey(key string) Option {
+	return func(c *Config) {
+		c.DisbursementWebhookKey = key
+	}
+}
+
 // WithTimeout sets the HTTP request timeout
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Config) {
@@ -105,31 +150,83 @@ func WithLogging(enabled bool) Option {
 	}
 }
 
+// WithInstallmentTerms sets the allowed card installment terms per bank code
+func WithInstallmentTerms(terms map[string][]int) Option {
+	return func(c *Config) {
+		c.InstallmentTerms = terms
+	}
+}
+
+// WithAmountLimits sets the amount limits per payment type, replacing the provider limits
+func WithAmountLimits(limits map[PaymentType]AmountLimit) Option {
+	return func(c *Config) {
+		c.AmountLimits = limits
+	}
+}
+
+// WithTransport sets the HTTP transport providers send requests with
+// It is used to add proxies, tracing or to record requests in tests
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
+// WithBaseURL sets the URL which replaces the provider API host
+// The Midtrans Iris API is served under /iris/api/v1 of it
+func WithBaseURL(url string) Option {
+	return func(c *Config) {
+		c.BaseURL = url
+	}
+}
+
+// WithBillResolver sets the function which looks up the bill of a virtual account
+// Espay asks for it before each VA payment, its inquiry is answered as not found without one
+func WithBillResolver(resolver BillResolver) Option {
+	return func(c *Config) {
+		c.BillResolver = resolver
+	}
+}
+
+// WithPaymentLedger sets the function which records each payment into a virtual account
+// and returns the running total reported as WebhookEvent.PaidAmount
+func WithPaymentLedger(ledger PaymentLedger) Option {
+	return func(c *Config) {
+		c.PaymentLedger = ledger
+	}
+}
+
 // Environment variable names
 const (
-	EnvProvider   = "PAYMENT_PROVIDER"
-	EnvEnv        = "PAYMENT_ENV"
-	EnvServerKey  = "PAYMENT_SERVER_KEY"
-	EnvClientKey  = "PAYMENT_CLIENT_KEY"
-	EnvMerchantID = "PAYMENT_MERCHANT_ID"
-	EnvPrivateKey = "PAYMENT_PRIVATE_KEY"
-	EnvTimeout    = "PAYMENT_TIMEOUT"
-	EnvSnap       = "PAYMENT_SNAP"
-	EnvLogging    = "PAYMENT_LOGGING"
+	EnvProvider               = "PAYMENT_PROVIDER"
+	EnvEnv                    = "PAYMENT_ENV"
+	EnvServerKey              = "PAYMENT_SERVER_KEY"
+	EnvClientKey              = "PAYMENT_CLIENT_KEY"
+	EnvMerchantID             = "PAYMENT_MERCHANT_ID"
+	EnvPrivateKey             = "PAYMENT_PRIVATE_KEY"
+	EnvDisbursementKey        = "PAYMENT_DISBURSEMENT_KEY"
+	EnvDisbursementWebhookKey = "PAYMENT_DISBURSEMENT_WEBHOOK_KEY"
+	EnvTimeout                = "PAYMENT_TIMEOUT"
+	EnvSnap                   = "PAYMENT_SNAP"
+	EnvLogging                = "PAYMENT_LOGGING"
+	EnvBaseURL                = "PAYMENT_BASE_URL"
 )
 
 // LoadConfigFromEnv loads configuration from environment variables
 func LoadConfigFromEnv() *Config {
 	cfg := &Config{
-		Provider:    os.Getenv(EnvProvider),
-		Environment: SandBox,
-		ServerKey:   os.Getenv(EnvServerKey),
-		ClientKey:   os.Getenv(EnvClientKey),
-		MerchantID:  os.Getenv(EnvMerchantID),
-		PrivateKey:  os.Getenv(EnvPrivateKey),
-		Timeout:     30 * time.Second,
-		SnapMode:    os.Getenv(EnvSnap) == "true",
-		LogEnabled:  os.Getenv(EnvLogging) != "false",
+		Provider:               os.Getenv(EnvProvider),
+		Environment:            SandBox,
+		ServerKey:              os.Getenv(EnvServerKey),
+		ClientKey:              os.Getenv(EnvClientKey),
+		MerchantID:             os.Getenv(EnvMerchantID),
+		PrivateKey:             os.Getenv(EnvPrivateKey),
+		DisbursementKey:        os.Getenv(EnvDisbursementKey),
+		DisbursementWebhookKey: os.Getenv(EnvDisbursementWebhookKey),
+		Timeout:                30 * time.Second,
+		SnapMode:               os.Getenv(EnvSnap) == "true",
+		LogEnabled:             os.Getenv(EnvLogging) != "false",
+		BaseURL:                os.Getenv(EnvBaseURL),
 	}
 
 	if env := os.Getenv(EnvEnv); env == string(Production) {
@@ -1,6 +1,8 @@
 package pg
 
 import (
+"context"
+"net/http"
 "os"
 "testing"
 "time"
@@ -70,6 +72,16 @@ func TestWithMerchantID(t *testing.T) {
 	}
 }
 
+func TestWithDisbursementWebhookKey(t *testing.T) {
+	cfg := &Config{}
+	key := "SB-Mid-merchant-key"
+	WithDisbursementWebhookKey(key)(cfg)
+
+	if cfg.DisbursementWebhookKey != key {
+		t.Errorf("DisbursementWebhookKey = %v, want %v", cfg.DisbursementWebhookKey, key)
+	}
+}
+
 func TestWithTimeout(t *testing.T) {
 	cfg := &Config{}
 	timeout := 60 * time.Second
@@ -89,6 +101,71 @@ func TestWithSnap(t *testing.T) {
 	}
 }
 
+func TestWithInstallmentTerms(t *testing.T) {
+	cfg := &Config{}
+	WithInstallmentTerms(map[string][]int{"BCA": {3, 6}})(cfg)
+
+	if len(cfg.InstallmentTerms["BCA"]) != 2 {
+		t.Errorf("InstallmentTerms = %v, want BCA 3 and 6", cfg.InstallmentTerms)
+	}
+}
+
+func TestWithAmountLimits(t *testing.T) {
+	cfg := &Config{}
+	WithAmountLimits(map[PaymentType]AmountLimit{PaymentTypeOVO: {Min: 10000, Max: 2000000}})(cfg)
+
+	if cfg.AmountLimits[PaymentTypeOVO].Max != 2000000 {
+		t.Errorf("AmountLimits = %v, want OVO max 2000000", cfg.AmountLimits)
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	cfg := &Config{}
+	transport := &http.Transport{}
+	WithTransport(transport)(cfg)
+
+	if cfg.Transport != transport {
+		t.Errorf("Transport = %v, want the given transport", cfg.Transport)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	cfg := &Config{}
+	WithBaseURL("http://localhost:8080")(cfg)
+
+	if cfg.BaseURL != "http://localhost:8080" {
+		t.Errorf("BaseURL = %v, want http://localhost:8080", cfg.BaseURL)
+	}
+}
+
+func TestWithBillResolver(t *testing.T) {
+	cfg := &Config{}
+	WithBillResolver(func(ctx context.Context, vaNumber string) (*Bill, error) {
+		return &Bill{OrderID: "ORDER-001", Amount: 50000}, nil
+	})(cfg)
+
+	if cfg.BillResolver == nil {
+		t.Fatal("BillResolver should be set")
+	}
+	if bill, err := cfg.BillResolver(context.Background(), "8808123"); err != nil || bill.OrderID != "ORDER-001" {
+		t.Errorf("BillResolver() = %+v, %v", bill, err)
+	}
+}
+
+func TestWithPaymentLedger(t *testing.T) {
+	cfg := &Config{}
+	WithPaymentLedger(func(ctx context.Context, payment VAPayment) (int64, error) {
+		return payment.Amount + 25000, nil
+	})(cfg)
+
+	if cfg.PaymentLedger == nil {
+		t.Fatal("PaymentLedger should be set")
+	}
+	if total, err := cfg.PaymentLedger(context.Background(), VAPayment{PaymentID: "pay-2", Amount: 50000}); err != nil || total != 75000 {
+		t.Errorf("PaymentLedger() = %v, %v, want 75000", total, err)
+	}
+}
+
 func TestWithLogging(t *testing.T) {
 	tests := []struct {
 		name     string
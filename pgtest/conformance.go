@@ -0,0 +1,235 @@
+package pgtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+// Stub fakes the API of a provider for RunProviderConformance
+// The handlers answer every request of one operation, whatever its URL
+type Stub struct {
+	// Config is the provider configuration, the suite sets Transport
+	Config pg.ProviderConfig
+
+	// Charge is a charge the provider accepts
+	Charge pg.ChargeParams
+
+	// Reference is passed to GetStatus and Cancel, Charge.OrderID when empty
+	// When set, CreateCharge must return it as ChargeResponse.Reference
+	// e.g. the trx_id:bill_no reference of a Faspay bill
+	Reference string
+
+	// CreateCharge serves a successful CreateCharge of Charge
+	CreateCharge http.Handler
+
+	// Rejected serves a CreateCharge the provider API rejects
+	Rejected http.Handler
+
+	// Statuses are the unified statuses checked with GetStatus and the webhooks
+	Statuses []pg.Status
+
+	// Status serves GetStatus of Reference in the provider status which maps to status
+	Status func(status pg.Status) http.Handler
+
+	// Cancel serves a successful Cancel of Reference, nil when the provider cannot cancel
+	Cancel http.Handler
+
+	// Webhook returns a notification of Charge in the provider status which maps to status
+	// The signature is wrong when signed is false
+	Webhook func(status pg.Status, signed bool) *http.Request
+}
+
+// RunProviderConformance checks a provider behaves like the built-in providers against stub:
+//   - CreateCharge returns the order and amount of the charge
+//   - API rejections are returned as *pg.ProviderError
+//   - GetStatus and ParseWebhook map every status of stub.Statuses
+//   - a cancelled context is returned as an error wrapping context.Canceled
+//   - VerifyWebhook rejects a wrong signature and leaves the body to ParseWebhook
+func RunProviderConformance(t *testing.T, factory pg.ProviderFactory, stub Stub) {
+	t.Helper()
+
+	if stub.CreateCharge == nil || stub.Rejected == nil || stub.Status == nil || stub.Webhook == nil {
+		t.Fatal("pgtest: Stub.CreateCharge, Stub.Rejected, Stub.Status and Stub.Webhook are required")
+	}
+	if len(stub.Statuses) == 0 {
+		t.Fatal("pgtest: Stub.Statuses is empty")
+	}
+
+	newProvider := func(t *testing.T, handler http.Handler) pg.Provider {
+		t.Helper()
+
+		cfg := stub.Config
+		cfg.Transport = &handlerTransport{handler: handler}
+		provider, err := factory(&cfg)
+		if err != nil {
+			t.Fatalf("factory() error = %v", err)
+		}
+		if provider.Name() == "" {
+			t.Error("Name() is empty")
+		}
+		return provider
+	}
+
+	reference := stub.Reference
+	if reference == "" {
+		reference = stub.Charge.OrderID
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("CreateCharge", func(t *testing.T) {
+		provider := newProvider(t, stub.CreateCharge)
+
+		resp, err := provider.CreateCharge(context.Background(), stub.Charge)
+		if err != nil {
+			t.Fatalf("CreateCharge() error = %v", err)
+		}
+		if resp == nil {
+			t.Fatal("CreateCharge() = nil")
+		}
+		if resp.OrderID != stub.Charge.OrderID {
+			t.Errorf("OrderID = %v, want %v", resp.OrderID, stub.Charge.OrderID)
+		}
+		if resp.Amount != stub.Charge.Amount {
+			t.Errorf("Amount = %v, want %v", resp.Amount, stub.Charge.Amount)
+		}
+		if stub.Reference != "" && resp.Reference != stub.Reference {
+			t.Errorf("Reference = %v, want %v", resp.Reference, stub.Reference)
+		}
+		if resp.Status == "" {
+			t.Error("Status is empty")
+		}
+	})
+
+	t.Run("CreateChargeRejected", func(t *testing.T) {
+		provider := newProvider(t, stub.Rejected)
+
+		_, err := provider.CreateCharge(context.Background(), stub.Charge)
+		var providerErr *pg.ProviderError
+		if !errors.As(err, &providerErr) {
+			t.Fatalf("CreateCharge() error = %v, want *pg.ProviderError", err)
+		}
+		if providerErr.Provider != provider.Name() {
+			t.Errorf("ProviderError.Provider = %v, want %v", providerErr.Provider, provider.Name())
+		}
+	})
+
+	t.Run("CreateChargeCancelled", func(t *testing.T) {
+		provider := newProvider(t, stub.CreateCharge)
+
+		if _, err := provider.CreateCharge(cancelled, stub.Charge); !errors.Is(err, context.Canceled) {
+			t.Errorf("CreateCharge() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	for _, status := range stub.Statuses {
+		t.Run("GetStatus_"+string(status), func(t *testing.T) {
+			provider := newProvider(t, stub.Status(status))
+
+			got, err := provider.GetStatus(context.Background(), reference)
+			if err != nil {
+				t.Fatalf("GetStatus() error = %v", err)
+			}
+			if got == nil {
+				t.Fatal("GetStatus() = nil")
+			}
+			if got.Status != status {
+				t.Errorf("Status = %v, want %v", got.Status, status)
+			}
+			if got.OrderID != stub.Charge.OrderID {
+				t.Errorf("OrderID = %v, want %v", got.OrderID, stub.Charge.OrderID)
+			}
+		})
+	}
+
+	t.Run("GetStatusCancelled", func(t *testing.T) {
+		provider := newProvider(t, stub.Status(stub.Statuses[0]))
+
+		if _, err := provider.GetStatus(cancelled, reference); !errors.Is(err, context.Canceled) {
+			t.Errorf("GetStatus() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		if stub.Cancel == nil {
+			provider := newProvider(t, http.NotFoundHandler())
+			if err := provider.Cancel(context.Background(), reference); err == nil {
+				t.Error("Cancel() error = nil, want an error from a provider which cannot cancel")
+			}
+			return
+		}
+
+		provider := newProvider(t, stub.Cancel)
+		if err := provider.Cancel(context.Background(), reference); err != nil {
+			t.Errorf("Cancel() error = %v", err)
+		}
+		if err := provider.Cancel(cancelled, reference); !errors.Is(err, context.Canceled) {
+			t.Errorf("Cancel() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	for _, status := range stub.Statuses {
+		t.Run("Webhook_"+string(status), func(t *testing.T) {
+			provider := newProvider(t, http.NotFoundHandler())
+
+			// Client.ParseWebhook verifies and parses the same request
+			r := stub.Webhook(status, true)
+			if !provider.VerifyWebhook(r) {
+				t.Fatal("VerifyWebhook() = false, want true")
+			}
+			event, err := provider.ParseWebhook(r)
+			if err != nil {
+				t.Fatalf("ParseWebhook() after VerifyWebhook() error = %v", err)
+			}
+			if event.Status != status {
+				t.Errorf("Status = %v, want %v", event.Status, status)
+			}
+			if event.OrderID != stub.Charge.OrderID {
+				t.Errorf("OrderID = %v, want %v", event.OrderID, stub.Charge.OrderID)
+			}
+
+			// ParseWebhook alone reads the body too
+			event, err = provider.ParseWebhook(stub.Webhook(status, true))
+			if err != nil {
+				t.Fatalf("ParseWebhook() error = %v", err)
+			}
+			if event.Status != status {
+				t.Errorf("Status = %v, want %v", event.Status, status)
+			}
+		})
+	}
+
+	t.Run("WebhookInvalidSignature", func(t *testing.T) {
+		provider := newProvider(t, http.NotFoundHandler())
+
+		if provider.VerifyWebhook(stub.Webhook(stub.Statuses[0], false)) {
+			t.Error("VerifyWebhook() = true, want false for a wrong signature")
+		}
+	})
+}
+
+// handlerTransport answers requests with an http.Handler, without a network
+// Like http.Transport, it fails requests whose context is done
+type handlerTransport struct {
+	handler http.Handler
+}
+
+// RoundTrip serves req with the handler
+func (h *handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	w := httptest.NewRecorder()
+	h.handler.ServeHTTP(w, req)
+
+	resp := w.Result()
+	resp.Request = req
+	return resp, nil
+}
@@ -0,0 +1,153 @@
+package pgtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+// acme is a minimal third-party provider with a JSON API and a token-signed webhook
+type acme struct {
+	config  *pg.ProviderConfig
+	httpCli *http.Client
+}
+
+func newAcme(cfg *pg.ProviderConfig) (pg.Provider, error) {
+	if cfg.ServerKey == "" {
+		return nil, pg.ErrMissingCredentials
+	}
+	return &acme{config: cfg, httpCli: &http.Client{Transport: cfg.Transport}}, nil
+}
+
+// acmePayment is a payment of the acme API
+type acmePayment struct {
+	Reference string `json:"reference"`
+	Amount    int64  `json:"amount"`
+	State     string `json:"state"`
+	Error     string `json:"error,omitempty"`
+}
+
+var acmeStates = map[string]pg.Status{"paid": pg.StatusSuccess, "open": pg.StatusPending, "void": pg.StatusCancelled}
+
+func (a *acme) Name() string { return "acme" }
+
+func (a *acme) do(ctx context.Context, method, path string, body interface{}) (*acmePayment, error) {
+	b, _ := json.Marshal(body)
+	req, err := http.NewRequestWithContext(ctx, method, "https://api.acme.test"+path, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var payment acmePayment
+	if err := json.NewDecoder(resp.Body).Decode(&payment); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, pg.WrapProviderError(a.Name(), fmt.Sprint(resp.StatusCode), payment.Error, nil)
+	}
+	return &payment, nil
+}
+
+func (a *acme) CreateCharge(ctx context.Context, params pg.ChargeParams) (*pg.ChargeResponse, error) {
+	payment, err := a.do(ctx, http.MethodPost, "/payments", acmePayment{Reference: params.OrderID, Amount: params.Amount})
+	if err != nil {
+		return nil, err
+	}
+	return &pg.ChargeResponse{OrderID: payment.Reference, Amount: payment.Amount, Status: acmeStates[payment.State]}, nil
+}
+
+func (a *acme) GetStatus(ctx context.Context, orderID string) (*pg.PaymentStatus, error) {
+	payment, err := a.do(ctx, http.MethodGet, "/payments/"+orderID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &pg.PaymentStatus{OrderID: payment.Reference, Amount: payment.Amount, Status: acmeStates[payment.State]}, nil
+}
+
+func (a *acme) Cancel(ctx context.Context, orderID string) error {
+	_, err := a.do(ctx, http.MethodPost, "/payments/"+orderID+"/void", nil)
+	return err
+}
+
+func (a *acme) VerifyWebhook(r *http.Request) bool {
+	return r.Header.Get("X-Acme-Token") == a.config.ServerKey
+}
+
+func (a *acme) ParseWebhook(r *http.Request) (*pg.WebhookEvent, error) {
+	var payment acmePayment
+	if err := json.NewDecoder(r.Body).Decode(&payment); err != nil {
+		return nil, pg.ErrInvalidPayload
+	}
+	return &pg.WebhookEvent{OrderID: payment.Reference, Amount: payment.Amount, Status: acmeStates[payment.State]}, nil
+}
+
+func (a *acme) GetToken(ctx context.Context) (*pg.TokenResponse, error) {
+	return nil, pg.ErrUnimplemented
+}
+
+func TestRunProviderConformance(t *testing.T) {
+	states := map[pg.Status]string{pg.StatusSuccess: "paid", pg.StatusPending: "open", pg.StatusCancelled: "void"}
+	respond := func(code int, body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+			io.WriteString(w, body)
+		})
+	}
+	payment := func(status pg.Status) string {
+		return `{"reference": "ORDER-001", "amount": 150000, "state": "` + states[status] + `"}`
+	}
+
+	RunProviderConformance(t, newAcme, Stub{
+		Config:       pg.ProviderConfig{ServerKey: "acme-key"},
+		Charge:       pg.ChargeParams{OrderID: "ORDER-001", Amount: 150000, PaymentType: pg.PaymentTypeQRIS},
+		CreateCharge: respond(http.StatusOK, payment(pg.StatusPending)),
+		Rejected:     respond(http.StatusUnprocessableEntity, `{"error": "amount is too low"}`),
+		Statuses:     []pg.Status{pg.StatusSuccess, pg.StatusPending, pg.StatusCancelled},
+		Status: func(status pg.Status) http.Handler {
+			return respond(http.StatusOK, payment(status))
+		},
+		Cancel: respond(http.StatusOK, payment(pg.StatusCancelled)),
+		Webhook: func(status pg.Status, signed bool) *http.Request {
+			r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payment(status)))
+			if signed {
+				r.Header.Set("X-Acme-Token", "acme-key")
+			}
+			return r
+		},
+	})
+}
+
+func TestHandlerTransport(t *testing.T) {
+	transport := &handlerTransport{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Path", r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	})}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/a", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("X-Path") != "/a" {
+		t.Errorf("RoundTrip() = %v %v, want 201 from the handler", resp.StatusCode, resp.Header)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := transport.RoundTrip(req.WithContext(ctx)); err != context.Canceled {
+		t.Errorf("RoundTrip() error = %v, want %v", err, context.Canceled)
+	}
+}
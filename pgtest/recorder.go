@@ -0,0 +1,404 @@
+// Package pgtest provides helpers for testing code and providers built on go-payment-gateway
+package pgtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+// RecordEnv is the environment variable which switches ModeFromEnv to ModeRecord
+const RecordEnv = "PG_RECORD"
+
+// Mode selects whether a Recorder replays or records interactions
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette and never touches the network
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests through the real transport and saves them to the cassette on Stop
+	ModeRecord
+)
+
+// ModeFromEnv returns ModeRecord when PG_RECORD is set, otherwise ModeReplay
+func ModeFromEnv() Mode {
+	if os.Getenv(RecordEnv) != "" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// scrubbedHeaders are the headers which always carry credentials or signatures
+var scrubbedHeaders = []string{
+	"Authorization", "Authorization-Customer", "Proxy-Authorization", "Cookie", "Set-Cookie",
+	"X-Callback-Token", "Signature", "X-Signature", "X-Timestamp", "Request-Timestamp",
+}
+
+// Cassette is a list of recorded HTTP interactions
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a scrubbed HTTP request
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+
+	// Body is set for JSON bodies, RawBody for anything else
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody string          `json:"raw_body,omitempty"`
+}
+
+// RecordedResponse is a scrubbed HTTP response
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+
+	// Body is set for JSON bodies, RawBody for anything else
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody string          `json:"raw_body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper which records interactions to a cassette file and replays them
+//
+// Requests match a recorded interaction on method, path, query and body. JSON bodies are compared
+// semantically, so key order and whitespace do not matter, and fields set with WithIgnoredFields
+// are left out of the comparison. Each interaction is replayed once, in recording order.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	secrets   []string
+	ignored   []string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// RecorderOption configures a Recorder
+type RecorderOption func(*Recorder)
+
+// WithMode sets the recorder mode, the default is ModeReplay
+func WithMode(mode Mode) RecorderOption {
+	return func(r *Recorder) {
+		r.mode = mode
+	}
+}
+
+// WithRealTransport sets the transport used in ModeRecord, the default is http.DefaultTransport
+func WithRealTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithSecrets replaces every occurrence of the given values with pg.Redacted before saving
+// Requests are scrubbed the same way before matching, so tests may replay with the real values
+func WithSecrets(secrets ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, secret := range secrets {
+			if secret != "" {
+				r.secrets = append(r.secrets, secret)
+			}
+		}
+	}
+}
+
+// WithIgnoredFields leaves JSON request body fields out of matching
+// Nested fields are written with dots, e.g. "transaction.date"
+func WithIgnoredFields(fields ...string) RecorderOption {
+	return func(r *Recorder) {
+		r.ignored = append(r.ignored, fields...)
+	}
+}
+
+// NewRecorder creates a Recorder for the cassette at path
+// In ModeReplay the cassette must exist, in ModeRecord it is overwritten on Stop
+func NewRecorder(path string, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		transport: http.DefaultTransport,
+		cassette:  &Cassette{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("pgtest: failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("pgtest: failed to parse cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the recorder mode
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// Stop saves the cassette in ModeRecord
+// In ModeReplay it returns an error listing the interactions which were never requested
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeRecord {
+		data, err := json.MarshalIndent(r.cassette, "", "  ")
+		if err != nil {
+			return fmt.Errorf("pgtest: failed to encode cassette: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+			return fmt.Errorf("pgtest: failed to create cassette directory: %w", err)
+		}
+		return os.WriteFile(r.path, append(data, '\n'), 0o644)
+	}
+
+	var unused []string
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction.Request.Method+" "+interaction.Request.URL)
+		}
+	}
+	if len(unused) > 0 {
+		return fmt.Errorf("pgtest: %s has unused interactions: %s", r.path, strings.Join(unused, ", "))
+	}
+	return nil
+}
+
+// record sends the request and appends the scrubbed interaction
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = nil
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    r.scrub(req.URL.String()),
+			Header: r.scrubHeader(req.Header),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.scrubHeader(resp.Header),
+		},
+	}
+	interaction.Request.Body, interaction.Request.RawBody = splitBody(r.scrub(string(body)))
+	interaction.Response.Body, interaction.Response.RawBody = splitBody(r.scrub(string(respBody)))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay answers the request with the first unused matching interaction
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	reqURL, err := url.Parse(r.scrub(req.URL.String()))
+	if err != nil {
+		return nil, err
+	}
+	reqBody := r.scrub(string(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matches(&interaction.Request, req.Method, reqURL, reqBody) {
+			continue
+		}
+		r.used[i] = true
+
+		respBody := interaction.Response.RawBody
+		if interaction.Response.Body != nil {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, interaction.Response.Body); err != nil {
+				return nil, fmt.Errorf("pgtest: invalid response body in %s: %w", r.path, err)
+			}
+			respBody = buf.String()
+		}
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("pgtest: no interaction in %s matches %s %s", r.path, req.Method, reqURL.RequestURI())
+}
+
+// matches reports whether a recorded request matches on method, path, query and body
+func (r *Recorder) matches(recorded *RecordedRequest, method string, reqURL *url.URL, body string) bool {
+	if recorded.Method != method {
+		return false
+	}
+
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil || recordedURL.Path != reqURL.Path {
+		return false
+	}
+	if !reflect.DeepEqual(recordedURL.Query(), reqURL.Query()) {
+		return false
+	}
+
+	recordedBody := recorded.RawBody
+	if recorded.Body != nil {
+		recordedBody = string(recorded.Body)
+	}
+	return r.bodyEqual(recordedBody, body)
+}
+
+// bodyEqual compares JSON bodies semantically without ignored fields, and other bodies byte for byte
+func (r *Recorder) bodyEqual(a, b string) bool {
+	var x, y interface{}
+	if decodeJSON(a, &x) != nil || decodeJSON(b, &y) != nil {
+		return a == b
+	}
+
+	for _, field := range r.ignored {
+		deleteField(x, strings.Split(field, "."))
+		deleteField(y, strings.Split(field, "."))
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// scrub replaces every secret in s with pg.Redacted
+func (r *Recorder) scrub(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, pg.Redacted)
+	}
+	return s
+}
+
+// scrubHeader copies h with credential headers and secrets replaced by pg.Redacted
+func (r *Recorder) scrubHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for name, values := range h {
+		for _, v := range values {
+			out.Add(name, r.scrub(v))
+		}
+	}
+	for _, name := range scrubbedHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, pg.Redacted)
+		}
+	}
+	return out
+}
+
+// readBody reads and closes body, returning nil for an empty body
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("pgtest: failed to read body: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// splitBody stores valid JSON as a raw message so cassettes stay readable
+func splitBody(body string) (json.RawMessage, string) {
+	if body == "" {
+		return nil, ""
+	}
+	if json.Valid([]byte(body)) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(body)); err == nil {
+			return buf.Bytes(), ""
+		}
+	}
+	return nil, body
+}
+
+// decodeJSON decodes s keeping numbers exact
+func decodeJSON(s string, v interface{}) error {
+	if s == "" {
+		return errors.New("empty body")
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// deleteField removes a dotted field from decoded JSON objects
+func deleteField(v interface{}, path []string) {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(path) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		delete(obj, path[0])
+		return
+	}
+	deleteField(obj[path[0]], path[1:])
+}
@@ -0,0 +1,201 @@
+package pgtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pandudpn/go-payment-gateway"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "tx-1", "key": "sk-secret", "echo": ` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "charge.json")
+
+	rec, err := NewRecorder(path, WithMode(ModeRecord), WithSecrets("sk-secret"))
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/v2/charge?key=sk-secret", strings.NewReader(`{"order_id": "ORDER-1", "amount": 10000}`))
+	req.Header.Set("Authorization", "Basic c2stc2VjcmV0Og==")
+	resp, err := (&http.Client{Transport: rec}).Do(req)
+	if err != nil {
+		t.Fatalf("record Do() error = %v", err)
+	}
+	recorded, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(recorded), "sk-secret") {
+		t.Errorf("recorded response = %s, want the real body", recorded)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(data), "sk-secret") || strings.Contains(string(data), "c2stc2VjcmV0") || strings.Contains(string(data), "session=abc") {
+		t.Errorf("cassette contains secrets: %s", data)
+	}
+
+	// Replay matches regardless of host and JSON key order
+	rec, err = NewRecorder(path, WithSecrets("sk-secret"))
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	req, _ = http.NewRequest(http.MethodPost, "https://api.example.com/v2/charge?key=sk-secret", strings.NewReader(`{"amount":10000,"order_id":"ORDER-1"}`))
+	resp, err = (&http.Client{Transport: rec}).Do(req)
+	if err != nil {
+		t.Fatalf("replay Do() error = %v", err)
+	}
+	replayed, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("StatusCode = %v, want %v", resp.StatusCode, http.StatusCreated)
+	}
+	if !strings.Contains(string(replayed), `"id":"tx-1"`) || !strings.Contains(string(replayed), pg.Redacted) {
+		t.Errorf("replayed body = %s", replayed)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+
+	// Each interaction is replayed once
+	req, _ = http.NewRequest(http.MethodPost, "https://api.example.com/v2/charge?key=sk-secret", strings.NewReader(`{"amount":10000,"order_id":"ORDER-1"}`))
+	if _, err := (&http.Client{Transport: rec}).Do(req); err == nil {
+		t.Error("second replay Do() error = nil, want no matching interaction")
+	}
+}
+
+func TestRecorder_Matching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := `{"interactions": [
+		{"request": {"method": "POST", "url": "https://api.example.com/charge", "body": {"order_id": "A", "meta": {"date": "2024-01-01"}}},
+		 "response": {"status_code": 200, "body": {"id": "first"}}},
+		{"request": {"method": "POST", "url": "https://api.example.com/charge", "raw_body": "order_id=B"},
+		 "response": {"status_code": 200, "raw_body": "second"}},
+		{"request": {"method": "GET", "url": "https://api.example.com/status?id=C"},
+		 "response": {"status_code": 404}}
+	]}`
+	if err := os.WriteFile(path, []byte(cassette), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name:     "json body with ignored field",
+			method:   http.MethodPost,
+			url:      "http://localhost/charge",
+			body:     `{"meta": {"date": "2030-12-31"}, "order_id": "A"}`,
+			wantBody: `{"id":"first"}`,
+		},
+		{
+			name:     "form body",
+			method:   http.MethodPost,
+			url:      "http://localhost/charge",
+			body:     "order_id=B",
+			wantBody: "second",
+		},
+		{
+			name:    "different body",
+			method:  http.MethodPost,
+			url:     "http://localhost/charge",
+			body:    `{"order_id": "Z"}`,
+			wantErr: true,
+		},
+		{
+			name:    "different method",
+			method:  http.MethodPut,
+			url:     "http://localhost/status?id=C",
+			wantErr: true,
+		},
+		{
+			name:    "different query",
+			method:  http.MethodGet,
+			url:     "http://localhost/status?id=D",
+			wantErr: true,
+		},
+		{
+			name:   "query",
+			method: http.MethodGet,
+			url:    "http://localhost/status?id=C",
+		},
+	}
+
+	rec, err := NewRecorder(path, WithIgnoredFields("meta.date"))
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req, _ := http.NewRequest(tt.method, tt.url, body)
+			resp, err := rec.RoundTrip(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RoundTrip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := io.ReadAll(resp.Body)
+			if string(got) != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+
+	if err := rec.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestRecorder_Errors(t *testing.T) {
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("NewRecorder() error = nil, want missing cassette error")
+	}
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	os.WriteFile(path, []byte(`{"interactions": [{"request": {"method": "GET", "url": "https://api.example.com/a"}, "response": {"status_code": 200}}]}`), 0o644)
+	rec, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if err := rec.Stop(); err == nil || !strings.Contains(err.Error(), "GET https://api.example.com/a") {
+		t.Errorf("Stop() error = %v, want unused interaction", err)
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(RecordEnv, "")
+	if got := ModeFromEnv(); got != ModeReplay {
+		t.Errorf("ModeFromEnv() = %v, want ModeReplay", got)
+	}
+
+	t.Setenv(RecordEnv, "1")
+	if got := ModeFromEnv(); got != ModeRecord {
+		t.Errorf("ModeFromEnv() = %v, want ModeRecord", got)
+	}
+}
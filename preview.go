@@ -0,0 +1,122 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Redacted replaces secrets in a RequestPreview
+const Redacted = "[REDACTED]"
+
+// errDryRun stops a provider request after it is captured
+var errDryRun = errors.New("dry run: request not sent")
+
+// redactedHeaders are the headers which always carry credentials
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Callback-Token"}
+
+// RequestPreview is a provider request built without sending it
+type RequestPreview struct {
+	// Method is the HTTP method
+	Method string `json:"method"`
+
+	// URL is the full request URL
+	URL string `json:"url"`
+
+	// Header are the request headers with credentials replaced by Redacted
+	Header http.Header `json:"header"`
+
+	// Body is the JSON request body, nil for requests without a body
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// PreviewCharge validates and maps params and returns the request CreateCharge would send, without sending it
+// Charges made of several requests, such as Xendit paylater or direct debits needing an access token,
+// preview the first request
+func (c *Client) PreviewCharge(ctx context.Context, params ChargeParams) (*RequestPreview, error) {
+	if err := c.checkPaymentType(params.PaymentType); err != nil {
+		return nil, err
+	}
+
+	// A provider copy sends through a transport which captures the request instead
+	capture := &captureTransport{}
+	cfg := *c.config
+	cfg.Transport = capture
+
+	p, err := createProvider(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.CreateCharge(ctx, params)
+	if capture.preview == nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s charge did not send a request", p.Name())
+	}
+
+	return capture.preview.redact(c.config.ServerKey, c.config.DisbursementKey, c.config.DisbursementWebhookKey, c.config.PrivateKey), nil
+}
+
+// captureTransport records the first request and fails it
+type captureTransport struct {
+	preview *RequestPreview
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *captureTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+
+	if t.preview == nil {
+		preview := &RequestPreview{
+			Method: r.Method,
+			URL:    r.URL.String(),
+			Header: r.Header.Clone(),
+		}
+		if r.Body != nil {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				return nil, err
+			}
+			if len(body) > 0 {
+				preview.Body = body
+			}
+		}
+		t.preview = preview
+	}
+
+	return nil, errDryRun
+}
+
+// redact replaces credential headers and every occurrence of the given secrets
+func (p *RequestPreview) redact(secrets ...string) *RequestPreview {
+	for _, name := range redactedHeaders {
+		if p.Header.Get(name) != "" {
+			p.Header.Set(name, Redacted)
+		}
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		p.URL = strings.ReplaceAll(p.URL, secret, Redacted)
+		for name, values := range p.Header {
+			for i, v := range values {
+				p.Header[name][i] = strings.ReplaceAll(v, secret, Redacted)
+			}
+		}
+		if p.Body != nil {
+			p.Body = json.RawMessage(strings.ReplaceAll(string(p.Body), secret, Redacted))
+		}
+	}
+
+	return p
+}
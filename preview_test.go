@@ -0,0 +1,83 @@
+package pg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// httpProvider is a mock provider which sends charges through its configured transport
+type httpProvider struct {
+	mockProvider
+	cfg *ProviderConfig
+}
+
+func (p *httpProvider) CreateCharge(ctx context.Context, params ChargeParams) (*ChargeResponse, error) {
+	if params.OrderID == "" {
+		return nil, NewRequiredFieldError("OrderID")
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"order_id": params.OrderID, "key": p.cfg.ServerKey})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.example.com/charge", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Basic c2VjcmV0Og==")
+	req.Header.Set("X-Signature", "sig-"+p.cfg.ServerKey)
+
+	client := &http.Client{Transport: p.cfg.Transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return &ChargeResponse{OrderID: params.OrderID}, nil
+}
+
+func newPreviewClient(t *testing.T) *Client {
+	t.Helper()
+
+	RegisterProvider("preview-mock", func(cfg *ProviderConfig) (Provider, error) {
+		return &httpProvider{mockProvider: mockProvider{name: "preview-mock"}, cfg: cfg}, nil
+	})
+
+	return &Client{
+		provider: &mockProvider{name: "preview-mock"},
+		config:   &Config{Provider: "preview-mock", ServerKey: "secret"},
+	}
+}
+
+func TestClient_PreviewCharge(t *testing.T) {
+	client := newPreviewClient(t)
+
+	preview, err := client.PreviewCharge(context.Background(), ChargeParams{OrderID: "ORDER-001"})
+	if err != nil {
+		t.Fatalf("PreviewCharge() error = %v", err)
+	}
+
+	if preview.Method != http.MethodPost || preview.URL != "https://api.example.com/charge" {
+		t.Errorf("request = %s %s, want POST https://api.example.com/charge", preview.Method, preview.URL)
+	}
+	if got := preview.Header.Get("Authorization"); got != Redacted {
+		t.Errorf("Authorization = %v, want %v", got, Redacted)
+	}
+	if got := preview.Header.Get("X-Signature"); got != "sig-"+Redacted {
+		t.Errorf("X-Signature = %v, want the server key redacted", got)
+	}
+	if want := `{"key":"[REDACTED]","order_id":"ORDER-001"}`; string(preview.Body) != want {
+		t.Errorf("Body = %s, want %s", preview.Body, want)
+	}
+	if client.config.Transport != nil {
+		t.Error("PreviewCharge() should not change the client transport")
+	}
+}
+
+func TestClient_PreviewCharge_ValidationError(t *testing.T) {
+	client := newPreviewClient(t)
+
+	_, err := client.PreviewCharge(context.Background(), ChargeParams{})
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "OrderID" {
+		t.Errorf("PreviewCharge() error = %v, want required OrderID", err)
+	}
+}
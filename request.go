@@ -0,0 +1,37 @@
+package pg
+
+import (
+	"context"
+	"strings"
+)
+
+// Requester is implemented by providers that send arbitrary API requests
+// with their base URL, authentication, signing and error mapping
+type Requester interface {
+	// Do sends body as JSON to path on the provider API and decodes the JSON response into out
+	Do(ctx context.Context, method, path string, body, out interface{}) error
+}
+
+// Do sends an authenticated request to an endpoint the SDK does not support yet
+// path is relative to the provider base URL, body is sent as JSON when not nil and
+// the response is decoded into out when out is not nil
+// Error responses are returned as *ProviderError where the provider error format is known
+// Returns ErrUnimplemented if the provider does not support raw requests
+func (c *Client) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	r, ok := c.provider.(Requester)
+	if !ok {
+		return ErrUnimplemented
+	}
+
+	if method == "" {
+		return NewRequiredFieldError("Method")
+	}
+	if path == "" {
+		return NewRequiredFieldError("Path")
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return r.Do(ctx, strings.ToUpper(method), path, body, out)
+}
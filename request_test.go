@@ -0,0 +1,53 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// mockRequester is a mock provider which also implements Requester
+type mockRequester struct {
+	mockProvider
+	method string
+	path   string
+}
+
+func (m *mockRequester) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	m.method = method
+	m.path = path
+	if resp, ok := out.(*map[string]string); ok {
+		*resp = map[string]string{"id": "123"}
+	}
+	return nil
+}
+
+func TestClient_Do(t *testing.T) {
+	mock := &mockRequester{mockProvider: mockProvider{name: "mock"}}
+	client := &Client{provider: mock, config: &Config{}}
+
+	var out map[string]string
+	if err := client.Do(context.Background(), "get", "v2/balance", nil, &out); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if mock.method != "GET" || mock.path != "/v2/balance" {
+		t.Errorf("request = %s %s, want GET /v2/balance", mock.method, mock.path)
+	}
+	if out["id"] != "123" {
+		t.Errorf("out = %v, want decoded response", out)
+	}
+
+	err := client.Do(context.Background(), "GET", "", nil, nil)
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Path" {
+		t.Errorf("Do() error = %v, want required Path", err)
+	}
+}
+
+func TestClient_Do_Unimplemented(t *testing.T) {
+	client := &Client{provider: &mockProvider{name: "mock"}, config: &Config{}}
+
+	if err := client.Do(context.Background(), "GET", "/balance", nil, nil); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("Do() error = %v, want %v", err, ErrUnimplemented)
+	}
+}
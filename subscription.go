@@ -0,0 +1,296 @@
+package pg
+
+import (
+	"context"
+	"time"
+)
+
+// Event Type for subscription webhooks
+// Each billing cycle is reported with the payment event types (EventPaymentCompleted, ...)
+// and WebhookEvent.SubscriptionID set
+const (
+	EventSubscriptionActivated = "subscription.activated"
+	EventSubscriptionPaused    = "subscription.paused"
+	EventSubscriptionCancelled = "subscription.cancelled"
+)
+
+// Interval is the billing interval of a subscription
+type Interval string
+
+const (
+	IntervalDay   Interval = "DAY"
+	IntervalWeek  Interval = "WEEK"
+	IntervalMonth Interval = "MONTH"
+	IntervalYear  Interval = "YEAR"
+)
+
+// IsValid checks if the interval is valid
+func (i Interval) IsValid() bool {
+	switch i {
+	case IntervalDay, IntervalWeek, IntervalMonth, IntervalYear:
+		return true
+	}
+	return false
+}
+
+// SubscriptionStatus is the unified status of a subscription
+type SubscriptionStatus string
+
+const (
+	// SubscriptionPending means the subscription waits for the customer to authorize it
+	SubscriptionPending SubscriptionStatus = "PENDING"
+	// SubscriptionActive means the customer is billed every interval
+	SubscriptionActive SubscriptionStatus = "ACTIVE"
+	// SubscriptionPaused means billing is paused and can be resumed
+	SubscriptionPaused SubscriptionStatus = "PAUSED"
+	// SubscriptionCancelled means the subscription is stopped for good
+	SubscriptionCancelled SubscriptionStatus = "CANCELLED"
+)
+
+// Subscriber is implemented by providers that support recurring payments
+// Operations a provider does not offer return ErrUnimplemented
+type Subscriber interface {
+	CreateSubscription(ctx context.Context, params SubscriptionParams) (*Subscription, error)
+	GetSubscription(ctx context.Context, id string) (*Subscription, error)
+	UpdateSubscription(ctx context.Context, id string, params SubscriptionUpdateParams) (*Subscription, error)
+	PauseSubscription(ctx context.Context, id string) (*Subscription, error)
+	ResumeSubscription(ctx context.Context, id string) (*Subscription, error)
+	CancelSubscription(ctx context.Context, id string) (*Subscription, error)
+	ListSubscriptionPayments(ctx context.Context, id string) ([]*PaymentStatus, error)
+}
+
+// SubscriptionParams represents the parameters for creating a subscription
+type SubscriptionParams struct {
+	// ReferenceID is the merchant's unique reference for the subscription (required)
+	ReferenceID string `json:"reference_id"`
+
+	// Name is the subscription name shown to the customer
+	Name string `json:"name,omitempty"`
+
+	// Customer is the customer information (required)
+	// Xendit requires Customer.ID to be a Xendit customer ID
+	Customer Customer `json:"customer"`
+
+	// Amount is the amount billed every interval in smallest currency unit (required)
+	Amount int64 `json:"amount"`
+
+	// PaymentType is the payment method billed every interval, defaults to PaymentTypeCC
+	PaymentType PaymentType `json:"payment_type,omitempty"`
+
+	// PaymentToken is the saved payment method (required)
+	// A saved card token or GoPay payment option token for Midtrans, a payment method ID for Xendit
+	PaymentToken string `json:"payment_token"`
+
+	// Interval is the billing interval (required)
+	Interval Interval `json:"interval"`
+
+	// IntervalCount is the number of intervals between charges, defaults to 1
+	IntervalCount int `json:"interval_count,omitempty"`
+
+	// TotalCycles is the number of charges before the subscription ends, 0 means no end
+	TotalCycles int `json:"total_cycles,omitempty"`
+
+	// StartAt is when the first charge is made, zero means now
+	StartAt time.Time `json:"start_at,omitempty"`
+
+	// Description is the subscription description
+	Description string `json:"description,omitempty"`
+
+	// Custom contains provider-specific parameters that are not mapped to unified fields
+	Custom map[string]interface{} `json:"-"`
+}
+
+// SubscriptionUpdateParams represents the fields that can be changed on a subscription
+// Zero values are left unchanged
+type SubscriptionUpdateParams struct {
+	// Name is the new subscription name
+	Name string `json:"name,omitempty"`
+
+	// Amount is the new amount billed every interval
+	Amount int64 `json:"amount,omitempty"`
+
+	// PaymentToken is the new saved payment method
+	PaymentToken string `json:"payment_token,omitempty"`
+
+	// Interval is the new billing interval
+	Interval Interval `json:"interval,omitempty"`
+
+	// IntervalCount is the new number of intervals between charges
+	IntervalCount int `json:"interval_count,omitempty"`
+}
+
+// Subscription represents a subscription
+type Subscription struct {
+	// ID is the unique identifier from the payment provider
+	ID string `json:"id"`
+
+	// ReferenceID is the merchant's reference
+	ReferenceID string `json:"reference_id"`
+
+	// Name is the subscription name
+	Name string `json:"name,omitempty"`
+
+	// Amount is the amount billed every interval
+	Amount int64 `json:"amount"`
+
+	// Interval is the billing interval
+	Interval Interval `json:"interval"`
+
+	// IntervalCount is the number of intervals between charges
+	IntervalCount int `json:"interval_count"`
+
+	// Status is the current subscription status
+	Status SubscriptionStatus `json:"status"`
+
+	// ActionURL is where the customer authorizes the subscription when Status is PENDING
+	ActionURL string `json:"action_url,omitempty"`
+
+	// NextChargeAt is when the next charge is scheduled
+	NextChargeAt *time.Time `json:"next_charge_at,omitempty"`
+
+	// CreatedAt is when the subscription was created
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Raw contains the raw response from the provider
+	Raw map[string]interface{} `json:"-"`
+}
+
+// subscriber returns the provider as a Subscriber, or ErrUnimplemented
+func (c *Client) subscriber() (Subscriber, error) {
+	s, ok := c.provider.(Subscriber)
+	if !ok {
+		return nil, ErrUnimplemented
+	}
+	return s, nil
+}
+
+// CreateSubscription creates a subscription which charges the customer every interval
+// Returns ErrUnimplemented if the provider does not support subscriptions
+func (c *Client) CreateSubscription(ctx context.Context, params SubscriptionParams) (*Subscription, error) {
+	s, err := c.subscriber()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.CreateSubscription(ctx, params)
+}
+
+// GetSubscription retrieves a subscription
+func (c *Client) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	s, err := c.subscriber()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+
+	return s.GetSubscription(ctx, id)
+}
+
+// UpdateSubscription changes the amount, payment method or schedule of a subscription
+func (c *Client) UpdateSubscription(ctx context.Context, id string, params SubscriptionUpdateParams) (*Subscription, error) {
+	s, err := c.subscriber()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+	if params.Amount < 0 {
+		return nil, NewFieldError("Amount", "must not be negative")
+	}
+	if params.Interval != "" && !params.Interval.IsValid() {
+		return nil, NewFieldError("Interval", "invalid interval")
+	}
+
+	return s.UpdateSubscription(ctx, id, params)
+}
+
+// PauseSubscription stops billing a subscription until it is resumed
+func (c *Client) PauseSubscription(ctx context.Context, id string) (*Subscription, error) {
+	s, err := c.subscriber()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+
+	return s.PauseSubscription(ctx, id)
+}
+
+// ResumeSubscription resumes billing a paused subscription
+func (c *Client) ResumeSubscription(ctx context.Context, id string) (*Subscription, error) {
+	s, err := c.subscriber()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+
+	return s.ResumeSubscription(ctx, id)
+}
+
+// CancelSubscription stops a subscription for good
+func (c *Client) CancelSubscription(ctx context.Context, id string) (*Subscription, error) {
+	s, err := c.subscriber()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+
+	return s.CancelSubscription(ctx, id)
+}
+
+// ListSubscriptionPayments returns the charge of every billing cycle of a subscription
+func (c *Client) ListSubscriptionPayments(ctx context.Context, id string) ([]*PaymentStatus, error) {
+	s, err := c.subscriber()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+
+	return s.ListSubscriptionPayments(ctx, id)
+}
+
+// Validate validates the subscription parameters
+func (p SubscriptionParams) Validate() error {
+	if p.ReferenceID == "" {
+		return NewRequiredFieldError("ReferenceID")
+	}
+	if p.Customer.ID == "" {
+		return NewRequiredFieldError("Customer.ID")
+	}
+	if p.Amount <= 0 {
+		return NewFieldError("Amount", "must be greater than 0")
+	}
+	if p.PaymentToken == "" {
+		return NewRequiredFieldError("PaymentToken")
+	}
+	if !p.Interval.IsValid() {
+		return NewFieldError("Interval", "must be DAY, WEEK, MONTH or YEAR")
+	}
+	if p.IntervalCount < 0 {
+		return NewFieldError("IntervalCount", "must not be negative")
+	}
+	if p.TotalCycles < 0 {
+		return NewFieldError("TotalCycles", "must not be negative")
+	}
+	return nil
+}
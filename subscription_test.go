@@ -0,0 +1,166 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// mockSubscriber is a mock provider which also implements Subscriber
+type mockSubscriber struct {
+	mockProvider
+	calls int
+}
+
+func (m *mockSubscriber) CreateSubscription(ctx context.Context, params SubscriptionParams) (*Subscription, error) {
+	m.calls++
+	return &Subscription{ID: "sub-123", ReferenceID: params.ReferenceID, Status: SubscriptionActive}, nil
+}
+
+func (m *mockSubscriber) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	m.calls++
+	return &Subscription{ID: id, Status: SubscriptionActive}, nil
+}
+
+func (m *mockSubscriber) UpdateSubscription(ctx context.Context, id string, params SubscriptionUpdateParams) (*Subscription, error) {
+	m.calls++
+	return &Subscription{ID: id, Amount: params.Amount, Status: SubscriptionActive}, nil
+}
+
+func (m *mockSubscriber) PauseSubscription(ctx context.Context, id string) (*Subscription, error) {
+	m.calls++
+	return &Subscription{ID: id, Status: SubscriptionPaused}, nil
+}
+
+func (m *mockSubscriber) ResumeSubscription(ctx context.Context, id string) (*Subscription, error) {
+	m.calls++
+	return &Subscription{ID: id, Status: SubscriptionActive}, nil
+}
+
+func (m *mockSubscriber) CancelSubscription(ctx context.Context, id string) (*Subscription, error) {
+	m.calls++
+	return &Subscription{ID: id, Status: SubscriptionCancelled}, nil
+}
+
+func (m *mockSubscriber) ListSubscriptionPayments(ctx context.Context, id string) ([]*PaymentStatus, error) {
+	m.calls++
+	return []*PaymentStatus{{TransactionID: "txn-1", SubscriptionID: id, Status: StatusSuccess}}, nil
+}
+
+func validSubscriptionParams() SubscriptionParams {
+	return SubscriptionParams{
+		ReferenceID:  "SUB-001",
+		Customer:     Customer{ID: "CUST-001", Name: "John Doe"},
+		Amount:       50000,
+		PaymentToken: "token-123",
+		Interval:     IntervalMonth,
+	}
+}
+
+func TestSubscriptionParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(p *SubscriptionParams)
+		wantErr bool
+	}{
+		{name: "valid params", modify: func(p *SubscriptionParams) {}},
+		{name: "missing reference ID", modify: func(p *SubscriptionParams) { p.ReferenceID = "" }, wantErr: true},
+		{name: "missing customer ID", modify: func(p *SubscriptionParams) { p.Customer.ID = "" }, wantErr: true},
+		{name: "zero amount", modify: func(p *SubscriptionParams) { p.Amount = 0 }, wantErr: true},
+		{name: "missing payment token", modify: func(p *SubscriptionParams) { p.PaymentToken = "" }, wantErr: true},
+		{name: "invalid interval", modify: func(p *SubscriptionParams) { p.Interval = "HOUR" }, wantErr: true},
+		{name: "negative interval count", modify: func(p *SubscriptionParams) { p.IntervalCount = -1 }, wantErr: true},
+		{name: "negative total cycles", modify: func(p *SubscriptionParams) { p.TotalCycles = -1 }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validSubscriptionParams()
+			tt.modify(&params)
+
+			err := params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Subscription_Unimplemented(t *testing.T) {
+	client := &Client{provider: &mockProvider{name: "mock"}, config: &Config{}}
+	ctx := context.Background()
+
+	if _, err := client.CreateSubscription(ctx, validSubscriptionParams()); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("CreateSubscription() error = %v, want %v", err, ErrUnimplemented)
+	}
+	if _, err := client.PauseSubscription(ctx, "sub-123"); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("PauseSubscription() error = %v, want %v", err, ErrUnimplemented)
+	}
+	if _, err := client.ListSubscriptionPayments(ctx, "sub-123"); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("ListSubscriptionPayments() error = %v, want %v", err, ErrUnimplemented)
+	}
+}
+
+func TestClient_Subscription(t *testing.T) {
+	mock := &mockSubscriber{}
+	client := &Client{provider: mock, config: &Config{}}
+	ctx := context.Background()
+
+	invalid := validSubscriptionParams()
+	invalid.Amount = 0
+	if _, err := client.CreateSubscription(ctx, invalid); err == nil {
+		t.Error("expected validation error, got nil")
+	}
+	if mock.calls != 0 {
+		t.Errorf("provider calls = %v, want 0", mock.calls)
+	}
+
+	sub, err := client.CreateSubscription(ctx, validSubscriptionParams())
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+	if sub.ReferenceID != "SUB-001" {
+		t.Errorf("ReferenceID = %v, want SUB-001", sub.ReferenceID)
+	}
+
+	if _, err := client.GetSubscription(ctx, ""); err == nil {
+		t.Error("expected error for empty ID, got nil")
+	}
+	if _, err := client.UpdateSubscription(ctx, "sub-123", SubscriptionUpdateParams{Interval: "HOUR"}); err == nil {
+		t.Error("expected error for invalid interval, got nil")
+	}
+
+	tests := []struct {
+		name string
+		call func(id string) (*Subscription, error)
+		want SubscriptionStatus
+	}{
+		{name: "pause", call: func(id string) (*Subscription, error) { return client.PauseSubscription(ctx, id) }, want: SubscriptionPaused},
+		{name: "resume", call: func(id string) (*Subscription, error) { return client.ResumeSubscription(ctx, id) }, want: SubscriptionActive},
+		{name: "cancel", call: func(id string) (*Subscription, error) { return client.CancelSubscription(ctx, id) }, want: SubscriptionCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.call(""); err == nil {
+				t.Error("expected error for empty ID, got nil")
+			}
+
+			sub, err := tt.call("sub-123")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sub.Status != tt.want {
+				t.Errorf("Status = %v, want %v", sub.Status, tt.want)
+			}
+		})
+	}
+
+	payments, err := client.ListSubscriptionPayments(ctx, "sub-123")
+	if err != nil {
+		t.Fatalf("ListSubscriptionPayments() error = %v", err)
+	}
+	if len(payments) != 1 || payments[0].SubscriptionID != "sub-123" {
+		t.Errorf("payments = %+v, want one payment of sub-123", payments)
+	}
+}
@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"net/http"
 	"time"
 )
 
@@ -17,6 +18,12 @@ type Customer struct {
 
 	// Phone is the customer's phone number
 	Phone string `json:"phone"`
+
+	// BillingAddress is the billing address (optional), used for fraud scoring and paylater approval
+	BillingAddress *Address `json:"billing_address,omitempty"`
+
+	// ShippingAddress is the shipping address (optional)
+	ShippingAddress *Address `json:"shipping_address,omitempty"`
 }
 
 // Item represents a transaction item
@@ -57,6 +64,10 @@ type ChargeParams struct {
 	// Items is the list of transaction items
 	Items []Item `json:"items,omitempty"`
 
+	// Adjustments are discount, fee, tax and shipping lines
+	// When Items or Adjustments are set, Amount must equal the items plus the adjustments
+	Adjustments []Adjustment `json:"adjustments,omitempty"`
+
 	// Description is the transaction description
 	Description string `json:"description,omitempty"`
 
@@ -69,8 +80,28 @@ type ChargeParams struct {
 	// ReturnURL is the URL to redirect after payment
 	ReturnURL string `json:"return_url,omitempty"`
 
+	// CreditCard contains card specific parameters (required for card charges with a token)
+	CreditCard *CreditCardParams `json:"credit_card,omitempty"`
+
+	// VirtualAccount contains virtual account specific parameters (optional)
+	VirtualAccount *VirtualAccountParams `json:"virtual_account,omitempty"`
+
+	// EWallet contains e-wallet specific parameters (optional)
+	EWallet *EWALLETParams `json:"ewallet,omitempty"`
+
+	// Paylater contains paylater specific parameters (optional)
+	Paylater *PaylaterParams `json:"paylater,omitempty"`
+
+	// DirectDebit contains direct debit specific parameters (required for direct debit)
+	DirectDebit *DirectDebitParams `json:"direct_debit,omitempty"`
+
+	// Metadata is merchant data kept with the charge and returned on PaymentStatus and WebhookEvent
+	// Midtrans keeps at most 3 entries in its custom fields
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	// Custom contains provider-specific parameters that are not mapped to unified fields
 	// This allows access to provider-specific features
+	// Keys the provider does not read as options are deep-merged into the provider request body, see MergeCustom
 	Custom map[string]interface{} `json:"-"`
 }
 
@@ -82,6 +113,10 @@ type ChargeResponse struct {
 	// OrderID is the merchant's order ID
 	OrderID string `json:"order_id"`
 
+	// Reference identifies the payment in GetStatus and Cancel
+	// It is the OrderID unless the provider needs more, e.g. the "trx_id:bill_no" of Faspay
+	Reference string `json:"reference"`
+
 	// Amount is the transaction amount
 	Amount int64 `json:"amount"`
 
@@ -103,6 +138,12 @@ type ChargeResponse struct {
 	// ExpiryTime is when the payment will expire
 	ExpiryTime time.Time `json:"expiry_time,omitempty"`
 
+	// InstallmentPlans are the installment options offered for paylater payments
+	InstallmentPlans []InstallmentPlan `json:"installment_plans,omitempty"`
+
+	// NextActions are the steps the customer must take to complete the payment
+	NextActions []Action `json:"next_actions,omitempty"`
+
 	// CreatedAt is when the transaction was created
 	CreatedAt time.Time `json:"created_at,omitempty"`
 
@@ -145,6 +186,12 @@ type PaymentStatus struct {
 	// FailureReason is the reason for payment failure (if applicable)
 	FailureReason string `json:"failure_reason,omitempty"`
 
+	// SubscriptionID is the subscription which created this payment (if applicable)
+	SubscriptionID string `json:"subscription_id,omitempty"`
+
+	// Metadata is the merchant data sent with the charge
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	// Raw contains the raw response from the provider
 	Raw map[string]interface{} `json:"-"`
 }
@@ -157,12 +204,20 @@ type WebhookEvent struct {
 	// TransactionID is the unique identifier from the payment provider
 	TransactionID string `json:"transaction_id"`
 
+	// Reference identifies the payment in GetStatus and Cancel, see ChargeResponse.Reference
+	Reference string `json:"reference"`
+
 	// Status is the payment status from the webhook
 	Status Status `json:"status"`
 
 	// Amount is the transaction amount
+	// For open amount and reusable VAs it is the amount of this individual payment
 	Amount int64 `json:"amount"`
 
+	// PaidAmount is the total paid into an open amount or reusable VA so far, including this payment
+	// It is returned by the PaymentLedger set with WithPaymentLedger, zero without one
+	PaidAmount int64 `json:"paid_amount,omitempty"`
+
 	// PaymentType is the type of payment method
 	PaymentType PaymentType `json:"payment_type,omitempty"`
 
@@ -175,8 +230,51 @@ type WebhookEvent struct {
 	// FraudStatus is the fraud status (if applicable)
 	FraudStatus string `json:"fraud_status,omitempty"`
 
+	// SubscriptionID is the subscription the event belongs to (if applicable)
+	SubscriptionID string `json:"subscription_id,omitempty"`
+
+	// Metadata is the merchant data sent with the charge
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	// Raw contains the raw webhook payload from the provider
 	Raw map[string]interface{} `json:"-"`
+
+	// Acknowledgement is the response body the provider expects in reply to the webhook
+	// It is nil when a plain HTTP 200 is enough
+	Acknowledgement *WebhookAcknowledgement `json:"-"`
+}
+
+// WebhookAcknowledgement represents the response a provider requires after delivering a webhook
+type WebhookAcknowledgement struct {
+	// StatusCode is the HTTP status code of the acknowledgement, 0 means HTTP 200
+	StatusCode int
+
+	// ContentType is the content type of the acknowledgement body
+	ContentType string
+
+	// Body is the acknowledgement body
+	Body []byte
+}
+
+// Acknowledge writes the acknowledgement expected by the provider to w
+// If the provider does not require a specific body, it writes HTTP 200 with an empty body
+func (e *WebhookEvent) Acknowledge(w http.ResponseWriter) error {
+	if e.Acknowledgement == nil {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	statusCode := e.Acknowledgement.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	if e.Acknowledgement.ContentType != "" {
+		w.Header().Set("Content-Type", e.Acknowledgement.ContentType)
+	}
+	w.WriteHeader(statusCode)
+	_, err := w.Write(e.Acknowledgement.Body)
+	return err
 }
 
 // ProviderError represents an error from the payment provider
@@ -243,7 +341,8 @@ type CreditCardParams struct {
 	// InstallmentTerm is the installment term in months (0 for full payment)
 	InstallmentTerm int `json:"installment_term,omitempty"`
 
-	// Bank is the acquiring bank for installment
+	// Bank is the acquiring bank for installment, a unified bank code such as BCA or BNI
+	// Required when InstallmentTerm is set
 	Bank string `json:"bank,omitempty"`
 }
 
@@ -257,6 +356,19 @@ type VirtualAccountParams struct {
 
 	// CustomerName is the customer name for VA display
 	CustomerName string `json:"customer_name,omitempty"`
+
+	// OpenAmount lets the customer pay any amount instead of exactly ChargeParams.Amount
+	// ChargeParams.Amount may be zero for open amount VAs
+	OpenAmount bool `json:"open_amount,omitempty"`
+
+	// Reusable keeps the VA active after a payment, e.g. a permanent per-customer top-up VA
+	Reusable bool `json:"reusable,omitempty"`
+
+	// MinAmount is the minimum accepted payment for open amount VAs (optional)
+	MinAmount int64 `json:"min_amount,omitempty"`
+
+	// MaxAmount is the maximum accepted payment for open amount VAs (optional)
+	MaxAmount int64 `json:"max_amount,omitempty"`
 }
 
 // EWALLETParams represents e-wallet specific parameters
@@ -264,10 +376,55 @@ type EWALLETParams struct {
 	// RedirectToApp indicates if user should be redirected to e-wallet app
 	RedirectToApp bool `json:"redirect_to_app,omitempty"`
 
-	// AccountLinkID is the account link ID for recurring payments
+	// AccountLinkID is the LinkedAccount.ID to charge without asking the customer again
+	// For subscriptions use SubscriptionParams.PaymentToken instead
 	AccountLinkID string `json:"account_link_id,omitempty"`
 }
 
+// PaylaterParams represents paylater specific parameters
+type PaylaterParams struct {
+	// Tenure is the number of monthly installments (optional)
+	// When empty the customer picks the tenure on the paylater page
+	Tenure int `json:"tenure,omitempty"`
+
+	// FailureURL is the URL to redirect after a rejected or cancelled application
+	// Defaults to ChargeParams.ReturnURL
+	FailureURL string `json:"failure_url,omitempty"`
+}
+
+// DirectDebitParams represents direct debit specific parameters
+type DirectDebitParams struct {
+	// AccountLinkID is the LinkedAccount.ID of the bound bank account (required)
+	AccountLinkID string `json:"account_link_id"`
+
+	// AccountToken is the LinkedAccount.Token of the bound bank account, required by Doku
+	AccountToken string `json:"-"`
+}
+
+// InstallmentPlan represents an installment option of a paylater or card payment
+type InstallmentPlan struct {
+	// Tenure is the number of monthly installments
+	Tenure int `json:"tenure"`
+
+	// Bank is the issuing bank of a card installment plan
+	Bank string `json:"bank,omitempty"`
+
+	// InstallmentAmount is the amount of each installment
+	InstallmentAmount int64 `json:"installment_amount"`
+
+	// DownPayment is the amount paid upfront (if applicable)
+	DownPayment int64 `json:"down_payment,omitempty"`
+
+	// TotalAmount is the total amount paid over the tenure
+	TotalAmount int64 `json:"total_amount"`
+
+	// InterestRate is the monthly interest rate in percent
+	InterestRate float64 `json:"interest_rate,omitempty"`
+
+	// Description is the plan description from the provider
+	Description string `json:"description,omitempty"`
+}
+
 // QRISParams represents QRIS specific parameters
 type QRISParams struct {
 	// QRString is the QR code string (generated by provider)
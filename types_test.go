@@ -2,6 +2,8 @@ package pg
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -395,6 +397,68 @@ func TestWebhookEvent_AllFields(t *testing.T) {
 	}
 }
 
+func TestWebhookEvent_Acknowledge(t *testing.T) {
+	t.Run("without acknowledgement", func(t *testing.T) {
+		event := &WebhookEvent{OrderID: "ORDER-001"}
+		rec := httptest.NewRecorder()
+
+		if err := event.Acknowledge(rec); err != nil {
+			t.Fatalf("Acknowledge() error = %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %v, want %v", rec.Code, http.StatusOK)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("body = %q, want empty", rec.Body.String())
+		}
+	})
+
+	t.Run("with acknowledgement", func(t *testing.T) {
+		event := &WebhookEvent{
+			OrderID: "ORDER-001",
+			Acknowledgement: &WebhookAcknowledgement{
+				ContentType: "application/json",
+				Body:        []byte(`{"response_code":"00"}`),
+			},
+		}
+		rec := httptest.NewRecorder()
+
+		if err := event.Acknowledge(rec); err != nil {
+			t.Fatalf("Acknowledge() error = %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %v, want %v", rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %v, want application/json", got)
+		}
+		if rec.Body.String() != `{"response_code":"00"}` {
+			t.Errorf("body = %q", rec.Body.String())
+		}
+	})
+
+	t.Run("with status code", func(t *testing.T) {
+		event := &WebhookEvent{
+			Acknowledgement: &WebhookAcknowledgement{
+				StatusCode:  http.StatusNotFound,
+				ContentType: "application/json",
+				Body:        []byte(`{"responseCode":"4042412"}`),
+			},
+		}
+		rec := httptest.NewRecorder()
+
+		if err := event.Acknowledge(rec); err != nil {
+			t.Fatalf("Acknowledge() error = %v", err)
+		}
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("status = %v, want %v", rec.Code, http.StatusNotFound)
+		}
+	})
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsHelper(s, substr))
 }
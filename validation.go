@@ -0,0 +1,178 @@
+package pg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxOrderIDLength is the longest order ID accepted by every provider
+const maxOrderIDLength = 100
+
+// emailRegex is the regex pattern for email validation
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+
+// phoneRegex is the regex pattern for phone number validation (Indonesia format)
+var phoneRegex = regexp.MustCompile(`^(\+62|62)[0-9]{9,13}$`)
+
+// Validate checks the charge parameters shared by every provider and returns all problems at once
+// Order ID, amount, customer email and phone, items, adjustments, payment type and expiry are checked,
+// and the items plus adjustments must add up to the amount.
+// Payment types and amount limits come from the provider capabilities when it reports them,
+// provider may be nil to only run the generic checks.
+// The returned error is a *ValidationError whose FieldErrors carry a stable Code
+func (p ChargeParams) Validate(provider Provider) error {
+	verr := NewValidationError()
+
+	var name string
+	var caps *Capabilities
+	if provider != nil {
+		name = provider.Name()
+		if r, ok := provider.(CapabilityReporter); ok {
+			c := r.Capabilities()
+			caps = &c
+		}
+	}
+
+	// Validate order ID
+	if p.OrderID == "" {
+		verr.Add(NewRequiredFieldError("OrderID"))
+	} else if len(p.OrderID) > maxOrderIDLength {
+		verr.Add(&FieldError{
+			Field:   "OrderID",
+			Code:    CodeTooLong,
+			Message: fmt.Sprintf("must be at most %d characters", maxOrderIDLength),
+			Err:     ErrInvalidParameter,
+		})
+	}
+
+	// Validate payment type
+	switch {
+	case p.PaymentType == "":
+		verr.Add(NewRequiredFieldError("PaymentType"))
+	case !p.PaymentType.IsValid():
+		verr.Add(NewFieldError("PaymentType", fmt.Sprintf("%s is not a known payment type", p.PaymentType)))
+	case caps != nil && !caps.SupportsPaymentType(p.PaymentType):
+		verr.Add(&FieldError{
+			Field:   "PaymentType",
+			Code:    CodeUnsupported,
+			Message: fmt.Sprintf("payment type %s is not supported by %s", p.PaymentType, name),
+			Err:     ErrUnsupported,
+		})
+	}
+
+	// Validate amount, open amount VAs may leave it empty
+	if !p.IsOpenAmount() || p.Amount > 0 {
+		limit := AmountLimit{Min: DefaultMinAmount(p.PaymentType)}
+		if caps != nil {
+			if l, ok := caps.Limit(p.PaymentType); ok {
+				limit = l
+			}
+		}
+		verr.Merge(limit.Validate(name, p.PaymentType, p.Amount))
+	}
+
+	// Validate customer
+	verr.Merge(ValidateEmail(p.Customer.Email, "Customer.Email"))
+	if p.Customer.Phone != "" {
+		verr.Merge(ValidatePhone(p.Customer.Phone, "Customer.Phone"))
+	}
+
+	// Validate items
+	for i, item := range p.Items {
+		field := fmt.Sprintf("Items[%d]", i)
+		if item.Name == "" {
+			verr.Add(NewRequiredFieldError(field + ".Name"))
+		}
+		if item.Price < 0 {
+			verr.Add(NewFieldError(field+".Price", "must not be negative"))
+		}
+		if item.Quantity <= 0 {
+			verr.Add(NewFieldError(field+".Quantity", "must be at least 1"))
+		}
+	}
+
+	// Validate adjustments
+	for i, a := range p.Adjustments {
+		field := fmt.Sprintf("Adjustments[%d]", i)
+		if !a.Type.IsValid() {
+			verr.Add(NewFieldError(field+".Type", "must be one of: DISCOUNT, FEE, TAX, SHIPPING"))
+		}
+		if a.Amount <= 0 {
+			verr.Add(NewFieldError(field+".Amount", "must be positive"))
+		}
+	}
+
+	// The line items sent to the provider (items plus adjustments) must add up to the amount,
+	// e.g. Midtrans rejects item_details not matching gross_amount
+	if lines := p.LineItems(); len(lines) > 0 && p.Amount > 0 {
+		var gross int64
+		for _, item := range lines {
+			gross += item.Price * item.Quantity
+		}
+		if gross != p.Amount {
+			verr.Add(&FieldError{
+				Field:   "Amount",
+				Code:    CodeMismatch,
+				Message: fmt.Sprintf("must equal the items plus adjustments (Rp%d)", gross),
+				Err:     ErrInvalidParameter,
+			})
+		}
+	}
+
+	// Validate expiry
+	if !p.ExpiryTime.IsZero() && !p.ExpiryTime.After(time.Now()) {
+		verr.Add(&FieldError{
+			Field:   "ExpiryTime",
+			Code:    CodeExpired,
+			Message: "must be in the future",
+			Err:     ErrInvalidParameter,
+		})
+	}
+
+	return verr.ToError()
+}
+
+// ValidateEmail validates email format
+func ValidateEmail(email, fieldName string) error {
+	if email == "" {
+		return NewRequiredFieldError(fieldName)
+	}
+	if !emailRegex.MatchString(email) {
+		return &FieldError{
+			Field:   fieldName,
+			Code:    CodeInvalidFormat,
+			Message: "must be a valid email address",
+			Err:     ErrInvalidParameter,
+		}
+	}
+	return nil
+}
+
+// ValidatePhone validates phone number for Indonesia format
+// Accepts formats: +62812345678, 62812345678, 0812345678
+func ValidatePhone(phone, fieldName string) error {
+	if phone == "" {
+		return NewRequiredFieldError(fieldName)
+	}
+
+	// Remove spaces and dashes
+	cleaned := strings.ReplaceAll(strings.ReplaceAll(phone, " ", ""), "-", "")
+
+	// Convert 08... to +628...
+	if strings.HasPrefix(cleaned, "08") {
+		cleaned = "+62" + cleaned[1:]
+	}
+
+	if !phoneRegex.MatchString(cleaned) {
+		return &FieldError{
+			Field:   fieldName,
+			Code:    CodeInvalidFormat,
+			Message: "must be a valid Indonesian phone number (e.g., +628123456789)",
+			Err:     ErrInvalidPhoneNumber,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package pg
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validChargeParams() ChargeParams {
+	return ChargeParams{
+		OrderID:     "ORDER-001",
+		Amount:      50000,
+		PaymentType: PaymentTypeVABCA,
+		Customer:    Customer{Email: "john@example.com", Phone: "081234567890"},
+		Items:       []Item{{ID: "ITEM-001", Name: "Product", Price: 50000, Quantity: 1}},
+	}
+}
+
+func TestChargeParams_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(p *ChargeParams)
+		wantField string
+		wantCode  ErrorCode
+	}{
+		{name: "valid", modify: func(p *ChargeParams) {}},
+		{name: "missing order ID", modify: func(p *ChargeParams) { p.OrderID = "" }, wantField: "OrderID", wantCode: CodeRequired},
+		{name: "long order ID", modify: func(p *ChargeParams) { p.OrderID = strings.Repeat("A", 101) }, wantField: "OrderID", wantCode: CodeTooLong},
+		{name: "missing payment type", modify: func(p *ChargeParams) { p.PaymentType = "" }, wantField: "PaymentType", wantCode: CodeRequired},
+		{name: "unknown payment type", modify: func(p *ChargeParams) { p.PaymentType = "BITCOIN" }, wantField: "PaymentType", wantCode: CodeInvalid},
+		{name: "amount too small", modify: func(p *ChargeParams) { p.Amount = 5000; p.Items[0].Price = 5000 }, wantField: "Amount", wantCode: CodeMinAmount},
+		{name: "open amount VA", modify: func(p *ChargeParams) { p.Amount = 0; p.VirtualAccount = &VirtualAccountParams{OpenAmount: true} }},
+		{name: "missing email", modify: func(p *ChargeParams) { p.Customer.Email = "" }, wantField: "Customer.Email", wantCode: CodeRequired},
+		{name: "invalid email", modify: func(p *ChargeParams) { p.Customer.Email = "invalid" }, wantField: "Customer.Email", wantCode: CodeInvalidFormat},
+		{name: "optional phone", modify: func(p *ChargeParams) { p.Customer.Phone = "" }},
+		{name: "invalid phone", modify: func(p *ChargeParams) { p.Customer.Phone = "12345" }, wantField: "Customer.Phone", wantCode: CodeInvalidFormat},
+		{name: "item without name", modify: func(p *ChargeParams) { p.Items[0].Name = "" }, wantField: "Items[0].Name", wantCode: CodeRequired},
+		{name: "item without quantity", modify: func(p *ChargeParams) { p.Items = append(p.Items, Item{Name: "Gift"}) }, wantField: "Items[1].Quantity", wantCode: CodeInvalid},
+		{name: "items mismatch", modify: func(p *ChargeParams) { p.Items[0].Quantity = 2 }, wantField: "Amount", wantCode: CodeMismatch},
+		{
+			name: "adjustments",
+			modify: func(p *ChargeParams) {
+				p.Adjustments = []Adjustment{{Type: AdjustmentDiscount, Amount: 5000}, {Type: AdjustmentShipping, Amount: 10000}}
+				p.Amount = 55000
+			},
+		},
+		{
+			name: "adjustments without items",
+			modify: func(p *ChargeParams) {
+				p.Items = nil
+				p.Adjustments = []Adjustment{{Type: AdjustmentShipping, Amount: 10000}}
+			},
+			wantField: "Amount",
+			wantCode:  CodeMismatch,
+		},
+		{name: "unknown adjustment", modify: func(p *ChargeParams) { p.Adjustments = []Adjustment{{Type: "TIP", Amount: 1000}}; p.Amount = 51000 }, wantField: "Adjustments[0].Type", wantCode: CodeInvalid},
+		{name: "expired", modify: func(p *ChargeParams) { p.ExpiryTime = time.Now().Add(-time.Minute) }, wantField: "ExpiryTime", wantCode: CodeExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validChargeParams()
+			tt.modify(&params)
+
+			err := params.Validate(nil)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) || len(verr.Errors) != 1 {
+				t.Fatalf("Validate() error = %v, want one field error", err)
+			}
+			if verr.Errors[0].Field != tt.wantField || verr.Errors[0].Code != tt.wantCode {
+				t.Errorf("field error = %+v, want %s %s", verr.Errors[0], tt.wantField, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestChargeParams_Validate_Aggregated(t *testing.T) {
+	params := ChargeParams{Amount: 100, Customer: Customer{Email: "invalid"}}
+
+	err := params.Validate(nil)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate() error = %v, want *ValidationError", err)
+	}
+	if len(verr.Errors) != 4 {
+		t.Errorf("got %d errors, want 4: %v", len(verr.Errors), verr.Errors)
+	}
+
+	body, _ := json.Marshal(verr)
+	if !strings.Contains(string(body), `{"field":"OrderID","code":"required","message":"is required"}`) {
+		t.Errorf("json = %s, want the OrderID error", body)
+	}
+}
+
+func TestChargeParams_Validate_Capabilities(t *testing.T) {
+	provider := &mockReporter{
+		mockProvider: mockProvider{name: "mock"},
+		caps: Capabilities{
+			PaymentTypes: []PaymentType{PaymentTypeVABCA},
+			Limits:       map[PaymentType]AmountLimit{PaymentTypeVABCA: {Min: 10000, Max: 40000}},
+		},
+	}
+
+	params := validChargeParams()
+	err := params.Validate(provider)
+	if !errors.Is(err, ErrMaxAmount) || !strings.Contains(err.Error(), "on mock") {
+		t.Errorf("Validate() error = %v, want the mock maximum", err)
+	}
+
+	params.PaymentType = PaymentTypeOVO
+	if err := params.Validate(provider); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrUnsupported)
+	}
+}
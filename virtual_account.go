@@ -0,0 +1,130 @@
+package pg
+
+import (
+	"context"
+	"time"
+)
+
+// VirtualAccountUpdater is implemented by providers that can update an issued virtual account
+type VirtualAccountUpdater interface {
+	UpdateVirtualAccount(ctx context.Context, id string, params VirtualAccountUpdateParams) (*ChargeResponse, error)
+}
+
+// VirtualAccountUpdateParams represents the changes to an issued virtual account
+// Zero values are left unchanged
+type VirtualAccountUpdateParams struct {
+	// Amount is the new expected amount of a closed amount VA
+	Amount int64 `json:"amount,omitempty"`
+
+	// ExpiryTime is the new expiry time
+	ExpiryTime time.Time `json:"expiry_time,omitempty"`
+
+	// Reusable switches the VA between reusable and single use
+	Reusable *bool `json:"reusable,omitempty"`
+
+	// Description is the new VA description
+	Description string `json:"description,omitempty"`
+}
+
+// Bill is the amount due on a virtual account, answered to a provider inquiry before a payment
+type Bill struct {
+	// OrderID is the order paid with the virtual account
+	OrderID string `json:"order_id"`
+
+	// Name is the name shown to the customer, e.g. the customer name
+	Name string `json:"name"`
+
+	// Amount is the amount due
+	Amount int64 `json:"amount"`
+
+	// PaymentType is the VA payment type, e.g. VA_BCA
+	PaymentType PaymentType `json:"payment_type,omitempty"`
+}
+
+// BillResolver returns the bill of a virtual account number when the provider asks for it before a payment (Espay)
+// It returns a nil Bill when nothing is due on the VA
+type BillResolver func(ctx context.Context, vaNumber string) (*Bill, error)
+
+// VAPayment is a payment received by an open amount or reusable virtual account
+type VAPayment struct {
+	// VirtualAccountID is the provider ID of the virtual account, the ChargeResponse.TransactionID
+	VirtualAccountID string `json:"virtual_account_id"`
+
+	// OrderID is the order of the virtual account
+	OrderID string `json:"order_id"`
+
+	// PaymentID is the provider ID of the payment, a redelivered webhook carries the same one
+	PaymentID string `json:"payment_id"`
+
+	// Amount is the amount of this payment
+	Amount int64 `json:"amount"`
+}
+
+// PaymentLedger records a payment received by a virtual account and returns the total received by
+// the VA so far, including the payment. A redelivered payment (same PaymentID) must be counted once
+// Providers which report each payment into a VA separately (Xendit) call it from ParseWebhook
+type PaymentLedger func(ctx context.Context, payment VAPayment) (int64, error)
+
+// virtualAccountUpdater returns the provider as a VirtualAccountUpdater, or ErrUnimplemented
+func (c *Client) virtualAccountUpdater() (VirtualAccountUpdater, error) {
+	u, ok := c.provider.(VirtualAccountUpdater)
+	if !ok {
+		return nil, ErrUnimplemented
+	}
+	return u, nil
+}
+
+// UpdateVirtualAccount updates the amount, expiry or reusability of a virtual account
+// id is the ChargeResponse.TransactionID returned when the VA was created
+// Returns ErrUnimplemented if the provider does not support updating virtual accounts
+func (c *Client) UpdateVirtualAccount(ctx context.Context, id string, params VirtualAccountUpdateParams) (*ChargeResponse, error) {
+	u, err := c.virtualAccountUpdater()
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		return nil, NewRequiredFieldError("ID")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return u.UpdateVirtualAccount(ctx, id, params)
+}
+
+// Validate validates the virtual account parameters
+func (p *VirtualAccountParams) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.MinAmount < 0 {
+		return NewFieldError("VirtualAccount.MinAmount", "must not be negative")
+	}
+	if p.MaxAmount < 0 {
+		return NewFieldError("VirtualAccount.MaxAmount", "must not be negative")
+	}
+	if (p.MinAmount > 0 || p.MaxAmount > 0) && !p.OpenAmount {
+		return NewFieldError("VirtualAccount.OpenAmount", "min and max amounts require an open amount VA")
+	}
+	if p.MinAmount > 0 && p.MaxAmount > 0 && p.MinAmount > p.MaxAmount {
+		return NewFieldError("VirtualAccount.MinAmount", "must not be greater than MaxAmount")
+	}
+	return nil
+}
+
+// IsOpenAmount reports whether the charge is an open amount virtual account
+func (p ChargeParams) IsOpenAmount() bool {
+	return p.VirtualAccount != nil && p.VirtualAccount.OpenAmount
+}
+
+// Validate validates the virtual account update parameters
+func (p VirtualAccountUpdateParams) Validate() error {
+	if p.Amount < 0 {
+		return NewFieldError("Amount", "must not be negative")
+	}
+	if p.Amount == 0 && p.ExpiryTime.IsZero() && p.Reusable == nil && p.Description == "" {
+		return NewFieldError("VirtualAccountUpdateParams", "nothing to update")
+	}
+	return nil
+}
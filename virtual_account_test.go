@@ -0,0 +1,82 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// mockVAUpdater is a mock provider which also implements VirtualAccountUpdater
+type mockVAUpdater struct {
+	mockProvider
+	calls int
+}
+
+func (m *mockVAUpdater) UpdateVirtualAccount(ctx context.Context, id string, params VirtualAccountUpdateParams) (*ChargeResponse, error) {
+	m.calls++
+	return &ChargeResponse{TransactionID: id, Amount: params.Amount, Status: StatusPending}, nil
+}
+
+func TestVirtualAccountParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *VirtualAccountParams
+		wantErr bool
+	}{
+		{name: "nil params"},
+		{name: "closed", params: &VirtualAccountParams{VANumber: "8808123"}},
+		{name: "open with limits", params: &VirtualAccountParams{OpenAmount: true, Reusable: true, MinAmount: 10000, MaxAmount: 1000000}},
+		{name: "limits on closed VA", params: &VirtualAccountParams{MinAmount: 10000}, wantErr: true},
+		{name: "min greater than max", params: &VirtualAccountParams{OpenAmount: true, MinAmount: 50000, MaxAmount: 10000}, wantErr: true},
+		{name: "negative max", params: &VirtualAccountParams{OpenAmount: true, MaxAmount: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChargeParams_IsOpenAmount(t *testing.T) {
+	if (ChargeParams{}).IsOpenAmount() {
+		t.Error("IsOpenAmount() = true without VirtualAccount")
+	}
+	if !(ChargeParams{VirtualAccount: &VirtualAccountParams{OpenAmount: true}}).IsOpenAmount() {
+		t.Error("IsOpenAmount() = false for open VA")
+	}
+}
+
+func TestClient_UpdateVirtualAccount(t *testing.T) {
+	ctx := context.Background()
+
+	client := &Client{provider: &mockProvider{name: "mock"}, config: &Config{}}
+	if _, err := client.UpdateVirtualAccount(ctx, "va-123", VirtualAccountUpdateParams{Amount: 50000}); !errors.Is(err, ErrUnimplemented) {
+		t.Errorf("UpdateVirtualAccount() error = %v, want %v", err, ErrUnimplemented)
+	}
+
+	mock := &mockVAUpdater{}
+	client = &Client{provider: mock, config: &Config{}}
+
+	if _, err := client.UpdateVirtualAccount(ctx, "", VirtualAccountUpdateParams{Amount: 50000}); err == nil {
+		t.Error("expected error for empty ID, got nil")
+	}
+	if _, err := client.UpdateVirtualAccount(ctx, "va-123", VirtualAccountUpdateParams{}); err == nil {
+		t.Error("expected error for empty update, got nil")
+	}
+	if mock.calls != 0 {
+		t.Errorf("provider calls = %v, want 0", mock.calls)
+	}
+
+	resp, err := client.UpdateVirtualAccount(ctx, "va-123", VirtualAccountUpdateParams{Amount: 75000, ExpiryTime: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("UpdateVirtualAccount() error = %v", err)
+	}
+	if resp.Amount != 75000 {
+		t.Errorf("Amount = %v, want 75000", resp.Amount)
+	}
+}
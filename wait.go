@@ -0,0 +1,160 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// ErrWaitExpired is returned by WaitForStatus when the expiry time passes before the awaited status
+var ErrWaitExpired = errors.New("payment expired before reaching the awaited status")
+
+const (
+	defaultWaitInterval    = 2 * time.Second
+	defaultWaitBackoff     = 1.5
+	defaultWaitMaxInterval = 30 * time.Second
+	defaultWaitJitter      = 0.2
+)
+
+// jitterRand returns a random number in [0, 1), replaced by tests
+var jitterRand = rand.Float64
+
+// WaitOptions configures WaitForStatus
+type WaitOptions struct {
+	// Interval is the delay after the first poll, 2 seconds when zero
+	Interval time.Duration
+
+	// Backoff multiplies the delay after each poll, 1.5 when zero, 1 polls at a fixed interval
+	Backoff float64
+
+	// MaxInterval caps the delay between polls, 30 seconds when zero
+	MaxInterval time.Duration
+
+	// Jitter randomizes each delay by up to this fraction, 0.2 when zero, negative disables it
+	Jitter float64
+
+	// Until reports whether the wait is over, Status.IsFinal when nil
+	Until func(Status) bool
+
+	// ExpiryTime is when the payment expires, e.g. ChargeResponse.ExpiryTime
+	// The last poll is made once it has passed, zero waits until ctx is done
+	ExpiryTime time.Time
+
+	// MaxErrors is the number of consecutive GetStatus errors tolerated, zero stops at the first error
+	MaxErrors int
+
+	// Changes receives the first status and every change of status, the final one included
+	// Sends block until received or ctx is done, the channel is not closed
+	Changes chan<- *PaymentStatus
+}
+
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.Interval <= 0 {
+		o.Interval = defaultWaitInterval
+	}
+	if o.Backoff == 0 {
+		o.Backoff = defaultWaitBackoff
+	} else if o.Backoff < 1 {
+		o.Backoff = 1
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = defaultWaitMaxInterval
+	}
+	if o.MaxInterval < o.Interval {
+		o.MaxInterval = o.Interval
+	}
+	if o.Jitter == 0 {
+		o.Jitter = defaultWaitJitter
+	} else if o.Jitter < 0 {
+		o.Jitter = 0
+	}
+	if o.Until == nil {
+		o.Until = Status.IsFinal
+	}
+	return o
+}
+
+// jittered returns delay moved randomly by up to the jitter fraction
+func (o WaitOptions) jittered(delay time.Duration) time.Duration {
+	if o.Jitter == 0 {
+		return delay
+	}
+	return time.Duration(float64(delay) * (1 + o.Jitter*(2*jitterRand()-1)))
+}
+
+// WaitForStatus polls GetStatus until Until reports the status of orderID is awaited and returns it
+// orderID is the Reference of the ChargeResponse, as for GetStatus
+// The delay between polls grows from Interval by Backoff up to MaxInterval, with jitter
+// When ctx is done or ExpiryTime passes first, the last status is returned with ctx.Err() or ErrWaitExpired
+func (c *Client) WaitForStatus(ctx context.Context, orderID string, opts WaitOptions) (*PaymentStatus, error) {
+	opts = opts.withDefaults()
+
+	var last *PaymentStatus
+	delay := opts.Interval
+	failures := 0
+	for {
+		status, err := c.GetStatus(ctx, orderID)
+		switch {
+		case ctx.Err() != nil:
+			return last, ctx.Err()
+		case err != nil:
+			failures++
+			if failures > opts.MaxErrors {
+				return last, err
+			}
+		default:
+			failures = 0
+			if last == nil || status.Status != last.Status {
+				if err := sendStatus(ctx, opts.Changes, status); err != nil {
+					return status, err
+				}
+			}
+			last = status
+			if opts.Until(status.Status) {
+				return status, nil
+			}
+		}
+
+		wait := opts.jittered(delay)
+		if !opts.ExpiryTime.IsZero() {
+			remaining := time.Until(opts.ExpiryTime)
+			if remaining <= 0 {
+				if err != nil {
+					return last, err
+				}
+				return last, ErrWaitExpired
+			}
+			// The last poll is made right after the expiry
+			if wait > remaining {
+				wait = remaining
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last, ctx.Err()
+		case <-timer.C:
+		}
+
+		delay = time.Duration(float64(delay) * opts.Backoff)
+		if delay > opts.MaxInterval {
+			delay = opts.MaxInterval
+		}
+	}
+}
+
+// sendStatus sends status to changes, unless changes is nil
+func sendStatus(ctx context.Context, changes chan<- *PaymentStatus, status *PaymentStatus) error {
+	if changes == nil {
+		return nil
+	}
+	select {
+	case changes <- status:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
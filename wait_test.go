@@ -0,0 +1,192 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sequenceProvider is a mock provider whose GetStatus returns the next result of a sequence,
+// repeating the last one
+type sequenceProvider struct {
+	mockProvider
+	mu       sync.Mutex
+	statuses []Status
+	errs     []error
+	calls    int
+}
+
+func (p *sequenceProvider) GetStatus(ctx context.Context, orderID string) (*PaymentStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := p.calls
+	if i >= len(p.statuses) {
+		i = len(p.statuses) - 1
+	}
+	p.calls++
+	if i < len(p.errs) && p.errs[i] != nil {
+		return nil, p.errs[i]
+	}
+	return &PaymentStatus{OrderID: orderID, Status: p.statuses[i]}, nil
+}
+
+func (p *sequenceProvider) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func TestClient_WaitForStatus(t *testing.T) {
+	p := &sequenceProvider{
+		mockProvider: mockProvider{name: "mock"},
+		statuses:     []Status{StatusPending, StatusPending, StatusProcessing, StatusProcessing, StatusSuccess},
+	}
+	client := &Client{provider: p, config: &Config{}}
+
+	changes := make(chan *PaymentStatus, 10)
+	status, err := client.WaitForStatus(context.Background(), "ORDER-001", WaitOptions{
+		Interval: time.Millisecond,
+		Changes:  changes,
+	})
+	if err != nil {
+		t.Fatalf("WaitForStatus() error = %v", err)
+	}
+	if status.Status != StatusSuccess || status.OrderID != "ORDER-001" {
+		t.Errorf("WaitForStatus() = %+v, want SUCCESS of ORDER-001", status)
+	}
+	if p.callCount() != 5 {
+		t.Errorf("GetStatus() calls = %d, want 5", p.callCount())
+	}
+
+	close(changes)
+	var got []Status
+	for change := range changes {
+		got = append(got, change.Status)
+	}
+	want := []Status{StatusPending, StatusProcessing, StatusSuccess}
+	if len(got) != len(want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("changes = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestClient_WaitForStatus_Until(t *testing.T) {
+	p := &sequenceProvider{
+		mockProvider: mockProvider{name: "mock"},
+		statuses:     []Status{StatusPending, StatusProcessing, StatusSuccess},
+	}
+	client := &Client{provider: p, config: &Config{}}
+
+	status, err := client.WaitForStatus(context.Background(), "ORDER-001", WaitOptions{
+		Interval: time.Millisecond,
+		Until:    func(s Status) bool { return s != StatusPending },
+	})
+	if err != nil {
+		t.Fatalf("WaitForStatus() error = %v", err)
+	}
+	if status.Status != StatusProcessing {
+		t.Errorf("WaitForStatus() = %s, want %s", status.Status, StatusProcessing)
+	}
+}
+
+func TestClient_WaitForStatus_Stops(t *testing.T) {
+	errUnavailable := errors.New("service unavailable")
+
+	tests := []struct {
+		name       string
+		statuses   []Status
+		errs       []error
+		opts       WaitOptions
+		timeout    time.Duration
+		wantErr    error
+		wantStatus Status
+		wantCalls  int
+	}{
+		{
+			name:       "context done",
+			statuses:   []Status{StatusPending},
+			opts:       WaitOptions{Interval: 5 * time.Millisecond, Backoff: 1},
+			timeout:    30 * time.Millisecond,
+			wantErr:    context.DeadlineExceeded,
+			wantStatus: StatusPending,
+		},
+		{
+			name:       "expired",
+			statuses:   []Status{StatusPending},
+			opts:       WaitOptions{Interval: 5 * time.Millisecond, ExpiryTime: time.Now().Add(20 * time.Millisecond)},
+			wantErr:    ErrWaitExpired,
+			wantStatus: StatusPending,
+		},
+		{
+			name:      "error",
+			statuses:  []Status{StatusPending, StatusPending},
+			errs:      []error{nil, errUnavailable},
+			opts:      WaitOptions{Interval: time.Millisecond},
+			wantErr:   errUnavailable,
+			wantCalls: 2,
+		},
+		{
+			name:       "tolerated errors",
+			statuses:   []Status{StatusPending, StatusPending, StatusPending, StatusExpired},
+			errs:       []error{nil, errUnavailable, errUnavailable, nil},
+			opts:       WaitOptions{Interval: time.Millisecond, MaxErrors: 2},
+			wantStatus: StatusExpired,
+			wantCalls:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &sequenceProvider{mockProvider: mockProvider{name: "mock"}, statuses: tt.statuses, errs: tt.errs}
+			client := &Client{provider: p, config: &Config{}}
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			status, err := client.WaitForStatus(ctx, "ORDER-001", tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WaitForStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantStatus != "" && (status == nil || status.Status != tt.wantStatus) {
+				t.Errorf("WaitForStatus() = %+v, want %s", status, tt.wantStatus)
+			}
+			if tt.wantCalls > 0 && p.callCount() != tt.wantCalls {
+				t.Errorf("GetStatus() calls = %d, want %d", p.callCount(), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWaitOptions_Delay(t *testing.T) {
+	opts := WaitOptions{Interval: time.Second, MaxInterval: 3 * time.Second}.withDefaults()
+	if opts.Backoff != defaultWaitBackoff || opts.Jitter != defaultWaitJitter || opts.Until == nil {
+		t.Errorf("withDefaults() = %+v", opts)
+	}
+
+	defer func(f func() float64) { jitterRand = f }(jitterRand)
+	jitterRand = func() float64 { return 0 }
+	if got := opts.jittered(time.Second); got != 800*time.Millisecond {
+		t.Errorf("jittered() = %v, want 800ms", got)
+	}
+	jitterRand = func() float64 { return 1 }
+	if got := opts.jittered(time.Second); got != 1200*time.Millisecond {
+		t.Errorf("jittered() = %v, want 1.2s", got)
+	}
+
+	opts = WaitOptions{Jitter: -1, Backoff: 0.5}.withDefaults()
+	if got := opts.jittered(time.Second); got != time.Second || opts.Backoff != 1 {
+		t.Errorf("jittered() = %v, Backoff = %v, want no jitter and a fixed interval", got, opts.Backoff)
+	}
+}